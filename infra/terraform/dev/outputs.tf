@@ -43,12 +43,20 @@ output "eks_cluster_arn" {
 output "dynamodb_tables" {
   description = "Map of DynamoDB table names"
   value = {
-    bookings  = aws_dynamodb_table.bookings.name
-    venues    = aws_dynamodb_table.venues.name
-    releases  = aws_dynamodb_table.releases.name
-    publicity = aws_dynamodb_table.publicity.name
-    social    = aws_dynamodb_table.social.name
-    money     = aws_dynamodb_table.money.name
+    bookings             = aws_dynamodb_table.bookings.name
+    venues               = aws_dynamodb_table.venues.name
+    releases             = aws_dynamodb_table.releases.name
+    publicity            = aws_dynamodb_table.publicity.name
+    social               = aws_dynamodb_table.social.name
+    money                = aws_dynamodb_table.money.name
+    saved_searches       = aws_dynamodb_table.saved_searches.name
+    saved_search_matches = aws_dynamodb_table.saved_search_matches.name
+    venue_duplicates     = aws_dynamodb_table.venue_duplicates.name
+    venue_redirects      = aws_dynamodb_table.venue_redirects.name
+    venue_history        = aws_dynamodb_table.venue_history.name
+    venue_claims         = aws_dynamodb_table.venue_claims.name
+    venue_reviews        = aws_dynamodb_table.venue_reviews.name
+    venue_events         = aws_dynamodb_table.venue_events.name
   }
 }
 
@@ -243,6 +243,35 @@ resource "aws_dynamodb_table" "bookings" {
     type = "S"
   }
 
+  attribute {
+    name = "artist_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "venue_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "event_date"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "ArtistIndex"
+    hash_key        = "artist_id"
+    range_key       = "event_date"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "VenueIndex"
+    hash_key        = "venue_id"
+    range_key       = "event_date"
+    projection_type = "ALL"
+  }
+
   point_in_time_recovery {
     enabled = true
   }
@@ -310,6 +339,16 @@ resource "aws_dynamodb_table" "venues" {
     type = "S"
   }
 
+  attribute {
+    name = "moderation_status"
+    type = "S"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
   global_secondary_index {
     name            = "VenueTypeIndex"
     hash_key        = "venue_type"
@@ -317,16 +356,299 @@ resource "aws_dynamodb_table" "venues" {
     projection_type = "ALL"
   }
 
-  # GSI4: External ID index for deduplication
+  # Sparse: only community submissions carry a moderation status
+  global_secondary_index {
+    name            = "ModerationIndex"
+    hash_key        = "moderation_status"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "saved_searches" {
+  name         = "saved-searches-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "user_id"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "UserIndex"
+    hash_key        = "user_id"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "saved_search_matches" {
+  name         = "saved-search-matches-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "saved_search_id"
+  range_key    = "venue_id"
+
+  attribute {
+    name = "saved_search_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "venue_id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "venue_duplicates" {
+  name         = "venue-duplicates-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "status"
+    type = "S"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "StatusIndex"
+    hash_key        = "status"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "venue_redirects" {
+  name         = "venue-redirects-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "venue_history" {
+  name         = "venue-history-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "venue_id"
+  range_key    = "version"
+
+  attribute {
+    name = "venue_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "version"
+    type = "N"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "venue_claims" {
+  name         = "venue-claims-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "user_id"
+    type = "S"
+  }
+
   attribute {
-    name = "external_source_id"
+    name = "created_at"
     type = "S"
   }
 
   global_secondary_index {
-    name            = "ExternalIdIndex"
-    hash_key        = "external_source_id"
-    range_key       = "id"
+    name            = "UserIndex"
+    hash_key        = "user_id"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_dynamodb_table" "venue_reviews" {
+  name         = "venue-reviews-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "venue_id"
+  range_key    = "artist_id"
+
+  attribute {
+    name = "venue_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "artist_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
+  local_secondary_index {
+    name            = "CreatedIndex"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+# Uploaded venue photos, served publicly by object URL
+resource "aws_s3_bucket" "venue_photos" {
+  bucket = "crowdunlocked-venue-photos-prod"
+
+  tags = {
+    Environment = "prod"
+    Service     = "bookings"
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "venue_photos" {
+  bucket = aws_s3_bucket.venue_photos.id
+
+  block_public_acls       = true
+  ignore_public_acls      = true
+  block_public_policy     = false
+  restrict_public_buckets = false
+}
+
+resource "aws_s3_bucket_policy" "venue_photos" {
+  bucket     = aws_s3_bucket.venue_photos.id
+  depends_on = [aws_s3_bucket_public_access_block.venue_photos]
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid       = "PublicReadPhotos"
+        Effect    = "Allow"
+        Principal = "*"
+        Action    = "s3:GetObject"
+        Resource  = "${aws_s3_bucket.venue_photos.arn}/venues/*"
+      }
+    ]
+  })
+}
+
+resource "aws_dynamodb_table" "venue_events" {
+  name         = "venue-events-prod"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "venue_id"
+  range_key    = "event_key"
+
+  attribute {
+    name = "venue_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "event_key"
+    type = "S"
+  }
+
+  attribute {
+    name = "date"
+    type = "S"
+  }
+
+  local_secondary_index {
+    name            = "DateIndex"
+    range_key       = "date"
     projection_type = "ALL"
   }
 
@@ -6,7 +6,12 @@ REGION=${2:-us-east-1}
 
 echo "Creating DynamoDB tables at $ENDPOINT..."
 
-TABLES=("bookings-local" "releases-local" "publicity-local" "social-local" "money-local")
+# The bookings service declares its tables and indexes in Go
+echo "Migrating bookings tables"
+(cd "$(dirname "$0")/../services/bookings" && \
+    AWS_ENDPOINT="$ENDPOINT" AWS_REGION="$REGION" go run ./cmd/migrate)
+
+TABLES=("releases-local" "publicity-local" "social-local" "money-local")
 
 for table in "${TABLES[@]}"; do
     echo "Creating table: $table"
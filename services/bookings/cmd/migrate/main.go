@@ -0,0 +1,135 @@
+// Command migrate creates the bookings service's DynamoDB tables and their
+// indexes, usually in DynamoDB Local, and records which migrations have been
+// applied. It is safe to run repeatedly: existing tables are left in place
+// and only given the global indexes they lack. Deployed tables are managed
+// by Terraform, which declares the same keys and indexes.
+//
+//	AWS_ENDPOINT=http://localhost:8000 migrate [-list]
+//
+// Table names come from the same DYNAMODB_*_TABLE variables as the server.
+// When VENUE_STORE is postgres it also applies the venue store's migrations
+// to the PostgreSQL database at POSTGRES_URL, naming tables by the
+// POSTGRES_*_TABLE variables, and records them in the same table under the
+// service name bookings-postgres.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/crowdunlocked/services/bookings/internal/migrate"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+	"github.com/jackc/pgx/v5"
+)
+
+func main() {
+	log.SetFlags(0)
+	list := flag.Bool("list", false, "list applied migrations without applying any")
+	timeout := flag.Duration("timeout", 10*time.Minute, "how long to wait for tables and indexes to become active")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatalf("unable to load SDK config: %v", err)
+	}
+	var client *dynamodb.Client
+	if endpoint := os.Getenv("AWS_ENDPOINT"); endpoint != "" {
+		client = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = &endpoint
+		})
+		log.Printf("Using DynamoDB endpoint: %s", endpoint)
+	} else {
+		client = dynamodb.NewFromConfig(cfg)
+	}
+
+	migrationsTable := getEnv("DYNAMODB_MIGRATIONS_TABLE", "schema-migrations")
+	run := func(migrator *migrate.Migrator, migrations []migrate.Migration) {
+		if *list {
+			listMigrations(ctx, migrator, migrations)
+			return
+		}
+		done, err := migrator.Up(ctx, migrations)
+		for _, m := range done {
+			log.Printf("applied migration %d: %s", m.Version, m.Description)
+		}
+		if err != nil {
+			log.Fatalf("migrating: %v", err)
+		}
+		if len(done) == 0 {
+			log.Print("tables are up to date")
+		}
+	}
+
+	run(migrate.NewMigrator(client, migrationsTable, "bookings"), schema.Migrations(tablesFromEnv()))
+
+	if getEnv("VENUE_STORE", "dynamodb") == "postgres" {
+		conn, err := pgx.Connect(ctx, os.Getenv("POSTGRES_URL"))
+		if err != nil {
+			log.Fatalf("connecting to PostgreSQL: %v", err)
+		}
+		defer conn.Close(context.Background())
+		log.Print("Migrating the PostgreSQL venue store")
+		run(migrate.NewMigrator(client, migrationsTable, "bookings-postgres"), schema.PostgresMigrations(conn, postgresTablesFromEnv()))
+	}
+}
+
+// listMigrations logs whether each migration has been applied
+func listMigrations(ctx context.Context, migrator *migrate.Migrator, migrations []migrate.Migration) {
+	applied, err := migrator.Applied(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range migrations {
+		status := "pending"
+		if applied[m.Version] {
+			status = "applied"
+		}
+		log.Printf("%4d  %-8s %s", m.Version, status, m.Description)
+	}
+}
+
+// postgresTablesFromEnv reads the PostgreSQL venue store's table names from
+// the same variables as the server
+func postgresTablesFromEnv() schema.PostgresTables {
+	defaults := schema.DefaultPostgresTables()
+	return schema.PostgresTables{
+		Venues:       getEnv("POSTGRES_VENUES_TABLE", defaults.Venues),
+		VenueReviews: getEnv("POSTGRES_VENUE_REVIEWS_TABLE", defaults.VenueReviews),
+	}
+}
+
+// tablesFromEnv reads table names from the same variables as the server
+func tablesFromEnv() schema.Tables {
+	defaults := schema.DefaultTables()
+	return schema.Tables{
+		Bookings:           getEnv("DYNAMODB_BOOKINGS_TABLE", defaults.Bookings),
+		Venues:             getEnv("DYNAMODB_VENUES_TABLE", defaults.Venues),
+		SavedSearches:      getEnv("DYNAMODB_SAVED_SEARCHES_TABLE", defaults.SavedSearches),
+		SavedSearchMatches: getEnv("DYNAMODB_SAVED_SEARCH_MATCHES_TABLE", defaults.SavedSearchMatches),
+		VenueDuplicates:    getEnv("DYNAMODB_VENUE_DUPLICATES_TABLE", defaults.VenueDuplicates),
+		VenueRedirects:     getEnv("DYNAMODB_VENUE_REDIRECTS_TABLE", defaults.VenueRedirects),
+		VenueHistory:       getEnv("DYNAMODB_VENUE_HISTORY_TABLE", defaults.VenueHistory),
+		VenueClaims:        getEnv("DYNAMODB_VENUE_CLAIMS_TABLE", defaults.VenueClaims),
+		VenueReviews:       getEnv("DYNAMODB_VENUE_REVIEWS_TABLE", defaults.VenueReviews),
+		VenueEvents:        getEnv("DYNAMODB_VENUE_EVENTS_TABLE", defaults.VenueEvents),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
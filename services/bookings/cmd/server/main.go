@@ -2,20 +2,30 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/crowdunlocked/services/bookings/internal/cache"
+	"github.com/crowdunlocked/services/bookings/internal/geocode"
 	"github.com/crowdunlocked/services/bookings/internal/handler"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
 	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
 	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
@@ -40,16 +50,69 @@ func main() {
 	// Initialize repositories
 	bookingsTable := getEnv("DYNAMODB_BOOKINGS_TABLE", "bookings")
 	venuesTable := getEnv("DYNAMODB_VENUES_TABLE", "venues")
+	savedSearchesTable := getEnv("DYNAMODB_SAVED_SEARCHES_TABLE", "saved-searches")
+	savedSearchMatchesTable := getEnv("DYNAMODB_SAVED_SEARCH_MATCHES_TABLE", "saved-search-matches")
+	venueDuplicatesTable := getEnv("DYNAMODB_VENUE_DUPLICATES_TABLE", "venue-duplicates")
+	venueRedirectsTable := getEnv("DYNAMODB_VENUE_REDIRECTS_TABLE", "venue-redirects")
+	venueHistoryTable := getEnv("DYNAMODB_VENUE_HISTORY_TABLE", "venue-history")
+	venueClaimsTable := getEnv("DYNAMODB_VENUE_CLAIMS_TABLE", "venue-claims")
+	venueReviewsTable := getEnv("DYNAMODB_VENUE_REVIEWS_TABLE", "venue-reviews")
+	venueEventsTable := getEnv("DYNAMODB_VENUE_EVENTS_TABLE", "venue-events")
 	
-	bookingRepo := repository.NewBookingRepository(dynamoClient, bookingsTable)
-	venueRepo := repository.NewDynamoDBVenueRepository(dynamoClient, venuesTable)
+	bookingRepo := repository.NewDynamoDBBookingRepository(dynamoClient, bookingsTable)
+	duplicateRepo := repository.NewDynamoDBDuplicateRepository(dynamoClient, venueDuplicatesTable, venueRedirectsTable)
+	venueRepo, reviewRepo := newVenueStore(ctx, dynamoClient, venuesTable, venueReviewsTable, venueRedirectsTable, duplicateRepo)
+	savedSearchRepo := repository.NewDynamoDBSavedSearchRepository(dynamoClient, savedSearchesTable, savedSearchMatchesTable)
+	venueHistoryRepo := repository.NewDynamoDBVenueHistoryRepository(dynamoClient, venueHistoryTable)
+	claimRepo := repository.NewDynamoDBClaimRepository(dynamoClient, venueClaimsTable)
+	venueEventRepo := repository.NewDynamoDBVenueEventRepository(dynamoClient, venueEventsTable)
+
+	// Reviews write ratings straight to the venue store, so they invalidate
+	// the cache themselves
+	venues := venueRepo
+	if venueCache := newVenueCache(venueRepo); venueCache != nil {
+		venues = venueCache
+		reviewRepo.SetVenueCache(venueCache)
+	}
+
+	// Initialize notification senders
+	notifier := newNotifier()
 
 	// Initialize services
-	venueService := service.NewVenueService(venueRepo)
+	venueService := service.NewVenueService(venues)
+	venueService.SetRedirectResolver(duplicateRepo)
+	venueService.SetGeocoder(newGeocoder())
+	venueService.SetHistory(venueHistoryRepo)
+	venueService.SetBookings(bookingRepo)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo, venueService, notifier)
+	recommendationService := service.NewRecommendationService(bookingRepo, venues)
+	dedupService := service.NewDedupService(venueService, bookingRepo, duplicateRepo)
+	dedupService.SetReviews(reviewRepo)
+	venueService.SetDuplicateFinder(dedupService)
+	claimService := service.NewClaimService(claimRepo, venueService, notifier)
+	reviewService := service.NewReviewService(reviewRepo, venueService, bookingRepo)
+	eventService := service.NewEventService(venueEventRepo, venueService, bookingRepo)
+	photoStore, photoDir := newPhotoStore(cfg)
+	photoService := service.NewPhotoService(venueService, photoStore)
+
+	// Initialize background workers
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	savedSearchWorker := service.NewSavedSearchWorker(savedSearchService, 1000)
+	venueService.AddListener(savedSearchWorker)
+	go savedSearchWorker.Run(workerCtx)
 
 	// Initialize handlers
-	bookingHandler := handler.NewBookingHandler(bookingRepo)
+	bookingHandler := handler.NewBookingHandler(bookingRepo, venueService)
 	venueHandler := handler.NewVenueHandler(venueService)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+	dedupHandler := handler.NewDedupHandler(dedupService, venueService)
+	claimHandler := handler.NewClaimHandler(claimService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	venueEventHandler := handler.NewVenueEventHandler(eventService)
+	photoHandler := handler.NewPhotoHandler(photoService)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -69,13 +132,70 @@ func main() {
 		// Venues routes
 		r.Route("/venues", func(r chi.Router) {
 			r.Get("/search", venueHandler.Search)
+			r.Post("/import", venueHandler.Import)
+			r.Get("/export", venueHandler.Export)
 			r.Post("/", venueHandler.Create)
 			r.Get("/{id}", venueHandler.GetByID)
 			r.Put("/{id}", venueHandler.Update)
+			r.Patch("/{id}", venueHandler.Patch)
 			r.Delete("/{id}", venueHandler.Delete)
+			r.Post("/{id}/deactivate", venueHandler.Deactivate)
+			r.Post("/{id}/reactivate", venueHandler.Reactivate)
+			r.Get("/{id}/provenance", venueHandler.Provenance)
+			r.Get("/{id}/history", venueHandler.History)
+			r.Get("/{id}/history/{version}", venueHandler.GetVersion)
+			r.Post("/{id}/history/{version}/revert", venueHandler.Revert)
+			r.Get("/{id}/duplicates", dedupHandler.FindDuplicates)
+			r.Post("/{id}/duplicates/scan", dedupHandler.Scan)
+			r.Post("/{id}/merge", dedupHandler.Merge)
+			r.Post("/{id}/claims", claimHandler.Create)
+			r.Get("/{id}/reviews", reviewHandler.List)
+			r.Post("/{id}/reviews", reviewHandler.Submit)
+			r.Get("/{id}/events", venueEventHandler.List)
+			r.Post("/{id}/photos", photoHandler.Upload)
+			r.Put("/{id}/photos/order", photoHandler.Reorder)
+			r.Delete("/{id}/photos/{photoID}", photoHandler.Delete)
+		})
+
+		// Community submission moderation routes
+		r.Route("/moderation/venues", func(r chi.Router) {
+			r.Get("/", venueHandler.ModerationQueue)
+			r.Post("/{id}/approve", venueHandler.Approve)
+			r.Post("/{id}/reject", venueHandler.Reject)
+		})
+
+		// Venue ownership claim routes
+		r.Route("/venue-claims", func(r chi.Router) {
+			r.Post("/{id}/confirm", claimHandler.Confirm)
+		})
+
+		// Duplicate review queue routes
+		r.Route("/venue-duplicates", func(r chi.Router) {
+			r.Get("/", dedupHandler.ListQueue)
+			r.Post("/{id}/merge", dedupHandler.MergeCandidate)
+			r.Post("/{id}/dismiss", dedupHandler.Dismiss)
+		})
+
+		// Artist routes
+		r.Route("/artists", func(r chi.Router) {
+			r.Get("/{id}/recommended-venues", recommendationHandler.RecommendedVenues)
+		})
+
+		// Saved search routes
+		r.Route("/saved-searches", func(r chi.Router) {
+			r.Post("/", savedSearchHandler.Create)
+			r.Get("/", savedSearchHandler.List)
+			r.Get("/{id}", savedSearchHandler.GetByID)
+			r.Delete("/{id}", savedSearchHandler.Delete)
+			r.Get("/{id}/matches", savedSearchHandler.Matches)
 		})
 	})
 
+	// Photos kept on local disk in development are served by the API itself
+	if photoDir != "" {
+		r.Handle("/media/*", http.StripPrefix("/media/", http.FileServer(http.Dir(photoDir))))
+	}
+
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		if _, err := w.Write([]byte("OK")); err != nil {
@@ -96,14 +216,35 @@ func main() {
 		}
 	}()
 
+	// Runtime and repository counters, such as corrupt items skipped by
+	// venue searches, include memory stats and the command line, so they are
+	// served on a separate listener that is only reachable from the host
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/vars", expvar.Handler())
+	debugSrv := &http.Server{
+		Addr:    getEnv("DEBUG_ADDR", "127.0.0.1:6060"),
+		Handler: debugMux,
+	}
+
+	go func() {
+		log.Printf("Serving debug vars on %s", debugSrv.Addr)
+		if err := debugSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("debug listener: %v", err)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if err := debugSrv.Shutdown(ctx); err != nil {
+		log.Printf("Debug listener forced to shutdown: %v", err)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
@@ -111,6 +252,128 @@ func main() {
 	log.Println("Server exiting")
 }
 
+// newNotifier routes webhook alerts over HTTP, email over SMTP when
+// NOTIFY_SMTP_ADDR is set and text messages through Twilio when
+// TWILIO_ACCOUNT_SID is set. Unconfigured channels are written to the log.
+func newNotifier() *notify.Mux {
+	mux := notify.NewMux()
+	mux.Handle(notify.ChannelWebhook, notify.NewWebhookSender(nil))
+
+	if smtpAddr := os.Getenv("NOTIFY_SMTP_ADDR"); smtpAddr != "" {
+		from := getEnv("NOTIFY_EMAIL_FROM", "alerts@crowdunlocked.com")
+		mux.Handle(notify.ChannelEmail, notify.NewEmailSender(smtpAddr, from, nil))
+	} else {
+		mux.Handle(notify.ChannelEmail, notify.LogSender{})
+	}
+
+	if sid := os.Getenv("TWILIO_ACCOUNT_SID"); sid != "" {
+		mux.Handle(notify.ChannelSMS, notify.NewTwilioSender(
+			os.Getenv("TWILIO_API_URL"), sid, os.Getenv("TWILIO_AUTH_TOKEN"), os.Getenv("TWILIO_FROM_NUMBER"), nil))
+	} else {
+		mux.Handle(notify.ChannelSMS, notify.LogSender{})
+	}
+
+	return mux
+}
+
+// newPhotoStore keeps photos in S3 when PHOTOS_S3_BUCKET is set and on local
+// disk otherwise. For local disk it also returns the directory to serve.
+func newPhotoStore(cfg aws.Config) (storage.ObjectStore, string) {
+	if bucket := os.Getenv("PHOTOS_S3_BUCKET"); bucket != "" {
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			if endpoint := os.Getenv("AWS_ENDPOINT"); endpoint != "" {
+				o.BaseEndpoint = &endpoint
+				o.UsePathStyle = true
+			}
+		})
+		return storage.NewS3Store(client, bucket, cfg.Region, os.Getenv("PHOTOS_BASE_URL")), ""
+	}
+
+	dir := getEnv("PHOTOS_DIR", "data/photos")
+	baseURL := getEnv("PHOTOS_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")+"/media")
+	log.Printf("Storing photos in %s", dir)
+	return storage.NewLocalStore(dir, baseURL), dir
+}
+
+// newGeocoder uses Mapbox when MAPBOX_ACCESS_TOKEN is set and falls back to
+// the embedded city gazetteer when Mapbox fails or is not configured
+func newGeocoder() geocode.Chain {
+	var chain geocode.Chain
+	if token := os.Getenv("MAPBOX_ACCESS_TOKEN"); token != "" {
+		chain = append(chain, geocode.NewMapboxGeocoder(os.Getenv("MAPBOX_API_URL"), token, nil))
+	}
+
+	offline, err := geocode.NewOfflineGeocoder()
+	if err != nil {
+		log.Fatalf("unable to load offline geocoder: %v", err)
+	}
+	return append(chain, offline)
+}
+
+// venueReviewRepository is a review repository that writes venue ratings
+// around the venue cache
+type venueReviewRepository interface {
+	repository.ReviewRepository
+	SetVenueCache(cache repository.VenueInvalidator)
+}
+
+// newVenueStore keeps venues and their reviews in DynamoDB, or in PostgreSQL
+// at POSTGRES_URL when VENUE_STORE is postgres. Reviews stay with venues, as
+// ratings are saved in the same transaction as the reviews. Merge redirects
+// stay in DynamoDB; the DynamoDB store writes them in its merge transaction.
+func newVenueStore(ctx context.Context, dynamoClient *dynamodb.Client, venuesTable, reviewsTable, redirectsTable string, redirects repository.RedirectWriter) (repository.VenueRepository, venueReviewRepository) {
+	switch store := getEnv("VENUE_STORE", "dynamodb"); store {
+	case "dynamodb":
+		venues := repository.NewDynamoDBVenueRepository(dynamoClient, venuesTable)
+		venues.SetRedirectsTable(redirectsTable)
+		return venues, repository.NewDynamoDBReviewRepository(dynamoClient, reviewsTable, venues)
+	case "postgres":
+		pool, err := pgxpool.New(ctx, os.Getenv("POSTGRES_URL"))
+		if err != nil {
+			log.Fatalf("unable to configure PostgreSQL: %v", err)
+		}
+		defaults := schema.DefaultPostgresTables()
+		venues := repository.NewPostgresVenueRepository(pool, getEnv("POSTGRES_VENUES_TABLE", defaults.Venues))
+		venues.SetRedirects(redirects)
+		log.Printf("Storing venues in PostgreSQL")
+		return venues, repository.NewPostgresReviewRepository(pool, getEnv("POSTGRES_VENUE_REVIEWS_TABLE", defaults.VenueReviews), venues)
+	default:
+		log.Fatalf("unknown VENUE_STORE %q, want dynamodb or postgres", store)
+		return nil, nil
+	}
+}
+
+// newVenueCache caches venues for VENUE_CACHE_TTL in an in-process LRU of
+// VENUE_CACHE_SIZE entries, in front of Redis when REDIS_ADDR is set. With
+// Redis, local copies are kept for VENUE_CACHE_LOCAL_TTL, as other replicas
+// cannot invalidate them. It returns nil when there is nowhere to cache.
+func newVenueCache(venues repository.VenueRepository) *repository.CachedVenueRepository {
+	size, err := strconv.Atoi(getEnv("VENUE_CACHE_SIZE", "10000"))
+	if err != nil {
+		log.Fatalf("invalid VENUE_CACHE_SIZE: %v", err)
+	}
+	ttl, err := time.ParseDuration(getEnv("VENUE_CACHE_TTL", "5m"))
+	if err != nil {
+		log.Fatalf("invalid VENUE_CACHE_TTL: %v", err)
+	}
+
+	var store cache.Store = cache.NewLRU(size)
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		db, err := strconv.Atoi(getEnv("REDIS_DB", "0"))
+		if err != nil {
+			log.Fatalf("invalid REDIS_DB: %v", err)
+		}
+		localTTL, err := time.ParseDuration(getEnv("VENUE_CACHE_LOCAL_TTL", "30s"))
+		if err != nil {
+			log.Fatalf("invalid VENUE_CACHE_LOCAL_TTL: %v", err)
+		}
+		store = cache.NewTiered(store, cache.NewRedis(addr, os.Getenv("REDIS_PASSWORD"), db), localTTL)
+	} else if size <= 0 {
+		return nil
+	}
+	return repository.NewCachedVenueRepository(venues, store, ttl)
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
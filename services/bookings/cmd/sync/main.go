@@ -0,0 +1,147 @@
+// Command sync enriches stored venues from Songkick and Bandsintown and
+// records the shows each venue has hosted from our bookings and their
+// calendars. It is run nightly as a Kubernetes CronJob and exits non-zero if
+// any source or the event sync fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	sources := flag.String("sources", "songkick,bandsintown", "comma-separated sources to sync")
+	timeout := flag.Duration("timeout", time.Hour, "maximum run time")
+	syncEvents := flag.Bool("events", true, "record past events and update venue genres from them")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatalf("unable to load SDK config: %v", err)
+	}
+
+	var dynamoClient *dynamodb.Client
+	if endpoint := os.Getenv("AWS_ENDPOINT"); endpoint != "" {
+		dynamoClient = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = &endpoint
+		})
+		log.Printf("Using DynamoDB endpoint: %s", endpoint)
+	} else {
+		dynamoClient = dynamodb.NewFromConfig(cfg)
+	}
+
+	venueService := service.NewVenueService(newVenueRepository(ctx, dynamoClient))
+	venueService.SetHistory(repository.NewDynamoDBVenueHistoryRepository(dynamoClient, getEnv("DYNAMODB_VENUE_HISTORY_TABLE", "venue-history")))
+
+	bookingRepo := repository.NewDynamoDBBookingRepository(dynamoClient, getEnv("DYNAMODB_BOOKINGS_TABLE", "bookings"))
+	eventRepo := repository.NewDynamoDBVenueEventRepository(dynamoClient, getEnv("DYNAMODB_VENUE_EVENTS_TABLE", "venue-events"))
+	eventService := service.NewEventService(eventRepo, venueService, bookingRepo)
+
+	failed := false
+	for _, name := range strings.Split(*sources, ",") {
+		searcher, ok := newSearcher(domain.DataSource(strings.TrimSpace(name)))
+		if !ok {
+			log.Printf("Skipping %s: unknown source or missing credentials", name)
+			continue
+		}
+		if events, ok := searcher.(source.EventSource); ok {
+			eventService.AddSource(events)
+		}
+
+		started := time.Now()
+		result, err := service.NewEnrichmentJob(venueService, searcher).Run(ctx)
+		if err != nil {
+			log.Printf("Sync from %s stopped: %v", name, err)
+			failed = true
+		}
+		if result != nil {
+			summary, _ := json.Marshal(result)
+			log.Printf("Sync from %s finished in %s: %s", name, time.Since(started).Round(time.Second), summary)
+			if result.Failed > 0 {
+				failed = true
+			}
+		}
+	}
+
+	if *syncEvents {
+		started := time.Now()
+		result, err := eventService.SyncAll(ctx)
+		if err != nil {
+			log.Printf("Event sync stopped: %v", err)
+			failed = true
+		}
+		if result != nil {
+			summary, _ := json.Marshal(result)
+			log.Printf("Event sync finished in %s: %s", time.Since(started).Round(time.Second), summary)
+			if result.Failed > 0 {
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// newSearcher builds the client for a source from its environment
+// credentials, reporting false when they are not configured
+func newSearcher(name domain.DataSource) (source.VenueSearcher, bool) {
+	switch name {
+	case domain.SourceSongkick:
+		apiKey := os.Getenv("SONGKICK_API_KEY")
+		if apiKey == "" {
+			return nil, false
+		}
+		return source.NewSongkickSource(os.Getenv("SONGKICK_API_URL"), apiKey, nil), true
+	case domain.SourceBandsintown:
+		appID := os.Getenv("BANDSINTOWN_APP_ID")
+		if appID == "" {
+			return nil, false
+		}
+		return source.NewBandsintownSource(os.Getenv("BANDSINTOWN_API_URL"), appID, nil), true
+	default:
+		return nil, false
+	}
+}
+
+// newVenueRepository opens the venue store the server uses: DynamoDB, or
+// PostgreSQL at POSTGRES_URL when VENUE_STORE is postgres
+func newVenueRepository(ctx context.Context, dynamoClient *dynamodb.Client) repository.VenueRepository {
+	if getEnv("VENUE_STORE", "dynamodb") != "postgres" {
+		return repository.NewDynamoDBVenueRepository(dynamoClient, getEnv("DYNAMODB_VENUES_TABLE", "venues"))
+	}
+	pool, err := pgxpool.New(ctx, os.Getenv("POSTGRES_URL"))
+	if err != nil {
+		log.Fatalf("unable to configure PostgreSQL: %v", err)
+	}
+	return repository.NewPostgresVenueRepository(pool, getEnv("POSTGRES_VENUES_TABLE", schema.DefaultPostgresTables().Venues))
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
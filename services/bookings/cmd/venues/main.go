@@ -0,0 +1,303 @@
+// Command venues imports venues in bulk from CSV or NDJSON files, exports
+// the venue table in the same formats, purges deleted venues and rebuilds
+// the venue type and geohash indexes.
+//
+//	venues import [-format csv|ndjson] [-dry-run] [-actor name] FILE
+//	venues export [-format csv|ndjson] [-o FILE]
+//	venues purge [-retention 720h]
+//	venues reindex-types
+//	venues reindex-geohash
+//
+// Imports read FILE, or standard input when it is "-", and print a JSON
+// report of every row; the command exits non-zero if any row failed.
+// Purge removes venues deleted longer ago than the retention period, unless
+// bookings still reference them, and runs daily as a Kubernetes CronJob.
+// Reindex-types indexes venues saved before they were indexed under every
+// type, and reindex-geohash moves venues saved before the geohash index was
+// partitioned by cell; both are safe to run again, and only apply to
+// DynamoDB. The other commands use PostgreSQL when VENUE_STORE is postgres,
+// like the server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/crowdunlocked/services/bookings/internal/bulk"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/geocode"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	switch os.Args[1] {
+	case "import":
+		runImport(ctx, os.Args[2:])
+	case "export":
+		runExport(ctx, os.Args[2:])
+	case "purge":
+		runPurge(ctx, os.Args[2:])
+	case "reindex-types":
+		runReindexTypes(ctx)
+	case "reindex-geohash":
+		runReindexGeohash(ctx)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: venues import [-format csv|ndjson] [-dry-run] [-actor name] FILE")
+	fmt.Fprintln(os.Stderr, "       venues export [-format csv|ndjson] [-o FILE]")
+	fmt.Fprintln(os.Stderr, "       venues purge [-retention 720h]")
+	fmt.Fprintln(os.Stderr, "       venues reindex-types")
+	fmt.Fprintln(os.Stderr, "       venues reindex-geohash")
+	os.Exit(2)
+}
+
+func runImport(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", "", "file format, csv or ndjson (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate and dedupe without saving")
+	actor := flags.String("actor", os.Getenv("USER"), "who the import is attributed to in venue history")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	path := flags.Arg(0)
+
+	format, err := fileFormat(*formatName, path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	rows, err := bulk.Read(in, format)
+	if err != nil {
+		log.Fatalf("reading %s: %v", path, err)
+	}
+
+	venueService := newVenueService(ctx)
+	venueService.SetGeocoder(newGeocoder())
+	// Only FindDuplicates is used, which needs neither bookings nor the queue
+	venueService.SetDuplicateFinder(service.NewDedupService(venueService, nil, nil))
+
+	report, err := venueService.Import(ctx, rows, *actor, *dryRun)
+	if err != nil {
+		log.Fatalf("importing %s: %v", path, err)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	if err := out.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d rows: %d created, %d valid, %d duplicates, %d invalid, %d failed",
+		report.Total, report.Created, report.Valid, report.Duplicates, report.Invalid, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runPurge(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	retention := flags.Duration("retention", domain.VenueRetention, "how long deleted venues are kept")
+	_ = flags.Parse(args)
+
+	cfg, dynamoClient := newDynamoClient(ctx)
+	venueService := newVenueServiceWithClient(ctx, dynamoClient)
+	venueService.SetBookings(repository.NewDynamoDBBookingRepository(dynamoClient, getEnv("DYNAMODB_BOOKINGS_TABLE", "bookings")))
+
+	result, err := service.NewPurgeJob(venueService, newPhotoStore(cfg), *retention).Run(ctx, time.Now())
+	if err != nil {
+		log.Fatalf("purging venues: %v", err)
+	}
+
+	log.Printf("%d venues due: %d purged, %d kept for their bookings, %d failed",
+		result.Due, result.Purged, result.Blocked, result.Failed)
+	for _, e := range result.Errors {
+		log.Print(e)
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runReindexTypes(ctx context.Context) {
+	_, dynamoClient := newDynamoClient(ctx)
+	venueRepo := repository.NewDynamoDBVenueRepository(dynamoClient, getEnv("DYNAMODB_VENUES_TABLE", "venues"))
+
+	indexed, err := venueRepo.BackfillTypeIndex(ctx)
+	if err != nil {
+		log.Fatalf("reindexing venue types after %d venues: %v", indexed, err)
+	}
+	log.Printf("indexed the types of %d venues", indexed)
+}
+
+func runReindexGeohash(ctx context.Context) {
+	_, dynamoClient := newDynamoClient(ctx)
+	venueRepo := repository.NewDynamoDBVenueRepository(dynamoClient, getEnv("DYNAMODB_VENUES_TABLE", "venues"))
+
+	moved, err := venueRepo.BackfillGeohashIndex(ctx)
+	if err != nil {
+		log.Fatalf("reindexing venue geohashes after %d venues: %v", moved, err)
+	}
+	log.Printf("moved %d venues to their geohash cell", moved)
+}
+
+func runExport(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := flags.String("format", "", "file format, csv or ndjson (default: from -o, else ndjson)")
+	output := flags.String("o", "-", "output file, or - for standard output")
+	_ = flags.Parse(args)
+
+	format, err := fileFormat(*formatName, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	writer := bulk.NewWriter(out, format)
+	count := 0
+	err = newVenueService(ctx).Export(ctx, func(v *domain.Venue) error {
+		count++
+		return writer.Write(v)
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		log.Fatalf("exporting venues: %v", err)
+	}
+	log.Printf("exported %d venues", count)
+}
+
+// fileFormat picks the format from the flag, else the file extension, else NDJSON
+func fileFormat(name, path string) (bulk.Format, error) {
+	if name != "" {
+		return bulk.ParseFormat(name)
+	}
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext != "" {
+		return bulk.ParseFormat(ext)
+	}
+	return bulk.FormatNDJSON, nil
+}
+
+func newVenueService(ctx context.Context) *service.VenueService {
+	_, dynamoClient := newDynamoClient(ctx)
+	return newVenueServiceWithClient(ctx, dynamoClient)
+}
+
+func newVenueServiceWithClient(ctx context.Context, dynamoClient *dynamodb.Client) *service.VenueService {
+	venueService := service.NewVenueService(newVenueRepository(ctx, dynamoClient))
+	venueService.SetHistory(repository.NewDynamoDBVenueHistoryRepository(dynamoClient, getEnv("DYNAMODB_VENUE_HISTORY_TABLE", "venue-history")))
+	return venueService
+}
+
+// newVenueRepository opens the venue store the server uses: DynamoDB, or
+// PostgreSQL at POSTGRES_URL when VENUE_STORE is postgres
+func newVenueRepository(ctx context.Context, dynamoClient *dynamodb.Client) repository.VenueRepository {
+	if getEnv("VENUE_STORE", "dynamodb") != "postgres" {
+		return repository.NewDynamoDBVenueRepository(dynamoClient, getEnv("DYNAMODB_VENUES_TABLE", "venues"))
+	}
+	pool, err := pgxpool.New(ctx, os.Getenv("POSTGRES_URL"))
+	if err != nil {
+		log.Fatalf("unable to configure PostgreSQL: %v", err)
+	}
+	return repository.NewPostgresVenueRepository(pool, getEnv("POSTGRES_VENUES_TABLE", schema.DefaultPostgresTables().Venues))
+}
+
+func newDynamoClient(ctx context.Context) (aws.Config, *dynamodb.Client) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Fatalf("unable to load SDK config: %v", err)
+	}
+
+	if endpoint := os.Getenv("AWS_ENDPOINT"); endpoint != "" {
+		return cfg, dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = &endpoint
+		})
+	}
+	return cfg, dynamodb.NewFromConfig(cfg)
+}
+
+// newPhotoStore opens the S3 bucket in PHOTOS_S3_BUCKET, else the local
+// photo directory the server uses in development
+func newPhotoStore(cfg aws.Config) storage.ObjectStore {
+	if bucket := os.Getenv("PHOTOS_S3_BUCKET"); bucket != "" {
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			if endpoint := os.Getenv("AWS_ENDPOINT"); endpoint != "" {
+				o.BaseEndpoint = &endpoint
+				o.UsePathStyle = true
+			}
+		})
+		return storage.NewS3Store(client, bucket, cfg.Region, os.Getenv("PHOTOS_BASE_URL"))
+	}
+	return storage.NewLocalStore(getEnv("PHOTOS_DIR", "data/photos"), os.Getenv("PHOTOS_BASE_URL"))
+}
+
+// newGeocoder uses Mapbox when MAPBOX_ACCESS_TOKEN is set, backed by the
+// embedded city gazetteer
+func newGeocoder() geocode.Chain {
+	var chain geocode.Chain
+	if token := os.Getenv("MAPBOX_ACCESS_TOKEN"); token != "" {
+		chain = append(chain, geocode.NewMapboxGeocoder(os.Getenv("MAPBOX_API_URL"), token, nil))
+	}
+
+	offline, err := geocode.NewOfflineGeocoder()
+	if err != nil {
+		log.Fatalf("unable to load offline geocoder: %v", err)
+	}
+	return append(chain, offline)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,439 @@
+// Package bulk reads and writes venues as CSV or newline-delimited JSON for
+// bulk import and export
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// Format is a bulk file format
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// MaxRows bounds how many venues one import may hold
+const MaxRows = 5000
+
+// listSeparator separates values in list columns of a CSV file
+const listSeparator = ";"
+
+// ErrTooManyRows is returned for files with more than MaxRows venues
+var ErrTooManyRows = fmt.Errorf("file has more than %d venues", MaxRows)
+
+// ParseFormat parses a format name
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", fmt.Errorf("unknown format %q, use csv or ndjson", s)
+}
+
+// FormatForContentType returns the format for a media type, if it has one
+func FormatForContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV, true
+	case "application/x-ndjson", "application/jsonl":
+		return FormatNDJSON, true
+	}
+	return "", false
+}
+
+// ContentType returns the media type files in the format are served as
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Row is one venue read from a file. Line is the line it started on, and
+// Err is set instead of Venue when the row could not be read.
+type Row struct {
+	Line  int
+	Venue *domain.Venue
+	Err   error
+}
+
+// importColumns are the CSV columns an import reads
+var importColumns = []string{
+	"name", "latitude", "longitude",
+	"street", "city", "state", "postal_code", "country",
+	"venue_types", "capacity", "genres", "amenities",
+	"pay_min", "pay_max", "pay_currency", "pay_type",
+	"email", "phone", "website", "booking_url", "contact_name",
+	"description", "songkick_id", "bandsintown_id", "google_place_id",
+}
+
+// exportColumns are written on export but ignored on import, so that an
+// export can be imported again
+var exportColumns = []string{
+	"id", "source", "verified", "active", "rating", "review_count", "created_at", "updated_at",
+}
+
+// Read reads every venue in a file. Problems with single rows are reported
+// on the row; the error is for files that cannot be read at all.
+func Read(r io.Reader, format Format) ([]Row, error) {
+	if format == FormatCSV {
+		return readCSV(r)
+	}
+	return readNDJSON(r)
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	columns, err := headerColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read csv: %w", err)
+			}
+			rows = append(rows, Row{Line: parseErr.StartLine, Err: err})
+		} else if line, _ := reader.FieldPos(0); len(record) != len(header) {
+			rows = append(rows, Row{Line: line, Err: fmt.Errorf("has %d fields, header has %d", len(record), len(header))})
+		} else {
+			fields := make(map[string]string, len(columns))
+			for i, name := range columns {
+				if name != "" {
+					fields[name] = strings.TrimSpace(record[i])
+				}
+			}
+			venue, err := venueFromFields(fields)
+			rows = append(rows, Row{Line: line, Venue: venue, Err: err})
+		}
+		if len(rows) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	return rows, nil
+}
+
+// headerColumns maps header cells to import columns, blanking the export-only
+// ones. Unknown columns are an error so that typos are not silently dropped.
+func headerColumns(header []string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, name := range importColumns {
+		known[name] = true
+	}
+	ignored := make(map[string]bool)
+	for _, name := range exportColumns {
+		ignored[name] = true
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, cell := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")))
+		switch {
+		case seen[name]:
+			return nil, fmt.Errorf("column %q appears twice", name)
+		case known[name]:
+			columns[i] = name
+		case !ignored[name]:
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		seen[name] = true
+	}
+	if !seen["name"] {
+		return nil, fmt.Errorf("a name column is required")
+	}
+	return columns, nil
+}
+
+// venueFromFields builds a venue from one CSV row
+func venueFromFields(f map[string]string) (*domain.Venue, error) {
+	var location domain.GeoPoint
+	var err error
+	if location.Latitude, err = parseFloat(f, "latitude"); err != nil {
+		return nil, err
+	}
+	if location.Longitude, err = parseFloat(f, "longitude"); err != nil {
+		return nil, err
+	}
+
+	venueTypes := make([]domain.VenueType, 0)
+	for _, t := range splitList(f["venue_types"]) {
+		venueTypes = append(venueTypes, domain.VenueType(t))
+	}
+
+	venue := newImportedVenue(f["name"], location, domain.Address{
+		Street:     f["street"],
+		City:       f["city"],
+		State:      f["state"],
+		PostalCode: f["postal_code"],
+		Country:    f["country"],
+	}, venueTypes)
+
+	if venue.Capacity, err = parseInt(f, "capacity"); err != nil {
+		return nil, err
+	}
+	venue.Genres = splitList(f["genres"])
+	for _, a := range splitList(f["amenities"]) {
+		venue.Amenities = append(venue.Amenities, domain.Amenity(a))
+	}
+
+	if f["pay_min"] != "" || f["pay_max"] != "" || f["pay_type"] != "" {
+		payRange := &domain.PayRange{Currency: f["pay_currency"], Type: domain.PaymentType(f["pay_type"])}
+		if payRange.Min, err = parseInt(f, "pay_min"); err != nil {
+			return nil, err
+		}
+		if payRange.Max, err = parseInt(f, "pay_max"); err != nil {
+			return nil, err
+		}
+		venue.PayRange = payRange
+	}
+
+	venue.ContactInfo = domain.ContactInfo{
+		Email:       f["email"],
+		Phone:       f["phone"],
+		Website:     f["website"],
+		BookingURL:  f["booking_url"],
+		ContactName: f["contact_name"],
+	}
+	venue.Description = f["description"]
+	venue.SongkickID = f["songkick_id"]
+	venue.BandsintownID = f["bandsintown_id"]
+	venue.GooglePlaceID = f["google_place_id"]
+	return venue, nil
+}
+
+func readNDJSON(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	rows := make([]Row, 0)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var v domain.Venue
+		if err := json.Unmarshal(data, &v); err != nil {
+			rows = append(rows, Row{Line: line, Err: err})
+		} else {
+			rows = append(rows, Row{Line: line, Venue: writableCopy(&v)})
+		}
+		if len(rows) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ndjson: %w", err)
+	}
+	return rows, nil
+}
+
+// writableCopy keeps only the fields an import may set, dropping the IDs,
+// ratings and other values the service maintains
+func writableCopy(v *domain.Venue) *domain.Venue {
+	venue := newImportedVenue(v.Name, domain.GeoPoint{Latitude: v.Location.Latitude, Longitude: v.Location.Longitude}, v.Address, v.VenueTypes)
+	venue.Capacity = v.Capacity
+	if v.Genres != nil {
+		venue.Genres = v.Genres
+	}
+	if v.Amenities != nil {
+		venue.Amenities = v.Amenities
+	}
+	venue.PayRange = v.PayRange
+	venue.ContactInfo = v.ContactInfo
+	venue.Availability = v.Availability
+	venue.Rooms = v.Rooms
+	venue.Description = v.Description
+	venue.SongkickID = v.SongkickID
+	venue.BandsintownID = v.BandsintownID
+	venue.GooglePlaceID = v.GooglePlaceID
+	return venue
+}
+
+// newImportedVenue creates a venue attributed to a manual import
+func newImportedVenue(name string, location domain.GeoPoint, address domain.Address, venueTypes []domain.VenueType) *domain.Venue {
+	if venueTypes == nil {
+		venueTypes = []domain.VenueType{}
+	}
+	return domain.NewVenue(name, location, address, venueTypes, domain.SourceManual)
+}
+
+// Writer writes venues to a file in one format
+type Writer struct {
+	format      Format
+	csv         *csv.Writer
+	json        *json.Encoder
+	wroteHeader bool
+}
+
+// NewWriter creates a writer for the format
+func NewWriter(w io.Writer, format Format) *Writer {
+	writer := &Writer{format: format}
+	if format == FormatCSV {
+		writer.csv = csv.NewWriter(w)
+	} else {
+		writer.json = json.NewEncoder(w)
+	}
+	return writer
+}
+
+// Write writes one venue
+func (w *Writer) Write(v *domain.Venue) error {
+	if w.format != FormatCSV {
+		return w.json.Encode(v)
+	}
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.csv.Write(venueRecord(v))
+}
+
+// Flush writes any buffered data, and the CSV header if no venue was written
+func (w *Writer) Flush() error {
+	if w.format != FormatCSV {
+		return nil
+	}
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.csv.Write(append(append([]string{}, exportColumns...), importColumns...))
+}
+
+// venueRecord lays a venue out in export column order
+func venueRecord(v *domain.Venue) []string {
+	var pay domain.PayRange
+	hasPay := v.PayRange != nil
+	if hasPay {
+		pay = *v.PayRange
+	}
+	venueTypes := make([]string, len(v.VenueTypes))
+	for i, t := range v.VenueTypes {
+		venueTypes[i] = string(t)
+	}
+	amenities := make([]string, len(v.Amenities))
+	for i, a := range v.Amenities {
+		amenities[i] = string(a)
+	}
+
+	return []string{
+		v.ID,
+		string(v.Source),
+		strconv.FormatBool(v.Verified),
+		strconv.FormatBool(v.Active),
+		strconv.FormatFloat(v.Rating, 'f', -1, 64),
+		strconv.Itoa(v.ReviewCount),
+		v.CreatedAt.UTC().Format(time.RFC3339),
+		v.UpdatedAt.UTC().Format(time.RFC3339),
+
+		v.Name,
+		strconv.FormatFloat(v.Location.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(v.Location.Longitude, 'f', -1, 64),
+		v.Address.Street,
+		v.Address.City,
+		v.Address.State,
+		v.Address.PostalCode,
+		v.Address.Country,
+		strings.Join(venueTypes, listSeparator),
+		strconv.Itoa(v.Capacity),
+		strings.Join(v.Genres, listSeparator),
+		strings.Join(amenities, listSeparator),
+		optionalInt(hasPay, pay.Min),
+		optionalInt(hasPay, pay.Max),
+		pay.Currency,
+		string(pay.Type),
+		v.ContactInfo.Email,
+		v.ContactInfo.Phone,
+		v.ContactInfo.Website,
+		v.ContactInfo.BookingURL,
+		v.ContactInfo.ContactName,
+		v.Description,
+		v.SongkickID,
+		v.BandsintownID,
+		v.GooglePlaceID,
+	}
+}
+
+func optionalInt(ok bool, n int) string {
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// splitList splits a list column, dropping empty entries
+func splitList(s string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(s, listSeparator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func parseFloat(f map[string]string, column string) (float64, error) {
+	if f[column] == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(f[column], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a number", column, f[column])
+	}
+	return value, nil
+}
+
+func parseInt(f map[string]string, column string) (int, error) {
+	if f[column] == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(f[column])
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is not a whole number", column, f[column])
+	}
+	return value, nil
+}
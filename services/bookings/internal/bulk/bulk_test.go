@@ -0,0 +1,122 @@
+package bulk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestRead_CSV(t *testing.T) {
+	input := "Name,Latitude,Longitude,City,State,Venue_Types,Capacity,Genres,Pay_Min,Pay_Max,Pay_Type,Songkick_ID\n" +
+		"The Chapel,37.76,-122.42,San Francisco,CA,club;bar,400,indie; folk,200,800,guarantee,sk-1\n" +
+		"Bad Capacity,,,Oakland,CA,club,lots,,,,,\n" +
+		"\"Unterminated,,,,,,,,,,,\n"
+
+	rows, err := Read(strings.NewReader(input), FormatCSV)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Read() = %d rows, want 3", len(rows))
+	}
+
+	chapel := rows[0]
+	if chapel.Err != nil || chapel.Line != 2 {
+		t.Fatalf("row 1 = line %d, err %v", chapel.Line, chapel.Err)
+	}
+	v := chapel.Venue
+	if v.Name != "The Chapel" || v.Capacity != 400 || v.Address.City != "San Francisco" || v.SongkickID != "sk-1" {
+		t.Errorf("venue = %+v", v)
+	}
+	if len(v.VenueTypes) != 2 || v.VenueTypes[1] != domain.VenueTypeBar {
+		t.Errorf("venue types = %v", v.VenueTypes)
+	}
+	if len(v.Genres) != 2 || v.Genres[1] != "folk" {
+		t.Errorf("genres = %v", v.Genres)
+	}
+	if v.PayRange == nil || v.PayRange.Max != 800 || v.PayRange.Type != domain.PaymentGuarantee {
+		t.Errorf("pay range = %+v", v.PayRange)
+	}
+	if v.Source != domain.SourceManual {
+		t.Errorf("source = %v, want manual", v.Source)
+	}
+
+	if rows[1].Err == nil || rows[1].Line != 3 {
+		t.Errorf("row 2 = line %d, err %v, want a capacity error on line 3", rows[1].Line, rows[1].Err)
+	}
+	if rows[2].Err == nil || rows[2].Line != 4 {
+		t.Errorf("row 3 = line %d, err %v, want a parse error on line 4", rows[2].Line, rows[2].Err)
+	}
+}
+
+func TestRead_CSVHeader(t *testing.T) {
+	if _, err := Read(strings.NewReader("name,capcity\nA,1\n"), FormatCSV); err == nil {
+		t.Error("Read() should reject unknown columns")
+	}
+	if _, err := Read(strings.NewReader("city\nOakland\n"), FormatCSV); err == nil {
+		t.Error("Read() should require a name column")
+	}
+}
+
+func TestRead_NDJSON(t *testing.T) {
+	input := `{"id":"keep-out","name":"The Chapel","location":{"latitude":37.76,"longitude":-122.42},"venue_types":["club"],"rating":4.9}` + "\n\n" +
+		`{"name":` + "\n"
+
+	rows, err := Read(strings.NewReader(input), FormatNDJSON)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Read() = %d rows, want 2", len(rows))
+	}
+	v := rows[0].Venue
+	if v.Name != "The Chapel" || v.ID == "keep-out" || v.Rating != 0 {
+		t.Errorf("venue = %+v, want service-maintained fields dropped", v)
+	}
+	if rows[1].Err == nil || rows[1].Line != 3 {
+		t.Errorf("row 2 = line %d, err %v, want an error on line 3", rows[1].Line, rows[1].Err)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	venue := domain.NewVenue("Hotel Utah", domain.GeoPoint{Latitude: 37.78, Longitude: -122.4}, domain.Address{Street: "500 4th St", City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeBar}, domain.SourceGooglePlaces)
+	venue.Genres = []string{"folk", "rock, mostly"}
+	venue.Amenities = []domain.Amenity{domain.AmenitySoundSystem}
+	venue.ContactInfo.Email = "booking@hotelutah.com"
+	venue.Description = "Saloon, \"since 1908\"\nwith a stage"
+
+	for _, format := range []Format{FormatCSV, FormatNDJSON} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, format)
+		if err := w.Write(venue); err != nil {
+			t.Fatalf("%s Write() error = %v", format, err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("%s Flush() error = %v", format, err)
+		}
+
+		rows, err := Read(&buf, format)
+		if err != nil || len(rows) != 1 || rows[0].Err != nil {
+			t.Fatalf("%s Read() = %+v, %v", format, rows, err)
+		}
+		got := rows[0].Venue
+		if got.Name != venue.Name || got.Address != venue.Address || got.Description != venue.Description ||
+			got.ContactInfo != venue.ContactInfo || len(got.Genres) != 2 || got.Genres[1] != "rock, mostly" ||
+			len(got.Amenities) != 1 || got.Location.Latitude != 37.78 {
+			t.Errorf("%s round trip = %+v", format, got)
+		}
+	}
+}
+
+func TestWriter_EmptyCSVHasHeader(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, FormatCSV)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "id,source,") {
+		t.Errorf("empty export = %q, want a header", buf.String())
+	}
+}
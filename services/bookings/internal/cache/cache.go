@@ -0,0 +1,68 @@
+// Package cache provides byte-valued key/value stores with expiry for
+// read-through caching: an in-process LRU, a Redis client, and a tiered store
+// that puts the LRU in front of Redis
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store holds values until they expire or are deleted. Get reports a miss
+// for missing and expired keys alike.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Tiered reads through a local store to a shared one, so that most hits stay
+// in process while every replica sees the same entries. Local copies live
+// for at most localTTL, as deletes on one replica cannot reach the local
+// stores of the others.
+type Tiered struct {
+	local    Store
+	remote   Store
+	localTTL time.Duration
+}
+
+// NewTiered creates a tiered store
+func NewTiered(local, remote Store, localTTL time.Duration) *Tiered {
+	return &Tiered{local: local, remote: remote, localTTL: localTTL}
+}
+
+// Get returns the local copy, or else the shared one, keeping it locally
+func (t *Tiered) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if value, ok, err := t.local.Get(ctx, key); err == nil && ok {
+		return value, true, nil
+	}
+
+	value, ok, err := t.remote.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if err := t.local.Set(ctx, key, value, t.localTTL); err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores the value in both stores
+func (t *Tiered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	localTTL := ttl
+	if localTTL > t.localTTL {
+		localTTL = t.localTTL
+	}
+	if err := t.local.Set(ctx, key, value, localTTL); err != nil {
+		return err
+	}
+	return t.remote.Set(ctx, key, value, ttl)
+}
+
+// Delete removes the keys from both stores
+func (t *Tiered) Delete(ctx context.Context, keys ...string) error {
+	if err := t.local.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	return t.remote.Delete(ctx, keys...)
+}
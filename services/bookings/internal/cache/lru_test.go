@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("Get(b) hit after b was evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := c.Get(ctx, key); !ok {
+			t.Errorf("Get(%s) missed", key)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+
+	now = now.Add(59 * time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Error("Get() missed before the entry expired")
+	}
+	now = now.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Get() hit after the entry expired")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after expiry, want 0", c.Len())
+	}
+}
+
+func TestLRU_SetReplacesAndCopies(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+	value := []byte("one")
+	c.Set(ctx, "a", value, time.Minute)
+	value[0] = 'X'
+	c.Set(ctx, "b", []byte("two"), time.Minute)
+	c.Set(ctx, "b", []byte("three"), time.Minute)
+
+	got, _, _ := c.Get(ctx, "a")
+	if string(got) != "one" {
+		t.Errorf("Get(a) = %q, want %q", got, "one")
+	}
+	got[0] = 'X'
+	got, _, _ = c.Get(ctx, "a")
+	if string(got) != "one" {
+		t.Errorf("Get(a) after changing a returned value = %q, want %q", got, "one")
+	}
+	got, _, _ = c.Get(ctx, "b")
+	if string(got) != "three" {
+		t.Errorf("Get(b) = %q, want %q", got, "three")
+	}
+}
+
+func TestLRU_Delete(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Delete(ctx, "a", "missing")
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Get(a) hit after Delete()")
+	}
+	if _, ok, _ := c.Get(ctx, "b"); !ok {
+		t.Error("Get(b) missed after deleting another key")
+	}
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+	local, remote := NewLRU(10), NewLRU(10)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	local.now = func() time.Time { return now }
+	tiered := NewTiered(local, remote, 30*time.Second)
+
+	remote.Set(ctx, "shared", []byte("1"), time.Hour)
+	if got, ok, _ := tiered.Get(ctx, "shared"); !ok || string(got) != "1" {
+		t.Errorf("Get(shared) = %q, %v, want the remote value", got, ok)
+	}
+	if _, ok, _ := local.Get(ctx, "shared"); !ok {
+		t.Error("Get() did not keep the remote value locally")
+	}
+
+	tiered.Set(ctx, "a", []byte("2"), time.Hour)
+	now = now.Add(30 * time.Second)
+	if _, ok, _ := local.Get(ctx, "a"); ok {
+		t.Error("local copy outlived the local TTL")
+	}
+	if _, ok, _ := tiered.Get(ctx, "a"); !ok {
+		t.Error("Get(a) missed while the remote copy is live")
+	}
+
+	tiered.Delete(ctx, "a")
+	for name, store := range map[string]Store{"local": local, "remote": remote} {
+		if _, ok, _ := store.Get(ctx, "a"); ok {
+			t.Errorf("%s store still holds a after Delete()", name)
+		}
+	}
+}
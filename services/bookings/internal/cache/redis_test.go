@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET, SET, DEL, AUTH and SELECT from a map, recording
+// each command it receives
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	f := &fakeRedis{listener: listener, password: password, values: make(map[string]string)}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		reply := "+OK\r\n"
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[1] != f.password {
+				reply = "-WRONGPASS invalid password\r\n"
+			} else {
+				authed = true
+			}
+		case "SELECT":
+		default:
+			if !authed {
+				reply = "-NOAUTH Authentication required.\r\n"
+				break
+			}
+			reply = f.run(args)
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) run(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		value, ok := f.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		f.values[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := f.values[key]; ok {
+				delete(f.values, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) commandLog() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func TestRedis_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t, "secret")
+	store := NewRedis(server.listener.Addr().String(), "secret", 2)
+	defer store.Close()
+
+	if _, ok, err := store.Get(ctx, "venue"); err != nil || ok {
+		t.Fatalf("Get() of a missing key = %v, %v, want a miss", ok, err)
+	}
+	value := "line one\r\nline two"
+	if err := store.Set(ctx, "venue", []byte(value), 1500*time.Microsecond); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, ok, err := store.Get(ctx, "venue")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v, want a hit", ok, err)
+	}
+	if string(got) != value {
+		t.Errorf("Get() = %q, want %q", got, value)
+	}
+	if err := store.Delete(ctx, "venue", "other"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "venue"); ok {
+		t.Error("Get() hit after Delete()")
+	}
+
+	want := []string{
+		"AUTH secret",
+		"SELECT 2",
+		"GET venue",
+		"SET venue " + value + " PX 2",
+		"GET venue",
+		"DEL venue other",
+		"GET venue",
+	}
+	if got := server.commandLog(); fmt.Sprintf("%q", got) != fmt.Sprintf("%q", want) {
+		t.Errorf("commands = %q\nwant %q", got, want)
+	}
+}
+
+func TestRedis_ErrorReply(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	store := NewRedis(server.listener.Addr().String(), "wrong", 0)
+	defer store.Close()
+
+	_, _, err := store.Get(context.Background(), "venue")
+	var replyErr *RedisError
+	if !errors.As(err, &replyErr) {
+		t.Fatalf("Get() with a wrong password error = %v, want RedisError", err)
+	}
+	if !strings.HasPrefix(replyErr.Message, "WRONGPASS") {
+		t.Errorf("RedisError.Message = %q, want the server's reply", replyErr.Message)
+	}
+}
+
+func TestRedis_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	store := NewRedis(addr, "", 0)
+	if _, _, err := store.Get(context.Background(), "venue"); err == nil {
+		t.Error("Get() against a closed port returned no error")
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Role is what a caller is allowed to do to venues, asserted by the API gateway
+type Role string
+
+const (
+	// RoleContributor is any signed-in or anonymous user suggesting edits
+	RoleContributor Role = "contributor"
+	// RoleOwner manages a venue they have claimed; see Venue.RoleFor
+	RoleOwner Role = "owner"
+	// RoleAdmin is staff with full access
+	RoleAdmin Role = "admin"
+)
+
+// ParseRole reads a role, treating an empty value as a contributor
+func ParseRole(s string) (Role, error) {
+	switch role := Role(s); role {
+	case "":
+		return RoleContributor, nil
+	case RoleContributor, RoleOwner, RoleAdmin:
+		return role, nil
+	default:
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+}
+
+// contributorPatchFields are the venue JSON members anyone may patch
+var contributorPatchFields = []string{
+	"name", "location", "address", "venue_types", "capacity",
+	"genres", "amenities", "photos", "description",
+}
+
+// ownerPatchFields adds what only the people running a venue should set
+var ownerPatchFields = append(append([]string{}, contributorPatchFields...),
+	"pay_range", "contact_info", "availability", "rooms",
+)
+
+// adminPatchFields adds moderation state and links to external sources
+var adminPatchFields = append(append([]string{}, ownerPatchFields...),
+	"verified", "active", "songkick_id", "bandsintown_id", "google_place_id",
+)
+
+// readOnlyVenueFields are venue JSON members maintained by the service
+// that no caller may patch
+var readOnlyVenueFields = []string{
+	"id", "source", "rating", "review_count", "flags", "owner_ids",
+	"moderation_status", "moderation", "gallery", "deleted_at", "deleted_by",
+	"created_at", "updated_at", "last_synced_at", "version",
+}
+
+// PatchableFields lists the venue JSON members a role may change
+func (r Role) PatchableFields() []string {
+	switch r {
+	case RoleAdmin:
+		return adminPatchFields
+	case RoleOwner:
+		return ownerPatchFields
+	default:
+		return contributorPatchFields
+	}
+}
+
+// CheckPatchFields sorts the top-level members of a venue patch into those
+// that are not venue fields at all and those the role may not change
+func (r Role) CheckPatchFields(keys []string) (unknown, forbidden []string) {
+	allowed := make(map[string]bool)
+	for _, key := range r.PatchableFields() {
+		allowed[key] = true
+	}
+	known := make(map[string]bool)
+	for _, key := range adminPatchFields {
+		known[key] = true
+	}
+	for _, key := range readOnlyVenueFields {
+		known[key] = true
+	}
+
+	for _, key := range keys {
+		switch {
+		case allowed[key]:
+		case known[key]:
+			forbidden = append(forbidden, key)
+		default:
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	sort.Strings(forbidden)
+	return unknown, forbidden
+}
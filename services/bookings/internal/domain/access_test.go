@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_CheckPatchFields(t *testing.T) {
+	unknown, forbidden := RoleContributor.CheckPatchFields([]string{"name", "pay_range", "verified", "rating", "colour"})
+	assert.Equal(t, []string{"colour"}, unknown)
+	assert.Equal(t, []string{"pay_range", "rating", "verified"}, forbidden)
+
+	unknown, forbidden = RoleOwner.CheckPatchFields([]string{"pay_range", "contact_info", "verified"})
+	assert.Empty(t, unknown)
+	assert.Equal(t, []string{"verified"}, forbidden)
+
+	_, forbidden = RoleAdmin.CheckPatchFields([]string{"verified", "songkick_id", "version"})
+	assert.Equal(t, []string{"version"}, forbidden)
+
+	role, err := ParseRole("")
+	assert.NoError(t, err)
+	assert.Equal(t, RoleContributor, role)
+	_, err = ParseRole("superuser")
+	assert.Error(t, err)
+}
+
+func TestVenue_RoleFor(t *testing.T) {
+	venue := NewVenue("The Independent", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+	venue.AddOwner("owner-1")
+	venue.AddOwner("owner-1")
+
+	assert.Equal(t, []string{"owner-1"}, venue.OwnerIDs)
+	assert.Equal(t, RoleOwner, venue.RoleFor("owner-1", RoleContributor))
+	assert.Equal(t, RoleContributor, venue.RoleFor("user-2", RoleOwner), "owner role needs a confirmed claim")
+	assert.Equal(t, RoleAdmin, venue.RoleFor("user-2", RoleAdmin))
+	assert.Equal(t, RoleContributor, venue.RoleFor("", RoleContributor))
+}
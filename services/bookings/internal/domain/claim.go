@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxClaimAttempts is how many wrong codes a claim accepts before it fails
+const MaxClaimAttempts = 5
+
+// ClaimStatus tracks a venue claim through verification
+type ClaimStatus string
+
+const (
+	ClaimPending   ClaimStatus = "pending"
+	ClaimConfirmed ClaimStatus = "confirmed"
+	ClaimFailed    ClaimStatus = "failed"
+)
+
+// ClaimChannel is how a claim's verification code is delivered
+type ClaimChannel string
+
+const (
+	ClaimByEmail ClaimChannel = "email"
+	ClaimBySMS   ClaimChannel = "sms"
+)
+
+// VenueClaim is a request by a user to manage a venue. It is confirmed by
+// entering a code sent to the contact details the venue already lists, which
+// proves the user speaks for the venue.
+type VenueClaim struct {
+	ID          string       `dynamodbav:"id" json:"id"`
+	VenueID     string       `dynamodbav:"venue_id" json:"venue_id"`
+	UserID      string       `dynamodbav:"user_id" json:"user_id"`
+	Channel     ClaimChannel `dynamodbav:"channel" json:"channel"`
+	Destination string       `dynamodbav:"destination" json:"-"`
+	SentTo      string       `dynamodbav:"sent_to" json:"sent_to"` // Destination with most of it hidden
+	CodeHash    string       `dynamodbav:"code_hash" json:"-"`
+	Attempts    int          `dynamodbav:"attempts" json:"attempts"`
+	Status      ClaimStatus  `dynamodbav:"status" json:"status"`
+	ExpiresAt   time.Time    `dynamodbav:"expires_at" json:"expires_at"`
+	CreatedAt   time.Time    `dynamodbav:"created_at" json:"created_at"`
+	ConfirmedAt *time.Time   `dynamodbav:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+}
+
+// NewVenueClaim creates a pending claim and returns it with the code to send.
+// Only a hash of the code is kept on the claim.
+func NewVenueClaim(venueID, userID string, channel ClaimChannel, destination string, ttl time.Duration) (*VenueClaim, string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate claim code: %w", err)
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	now := time.Now()
+	return &VenueClaim{
+		ID:          uuid.New().String(),
+		VenueID:     venueID,
+		UserID:      userID,
+		Channel:     channel,
+		Destination: destination,
+		SentTo:      maskDestination(destination),
+		CodeHash:    hashClaimCode(code),
+		Status:      ClaimPending,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}, code, nil
+}
+
+// Expired reports whether the claim's code can no longer be used
+func (c *VenueClaim) Expired(now time.Time) bool {
+	return now.After(c.ExpiresAt)
+}
+
+// MatchesCode reports whether code is the one sent. Attempts are counted
+// by the repository, which can do so atomically.
+func (c *VenueClaim) MatchesCode(code string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashClaimCode(code)), []byte(c.CodeHash)) == 1
+}
+
+// AttemptsLeft reports whether another wrong code may be entered
+func (c *VenueClaim) AttemptsLeft() bool {
+	return c.Attempts < MaxClaimAttempts
+}
+
+// Fail closes the claim after too many wrong codes
+func (c *VenueClaim) Fail() {
+	c.Status = ClaimFailed
+}
+
+// Confirm marks the claim as verified
+func (c *VenueClaim) Confirm() {
+	now := time.Now()
+	c.Status = ClaimConfirmed
+	c.ConfirmedAt = &now
+}
+
+// hashClaimCode hashes a code for storage
+func hashClaimCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// maskDestination hides all but enough of an email address or phone number
+// for the claimant to recognise it
+func maskDestination(destination string) string {
+	if at := strings.LastIndex(destination, "@"); at > 0 {
+		return destination[:1] + "***" + destination[at:]
+	}
+	if len(destination) > 4 {
+		return "***" + destination[len(destination)-4:]
+	}
+	return "***"
+}
+
+// IsOwner reports whether a user has claimed the venue
+func (v *Venue) IsOwner(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, id := range v.OwnerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddOwner grants a user the owner role on the venue
+func (v *Venue) AddOwner(userID string) {
+	if !v.IsOwner(userID) {
+		v.OwnerIDs = append(v.OwnerIDs, userID)
+	}
+}
+
+// RoleFor returns the role a caller has on this venue. Ownership comes only
+// from a confirmed claim, so a caller asserting the owner role without one is
+// treated as a contributor.
+func (v *Venue) RoleFor(userID string, asserted Role) Role {
+	if asserted == RoleAdmin {
+		return RoleAdmin
+	}
+	if v.IsOwner(userID) {
+		return RoleOwner
+	}
+	return RoleContributor
+}
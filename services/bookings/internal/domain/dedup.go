@@ -0,0 +1,387 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Duplicate score thresholds
+const (
+	// DuplicateReviewThreshold is the score above which a pair is queued for review
+	DuplicateReviewThreshold = 0.6
+	// DuplicateAutoMergeThreshold is the score above which a pair is merged without review
+	DuplicateAutoMergeThreshold = 0.92
+	// duplicateMaxDistanceKm is the distance at which the distance signal drops to zero
+	duplicateMaxDistanceKm = 1.0
+)
+
+// DuplicateStatus represents the review state of a candidate duplicate pair
+type DuplicateStatus string
+
+const (
+	DuplicatePending   DuplicateStatus = "pending"
+	DuplicateMerged    DuplicateStatus = "merged"
+	DuplicateDismissed DuplicateStatus = "dismissed"
+)
+
+// DuplicateScore breaks down how similar two venues are
+type DuplicateScore struct {
+	Total          float64 `dynamodbav:"total" json:"total"`
+	NameScore      float64 `dynamodbav:"name_score" json:"name_score"`
+	AddressScore   float64 `dynamodbav:"address_score" json:"address_score"`
+	DistanceKm     float64 `dynamodbav:"distance_km" json:"distance_km"`
+	SharedExternal bool    `dynamodbav:"shared_external" json:"shared_external"` // Both carry the same external ID
+}
+
+// DuplicateCandidate is a pair of venues that may describe the same place
+type DuplicateCandidate struct {
+	ID         string          `dynamodbav:"id" json:"id"`
+	VenueID    string          `dynamodbav:"venue_id" json:"venue_id"`
+	OtherID    string          `dynamodbav:"other_id" json:"other_id"`
+	Score      DuplicateScore  `dynamodbav:"score" json:"score"`
+	Status     DuplicateStatus `dynamodbav:"status" json:"status"`
+	CreatedAt  time.Time       `dynamodbav:"created_at" json:"created_at"`
+	ResolvedAt *time.Time      `dynamodbav:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	ResolvedBy string          `dynamodbav:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	SurvivorID string          `dynamodbav:"survivor_id,omitempty" json:"survivor_id,omitempty"`
+}
+
+// NewDuplicateCandidate creates a pending candidate. The ID is derived from the
+// sorted venue IDs so the same pair is only ever queued once.
+func NewDuplicateCandidate(a, b *Venue, score DuplicateScore) *DuplicateCandidate {
+	ids := []string{a.ID, b.ID}
+	sort.Strings(ids)
+	return &DuplicateCandidate{
+		ID:        ids[0] + "#" + ids[1],
+		VenueID:   ids[0],
+		OtherID:   ids[1],
+		Score:     score,
+		Status:    DuplicatePending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Resolve marks the candidate as merged or dismissed
+func (c *DuplicateCandidate) Resolve(status DuplicateStatus, actor, survivorID string) {
+	now := time.Now()
+	c.Status = status
+	c.ResolvedAt = &now
+	c.ResolvedBy = actor
+	c.SurvivorID = survivorID
+}
+
+// VenueRedirect points a merged venue ID at the venue it was merged into
+type VenueRedirect struct {
+	FromID    string    `dynamodbav:"id" json:"from_id"`
+	ToID      string    `dynamodbav:"to_id" json:"to_id"`
+	CreatedAt time.Time `dynamodbav:"created_at" json:"created_at"`
+}
+
+// ScoreDuplicate scores how likely two venues are the same place, from 0 to 1
+func ScoreDuplicate(a, b *Venue) DuplicateScore {
+	score := DuplicateScore{
+		NameScore:      nameSimilarity(a.Name, b.Name),
+		AddressScore:   addressSimilarity(a.Address, b.Address),
+		DistanceKm:     CalculateDistance(a.Location.Latitude, a.Location.Longitude, b.Location.Latitude, b.Location.Longitude),
+		SharedExternal: sharesExternalID(a, b),
+	}
+
+	if score.SharedExternal {
+		score.Total = 1
+		return score
+	}
+
+	distanceScore := math.Max(0, 1-score.DistanceKm/duplicateMaxDistanceKm)
+	score.Total = 0.5*score.NameScore + 0.2*score.AddressScore + 0.3*distanceScore
+	score.Total = math.Round(score.Total*1000) / 1000
+
+	return score
+}
+
+// sharesExternalID reports whether both venues carry the same ID from any source
+func sharesExternalID(a, b *Venue) bool {
+	return (a.SongkickID != "" && a.SongkickID == b.SongkickID) ||
+		(a.BandsintownID != "" && a.BandsintownID == b.BandsintownID) ||
+		(a.GooglePlaceID != "" && a.GooglePlaceID == b.GooglePlaceID)
+}
+
+// nameStopwords are dropped when comparing venue names
+var nameStopwords = map[string]bool{
+	"the": true, "and": true, "at": true, "of": true,
+}
+
+// NormalizeName lowercases a venue name, strips punctuation and drops stopwords
+func NormalizeName(name string) string {
+	tokens := tokenize(name)
+	kept := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if !nameStopwords[t] {
+			kept = append(kept, t)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// streetAbbreviations maps common street words to their postal abbreviation
+var streetAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "boulevard": "blvd", "road": "rd",
+	"drive": "dr", "lane": "ln", "place": "pl", "court": "ct",
+	"north": "n", "south": "s", "east": "e", "west": "w",
+	"suite": "ste",
+}
+
+// NormalizeStreet lowercases a street address and abbreviates common words
+func NormalizeStreet(street string) string {
+	tokens := tokenize(street)
+	for i, t := range tokens {
+		if abbr, ok := streetAbbreviations[t]; ok {
+			tokens[i] = abbr
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// tokenize splits text into lowercase alphanumeric tokens, treating "&" as "and"
+func tokenize(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "&", " and ")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// nameSimilarity combines token overlap and edit distance on normalized names
+func nameSimilarity(a, b string) float64 {
+	na, nb := NormalizeName(a), NormalizeName(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	if na == nb {
+		return 1
+	}
+	return math.Max(tokenJaccard(na, nb), editSimilarity(na, nb))
+}
+
+// addressSimilarity scores street, postal code and city agreement
+func addressSimilarity(a, b Address) float64 {
+	score, weight := 0.0, 0.0
+
+	if a.Street != "" && b.Street != "" {
+		weight += 0.6
+		sa, sb := NormalizeStreet(a.Street), NormalizeStreet(b.Street)
+		if sa == sb {
+			score += 0.6
+		} else {
+			score += 0.6 * editSimilarity(sa, sb)
+		}
+	}
+	if a.PostalCode != "" && b.PostalCode != "" {
+		weight += 0.2
+		if strings.EqualFold(strings.TrimSpace(a.PostalCode), strings.TrimSpace(b.PostalCode)) {
+			score += 0.2
+		}
+	}
+	if a.City != "" && b.City != "" {
+		weight += 0.2
+		if strings.EqualFold(strings.TrimSpace(a.City), strings.TrimSpace(b.City)) {
+			score += 0.2
+		}
+	}
+
+	if weight == 0 {
+		return 0
+	}
+	return score / weight
+}
+
+// tokenJaccard is the Jaccard index of the space separated tokens in a and b
+func tokenJaccard(a, b string) float64 {
+	setA := make(map[string]bool)
+	for _, t := range strings.Fields(a) {
+		setA[t] = true
+	}
+	setB := make(map[string]bool)
+	for _, t := range strings.Fields(b) {
+		setB[t] = true
+	}
+
+	intersection := 0
+	for t := range setA {
+		if setB[t] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// editSimilarity is 1 minus the Levenshtein distance normalized by the longer string
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// MergeVenues folds the merged venue into the survivor. For each tracked
+// field the survivor keeps its value unless it is empty or the merged
+// venue's value comes from a more trusted source; a verified survivor only
+// takes values to fill gaps. The winning field's provenance moves with it.
+// List fields are unioned, and artist review totals are added up.
+func MergeVenues(survivor, merged *Venue) {
+	if survivor.SongkickID == "" {
+		survivor.SongkickID = merged.SongkickID
+	}
+	if survivor.BandsintownID == "" {
+		survivor.BandsintownID = merged.BandsintownID
+	}
+	if survivor.GooglePlaceID == "" {
+		survivor.GooglePlaceID = merged.GooglePlaceID
+	}
+
+	survivor.Photos = union(survivor.Photos, merged.Photos)
+	survivor.Gallery = append(survivor.Gallery, merged.Gallery...)
+	survivor.Genres = union(survivor.Genres, merged.Genres)
+	survivor.Amenities = union(survivor.Amenities, merged.Amenities)
+	survivor.VenueTypes = union(survivor.VenueTypes, merged.VenueTypes)
+
+	// Rooms are taken whole, as two venues' room lists rarely line up
+	if len(survivor.Rooms) == 0 {
+		survivor.Rooms = merged.Rooms
+	}
+
+	// take reports whether the merged venue's value wins, and if so moves
+	// its provenance to the survivor
+	take := func(field VenueField) bool {
+		mergedValue := fieldValue(merged, field)
+		if isEmpty(mergedValue) {
+			return false
+		}
+		incoming := merged.FieldSource(field)
+		if !isEmpty(fieldValue(survivor, field)) &&
+			(survivor.Verified || !incoming.outranks(survivor.FieldSource(field))) {
+			return false
+		}
+		survivor.setFieldSource(field, incoming)
+		return true
+	}
+
+	if take(FieldName) {
+		survivor.Name = merged.Name
+	}
+	if take(FieldLocation) {
+		survivor.Location = merged.Location
+	}
+	if take(FieldCapacity) {
+		survivor.Capacity = merged.Capacity
+	}
+	if take(FieldPayRange) {
+		survivor.PayRange = merged.PayRange
+	}
+	if take(FieldDescription) {
+		survivor.Description = merged.Description
+	}
+	if take(FieldContactInfo) {
+		contact := merged.ContactInfo
+		mergeContactInfo(&contact, survivor.ContactInfo)
+		survivor.ContactInfo = contact
+	} else {
+		mergeContactInfo(&survivor.ContactInfo, merged.ContactInfo)
+	}
+	if take(FieldAddress) {
+		address := merged.Address
+		address.FillFrom(survivor.Address)
+		survivor.Address = address
+	} else {
+		survivor.Address.FillFrom(merged.Address)
+	}
+
+	// Artist reviews are combined through their score totals, as their
+	// counts cannot be mixed with those imported from other sources. An
+	// artist who reviewed both venues is counted twice until the service
+	// drops one of the reviews with RemoveReview.
+	switch {
+	case merged.hasArtistReviews() && survivor.hasArtistReviews():
+		survivor.ReviewTotals = survivor.ReviewTotals.add(merged.ReviewTotals, 1)
+		survivor.ReviewCount += merged.ReviewCount
+		survivor.recomputeRating()
+	case merged.hasArtistReviews():
+		survivor.ReviewTotals = merged.ReviewTotals
+		survivor.ReviewCount = merged.ReviewCount
+		survivor.recomputeRating()
+		survivor.setFieldSource(FieldRating, merged.FieldSource(FieldRating))
+	case !survivor.hasArtistReviews() && take(FieldRating):
+		survivor.Rating = merged.Rating
+		survivor.ReviewCount = merged.ReviewCount
+	}
+
+	survivor.Verified = survivor.Verified || merged.Verified
+	survivor.Active = survivor.Active || merged.Active
+	if merged.CreatedAt.Before(survivor.CreatedAt) {
+		survivor.CreatedAt = merged.CreatedAt
+	}
+
+	survivor.Update()
+}
+
+func mergeContactInfo(dst *ContactInfo, src ContactInfo) {
+	if dst.Email == "" {
+		dst.Email = src.Email
+	}
+	if dst.Phone == "" {
+		dst.Phone = src.Phone
+	}
+	if dst.Website == "" {
+		dst.Website = src.Website
+	}
+	if dst.BookingURL == "" {
+		dst.BookingURL = src.BookingURL
+	}
+	if dst.ContactName == "" {
+		dst.ContactName = src.ContactName
+	}
+}
+
+// union appends the items of b missing from a, preserving order
+func union[T comparable](a, b []T) []T {
+	out := append([]T{}, a...)
+	seen := make(map[T]bool, len(a))
+	for _, x := range a {
+		seen[x] = true
+	}
+	for _, x := range b {
+		if !seen[x] {
+			out = append(out, x)
+			seen[x] = true
+		}
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDedupVenue(name, street string, lat, lng float64) *Venue {
+	return NewVenue(
+		name,
+		GeoPoint{Latitude: lat, Longitude: lng},
+		Address{Street: street, City: "San Francisco", State: "CA", PostalCode: "94115", Country: "US"},
+		[]VenueType{VenueTypeClub},
+		SourceGooglePlaces,
+	)
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"The Fillmore", "fillmore"},
+		{"Bottom of the Hill", "bottom hill"},
+		{"Rock & Roll Hotel", "rock roll hotel"},
+		{"  CAFÉ   du Nord! ", "café du nord"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeName(tt.input))
+		})
+	}
+}
+
+func TestNormalizeStreet(t *testing.T) {
+	assert.Equal(t, "1805 geary blvd", NormalizeStreet("1805 Geary Boulevard"))
+	assert.Equal(t, "1805 geary blvd", NormalizeStreet("1805 Geary Blvd."))
+	assert.Equal(t, "100 n main st ste 2", NormalizeStreet("100 North Main Street, Suite 2"))
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	fillmore := newDedupVenue("The Fillmore", "1805 Geary Boulevard", 37.7840, -122.4330)
+
+	tests := []struct {
+		name    string
+		other   *Venue
+		wantMin float64
+		wantMax float64
+	}{
+		{
+			name:    "same venue from another source",
+			other:   newDedupVenue("Fillmore", "1805 Geary Blvd", 37.7841, -122.4331),
+			wantMin: DuplicateAutoMergeThreshold,
+			wantMax: 1,
+		},
+		{
+			name:    "similar name nearby but different address",
+			other:   newDedupVenue("Fillmore West", "10 South Van Ness Ave", 37.7780, -122.4190),
+			wantMin: 0,
+			wantMax: DuplicateAutoMergeThreshold,
+		},
+		{
+			name:    "different venue far away",
+			other:   newDedupVenue("Great American Music Hall", "859 O'Farrell St", 37.7850, -122.4186),
+			wantMin: 0,
+			wantMax: DuplicateReviewThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ScoreDuplicate(fillmore, tt.other)
+			assert.GreaterOrEqual(t, score.Total, tt.wantMin)
+			assert.Less(t, score.Total, tt.wantMax+0.0001)
+		})
+	}
+}
+
+func TestScoreDuplicate_SharedExternalID(t *testing.T) {
+	a := newDedupVenue("Venue A", "", 37.0, -122.0)
+	b := newDedupVenue("Completely Different", "", 38.0, -121.0)
+	a.SongkickID = "sk-1"
+	b.SongkickID = "sk-1"
+
+	score := ScoreDuplicate(a, b)
+
+	assert.True(t, score.SharedExternal)
+	assert.Equal(t, 1.0, score.Total)
+}
+
+func TestNewDuplicateCandidate_StableID(t *testing.T) {
+	a := newDedupVenue("A", "", 0, 0)
+	b := newDedupVenue("B", "", 0, 0)
+
+	assert.Equal(t, NewDuplicateCandidate(a, b, DuplicateScore{}).ID, NewDuplicateCandidate(b, a, DuplicateScore{}).ID)
+	assert.Equal(t, DuplicatePending, NewDuplicateCandidate(a, b, DuplicateScore{}).Status)
+}
+
+func TestMergeVenues(t *testing.T) {
+	survivor := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	survivor.GooglePlaceID = "gp-1"
+	survivor.Photos = []string{"a.jpg"}
+	survivor.Amenities = []Amenity{AmenitySoundSystem}
+	survivor.Rating = 4.0
+	survivor.ReviewCount = 1
+
+	merged := newDedupVenue("Fillmore", "", 37.7841, -122.4331)
+	merged.GooglePlaceID = "gp-other"
+	merged.SongkickID = "sk-1"
+	merged.BandsintownID = "bit-1"
+	merged.Photos = []string{"a.jpg", "b.jpg"}
+	merged.Amenities = []Amenity{AmenitySoundSystem, AmenityGreenRoom}
+	merged.Capacity = 1150
+	merged.ContactInfo.Email = "booking@fillmore.example"
+	merged.Rating = 5.0
+	merged.ReviewCount = 3
+
+	MergeVenues(survivor, merged)
+
+	assert.Equal(t, "gp-1", survivor.GooglePlaceID, "survivor's own external ID wins")
+	assert.Equal(t, "sk-1", survivor.SongkickID)
+	assert.Equal(t, "bit-1", survivor.BandsintownID)
+	assert.Equal(t, []string{"a.jpg", "b.jpg"}, survivor.Photos)
+	assert.Equal(t, []Amenity{AmenitySoundSystem, AmenityGreenRoom}, survivor.Amenities)
+	assert.Equal(t, 1150, survivor.Capacity)
+	assert.Equal(t, "booking@fillmore.example", survivor.ContactInfo.Email)
+	// Imported ratings are one field, and the survivor's comes from as
+	// trusted a source
+	assert.Equal(t, 1, survivor.ReviewCount)
+	assert.InDelta(t, 4.0, survivor.Rating, 0.001)
+}
+
+func TestMergeVenues_CombinesArtistReviews(t *testing.T) {
+	review := func(venue *Venue, artistID string, score int) *VenueReview {
+		r := &VenueReview{VenueID: venue.ID, ArtistID: artistID, Scores: ReviewScores{score, score, score, score}}
+		venue.ApplyReview(nil, r)
+		return r
+	}
+
+	survivor := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	survivor.Rating = 4.6
+	survivor.ReviewCount = 1200 // Imported, replaced by the first review
+	review(survivor, "artist-1", 4)
+
+	merged := newDedupVenue("Fillmore", "", 37.7841, -122.4331)
+	twice := review(merged, "artist-1", 2)
+	review(merged, "artist-2", 3)
+
+	MergeVenues(survivor, merged)
+	assert.Equal(t, 3, survivor.ReviewCount)
+	assert.InDelta(t, 3.0, survivor.Rating, 0.001)
+
+	// The service drops the duplicate review of an artist who reviewed both
+	survivor.RemoveReview(twice)
+	assert.Equal(t, 2, survivor.ReviewCount)
+	assert.InDelta(t, 3.5, survivor.Rating, 0.001)
+}
+
+func TestMergeVenues_ArtistReviewsReplaceImportedRating(t *testing.T) {
+	survivor := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	survivor.Rating = 4.6
+	survivor.ReviewCount = 1200
+
+	merged := newDedupVenue("Fillmore", "", 37.7841, -122.4331)
+	merged.ApplyReview(nil, &VenueReview{VenueID: merged.ID, ArtistID: "artist-1", Scores: ReviewScores{2, 2, 2, 2}})
+
+	MergeVenues(survivor, merged)
+	assert.Equal(t, 1, survivor.ReviewCount)
+	assert.InDelta(t, 2.0, survivor.Rating, 0.001)
+	assert.Equal(t, SourceUserSubmitted, survivor.FieldSource(FieldRating).Source)
+}
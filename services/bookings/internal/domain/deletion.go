@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// VenueRetention is how long a deleted venue is kept, and can be restored,
+// before it is purged for good
+const VenueRetention = 30 * 24 * time.Hour
+
+// SoftDelete hides the venue from search and lookups. It stays stored until
+// it is restored or purged.
+func (v *Venue) SoftDelete(userID string) {
+	now := time.Now()
+	v.Active = false
+	v.DeletedAt = &now
+	v.DeletedBy = userID
+	v.UpdatedAt = now
+}
+
+// Restore brings back a deleted venue and makes it active
+func (v *Venue) Restore() {
+	v.DeletedAt = nil
+	v.DeletedBy = ""
+	v.Reactivate()
+}
+
+// Deleted reports whether the venue has been soft-deleted
+func (v *Venue) Deleted() bool {
+	return v.DeletedAt != nil
+}
+
+// PurgeDue reports whether a deleted venue has been kept for the whole
+// retention period as of now
+func (v *Venue) PurgeDue(now time.Time, retention time.Duration) bool {
+	return v.DeletedAt != nil && !now.Before(v.DeletedAt.Add(retention))
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenue_SoftDelete(t *testing.T) {
+	venue := NewVenue("Bottom of the Hill", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceUserSubmitted)
+
+	venue.SoftDelete("admin-1")
+	assert.True(t, venue.Deleted())
+	assert.False(t, venue.Active)
+	assert.Equal(t, "admin-1", venue.DeletedBy)
+
+	clone := venue.Clone()
+	assert.NotSame(t, venue.DeletedAt, clone.DeletedAt, "clones do not share the deletion time")
+
+	deletedAt := *venue.DeletedAt
+	assert.False(t, venue.PurgeDue(deletedAt.Add(VenueRetention-time.Second), VenueRetention))
+	assert.True(t, venue.PurgeDue(deletedAt.Add(VenueRetention), VenueRetention))
+
+	venue.Restore()
+	assert.False(t, venue.Deleted())
+	assert.True(t, venue.Active)
+	assert.Empty(t, venue.DeletedBy)
+	assert.False(t, venue.PurgeDue(deletedAt.Add(2*VenueRetention), VenueRetention), "restored venues are never purged")
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SourceBooking marks events that come from bookings made through us
+	SourceBooking DataSource = "booking"
+	// SourceEventHistory marks venue fields derived from the venue's past events
+	SourceEventHistory DataSource = "event_history"
+)
+
+const (
+	// EventStatsMonths is how far back event stats look
+	EventStatsMonths = 12
+	// minGenreShows is how many shows with known genres a venue needs before
+	// its typical genres are derived
+	minGenreShows = 5
+	// typicalGenreShare is the share of those shows a genre must appear at
+	typicalGenreShare = 0.2
+	// maxTypicalGenres caps how many genres are derived
+	maxTypicalGenres = 5
+)
+
+// VenueEvent is a show that took place at a venue, from one of our bookings
+// or a provider's calendar. Each is stored once per venue under its source
+// and its ID there, so repeated syncs replace rather than duplicate it.
+type VenueEvent struct {
+	VenueID    string     `dynamodbav:"venue_id" json:"venue_id"`
+	EventKey   string     `dynamodbav:"event_key" json:"-"`
+	Source     DataSource `dynamodbav:"source" json:"source"`
+	SourceID   string     `dynamodbav:"source_id" json:"source_id"`                         // Booking ID or the provider's event ID
+	ArtistID   string     `dynamodbav:"artist_id,omitempty" json:"artist_id,omitempty"`     // Set for our own bookings
+	ArtistName string     `dynamodbav:"artist_name,omitempty" json:"artist_name,omitempty"` // The headliner for provider events
+	Date       time.Time  `dynamodbav:"date" json:"date"`
+	Genres     []string   `dynamodbav:"genres" json:"genres"`
+}
+
+// NewVenueEvent creates an event. Dates are kept in UTC to the second so
+// that they sort as strings.
+func NewVenueEvent(venueID string, source DataSource, sourceID string, date time.Time) *VenueEvent {
+	return &VenueEvent{
+		VenueID:  venueID,
+		EventKey: string(source) + "#" + sourceID,
+		Source:   source,
+		SourceID: sourceID,
+		Date:     date.UTC().Truncate(time.Second),
+		Genres:   []string{},
+	}
+}
+
+// EventFromBooking records a played booking as an event at its venue
+func EventFromBooking(b *Booking) *VenueEvent {
+	event := NewVenueEvent(b.VenueID, SourceBooking, b.ID, b.EventDate)
+	event.ArtistID = b.ArtistID
+	return event
+}
+
+// GenreCount is how many shows featured a genre
+type GenreCount struct {
+	Genre string `json:"genre"`
+	Shows int    `json:"shows"`
+}
+
+// EventStats summarises the shows a venue hosted over a recent window
+type EventStats struct {
+	Since         time.Time    `json:"since"`
+	Shows         int          `json:"shows"`
+	ShowsPerMonth float64      `json:"shows_per_month"`
+	LastShow      *time.Time   `json:"last_show,omitempty"`
+	Genres        []GenreCount `json:"genres"`
+	// TypicalGenres are what the venue usually books, once enough shows
+	// have known genres
+	TypicalGenres []string `json:"typical_genres"`
+}
+
+// SummarizeEvents computes stats over the events in the months before now.
+// Genres are counted once per show, ignoring case.
+func SummarizeEvents(events []*VenueEvent, now time.Time, months int) EventStats {
+	stats := EventStats{
+		Since:         now.AddDate(0, -months, 0),
+		Genres:        []GenreCount{},
+		TypicalGenres: []string{},
+	}
+
+	counts := make(map[string]int)
+	withGenres := 0
+	for _, e := range events {
+		if e.Date.Before(stats.Since) || e.Date.After(now) {
+			continue
+		}
+		stats.Shows++
+		if stats.LastShow == nil || e.Date.After(*stats.LastShow) {
+			date := e.Date
+			stats.LastShow = &date
+		}
+
+		seen := make(map[string]bool)
+		for _, genre := range e.Genres {
+			genre = strings.ToLower(strings.TrimSpace(genre))
+			if genre == "" || seen[genre] {
+				continue
+			}
+			seen[genre] = true
+			counts[genre]++
+		}
+		if len(seen) > 0 {
+			withGenres++
+		}
+	}
+	if months > 0 {
+		stats.ShowsPerMonth = math.Round(float64(stats.Shows)/float64(months)*10) / 10
+	}
+
+	for genre, shows := range counts {
+		stats.Genres = append(stats.Genres, GenreCount{Genre: genre, Shows: shows})
+	}
+	sort.Slice(stats.Genres, func(i, j int) bool {
+		if stats.Genres[i].Shows != stats.Genres[j].Shows {
+			return stats.Genres[i].Shows > stats.Genres[j].Shows
+		}
+		return stats.Genres[i].Genre < stats.Genres[j].Genre
+	})
+
+	if withGenres >= minGenreShows {
+		for _, g := range stats.Genres {
+			if len(stats.TypicalGenres) == maxTypicalGenres || float64(g.Shows) < typicalGenreShare*float64(withGenres) {
+				break
+			}
+			stats.TypicalGenres = append(stats.TypicalGenres, g.Genre)
+		}
+	}
+
+	return stats
+}
+
+// ApplyEventGenres sets the venue's genres to those derived from its events.
+// Genres someone more trusted entered are kept, as are a verified venue's.
+// It reports whether the genres changed.
+func (v *Venue) ApplyEventGenres(genres []string) bool {
+	if len(genres) == 0 || reflect.DeepEqual(genres, v.Genres) {
+		return false
+	}
+	if len(v.Genres) > 0 && (v.Verified || !v.accepts(FieldGenres, SourceEventHistory)) {
+		return false
+	}
+
+	before := v.Clone()
+	v.Genres = append([]string{}, genres...)
+	v.RecordChanges(before, SourceEventHistory, "")
+	return true
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeEvents(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	event := func(id string, daysAgo int, genres ...string) *VenueEvent {
+		e := NewVenueEvent("venue-1", SourceSongkick, id, now.AddDate(0, 0, -daysAgo))
+		e.Genres = genres
+		return e
+	}
+
+	events := []*VenueEvent{
+		event("1", 10, "Indie Rock", "indie rock"),
+		event("2", 40, "indie rock", "punk"),
+		event("3", 70, "Indie Rock"),
+		event("4", 100, "jazz"),
+		event("5", 130, "Punk"),
+		event("6", 160),
+		event("7", 400, "jazz"), // Outside the window
+	}
+
+	stats := SummarizeEvents(events, now, EventStatsMonths)
+	assert.Equal(t, 6, stats.Shows)
+	assert.Equal(t, 0.5, stats.ShowsPerMonth)
+	assert.Equal(t, now.AddDate(0, 0, -10), *stats.LastShow)
+	assert.Equal(t, []GenreCount{{"indie rock", 3}, {"punk", 2}, {"jazz", 1}}, stats.Genres)
+	// Five shows have genres, so a genre needs at least one in five of them
+	assert.Equal(t, []string{"indie rock", "punk", "jazz"}, stats.TypicalGenres)
+
+	few := SummarizeEvents(events[:3], now, EventStatsMonths)
+	assert.Empty(t, few.TypicalGenres, "too few shows to derive genres")
+}
+
+func TestVenue_ApplyEventGenres(t *testing.T) {
+	venue := NewVenue("Thee Parkside", GeoPoint{}, Address{}, []VenueType{VenueTypeBar}, SourceGooglePlaces)
+
+	assert.True(t, venue.ApplyEventGenres([]string{"punk"}), "empty genres are filled")
+	assert.Equal(t, SourceEventHistory, venue.FieldSource(FieldGenres).Source)
+	assert.False(t, venue.ApplyEventGenres([]string{"punk"}), "unchanged genres are not rewritten")
+	assert.True(t, venue.ApplyEventGenres([]string{"punk", "garage"}), "derived genres refresh themselves")
+
+	before := venue.Clone()
+	venue.Genres = []string{"country"}
+	venue.RecordChanges(before, SourceUserSubmitted, "user-1")
+	assert.False(t, venue.ApplyEventGenres([]string{"punk"}), "user-entered genres are kept")
+	assert.Equal(t, []string{"country"}, venue.Genres)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import "time"
+
+// Fields compared in change history but not tracked for provenance
+const (
+	FieldExternalIDs VenueField = "external_ids"
+	FieldVerified    VenueField = "verified"
+	FieldActive      VenueField = "active"
+	FieldOwners      VenueField = "owner_ids"
+	FieldModeration  VenueField = "moderation_status"
+	FieldGallery     VenueField = "gallery"
+)
+
+// historyFields lists every field a version diff covers
+var historyFields = append(append([]VenueField{}, TrackedFields...), FieldExternalIDs, FieldVerified, FieldActive, FieldOwners, FieldModeration, FieldGallery, FieldRooms)
+
+// VenueChangeAction describes the write that produced a version
+type VenueChangeAction string
+
+const (
+	VenueCreated  VenueChangeAction = "create"
+	VenueUpdated  VenueChangeAction = "update"
+	VenueDeleted  VenueChangeAction = "delete"
+	VenueReverted VenueChangeAction = "revert"
+	VenuePurged   VenueChangeAction = "purge"
+)
+
+// FieldChange is one field's value before and after a change, with whoever
+// wrote the new value when that is known
+type FieldChange struct {
+	Field  VenueField  `dynamodbav:"field" json:"field"`
+	Old    interface{} `dynamodbav:"old" json:"old"`
+	New    interface{} `dynamodbav:"new" json:"new"`
+	Source DataSource  `dynamodbav:"source,omitempty" json:"source,omitempty"`
+	Actor  string      `dynamodbav:"actor,omitempty" json:"actor,omitempty"`
+}
+
+// VenueVersion is one entry in a venue's append-only change history. It
+// keeps a snapshot of the venue after the change, or before it for a
+// delete, so the venue can be restored to that point.
+type VenueVersion struct {
+	VenueID    string            `dynamodbav:"venue_id" json:"venue_id"`
+	Version    int               `dynamodbav:"version" json:"version"`
+	Action     VenueChangeAction `dynamodbav:"action" json:"action"`
+	Changes    []FieldChange     `dynamodbav:"changes" json:"changes"`
+	RevertedTo int               `dynamodbav:"reverted_to,omitempty" json:"reverted_to,omitempty"`
+	Snapshot   *Venue            `dynamodbav:"snapshot" json:"snapshot,omitempty"`
+	CreatedAt  time.Time         `dynamodbav:"created_at" json:"created_at"`
+}
+
+// NewVenueVersion records a change from before to after. before is nil for
+// a new venue and after is nil for a deleted one. The version number is
+// assigned when the version is stored.
+func NewVenueVersion(before, after *Venue, action VenueChangeAction) *VenueVersion {
+	version := &VenueVersion{
+		Action:    action,
+		Changes:   DiffVenues(before, after),
+		CreatedAt: time.Now(),
+	}
+	if after != nil {
+		version.VenueID = after.ID
+		version.Snapshot = after.Clone()
+	} else {
+		version.VenueID = before.ID
+		version.Snapshot = before.Clone()
+	}
+	return version
+}
+
+// DiffVenues lists the fields that differ between two venues. A nil venue
+// counts as having every field empty.
+func DiffVenues(before, after *Venue) []FieldChange {
+	changes := make([]FieldChange, 0)
+	if after == nil {
+		// Deletes keep the snapshot; listing every field as removed adds nothing
+		return changes
+	}
+
+	for _, field := range historyFields {
+		newValue := fieldValue(after, field)
+		if before == nil {
+			if isEmpty(newValue) {
+				continue
+			}
+		} else if sameValue(newValue, fieldValue(before, field)) {
+			continue
+		}
+
+		change := FieldChange{Field: field, New: displayValue(after, field)}
+		if before != nil {
+			change.Old = displayValue(before, field)
+		}
+		if p, ok := after.Provenance[field]; ok {
+			change.Source = p.Source
+			change.Actor = p.Actor
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// RevertTo returns a copy of snapshot to be saved in place of the venue,
+// keeping the venue's identity, creation time and current version
+func (v *Venue) RevertTo(snapshot *Venue) *Venue {
+	reverted := snapshot.Clone()
+	reverted.ID = v.ID
+	reverted.CreatedAt = v.CreatedAt
+	reverted.Version = v.Version
+	// Deleted photos are gone from storage, so the gallery stays as it is
+	reverted.Gallery = cloneSlice(v.Gallery)
+	reverted.Update()
+	return reverted
+}
+
+// displayValue returns a field's value in the form shown in a diff
+func displayValue(v *Venue, field VenueField) interface{} {
+	switch field {
+	case FieldLocation:
+		return GeoPoint{Latitude: v.Location.Latitude, Longitude: v.Location.Longitude}
+	case FieldRating:
+		return map[string]interface{}{"rating": v.Rating, "review_count": v.ReviewCount}
+	}
+	return fieldValue(v, field).Interface()
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffVenues(t *testing.T) {
+	before := newDedupVenue("The Chapel", "777 Valencia St", 37.7603, -122.4212)
+	before.Capacity = 300
+
+	after := before.Clone()
+	after.Capacity = 450
+	after.SongkickID = "sk-9"
+	after.Location.Geohash = "9q8yy"
+	after.RecordChanges(before, SourceSongkick, "")
+
+	changes := DiffVenues(before, after)
+
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, FieldCapacity, changes[0].Field)
+		assert.Equal(t, 300, changes[0].Old)
+		assert.Equal(t, 450, changes[0].New)
+		assert.Equal(t, SourceSongkick, changes[0].Source)
+		assert.Equal(t, FieldExternalIDs, changes[1].Field)
+		assert.Equal(t, map[DataSource]string{SourceSongkick: "sk-9"}, changes[1].New)
+	}
+}
+
+func TestNewVenueVersion(t *testing.T) {
+	venue := newDedupVenue("The Chapel", "777 Valencia St", 37.7603, -122.4212)
+
+	created := NewVenueVersion(nil, venue, VenueCreated)
+	assert.Equal(t, venue.ID, created.VenueID)
+	assert.Contains(t, fieldsOf(created.Changes), FieldName)
+	assert.NotContains(t, fieldsOf(created.Changes), FieldCapacity, "empty fields are not listed")
+
+	venue.Name = "Renamed"
+	assert.Equal(t, "The Chapel", created.Snapshot.Name, "the snapshot is a copy")
+
+	deleted := NewVenueVersion(venue, nil, VenueDeleted)
+	assert.Equal(t, venue.ID, deleted.VenueID)
+	assert.Empty(t, deleted.Changes)
+	assert.Equal(t, "Renamed", deleted.Snapshot.Name)
+}
+
+func TestVenue_RevertTo(t *testing.T) {
+	snapshot := newDedupVenue("The Chapel", "777 Valencia St", 37.7603, -122.4212)
+	current := snapshot.Clone()
+	current.Name = "Chapel SF"
+	current.ID = "other-id"
+
+	reverted := current.RevertTo(snapshot)
+
+	assert.Equal(t, "The Chapel", reverted.Name)
+	assert.Equal(t, "other-id", reverted.ID)
+	assert.Equal(t, current.CreatedAt, reverted.CreatedAt)
+}
+
+func fieldsOf(changes []FieldChange) []VenueField {
+	fields := make([]VenueField, len(changes))
+	for i, c := range changes {
+		fields[i] = c.Field
+	}
+	return fields
+}
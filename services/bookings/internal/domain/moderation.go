@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ModerationStatus tracks a community submission through review. Venues
+// from other sources carry no status and are listed as soon as they exist.
+type ModerationStatus string
+
+const (
+	ModerationPending  ModerationStatus = "pending"
+	ModerationApproved ModerationStatus = "approved"
+	ModerationRejected ModerationStatus = "rejected"
+)
+
+// ModerationCheck names an automatic check run on a submission
+type ModerationCheck string
+
+const (
+	CheckDuplicate        ModerationCheck = "duplicate"
+	CheckLocationMismatch ModerationCheck = "location_mismatch"
+	CheckProfanity        ModerationCheck = "profanity"
+	CheckMissingFields    ModerationCheck = "missing_fields"
+)
+
+// ModerationIssue is something an automatic check found for a moderator to look at
+type ModerationIssue struct {
+	Check  ModerationCheck `dynamodbav:"check" json:"check"`
+	Detail string          `dynamodbav:"detail" json:"detail"`
+}
+
+// ModerationReview records who submitted a venue, what the automatic checks
+// found and how a moderator decided
+type ModerationReview struct {
+	SubmittedBy string            `dynamodbav:"submitted_by,omitempty" json:"submitted_by,omitempty"`
+	SubmittedAt time.Time         `dynamodbav:"submitted_at" json:"submitted_at"`
+	Issues      []ModerationIssue `dynamodbav:"issues" json:"issues"`
+	ReviewedBy  string            `dynamodbav:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time        `dynamodbav:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	Reason      string            `dynamodbav:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// profaneWords are rejected in venue names and descriptions. Matching is on
+// whole words so that place names like Scunthorpe pass.
+var profaneWords = map[string]bool{
+	"fuck": true, "fucking": true, "shit": true, "bitch": true, "cunt": true,
+	"asshole": true, "bastard": true, "dick": true, "cock": true, "pussy": true,
+	"whore": true, "slut": true, "fag": true, "faggot": true, "nigger": true,
+	"retard": true,
+}
+
+// Submit holds a community submission back from search until a moderator
+// approves it
+func (v *Venue) Submit(userID string) {
+	v.ModerationStatus = ModerationPending
+	v.Active = false
+	v.Moderation = &ModerationReview{
+		SubmittedBy: userID,
+		SubmittedAt: time.Now(),
+		Issues:      []ModerationIssue{},
+	}
+}
+
+// Listed reports whether the venue has passed moderation, or never needed it
+func (v *Venue) Listed() bool {
+	return v.ModerationStatus == "" || v.ModerationStatus == ModerationApproved
+}
+
+// Approve lists a submission
+func (v *Venue) Approve(moderator, note string) {
+	v.review(ModerationApproved, moderator, note)
+	v.Active = true
+}
+
+// Reject keeps a submission out of search, recording why
+func (v *Venue) Reject(moderator, reason string) {
+	v.review(ModerationRejected, moderator, reason)
+	v.Active = false
+}
+
+// review records a moderator's decision
+func (v *Venue) review(status ModerationStatus, moderator, reason string) {
+	now := time.Now()
+	if v.Moderation == nil {
+		v.Moderation = &ModerationReview{Issues: []ModerationIssue{}}
+	}
+	v.ModerationStatus = status
+	v.Moderation.ReviewedBy = moderator
+	v.Moderation.ReviewedAt = &now
+	v.Moderation.Reason = reason
+	v.UpdatedAt = now
+}
+
+// ProfaneWords returns the profane words in the venue's name and description
+func (v *Venue) ProfaneWords() []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, text := range []string{v.Name, v.Description} {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, word := range words {
+			if profaneWords[word] && !seen[word] {
+				seen[word] = true
+				found = append(found, word)
+			}
+		}
+	}
+	return found
+}
+
+// MissingFields lists details a submission should have before it is listed:
+// a full address, a capacity and some way to contact the venue
+func (v *Venue) MissingFields() []string {
+	var missing []string
+	if v.Address.Street == "" {
+		missing = append(missing, "address.street")
+	}
+	if v.Address.City == "" {
+		missing = append(missing, "address.city")
+	}
+	if v.Address.Country == "" {
+		missing = append(missing, "address.country")
+	}
+	if v.Capacity == 0 {
+		missing = append(missing, "capacity")
+	}
+	c := v.ContactInfo
+	if c.Email == "" && c.Phone == "" && c.Website == "" && c.BookingURL == "" {
+		missing = append(missing, "contact_info")
+	}
+	return missing
+}
+
+// clone copies the review so that it shares nothing with the original
+func (m *ModerationReview) clone() *ModerationReview {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	c.Issues = cloneSlice(m.Issues)
+	if m.ReviewedAt != nil {
+		reviewedAt := *m.ReviewedAt
+		c.ReviewedAt = &reviewedAt
+	}
+	return &c
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenue_ProfaneWords(t *testing.T) {
+	venue := NewVenue("Scunthorpe Social Club", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceUserSubmitted)
+	assert.Empty(t, venue.ProfaneWords(), "only whole words match")
+
+	venue.Description = "The SHIT-hot room. Shit sound too."
+	assert.Equal(t, []string{"shit"}, venue.ProfaneWords())
+}
+
+func TestVenue_MissingFields(t *testing.T) {
+	venue := NewVenue("Hotel Utah", GeoPoint{}, Address{City: "San Francisco"}, []VenueType{VenueTypeBar}, SourceUserSubmitted)
+	assert.Equal(t, []string{"address.street", "address.country", "capacity", "contact_info"}, venue.MissingFields())
+
+	venue.Address.Street = "500 4th St"
+	venue.Address.Country = "US"
+	venue.Capacity = 80
+	venue.ContactInfo.Website = "https://hotelutah.com"
+	assert.Empty(t, venue.MissingFields())
+}
+
+func TestVenue_ModerationLifecycle(t *testing.T) {
+	venue := NewVenue("Hotel Utah", GeoPoint{}, Address{}, []VenueType{VenueTypeBar}, SourceUserSubmitted)
+	assert.True(t, venue.Listed(), "venues that never went through moderation are listed")
+
+	venue.Submit("user-1")
+	assert.False(t, venue.Listed())
+	assert.False(t, venue.Active)
+
+	venue.Reject("mod-1", "duplicate")
+	assert.False(t, venue.Listed())
+	assert.Equal(t, "duplicate", venue.Moderation.Reason)
+
+	clone := venue.Clone()
+	clone.Moderation.Reason = "changed"
+	assert.Equal(t, "duplicate", venue.Moderation.Reason, "clones do not share the review")
+
+	venue.Approve("mod-2", "")
+	assert.True(t, venue.Listed())
+	assert.True(t, venue.Active)
+	assert.Equal(t, "mod-2", venue.Moderation.ReviewedBy)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxVenuePhotos caps how many photos can be uploaded to one venue
+const MaxVenuePhotos = 30
+
+// ErrPhotoOrder is returned when a new photo order does not list each of the
+// venue's photos exactly once
+var ErrPhotoOrder = errors.New("photo order must list every photo exactly once")
+
+// VenuePhoto is a photo uploaded to a venue, stored with a thumbnail
+type VenuePhoto struct {
+	ID           string    `dynamodbav:"id" json:"id"`
+	URL          string    `dynamodbav:"url" json:"url"`
+	ThumbnailURL string    `dynamodbav:"thumbnail_url" json:"thumbnail_url"`
+	Key          string    `dynamodbav:"key" json:"-"`           // Object store key of the photo
+	ThumbnailKey string    `dynamodbav:"thumbnail_key" json:"-"` // Object store key of the thumbnail
+	ContentType  string    `dynamodbav:"content_type" json:"content_type"`
+	Width        int       `dynamodbav:"width" json:"width"`
+	Height       int       `dynamodbav:"height" json:"height"`
+	Size         int       `dynamodbav:"size" json:"size"`
+	UploadedBy   string    `dynamodbav:"uploaded_by,omitempty" json:"uploaded_by,omitempty"`
+	UploadedAt   time.Time `dynamodbav:"uploaded_at" json:"uploaded_at"`
+}
+
+// NewPhotoID returns an ID for a photo about to be uploaded, so its object
+// keys can be chosen before the photo is added to the venue
+func NewPhotoID() string {
+	return uuid.New().String()
+}
+
+// Photo returns one of the venue's uploaded photos
+func (v *Venue) Photo(id string) (*VenuePhoto, bool) {
+	for i := range v.Gallery {
+		if v.Gallery[i].ID == id {
+			return &v.Gallery[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddPhoto appends an uploaded photo to the end of the gallery
+func (v *Venue) AddPhoto(photo VenuePhoto) {
+	v.Gallery = append(v.Gallery, photo)
+	v.UpdatedAt = time.Now()
+}
+
+// RemovePhoto takes a photo out of the gallery and returns it
+func (v *Venue) RemovePhoto(id string) (VenuePhoto, bool) {
+	for i, photo := range v.Gallery {
+		if photo.ID == id {
+			v.Gallery = append(v.Gallery[:i:i], v.Gallery[i+1:]...)
+			v.UpdatedAt = time.Now()
+			return photo, true
+		}
+	}
+	return VenuePhoto{}, false
+}
+
+// ReorderPhotos puts the gallery in the given order of photo IDs. The first
+// photo is the venue's cover.
+func (v *Venue) ReorderPhotos(ids []string) error {
+	if len(ids) != len(v.Gallery) {
+		return ErrPhotoOrder
+	}
+	byID := make(map[string]VenuePhoto, len(v.Gallery))
+	for _, photo := range v.Gallery {
+		byID[photo.ID] = photo
+	}
+
+	ordered := make([]VenuePhoto, 0, len(ids))
+	for _, id := range ids {
+		photo, ok := byID[id]
+		if !ok {
+			return ErrPhotoOrder
+		}
+		delete(byID, id)
+		ordered = append(ordered, photo)
+	}
+
+	v.Gallery = ordered
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// photoIDs lists the gallery's photo IDs in order
+func (v *Venue) photoIDs() []string {
+	ids := make([]string, len(v.Gallery))
+	for i, photo := range v.Gallery {
+		ids[i] = photo.ID
+	}
+	return ids
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenue_Photos(t *testing.T) {
+	venue := NewVenue("Hotel Utah", GeoPoint{}, Address{}, []VenueType{VenueTypeBar}, SourceUserSubmitted)
+	for _, id := range []string{"p1", "p2", "p3"} {
+		venue.AddPhoto(VenuePhoto{ID: id})
+	}
+
+	assert.ErrorIs(t, venue.ReorderPhotos([]string{"p3", "p1"}), ErrPhotoOrder, "every photo must be listed")
+	assert.ErrorIs(t, venue.ReorderPhotos([]string{"p3", "p1", "p1"}), ErrPhotoOrder, "no photo may be listed twice")
+	assert.NoError(t, venue.ReorderPhotos([]string{"p3", "p1", "p2"}))
+	assert.Equal(t, []string{"p3", "p1", "p2"}, venue.photoIDs())
+
+	before := venue.Clone()
+	removed, ok := venue.RemovePhoto("p1")
+	assert.True(t, ok)
+	assert.Equal(t, "p1", removed.ID)
+	assert.Equal(t, []string{"p3", "p2"}, venue.photoIDs())
+	assert.Equal(t, []string{"p3", "p1", "p2"}, before.photoIDs(), "clones do not share the gallery")
+
+	_, ok = venue.RemovePhoto("p1")
+	assert.False(t, ok)
+
+	changes := DiffVenues(before, venue)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, FieldGallery, changes[0].Field)
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"reflect"
+	"time"
+)
+
+// VenueField names a venue field whose origin is tracked
+type VenueField string
+
+const (
+	FieldName        VenueField = "name"
+	FieldLocation    VenueField = "location"
+	FieldAddress     VenueField = "address"
+	FieldVenueTypes  VenueField = "venue_types"
+	FieldCapacity    VenueField = "capacity"
+	FieldGenres      VenueField = "genres"
+	FieldPayRange    VenueField = "pay_range"
+	FieldAmenities   VenueField = "amenities"
+	FieldContactInfo VenueField = "contact_info"
+	FieldRating      VenueField = "rating"
+	FieldDescription VenueField = "description"
+)
+
+// TrackedFields lists every field with recorded provenance
+var TrackedFields = []VenueField{
+	FieldName, FieldLocation, FieldAddress, FieldVenueTypes, FieldCapacity, FieldGenres,
+	FieldPayRange, FieldAmenities, FieldContactInfo, FieldRating, FieldDescription,
+}
+
+// sourceConfidence is how far each source is trusted by default, from 0 to 1
+var sourceConfidence = map[DataSource]float64{
+	SourceManual:        1.0,
+	SourceUserSubmitted: 0.8,
+	SourceGooglePlaces:  0.7,
+	SourceSongkick:      0.6,
+	SourceBandsintown:   0.5,
+	SourceEventHistory:  0.6,
+}
+
+// fieldConfidence overrides sourceConfidence for fields a source is known
+// to report better than usual. Songkick capacities come from ticketing and
+// beat user estimates.
+var fieldConfidence = map[DataSource]map[VenueField]float64{
+	SourceSongkick: {FieldCapacity: 0.85},
+}
+
+// Confidence is how far a source is trusted for one field
+func Confidence(source DataSource, field VenueField) float64 {
+	if c, ok := fieldConfidence[source][field]; ok {
+		return c
+	}
+	return sourceConfidence[source]
+}
+
+// FieldProvenance records who last wrote a field
+type FieldProvenance struct {
+	Source     DataSource `dynamodbav:"source" json:"source"`
+	Actor      string     `dynamodbav:"actor,omitempty" json:"actor,omitempty"`
+	UpdatedAt  time.Time  `dynamodbav:"updated_at" json:"updated_at"`
+	Confidence float64    `dynamodbav:"confidence" json:"confidence"`
+	// Inferred is set when nothing was recorded and the venue's own source
+	// is assumed
+	Inferred bool `dynamodbav:"-" json:"inferred,omitempty"`
+}
+
+// outranks reports whether p should replace current. A higher confidence
+// wins; on a tie the current value is kept.
+func (p FieldProvenance) outranks(current FieldProvenance) bool {
+	return p.Confidence > current.Confidence
+}
+
+// FieldSource returns the provenance of a field. Venues written before
+// provenance was tracked are assumed to carry their own source's data.
+func (v *Venue) FieldSource(field VenueField) FieldProvenance {
+	if p, ok := v.Provenance[field]; ok {
+		return p
+	}
+	return FieldProvenance{
+		Source:     v.Source,
+		UpdatedAt:  v.CreatedAt,
+		Confidence: Confidence(v.Source, field),
+		Inferred:   true,
+	}
+}
+
+// ProvenanceReport returns the provenance of every tracked field
+func (v *Venue) ProvenanceReport() map[VenueField]FieldProvenance {
+	report := make(map[VenueField]FieldProvenance, len(TrackedFields))
+	for _, field := range TrackedFields {
+		report[field] = v.FieldSource(field)
+	}
+	return report
+}
+
+// RecordChanges stamps every tracked field that differs from before as
+// written by source and actor. A nil before stamps every non-empty field,
+// as for a new venue.
+func (v *Venue) RecordChanges(before *Venue, source DataSource, actor string) {
+	now := time.Now()
+	for _, field := range TrackedFields {
+		value := fieldValue(v, field)
+		if before == nil {
+			if isEmpty(value) {
+				continue
+			}
+		} else if sameValue(value, fieldValue(before, field)) {
+			continue
+		}
+		v.setFieldSource(field, FieldProvenance{
+			Source:     source,
+			Actor:      actor,
+			UpdatedAt:  now,
+			Confidence: Confidence(source, field),
+		})
+	}
+}
+
+// accepts reports whether data from source may overwrite a field: the
+// source wrote the current value itself or is trusted more than whoever did
+func (v *Venue) accepts(field VenueField, source DataSource) bool {
+	current := v.FieldSource(field)
+	if current.Source == source {
+		return true
+	}
+	return Confidence(source, field) > current.Confidence
+}
+
+func (v *Venue) setFieldSource(field VenueField, p FieldProvenance) {
+	if v.Provenance == nil {
+		v.Provenance = make(map[VenueField]FieldProvenance)
+	}
+	p.Inferred = false
+	v.Provenance[field] = p
+}
+
+// fieldValue returns the value of a tracked or history field. Rating covers the
+// review count that goes with it.
+func fieldValue(v *Venue, field VenueField) reflect.Value {
+	switch field {
+	case FieldName:
+		return reflect.ValueOf(v.Name)
+	case FieldLocation:
+		// The geohash is derived, so only the coordinates count
+		return reflect.ValueOf([2]float64{v.Location.Latitude, v.Location.Longitude})
+	case FieldAddress:
+		return reflect.ValueOf(v.Address)
+	case FieldVenueTypes:
+		return reflect.ValueOf(v.VenueTypes)
+	case FieldCapacity:
+		return reflect.ValueOf(v.Capacity)
+	case FieldGenres:
+		return reflect.ValueOf(v.Genres)
+	case FieldPayRange:
+		return reflect.ValueOf(v.PayRange)
+	case FieldAmenities:
+		return reflect.ValueOf(v.Amenities)
+	case FieldContactInfo:
+		return reflect.ValueOf(v.ContactInfo)
+	case FieldRating:
+		return reflect.ValueOf([2]float64{v.Rating, float64(v.ReviewCount)})
+	case FieldDescription:
+		return reflect.ValueOf(v.Description)
+	case FieldExternalIDs:
+		return reflect.ValueOf(v.ExternalIDs())
+	case FieldVerified:
+		return reflect.ValueOf(v.Verified)
+	case FieldActive:
+		return reflect.ValueOf(v.Active)
+	case FieldOwners:
+		return reflect.ValueOf(v.OwnerIDs)
+	case FieldModeration:
+		return reflect.ValueOf(v.ModerationStatus)
+	case FieldGallery:
+		// Photos never change once uploaded, so their IDs in order say it all
+		return reflect.ValueOf(v.photoIDs())
+	case FieldRooms:
+		return reflect.ValueOf(v.Rooms)
+	}
+	return reflect.Value{}
+}
+
+// isEmpty treats empty slices and maps like nil ones
+func isEmpty(value reflect.Value) bool {
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Map {
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+func sameValue(a, b reflect.Value) bool {
+	if isEmpty(a) && isEmpty(b) {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenue_RecordChanges(t *testing.T) {
+	venue := newDedupVenue("The Chapel", "777 Valencia St", 37.7603, -122.4212)
+	venue.Source = SourceUserSubmitted
+	venue.RecordChanges(nil, SourceUserSubmitted, "user-1")
+
+	assert.Equal(t, "user-1", venue.Provenance[FieldName].Actor)
+	assert.Contains(t, venue.Provenance, FieldAddress)
+	assert.NotContains(t, venue.Provenance, FieldCapacity, "empty fields are not stamped")
+	assert.NotContains(t, venue.Provenance, FieldGenres, "empty lists are not stamped")
+
+	before := *venue
+	venue.Capacity = 400
+	venue.Location.Geohash = "9q8yy"
+	venue.RecordChanges(&before, SourceSongkick, "")
+
+	capacity := venue.Provenance[FieldCapacity]
+	assert.Equal(t, SourceSongkick, capacity.Source)
+	assert.Equal(t, 0.85, capacity.Confidence)
+	assert.Equal(t, SourceUserSubmitted, venue.Provenance[FieldName].Source)
+	assert.Equal(t, SourceUserSubmitted, venue.Provenance[FieldLocation].Source, "a geohash alone is not a move")
+}
+
+func TestVenue_FieldSource_InfersFromVenueSource(t *testing.T) {
+	venue := newDedupVenue("The Chapel", "", 37.7603, -122.4212)
+
+	p := venue.FieldSource(FieldCapacity)
+
+	assert.True(t, p.Inferred)
+	assert.Equal(t, SourceGooglePlaces, p.Source)
+	assert.Equal(t, 0.7, p.Confidence)
+	assert.Len(t, venue.ProvenanceReport(), len(TrackedFields))
+}
+
+func TestRefreshFromSource_KeepsUserEdits(t *testing.T) {
+	venue := newDedupVenue("Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	venue.GooglePlaceID = "gp-1"
+	before := *venue
+	venue.Name = "The Fillmore"
+	venue.RecordChanges(&before, SourceUserSubmitted, "user-1")
+
+	fetched := newDedupVenue("Fillmore Auditorium", "1805 Geary Blvd", 37.7841, -122.4331)
+	fetched.GooglePlaceID = "gp-1"
+
+	RefreshFromSource(venue, fetched)
+
+	assert.Equal(t, "The Fillmore", venue.Name, "a user edit outranks the source")
+	assert.Equal(t, 37.7841, venue.Location.Latitude, "untouched fields still refresh")
+	assert.Equal(t, SourceGooglePlaces, venue.Provenance[FieldLocation].Source)
+	assert.Equal(t, "user-1", venue.Provenance[FieldName].Actor)
+}
+
+func TestMergeVenues_MoreTrustedValueWins(t *testing.T) {
+	survivor := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	survivor.Capacity = 1000
+	survivor.Description = "Historic ballroom"
+
+	merged := newDedupVenue("Fillmore", "", 37.7841, -122.4331)
+	merged.Source = SourceSongkick
+	before := *merged
+	merged.Capacity = 1150
+	merged.Description = "Concert hall"
+	merged.RecordChanges(&before, SourceSongkick, "")
+
+	MergeVenues(survivor, merged)
+
+	assert.Equal(t, 1150, survivor.Capacity, "songkick capacity outranks google")
+	assert.Equal(t, SourceSongkick, survivor.Provenance[FieldCapacity].Source)
+	assert.Equal(t, "Historic ballroom", survivor.Description, "songkick descriptions do not outrank google")
+	assert.Equal(t, "The Fillmore", survivor.Name)
+
+	verified := newDedupVenue("The Fillmore", "", 37.7840, -122.4330)
+	verified.Capacity = 1000
+	verified.Verify()
+	MergeVenues(verified, merged)
+	assert.Equal(t, 1000, verified.Capacity, "a verified survivor keeps its values")
+}
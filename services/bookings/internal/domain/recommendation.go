@@ -0,0 +1,59 @@
+package domain
+
+// VenueRecommendation is a venue suggested for an artist with the reasons behind it
+type VenueRecommendation struct {
+	Venue   *Venue   `json:"venue"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// ArtistProfile summarises an artist's booking history for recommendations
+type ArtistProfile struct {
+	ArtistID       string
+	PlayedVenueIDs map[string]bool
+	PlayedVenues   []*Venue
+	GenreWeights   map[string]float64 // Share of played venues carrying each genre
+	AvgCapacity    float64
+	AvgFee         float64
+}
+
+// NewArtistProfile builds a profile from the venues an artist has played and the fees they were paid
+func NewArtistProfile(artistID string, venues []*Venue, fees []float64) *ArtistProfile {
+	profile := &ArtistProfile{
+		ArtistID:       artistID,
+		PlayedVenueIDs: make(map[string]bool),
+		PlayedVenues:   venues,
+		GenreWeights:   make(map[string]float64),
+	}
+
+	capacityTotal, capacityCount := 0, 0
+	for _, v := range venues {
+		profile.PlayedVenueIDs[v.ID] = true
+		for _, g := range v.Genres {
+			profile.GenreWeights[g]++
+		}
+		if v.Capacity > 0 {
+			capacityTotal += v.Capacity
+			capacityCount++
+		}
+	}
+	for g := range profile.GenreWeights {
+		profile.GenreWeights[g] /= float64(len(venues))
+	}
+	if capacityCount > 0 {
+		profile.AvgCapacity = float64(capacityTotal) / float64(capacityCount)
+	}
+
+	feeTotal, feeCount := 0.0, 0
+	for _, fee := range fees {
+		if fee > 0 {
+			feeTotal += fee
+			feeCount++
+		}
+	}
+	if feeCount > 0 {
+		profile.AvgFee = feeTotal / float64(feeCount)
+	}
+
+	return profile
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewArtistProfile(t *testing.T) {
+	rock := NewVenue("Rock Club", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+	rock.Genres = []string{"rock", "indie"}
+	rock.Capacity = 200
+
+	jazz := NewVenue("Jazz Bar", GeoPoint{}, Address{}, []VenueType{VenueTypeBar}, SourceManual)
+	jazz.Genres = []string{"jazz", "indie"}
+	jazz.Capacity = 100
+
+	profile := NewArtistProfile("artist-1", []*Venue{rock, jazz}, []float64{400, 0, 600})
+
+	assert.True(t, profile.PlayedVenueIDs[rock.ID])
+	assert.True(t, profile.PlayedVenueIDs[jazz.ID])
+	assert.Equal(t, 1.0, profile.GenreWeights["indie"])
+	assert.Equal(t, 0.5, profile.GenreWeights["rock"])
+	assert.Equal(t, 150.0, profile.AvgCapacity)
+	assert.Equal(t, 500.0, profile.AvgFee, "zero fees should be ignored")
+}
+
+func TestNewArtistProfile_Empty(t *testing.T) {
+	profile := NewArtistProfile("artist-1", nil, nil)
+
+	assert.Empty(t, profile.PlayedVenueIDs)
+	assert.Zero(t, profile.AvgCapacity)
+	assert.Zero(t, profile.AvgFee)
+}
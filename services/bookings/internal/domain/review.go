@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Review scores run from MinReviewScore to MaxReviewScore
+const (
+	MinReviewScore = 1
+	MaxReviewScore = 5
+)
+
+// ReviewScores are an artist's ratings of the parts of playing a venue.
+// On a venue they hold the totals across all its reviews.
+type ReviewScores struct {
+	Sound              int `dynamodbav:"sound" json:"sound"`
+	Hospitality        int `dynamodbav:"hospitality" json:"hospitality"`
+	PaymentReliability int `dynamodbav:"payment_reliability" json:"payment_reliability"`
+	Turnout            int `dynamodbav:"turnout" json:"turnout"`
+}
+
+// Validate checks that every score is in range
+func (s ReviewScores) Validate() error {
+	for name, score := range map[string]int{
+		"sound":               s.Sound,
+		"hospitality":         s.Hospitality,
+		"payment_reliability": s.PaymentReliability,
+		"turnout":             s.Turnout,
+	} {
+		if score < MinReviewScore || score > MaxReviewScore {
+			return fmt.Errorf("%s must be between %d and %d", name, MinReviewScore, MaxReviewScore)
+		}
+	}
+	return nil
+}
+
+// sum returns the total of all four scores
+func (s ReviewScores) sum() int {
+	return s.Sound + s.Hospitality + s.PaymentReliability + s.Turnout
+}
+
+// add returns s plus sign times o
+func (s ReviewScores) add(o ReviewScores, sign int) ReviewScores {
+	return ReviewScores{
+		Sound:              s.Sound + sign*o.Sound,
+		Hospitality:        s.Hospitality + sign*o.Hospitality,
+		PaymentReliability: s.PaymentReliability + sign*o.PaymentReliability,
+		Turnout:            s.Turnout + sign*o.Turnout,
+	}
+}
+
+// VenueReview is an artist's review of a venue they have played. Each
+// artist has at most one review per venue, which they can revise.
+type VenueReview struct {
+	VenueID   string       `dynamodbav:"venue_id" json:"venue_id"`
+	ArtistID  string       `dynamodbav:"artist_id" json:"artist_id"`
+	BookingID string       `dynamodbav:"booking_id" json:"booking_id"` // The played booking that allowed the review
+	Scores    ReviewScores `dynamodbav:"scores" json:"scores"`
+	Overall   float64      `dynamodbav:"overall" json:"overall"`
+	Comment   string       `dynamodbav:"comment,omitempty" json:"comment,omitempty"`
+	CreatedAt time.Time    `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time    `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// NewVenueReview creates a review, or revises previous when the artist has
+// already reviewed the venue
+func NewVenueReview(previous *VenueReview, venueID, artistID, bookingID string, scores ReviewScores, comment string) *VenueReview {
+	now := time.Now()
+	review := &VenueReview{
+		VenueID:   venueID,
+		ArtistID:  artistID,
+		BookingID: bookingID,
+		Scores:    scores,
+		Overall:   roundRating(float64(scores.sum()) / 4),
+		Comment:   comment,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if previous != nil {
+		review.CreatedAt = previous.CreatedAt
+	}
+	return review
+}
+
+// RatingBreakdown is a venue's average score in each review category
+type RatingBreakdown struct {
+	Sound              float64 `json:"sound"`
+	Hospitality        float64 `json:"hospitality"`
+	PaymentReliability float64 `json:"payment_reliability"`
+	Turnout            float64 `json:"turnout"`
+}
+
+// ApplyReview folds a new or revised review into the venue's aggregate
+// rating. previous is the artist's earlier review, or nil.
+func (v *Venue) ApplyReview(previous, review *VenueReview) {
+	// A rating imported from another source is replaced, not averaged in
+	if v.FieldSource(FieldRating).Source != SourceUserSubmitted {
+		v.ReviewTotals = ReviewScores{}
+		v.ReviewCount = 0
+	}
+
+	if previous != nil {
+		v.ReviewTotals = v.ReviewTotals.add(previous.Scores, -1)
+		v.ReviewCount--
+	}
+	v.ReviewTotals = v.ReviewTotals.add(review.Scores, 1)
+	v.ReviewCount++
+	v.recomputeRating()
+
+	// Artist reviews now own the rating, so syncs stop overwriting it
+	now := time.Now()
+	v.setFieldSource(FieldRating, FieldProvenance{
+		Source:     SourceUserSubmitted,
+		Actor:      review.ArtistID,
+		UpdatedAt:  now,
+		Confidence: Confidence(SourceUserSubmitted, FieldRating),
+	})
+	v.UpdatedAt = now
+}
+
+// RemoveReview takes a review back out of the venue's aggregate rating, for
+// example when merging venues leaves an artist with two reviews
+func (v *Venue) RemoveReview(review *VenueReview) {
+	if !v.hasArtistReviews() {
+		return
+	}
+	v.ReviewTotals = v.ReviewTotals.add(review.Scores, -1)
+	v.ReviewCount--
+	v.recomputeRating()
+	v.UpdatedAt = time.Now()
+}
+
+// hasArtistReviews reports whether the venue's rating comes from artist
+// reviews rather than another source
+func (v *Venue) hasArtistReviews() bool {
+	return v.ReviewCount > 0 && v.FieldSource(FieldRating).Source == SourceUserSubmitted
+}
+
+// recomputeRating sets the rating from the review totals
+func (v *Venue) recomputeRating() {
+	v.Rating = 0
+	if v.ReviewCount > 0 {
+		v.Rating = roundRating(float64(v.ReviewTotals.sum()) / float64(4*v.ReviewCount))
+	}
+}
+
+// RatingBreakdown averages the venue's reviews in each category
+func (v *Venue) RatingBreakdown() RatingBreakdown {
+	if v.ReviewCount == 0 {
+		return RatingBreakdown{}
+	}
+	n := float64(v.ReviewCount)
+	return RatingBreakdown{
+		Sound:              roundRating(float64(v.ReviewTotals.Sound) / n),
+		Hospitality:        roundRating(float64(v.ReviewTotals.Hospitality) / n),
+		PaymentReliability: roundRating(float64(v.ReviewTotals.PaymentReliability) / n),
+		Turnout:            roundRating(float64(v.ReviewTotals.Turnout) / n),
+	}
+}
+
+// roundRating rounds to two decimal places
+func roundRating(r float64) float64 {
+	return math.Round(r*100) / 100
+}
+
+// Played reports whether the booking is a show that went ahead
+func (b *Booking) Played(now time.Time) bool {
+	return b.Status == StatusConfirmed && b.EventDate.Before(now)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewScores_Validate(t *testing.T) {
+	assert.NoError(t, ReviewScores{Sound: 1, Hospitality: 5, PaymentReliability: 3, Turnout: 2}.Validate())
+	assert.Error(t, ReviewScores{Sound: 6, Hospitality: 5, PaymentReliability: 3, Turnout: 2}.Validate())
+	assert.Error(t, ReviewScores{Sound: 4, Hospitality: 5, PaymentReliability: 3}.Validate(), "every score is required")
+}
+
+func TestVenue_ApplyReview(t *testing.T) {
+	venue := NewVenue("Bottom of the Hill", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceUserSubmitted)
+
+	first := NewVenueReview(nil, venue.ID, "artist-1", "booking-1", ReviewScores{Sound: 5, Hospitality: 4, PaymentReliability: 5, Turnout: 3}, "")
+	assert.Equal(t, 4.25, first.Overall)
+	venue.ApplyReview(nil, first)
+	assert.Equal(t, 4.25, venue.Rating)
+	assert.Equal(t, 1, venue.ReviewCount)
+
+	second := NewVenueReview(nil, venue.ID, "artist-2", "booking-2", ReviewScores{Sound: 3, Hospitality: 2, PaymentReliability: 1, Turnout: 1}, "")
+	venue.ApplyReview(nil, second)
+	assert.Equal(t, 2, venue.ReviewCount)
+	assert.Equal(t, 3.0, venue.Rating)
+	assert.Equal(t, RatingBreakdown{Sound: 4, Hospitality: 3, PaymentReliability: 3, Turnout: 2}, venue.RatingBreakdown())
+
+	revised := NewVenueReview(second, venue.ID, "artist-2", "booking-2", ReviewScores{Sound: 5, Hospitality: 4, PaymentReliability: 5, Turnout: 3}, "paid in the end")
+	assert.Equal(t, second.CreatedAt, revised.CreatedAt, "revising keeps the original creation time")
+	venue.ApplyReview(second, revised)
+	assert.Equal(t, 2, venue.ReviewCount, "a revision replaces the earlier review")
+	assert.Equal(t, 4.25, venue.Rating)
+
+	p := venue.FieldSource(FieldRating)
+	assert.Equal(t, SourceUserSubmitted, p.Source)
+	assert.Equal(t, "artist-2", p.Actor)
+}
+
+func TestVenue_ApplyReview_ReplacesImportedRating(t *testing.T) {
+	venue := NewVenue("The Fillmore", GeoPoint{}, Address{}, []VenueType{VenueTypeTheater}, SourceGooglePlaces)
+	venue.Rating = 4.7
+	venue.ReviewCount = 2310
+
+	review := NewVenueReview(nil, venue.ID, "artist-1", "booking-1", ReviewScores{Sound: 2, Hospitality: 2, PaymentReliability: 2, Turnout: 2}, "")
+	venue.ApplyReview(nil, review)
+	assert.Equal(t, 2.0, venue.Rating)
+	assert.Equal(t, 1, venue.ReviewCount)
+}
+
+func TestBooking_Played(t *testing.T) {
+	now := time.Now()
+	booking := NewBooking("artist-1", "venue-1", now.Add(-24*time.Hour), 100)
+	assert.False(t, booking.Played(now), "unconfirmed bookings were not played")
+
+	booking.Confirm()
+	assert.True(t, booking.Played(now))
+	assert.False(t, booking.Played(now.Add(-48*time.Hour)), "future shows have not been played")
+}
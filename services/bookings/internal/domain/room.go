@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldRooms is compared in change history but not tracked for provenance
+const FieldRooms VenueField = "rooms"
+
+// Room is a room or stage within a venue that is booked on its own, such as
+// a main room and a side stage. Its amenities add to the venue's, and its
+// pay range overrides the venue's when set.
+type Room struct {
+	ID           string      `dynamodbav:"id" json:"id"`
+	Name         string      `dynamodbav:"name" json:"name"`
+	Capacity     int         `dynamodbav:"capacity" json:"capacity"`
+	Amenities    []Amenity   `dynamodbav:"amenities" json:"amenities"`
+	PayRange     *PayRange   `dynamodbav:"pay_range,omitempty" json:"pay_range,omitempty"`
+	Availability []DateRange `dynamodbav:"availability,omitempty" json:"availability,omitempty"`
+}
+
+// NewRoom creates a room
+func NewRoom(name string, capacity int) *Room {
+	return &Room{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Capacity:  capacity,
+		Amenities: []Amenity{},
+	}
+}
+
+func (r Room) clone() Room {
+	r.Amenities = cloneSlice(r.Amenities)
+	r.Availability = cloneSlice(r.Availability)
+	if r.PayRange != nil {
+		payRange := *r.PayRange
+		r.PayRange = &payRange
+	}
+	return r
+}
+
+// Room returns the venue's room with the ID, or nil
+func (v *Venue) Room(id string) *Room {
+	for i := range v.Rooms {
+		if v.Rooms[i].ID == id {
+			return &v.Rooms[i]
+		}
+	}
+	return nil
+}
+
+// AssignRoomIDs gives rooms added without an ID one of their own
+func (v *Venue) AssignRoomIDs() {
+	for i := range v.Rooms {
+		if v.Rooms[i].ID == "" {
+			v.Rooms[i].ID = uuid.New().String()
+		}
+		if v.Rooms[i].Amenities == nil {
+			v.Rooms[i].Amenities = []Amenity{}
+		}
+	}
+}
+
+// CheckRoom checks that a booking may be made for the room. Venues with
+// rooms must be booked for one of them; venues without are booked whole.
+func (v *Venue) CheckRoom(roomID string) error {
+	if roomID == "" {
+		if len(v.Rooms) > 0 {
+			return fmt.Errorf("venue %s has %d rooms, so a room is required", v.ID, len(v.Rooms))
+		}
+		return nil
+	}
+	if v.Room(roomID) == nil {
+		return fmt.Errorf("venue %s has no room %q", v.ID, roomID)
+	}
+	return nil
+}
+
+// RoomBooked reports whether any of the venue's bookings takes the room on
+// the date's day, in UTC. Cancelled bookings free the room, and a booking
+// without a room takes the whole venue.
+func RoomBooked(bookings []*Booking, roomID string, date time.Time) bool {
+	year, month, day := date.UTC().Date()
+	for _, b := range bookings {
+		if b.Status == StatusCancelled {
+			continue
+		}
+		if b.RoomID != "" && roomID != "" && b.RoomID != roomID {
+			continue
+		}
+		if y, m, d := b.EventDate.UTC().Date(); y == year && m == month && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Spaces returns what can be booked at the venue, as rooms with the
+// venue-wide amenities and pay range folded in. A venue without rooms is a
+// single space with no ID and the venue's own capacity and availability.
+func (v *Venue) Spaces() []Room {
+	if len(v.Rooms) == 0 {
+		return []Room{{
+			Capacity:     v.Capacity,
+			Amenities:    v.Amenities,
+			PayRange:     v.PayRange,
+			Availability: v.Availability,
+		}}
+	}
+
+	spaces := make([]Room, len(v.Rooms))
+	for i, room := range v.Rooms {
+		space := room.clone()
+		space.Amenities = union(v.Amenities, room.Amenities)
+		if space.PayRange == nil {
+			space.PayRange = v.PayRange
+		}
+		spaces[i] = space
+	}
+	return spaces
+}
+
+// validateRooms checks each room like the venue fields it mirrors
+func (v *Venue) validateRooms() error {
+	ids := make(map[string]bool, len(v.Rooms))
+	for _, room := range v.Rooms {
+		if room.Name == "" {
+			return fmt.Errorf("room name is required")
+		}
+		if room.ID != "" {
+			if ids[room.ID] {
+				return fmt.Errorf("room ID %q is used twice", room.ID)
+			}
+			ids[room.ID] = true
+		}
+		if room.Capacity < 0 {
+			return fmt.Errorf("room %q capacity cannot be negative", room.Name)
+		}
+		for _, a := range room.Amenities {
+			if !validAmenities[a] {
+				return fmt.Errorf("room %q has unknown amenity %q", room.Name, a)
+			}
+		}
+		if err := validatePayRange(room.PayRange); err != nil {
+			return fmt.Errorf("room %q: %w", room.Name, err)
+		}
+		if err := validateAvailability(room.Availability); err != nil {
+			return fmt.Errorf("room %q: %w", room.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenue_Spaces(t *testing.T) {
+	venue := NewVenue("The Independent", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+	venue.Capacity = 500
+	venue.Amenities = []Amenity{AmenityParking}
+	venue.PayRange = &PayRange{Min: 200, Max: 800, Currency: "USD", Type: PaymentGuarantee}
+
+	spaces := venue.Spaces()
+	assert.Len(t, spaces, 1, "a venue without rooms is booked whole")
+	assert.Empty(t, spaces[0].ID)
+	assert.Equal(t, 500, spaces[0].Capacity)
+
+	stage := NewRoom("Side Stage", 120)
+	stage.Amenities = []Amenity{AmenityGreenRoom}
+	stage.PayRange = &PayRange{Min: 50, Max: 150, Currency: "USD", Type: PaymentDoorSplit}
+	venue.Rooms = []Room{*NewRoom("Main Room", 500), *stage}
+
+	spaces = venue.Spaces()
+	assert.Len(t, spaces, 2)
+	assert.Equal(t, []Amenity{AmenityParking}, spaces[0].Amenities)
+	assert.Equal(t, venue.PayRange, spaces[0].PayRange, "rooms without pay use the venue's")
+	assert.Equal(t, []Amenity{AmenityParking, AmenityGreenRoom}, spaces[1].Amenities)
+	assert.Equal(t, 150, spaces[1].PayRange.Max)
+	assert.Equal(t, []Amenity{AmenityGreenRoom}, venue.Rooms[1].Amenities, "spaces leave the rooms alone")
+}
+
+func TestVenue_CheckRoom(t *testing.T) {
+	venue := NewVenue("The Independent", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+	assert.NoError(t, venue.CheckRoom(""))
+	assert.Error(t, venue.CheckRoom("main"))
+
+	room := NewRoom("Main Room", 500)
+	venue.Rooms = []Room{*room}
+	assert.Error(t, venue.CheckRoom(""), "venues with rooms are booked a room at a time")
+	assert.NoError(t, venue.CheckRoom(room.ID))
+	assert.Error(t, venue.CheckRoom("basement"))
+}
+
+func TestRoomBooked(t *testing.T) {
+	show := time.Date(2025, 3, 15, 20, 0, 0, 0, time.UTC)
+	main := NewBooking("artist-1", "venue-1", show, 500)
+	main.RoomID = "main"
+	cancelled := NewBooking("artist-2", "venue-1", show, 500)
+	cancelled.RoomID = "side"
+	cancelled.Cancel()
+	bookings := []*Booking{main, cancelled}
+
+	assert.True(t, RoomBooked(bookings, "main", show.Add(-18*time.Hour)), "any time that day clashes")
+	assert.False(t, RoomBooked(bookings, "main", show.Add(24*time.Hour)))
+	assert.False(t, RoomBooked(bookings, "side", show), "cancelled bookings free the room")
+	assert.True(t, RoomBooked(bookings, "", show), "booking the whole venue clashes with every room")
+
+	whole := NewBooking("artist-3", "venue-1", show.Add(48*time.Hour), 500)
+	assert.True(t, RoomBooked([]*Booking{whole}, "side", whole.EventDate), "a booking without a room takes them all")
+}
+
+func TestVenue_ValidateRooms(t *testing.T) {
+	newVenue := func(rooms ...Room) *Venue {
+		venue := NewVenue("The Independent", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+		venue.Rooms = rooms
+		return venue
+	}
+
+	assert.NoError(t, newVenue(Room{Name: "Main Room", Capacity: 500}).Validate(), "IDs are assigned on save")
+	assert.Error(t, newVenue(Room{Capacity: 500}).Validate())
+	assert.Error(t, newVenue(Room{Name: "Main Room", Capacity: -1}).Validate())
+	assert.Error(t, newVenue(Room{Name: "Main Room", Amenities: []Amenity{"hot_tub"}}).Validate())
+	assert.Error(t, newVenue(Room{Name: "Main Room", PayRange: &PayRange{Min: 500, Max: 100, Type: PaymentGuarantee}}).Validate())
+	assert.Error(t, newVenue(Room{ID: "a", Name: "Main Room"}, Room{ID: "a", Name: "Side Stage"}).Validate())
+}
+
+func TestVenue_AssignRoomIDs(t *testing.T) {
+	venue := NewVenue("The Independent", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+	venue.Rooms = []Room{{ID: "main", Name: "Main Room"}, {Name: "Side Stage"}}
+
+	venue.AssignRoomIDs()
+	assert.Equal(t, "main", venue.Rooms[0].ID)
+	assert.NotEmpty(t, venue.Rooms[1].ID)
+	assert.NotNil(t, venue.Rooms[1].Amenities)
+
+	clone := venue.Clone()
+	clone.Rooms[1].Amenities = append(clone.Rooms[1].Amenities, AmenityBackline)
+	assert.Empty(t, venue.Rooms[1].Amenities, "clones do not share rooms")
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTarget describes where new-match alerts for a saved search are sent
+type NotificationTarget struct {
+	Channel string `dynamodbav:"channel" json:"channel"` // "webhook" or "email"
+	Address string `dynamodbav:"address" json:"address"` // URL or email address
+}
+
+// SavedSearch is a named venue search that is re-evaluated as venues change
+type SavedSearch struct {
+	ID        string              `dynamodbav:"id" json:"id"`
+	UserID    string              `dynamodbav:"user_id" json:"user_id"`
+	Name      string              `dynamodbav:"name" json:"name"`
+	Criteria  VenueSearchCriteria `dynamodbav:"criteria" json:"criteria"`
+	Notify    *NotificationTarget `dynamodbav:"notify,omitempty" json:"notify,omitempty"`
+	CreatedAt time.Time           `dynamodbav:"created_at" json:"created_at"`
+	UpdatedAt time.Time           `dynamodbav:"updated_at" json:"updated_at"`
+}
+
+// NewSavedSearch creates a new saved search for a user
+func NewSavedSearch(userID, name string, criteria VenueSearchCriteria, notify *NotificationTarget) *SavedSearch {
+	now := time.Now()
+	return &SavedSearch{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Criteria:  criteria,
+		Notify:    notify,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SavedSearchMatch records a venue that matched a saved search
+type SavedSearchMatch struct {
+	SavedSearchID string     `dynamodbav:"saved_search_id" json:"saved_search_id"`
+	VenueID       string     `dynamodbav:"venue_id" json:"venue_id"`
+	VenueName     string     `dynamodbav:"venue_name" json:"venue_name"`
+	MatchedAt     time.Time  `dynamodbav:"matched_at" json:"matched_at"`
+	Baseline      bool       `dynamodbav:"baseline" json:"baseline"` // Matched when the search was saved, never alerted
+	NotifiedAt    *time.Time `dynamodbav:"notified_at,omitempty" json:"notified_at,omitempty"`
+}
+
+// NewSavedSearchMatch creates a match record for a venue
+func NewSavedSearchMatch(savedSearchID string, venue *Venue, baseline bool) *SavedSearchMatch {
+	return &SavedSearchMatch{
+		SavedSearchID: savedSearchID,
+		VenueID:       venue.ID,
+		VenueName:     venue.Name,
+		MatchedAt:     time.Now(),
+		Baseline:      baseline,
+	}
+}
+
+// MarkNotified records that an alert was delivered for the match
+func (m *SavedSearchMatch) MarkNotified() {
+	now := time.Now()
+	m.NotifiedAt = &now
+}
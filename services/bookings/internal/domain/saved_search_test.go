@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSavedSearch(t *testing.T) {
+	criteria := VenueSearchCriteria{City: "San Francisco", State: "CA", Limit: 10}
+	target := &NotificationTarget{Channel: "email", Address: "agent@example.com"}
+
+	search := NewSavedSearch("user-1", "SF clubs", criteria, target)
+
+	assert.NotEmpty(t, search.ID)
+	assert.Equal(t, "user-1", search.UserID)
+	assert.Equal(t, "SF clubs", search.Name)
+	assert.Equal(t, "San Francisco", search.Criteria.City)
+	assert.Equal(t, target, search.Notify)
+	assert.False(t, search.CreatedAt.IsZero())
+	assert.Equal(t, search.CreatedAt, search.UpdatedAt)
+}
+
+func TestNewSavedSearchMatch(t *testing.T) {
+	venue := NewVenue("Test Venue", GeoPoint{}, Address{}, []VenueType{VenueTypeClub}, SourceManual)
+
+	match := NewSavedSearchMatch("search-1", venue, false)
+
+	assert.Equal(t, "search-1", match.SavedSearchID)
+	assert.Equal(t, venue.ID, match.VenueID)
+	assert.Equal(t, "Test Venue", match.VenueName)
+	assert.False(t, match.Baseline)
+	assert.Nil(t, match.NotifiedAt)
+
+	match.MarkNotified()
+	assert.NotNil(t, match.NotifiedAt)
+}
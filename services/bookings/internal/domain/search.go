@@ -1,41 +1,45 @@
 package domain
 
+import "time"
+
 // VenueSearchCriteria represents search filters for venues
 type VenueSearchCriteria struct {
 	// Geographic filters
-	Location      *GeoPoint
-	RadiusKm      float64
-	City          string
-	State         string
-	Country       string
-	
+	Location *GeoPoint `dynamodbav:"location,omitempty" json:"location,omitempty"`
+	RadiusKm float64   `dynamodbav:"radius_km,omitempty" json:"radius_km,omitempty"`
+	City     string    `dynamodbav:"city,omitempty" json:"city,omitempty"`
+	State    string    `dynamodbav:"state,omitempty" json:"state,omitempty"`
+	Country  string    `dynamodbav:"country,omitempty" json:"country,omitempty"`
+
 	// Venue characteristics
-	VenueTypes    []VenueType
-	MinCapacity   int
-	MaxCapacity   int
-	Genres        []string
-	Amenities     []Amenity
-	
+	VenueTypes  []VenueType `dynamodbav:"venue_types,omitempty" json:"venue_types,omitempty"`
+	MinCapacity int         `dynamodbav:"min_capacity,omitempty" json:"min_capacity,omitempty"`
+	MaxCapacity int         `dynamodbav:"max_capacity,omitempty" json:"max_capacity,omitempty"`
+	Genres      []string    `dynamodbav:"genres,omitempty" json:"genres,omitempty"`
+	Amenities   []Amenity   `dynamodbav:"amenities,omitempty" json:"amenities,omitempty"`
+
 	// Payment filters
-	MinPay        int
-	MaxPay        int
-	PaymentTypes  []PaymentType
-	
+	MinPay       int           `dynamodbav:"min_pay,omitempty" json:"min_pay,omitempty"`
+	MaxPay       int           `dynamodbav:"max_pay,omitempty" json:"max_pay,omitempty"`
+	PaymentTypes []PaymentType `dynamodbav:"payment_types,omitempty" json:"payment_types,omitempty"`
+
 	// Availability
-	AvailableFrom *DateRange
-	
+	AvailableFrom *DateRange `dynamodbav:"available_from,omitempty" json:"available_from,omitempty"`
+	// Date leaves out rooms, and venues without rooms, booked that day
+	Date *time.Time `dynamodbav:"date,omitempty" json:"date,omitempty"`
+
 	// Quality filters
-	MinRating     float64
-	VerifiedOnly  bool
-	ActiveOnly    bool
-	
+	MinRating    float64 `dynamodbav:"min_rating,omitempty" json:"min_rating,omitempty"`
+	VerifiedOnly bool    `dynamodbav:"verified_only,omitempty" json:"verified_only,omitempty"`
+	ActiveOnly   bool    `dynamodbav:"active_only,omitempty" json:"active_only,omitempty"`
+
 	// Pagination
-	Limit         int
-	Offset        int
-	
+	Limit  int `dynamodbav:"limit,omitempty" json:"limit,omitempty"`
+	Offset int `dynamodbav:"offset,omitempty" json:"offset,omitempty"`
+
 	// Sorting
-	SortBy        VenueSortField
-	SortOrder     SortOrder
+	SortBy    VenueSortField `dynamodbav:"sort_by,omitempty" json:"sort_by,omitempty"`
+	SortOrder SortOrder      `dynamodbav:"sort_order,omitempty" json:"sort_order,omitempty"`
 }
 
 type VenueSortField string
@@ -69,4 +73,7 @@ type VenueSearchResult struct {
 type VenueWithDistance struct {
 	*Venue
 	DistanceKm float64 `json:"distance_km"`
+	// MatchingRooms are the IDs of the rooms that meet the filters, for
+	// venues with rooms
+	MatchingRooms []string `json:"matching_rooms,omitempty"`
 }
@@ -0,0 +1,167 @@
+package domain
+
+import "math"
+
+// Enrichment matching thresholds
+const (
+	// EnrichmentMatchThreshold is the minimum score for a source venue to be
+	// treated as the same place as one of ours
+	EnrichmentMatchThreshold = 0.75
+	// EnrichmentMaxDistanceKm is the distance beyond which a source venue
+	// never matches, however similar the name
+	EnrichmentMaxDistanceKm = 0.5
+	// enrichmentMinNameScore rejects neighbours with unrelated names
+	enrichmentMinNameScore = 0.6
+)
+
+// EnrichmentMatch is the best source candidate for one of our venues
+type EnrichmentMatch struct {
+	Candidate  *Venue
+	NameScore  float64
+	DistanceKm float64
+	Score      float64
+}
+
+// MatchForEnrichment picks the candidate from an external source that is
+// the same place as venue, judged by name similarity and distance. It
+// returns nil when no candidate is close and similar enough.
+func MatchForEnrichment(venue *Venue, candidates []*Venue) *EnrichmentMatch {
+	var best *EnrichmentMatch
+
+	for _, candidate := range candidates {
+		nameScore := nameSimilarity(venue.Name, candidate.Name)
+		distance := CalculateDistance(venue.Location.Latitude, venue.Location.Longitude, candidate.Location.Latitude, candidate.Location.Longitude)
+		if nameScore < enrichmentMinNameScore || distance > EnrichmentMaxDistanceKm {
+			continue
+		}
+
+		score := 0.7*nameScore + 0.3*(1-distance/EnrichmentMaxDistanceKm)
+		score = math.Round(score*1000) / 1000
+		if score < EnrichmentMatchThreshold {
+			continue
+		}
+		if best == nil || score > best.Score {
+			best = &EnrichmentMatch{Candidate: candidate, NameScore: nameScore, DistanceKm: distance, Score: score}
+		}
+	}
+
+	return best
+}
+
+// EnrichFromSource applies data from a secondary source to a venue we
+// already hold. Enrichment never renames or moves a venue. Capacity is
+// overwritten on unverified venues when the source is trusted more for it
+// than whoever wrote the current value; every other field, and every field
+// of a verified venue, is only filled when empty.
+func EnrichFromSource(venue, fetched *Venue) {
+	before := *venue
+
+	if venue.SongkickID == "" {
+		venue.SongkickID = fetched.SongkickID
+	}
+	if venue.BandsintownID == "" {
+		venue.BandsintownID = fetched.BandsintownID
+	}
+	if venue.GooglePlaceID == "" {
+		venue.GooglePlaceID = fetched.GooglePlaceID
+	}
+
+	switch {
+	case venue.Capacity == 0:
+		venue.Capacity = fetched.Capacity
+	case fetched.Capacity > 0 && !venue.Verified && venue.accepts(FieldCapacity, fetched.Source):
+		venue.Capacity = fetched.Capacity
+	}
+
+	if venue.Description == "" {
+		venue.Description = fetched.Description
+	}
+	mergeContactInfo(&venue.ContactInfo, fetched.ContactInfo)
+	venue.Address.FillFrom(fetched.Address)
+	venue.Photos = union(venue.Photos, fetched.Photos)
+
+	venue.RecordChanges(&before, fetched.Source, "")
+	venue.MarkSynced()
+}
+
+// RefreshFromSource applies a freshly fetched copy of a venue from an
+// external data source to the stored record. A field is overwritten only
+// when the source wrote its current value, so user edits and other sources'
+// data survive, and never on a verified venue; otherwise the source only
+// fills gaps. External IDs and photos are always combined.
+func RefreshFromSource(venue, fetched *Venue) {
+	before := *venue
+
+	if venue.SongkickID == "" {
+		venue.SongkickID = fetched.SongkickID
+	}
+	if venue.BandsintownID == "" {
+		venue.BandsintownID = fetched.BandsintownID
+	}
+	if venue.GooglePlaceID == "" {
+		venue.GooglePlaceID = fetched.GooglePlaceID
+	}
+
+	venue.Photos = union(venue.Photos, fetched.Photos)
+	if !onlyOtherType(fetched.VenueTypes) {
+		venue.VenueTypes = union(venue.VenueTypes, fetched.VenueTypes)
+	}
+
+	wins := func(field VenueField) bool {
+		return !venue.Verified && venue.FieldSource(field).Source == fetched.Source
+	}
+
+	if fetched.Name != "" && wins(FieldName) {
+		venue.Name = fetched.Name
+	}
+	// Clearing the geohash makes the service recompute it on save
+	if moved(venue.Location, fetched.Location) && wins(FieldLocation) {
+		venue.Location = GeoPoint{
+			Latitude:  fetched.Location.Latitude,
+			Longitude: fetched.Location.Longitude,
+		}
+	}
+	if fetched.Address.City != "" && wins(FieldAddress) {
+		venue.Address = fetched.Address
+	} else {
+		venue.Address.FillFrom(fetched.Address)
+	}
+	if fetched.ReviewCount > 0 && wins(FieldRating) {
+		venue.Rating = fetched.Rating
+		venue.ReviewCount = fetched.ReviewCount
+	}
+	if fetched.Capacity > 0 && (venue.Capacity == 0 || wins(FieldCapacity)) {
+		venue.Capacity = fetched.Capacity
+	}
+	if wins(FieldContactInfo) {
+		if fetched.ContactInfo.Website != "" {
+			venue.ContactInfo.Website = fetched.ContactInfo.Website
+		}
+		if fetched.ContactInfo.Phone != "" {
+			venue.ContactInfo.Phone = fetched.ContactInfo.Phone
+		}
+	}
+	mergeContactInfo(&venue.ContactInfo, fetched.ContactInfo)
+
+	venue.RecordChanges(&before, fetched.Source, "")
+	venue.MarkSynced()
+}
+
+// onlyOtherType reports whether a source had no specific type for the venue
+func onlyOtherType(types []VenueType) bool {
+	for _, t := range types {
+		if t != VenueTypeOther {
+			return false
+		}
+	}
+	return true
+}
+
+// moved reports whether a fetched location is set and differs from the
+// stored one
+func moved(stored, fetched GeoPoint) bool {
+	if fetched.Latitude == 0 && fetched.Longitude == 0 {
+		return false
+	}
+	return stored.Latitude != fetched.Latitude || stored.Longitude != fetched.Longitude
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshFromSource_SourceOwnedRecord(t *testing.T) {
+	venue := newDedupVenue("Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	venue.GooglePlaceID = "gp-1"
+	venue.Location.Geohash = "9q8yyk"
+	venue.Rating = 4.0
+	venue.ReviewCount = 10
+	venue.Photos = []string{"a.jpg"}
+
+	fetched := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7841, -122.4331)
+	fetched.GooglePlaceID = "gp-1"
+	fetched.Rating = 4.6
+	fetched.ReviewCount = 250
+	fetched.Photos = []string{"a.jpg", "b.jpg"}
+
+	RefreshFromSource(venue, fetched)
+
+	assert.Equal(t, "The Fillmore", venue.Name)
+	assert.Equal(t, 37.7841, venue.Location.Latitude)
+	assert.Empty(t, venue.Location.Geohash, "moved venues need a new geohash")
+	assert.Equal(t, 4.6, venue.Rating)
+	assert.Equal(t, 250, venue.ReviewCount)
+	assert.Equal(t, []string{"a.jpg", "b.jpg"}, venue.Photos)
+	assert.NotNil(t, venue.LastSyncedAt)
+}
+
+func TestRefreshFromSource_OtherSourceOnlyFillsGaps(t *testing.T) {
+	venue := newDedupVenue("Fillmore", "", 37.7840, -122.4330)
+	venue.Source = SourceUserSubmitted
+	venue.ContactInfo.Website = "https://fillmore.example"
+
+	fetched := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.9, -122.5)
+	fetched.GooglePlaceID = "gp-1"
+	fetched.Rating = 4.6
+	fetched.ReviewCount = 250
+	fetched.ContactInfo = ContactInfo{Website: "https://other.example", Phone: "555-0100"}
+	fetched.VenueTypes = []VenueType{VenueTypeOther}
+
+	RefreshFromSource(venue, fetched)
+
+	assert.Equal(t, "Fillmore", venue.Name)
+	assert.Equal(t, 37.7840, venue.Location.Latitude)
+	assert.Equal(t, "1805 Geary Blvd", venue.Address.Street)
+	assert.Equal(t, "gp-1", venue.GooglePlaceID)
+	assert.Equal(t, 0, venue.ReviewCount)
+	assert.Equal(t, "https://fillmore.example", venue.ContactInfo.Website)
+	assert.Equal(t, "555-0100", venue.ContactInfo.Phone)
+	assert.Equal(t, []VenueType{VenueTypeClub}, venue.VenueTypes)
+}
+
+func TestRefreshFromSource_VerifiedVenueKeepsUserData(t *testing.T) {
+	venue := newDedupVenue("The Fillmore", "1805 Geary Blvd", 37.7840, -122.4330)
+	venue.Verify()
+	venue.Capacity = 1150
+
+	fetched := newDedupVenue("Fillmore Auditorium", "1805 Geary Blvd", 37.79, -122.44)
+	fetched.Capacity = 1300
+
+	RefreshFromSource(venue, fetched)
+
+	assert.Equal(t, "The Fillmore", venue.Name)
+	assert.Equal(t, 37.7840, venue.Location.Latitude)
+	assert.Equal(t, 1150, venue.Capacity)
+}
+
+func TestMatchForEnrichment(t *testing.T) {
+	venue := newDedupVenue("The Independent", "628 Divisadero St", 37.7755, -122.4376)
+
+	sameName := newDedupVenue("Independent", "", 37.7756, -122.4377)
+	neighbour := newDedupVenue("Divisadero Pizza", "", 37.7755, -122.4376)
+	farAway := newDedupVenue("The Independent", "", 37.8044, -122.2712)
+
+	match := MatchForEnrichment(venue, []*Venue{neighbour, farAway, sameName})
+
+	if assert.NotNil(t, match) {
+		assert.Same(t, sameName, match.Candidate)
+		assert.GreaterOrEqual(t, match.Score, EnrichmentMatchThreshold)
+		assert.Less(t, match.DistanceKm, 0.05)
+	}
+	assert.Nil(t, MatchForEnrichment(venue, []*Venue{neighbour, farAway}))
+}
+
+func TestEnrichFromSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		verified     bool
+		capacity     int
+		wantCapacity int
+	}{
+		{"fills missing capacity", false, 0, 500},
+		{"songkick capacity wins on unverified venue", false, 300, 500},
+		{"verified capacity is kept", true, 300, 300},
+		{"verified venue still gets missing capacity", true, 0, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := newDedupVenue("The Chapel", "777 Valencia St", 37.7603, -122.4212)
+			venue.Capacity = tt.capacity
+			venue.Verified = tt.verified
+			venue.ContactInfo.Website = "https://ours.example"
+
+			fetched := newDedupVenue("Chapel", "", 37.7604, -122.4213)
+			fetched.Source = SourceSongkick
+			fetched.SongkickID = "sk-9"
+			fetched.Capacity = 500
+			fetched.ContactInfo = ContactInfo{Website: "https://theirs.example", Phone: "555-0100"}
+
+			EnrichFromSource(venue, fetched)
+
+			assert.Equal(t, tt.wantCapacity, venue.Capacity)
+			assert.Equal(t, "The Chapel", venue.Name)
+			assert.Equal(t, 37.7603, venue.Location.Latitude)
+			assert.Equal(t, "sk-9", venue.SongkickID)
+			assert.Equal(t, "https://ours.example", venue.ContactInfo.Website)
+			assert.Equal(t, "555-0100", venue.ContactInfo.Phone)
+			assert.NotNil(t, venue.LastSyncedAt)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -44,6 +45,15 @@ const (
 	SourceManual       DataSource = "manual"
 )
 
+// VenueFlag marks a venue as needing attention
+type VenueFlag string
+
+const (
+	// FlagLocationMismatch means the coordinates are far from where the
+	// stated address geocodes to
+	FlagLocationMismatch VenueFlag = "location_mismatch"
+)
+
 // GeoPoint represents a geographic coordinate
 type GeoPoint struct {
 	Latitude  float64 `dynamodbav:"latitude" json:"latitude"`
@@ -60,6 +70,25 @@ type Address struct {
 	Country    string `dynamodbav:"country" json:"country"`
 }
 
+// FillFrom copies the parts of src that are missing from the address
+func (a *Address) FillFrom(src Address) {
+	if a.Street == "" {
+		a.Street = src.Street
+	}
+	if a.City == "" {
+		a.City = src.City
+	}
+	if a.State == "" {
+		a.State = src.State
+	}
+	if a.PostalCode == "" {
+		a.PostalCode = src.PostalCode
+	}
+	if a.Country == "" {
+		a.Country = src.Country
+	}
+}
+
 // PayRange represents compensation range
 type PayRange struct {
 	Min      int         `dynamodbav:"min" json:"min"`
@@ -111,11 +140,14 @@ type Venue struct {
 	Genres       []string    `dynamodbav:"genres" json:"genres"`
 	PayRange     *PayRange   `dynamodbav:"pay_range,omitempty" json:"pay_range,omitempty"`
 	Amenities    []Amenity   `dynamodbav:"amenities" json:"amenities"`
-	Photos       []string    `dynamodbav:"photos" json:"photos"`
+	Photos       []string    `dynamodbav:"photos" json:"photos"` // Photo references imported from sources
+	Gallery      []VenuePhoto `dynamodbav:"gallery,omitempty" json:"gallery,omitempty"` // Uploaded photos, cover first
 	ContactInfo  ContactInfo `dynamodbav:"contact_info" json:"contact_info"`
 	Availability []DateRange `dynamodbav:"availability,omitempty" json:"availability,omitempty"`
+	Rooms        []Room      `dynamodbav:"rooms,omitempty" json:"rooms,omitempty"` // Rooms and stages booked separately
 	Rating       float64     `dynamodbav:"rating" json:"rating"`
 	ReviewCount  int         `dynamodbav:"review_count" json:"review_count"`
+	ReviewTotals ReviewScores `dynamodbav:"review_totals" json:"-"` // Sum of each score across reviews
 	Description  string      `dynamodbav:"description,omitempty" json:"description,omitempty"`
 	
 	// External IDs for syncing
@@ -126,10 +158,28 @@ type Venue struct {
 	Source         DataSource `dynamodbav:"source" json:"source"`
 	Verified       bool       `dynamodbav:"verified" json:"verified"`
 	Active         bool       `dynamodbav:"active" json:"active"`
+	Flags          []VenueFlag `dynamodbav:"flags,omitempty" json:"flags,omitempty"`
+	
+	// Community submissions wait in moderation before they are listed
+	ModerationStatus ModerationStatus  `dynamodbav:"moderation_status,omitempty" json:"moderation_status,omitempty"`
+	Moderation       *ModerationReview `dynamodbav:"moderation,omitempty" json:"moderation,omitempty"`
+	
+	// Users who have claimed the venue through a verified claim
+	OwnerIDs       []string   `dynamodbav:"owner_ids,omitempty" json:"owner_ids,omitempty"`
+	
+	// Deleted venues are hidden until restored or purged after VenueRetention
+	DeletedAt      *time.Time `dynamodbav:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy      string     `dynamodbav:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+	
+	// Who last wrote each tracked field, served separately from the venue
+	Provenance     map[VenueField]FieldProvenance `dynamodbav:"provenance,omitempty" json:"-"`
 	
 	CreatedAt      time.Time  `dynamodbav:"created_at" json:"created_at"`
 	UpdatedAt      time.Time  `dynamodbav:"updated_at" json:"updated_at"`
 	LastSyncedAt   *time.Time `dynamodbav:"last_synced_at,omitempty" json:"last_synced_at,omitempty"`
+	
+	// Version increments on every save; updates must carry the stored version
+	Version        int        `dynamodbav:"version" json:"version"`
 }
 
 // NewVenue creates a new venue
@@ -172,8 +222,194 @@ func (v *Venue) Verify() {
 	v.UpdatedAt = time.Now()
 }
 
+// ExternalIDs returns the venue's non-empty IDs in external data sources
+func (v *Venue) ExternalIDs() map[DataSource]string {
+	ids := make(map[DataSource]string)
+	if v.SongkickID != "" {
+		ids[SourceSongkick] = v.SongkickID
+	}
+	if v.BandsintownID != "" {
+		ids[SourceBandsintown] = v.BandsintownID
+	}
+	if v.GooglePlaceID != "" {
+		ids[SourceGooglePlaces] = v.GooglePlaceID
+	}
+	return ids
+}
+
+// HasLocation reports whether the venue has coordinates. Only the exact
+// point 0,0 counts as missing; either axis alone may legitimately be zero.
+func (v *Venue) HasLocation() bool {
+	return v.Location.Latitude != 0 || v.Location.Longitude != 0
+}
+
+// HasFlag reports whether the venue carries a flag
+func (v *Venue) HasFlag(flag VenueFlag) bool {
+	for _, f := range v.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFlag adds or removes a flag
+func (v *Venue) SetFlag(flag VenueFlag, on bool) {
+	if on == v.HasFlag(flag) {
+		return
+	}
+	if on {
+		v.Flags = append(v.Flags, flag)
+		return
+	}
+
+	flags := make([]VenueFlag, 0, len(v.Flags))
+	for _, f := range v.Flags {
+		if f != flag {
+			flags = append(flags, f)
+		}
+	}
+	v.Flags = flags
+}
+
+// Clone returns a copy of the venue that shares no slices, maps or
+// pointers with the original
+func (v *Venue) Clone() *Venue {
+	c := *v
+	c.VenueTypes = cloneSlice(v.VenueTypes)
+	c.Genres = cloneSlice(v.Genres)
+	c.Amenities = cloneSlice(v.Amenities)
+	c.Photos = cloneSlice(v.Photos)
+	c.Gallery = cloneSlice(v.Gallery)
+	c.Availability = cloneSlice(v.Availability)
+	if v.Rooms != nil {
+		c.Rooms = make([]Room, len(v.Rooms))
+		for i, room := range v.Rooms {
+			c.Rooms[i] = room.clone()
+		}
+	}
+	c.Flags = cloneSlice(v.Flags)
+	c.OwnerIDs = cloneSlice(v.OwnerIDs)
+	c.Moderation = v.Moderation.clone()
+	if v.PayRange != nil {
+		payRange := *v.PayRange
+		c.PayRange = &payRange
+	}
+	if v.LastSyncedAt != nil {
+		syncedAt := *v.LastSyncedAt
+		c.LastSyncedAt = &syncedAt
+	}
+	if v.DeletedAt != nil {
+		deletedAt := *v.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	if v.Provenance != nil {
+		c.Provenance = make(map[VenueField]FieldProvenance, len(v.Provenance))
+		for field, p := range v.Provenance {
+			c.Provenance[field] = p
+		}
+	}
+	return &c
+}
+
+// cloneSlice copies s, keeping nil and empty slices apart
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
 // Deactivate deactivates the venue
 func (v *Venue) Deactivate() {
 	v.Active = false
 	v.UpdatedAt = time.Now()
 }
+
+// Reactivate makes a deactivated venue active again
+func (v *Venue) Reactivate() {
+	v.Active = true
+	v.UpdatedAt = time.Now()
+}
+
+// validVenueTypes, validPaymentTypes and validAmenities list the values a
+// venue may carry
+var (
+	validVenueTypes = map[VenueType]bool{
+		VenueTypeClub: true, VenueTypeTheater: true, VenueTypeBrewery: true,
+		VenueTypeWinery: true, VenueTypeCoffeehouse: true, VenueTypeFestival: true,
+		VenueTypeBar: true, VenueTypeRestaurant: true, VenueTypeArena: true,
+		VenueTypeOther: true,
+	}
+	validPaymentTypes = map[PaymentType]bool{
+		PaymentGuarantee: true, PaymentDoorSplit: true, PaymentBarTab: true,
+		PaymentTicketSales: true, PaymentNone: true,
+	}
+	validAmenities = map[Amenity]bool{
+		AmenitySoundSystem: true, AmenityBackline: true, AmenityGreenRoom: true,
+		AmenityParking: true, AmenityLoadingDock: true, AmenityLighting: true,
+		AmenityRecording: true, AmenityLiveStream: true, AmenityMerchTable: true,
+		AmenityAccessible: true,
+	}
+)
+
+// Validate checks that the venue's fields hold values the service can store
+// and search on
+func (v *Venue) Validate() error {
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if v.Location.Latitude < -90 || v.Location.Latitude > 90 {
+		return fmt.Errorf("latitude %v is out of range", v.Location.Latitude)
+	}
+	if v.Location.Longitude < -180 || v.Location.Longitude > 180 {
+		return fmt.Errorf("longitude %v is out of range", v.Location.Longitude)
+	}
+	if len(v.VenueTypes) == 0 {
+		return fmt.Errorf("at least one venue type is required")
+	}
+	for _, t := range v.VenueTypes {
+		if !validVenueTypes[t] {
+			return fmt.Errorf("unknown venue type %q", t)
+		}
+	}
+	if v.Capacity < 0 {
+		return fmt.Errorf("capacity cannot be negative")
+	}
+	for _, a := range v.Amenities {
+		if !validAmenities[a] {
+			return fmt.Errorf("unknown amenity %q", a)
+		}
+	}
+	if err := validatePayRange(v.PayRange); err != nil {
+		return err
+	}
+	if err := validateAvailability(v.Availability); err != nil {
+		return err
+	}
+	return v.validateRooms()
+}
+
+// validatePayRange checks a pay range, which may be unset
+func validatePayRange(p *PayRange) error {
+	if p == nil {
+		return nil
+	}
+	if p.Min < 0 || p.Max < p.Min {
+		return fmt.Errorf("pay range %d-%d is invalid", p.Min, p.Max)
+	}
+	if !validPaymentTypes[p.Type] {
+		return fmt.Errorf("unknown payment type %q", p.Type)
+	}
+	return nil
+}
+
+// validateAvailability checks that every window ends after it starts
+func validateAvailability(windows []DateRange) error {
+	for _, r := range windows {
+		if !r.End.After(r.Start) {
+			return fmt.Errorf("availability must end after it starts")
+		}
+	}
+	return nil
+}
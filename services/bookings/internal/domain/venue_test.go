@@ -115,6 +115,50 @@ func TestVenue_Deactivate(t *testing.T) {
 	}
 }
 
+func TestVenue_ExternalIDs(t *testing.T) {
+	venue := &Venue{
+		ID:            "test-id",
+		GooglePlaceID: "place-1",
+		SongkickID:    "songkick-1",
+	}
+
+	ids := venue.ExternalIDs()
+
+	if len(ids) != 2 {
+		t.Fatalf("ExternalIDs() = %v, want 2 entries", ids)
+	}
+	if ids[SourceGooglePlaces] != "place-1" || ids[SourceSongkick] != "songkick-1" {
+		t.Errorf("ExternalIDs() = %v", ids)
+	}
+	if _, ok := ids[SourceBandsintown]; ok {
+		t.Error("ExternalIDs() should omit empty IDs")
+	}
+}
+
+func TestVenue_SetFlag(t *testing.T) {
+	venue := &Venue{ID: "test-id"}
+
+	venue.SetFlag(FlagLocationMismatch, true)
+	venue.SetFlag(FlagLocationMismatch, true)
+	if !venue.HasFlag(FlagLocationMismatch) || len(venue.Flags) != 1 {
+		t.Errorf("SetFlag(true) flags = %v, want one location_mismatch", venue.Flags)
+	}
+
+	venue.SetFlag(FlagLocationMismatch, false)
+	if venue.HasFlag(FlagLocationMismatch) {
+		t.Error("SetFlag(false) should clear the flag")
+	}
+}
+
+func TestVenue_HasLocation(t *testing.T) {
+	if (&Venue{}).HasLocation() {
+		t.Error("HasLocation() should be false at 0,0")
+	}
+	if !(&Venue{Location: GeoPoint{Latitude: 0, Longitude: -78.4558}}).HasLocation() {
+		t.Error("HasLocation() should accept a point on the equator")
+	}
+}
+
 func TestVenueTypes(t *testing.T) {
 	// Test that all venue type constants are defined
 	types := []VenueType{
@@ -192,3 +236,32 @@ func TestDataSources(t *testing.T) {
 		}
 	}
 }
+
+func TestVenue_Validate(t *testing.T) {
+	valid := func() *Venue {
+		return NewVenue("The Fillmore", GeoPoint{Latitude: 37.784, Longitude: -122.433},
+			Address{City: "San Francisco"}, []VenueType{VenueTypeTheater}, SourceManual)
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := map[string]func(v *Venue){
+		"missing name":        func(v *Venue) { v.Name = "" },
+		"no venue types":      func(v *Venue) { v.VenueTypes = nil },
+		"unknown venue type":  func(v *Venue) { v.VenueTypes = []VenueType{"castle"} },
+		"negative capacity":   func(v *Venue) { v.Capacity = -1 },
+		"unknown amenity":     func(v *Venue) { v.Amenities = []Amenity{"pool"} },
+		"latitude range":      func(v *Venue) { v.Location.Latitude = 91 },
+		"inverted pay range":  func(v *Venue) { v.PayRange = &PayRange{Min: 500, Max: 100, Type: PaymentGuarantee} },
+		"unknown pay type":    func(v *Venue) { v.PayRange = &PayRange{Min: 0, Max: 100, Type: "barter"} },
+		"backwards available": func(v *Venue) { v.Availability = []DateRange{{Start: time.Now(), End: time.Now().Add(-time.Hour)}} },
+	}
+	for name, mutate := range tests {
+		v := valid()
+		mutate(v)
+		if err := v.Validate(); err == nil {
+			t.Errorf("Validate() with %s should fail", name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package geocode converts between venue addresses and coordinates
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// Accuracy describes how precisely a result locates a place
+type Accuracy string
+
+const (
+	AccuracyAddress Accuracy = "address"
+	AccuracyCity    Accuracy = "city"
+)
+
+// Result is a geocoded place. Address parts the geocoder could not
+// determine are left empty.
+type Result struct {
+	Location domain.GeoPoint
+	Address  domain.Address
+	Accuracy Accuracy
+}
+
+// Geocoder resolves addresses to coordinates and back
+type Geocoder interface {
+	// Forward finds the coordinates of an address
+	Forward(ctx context.Context, address domain.Address) (*Result, error)
+	// Reverse finds the address at a point
+	Reverse(ctx context.Context, point domain.GeoPoint) (*Result, error)
+}
+
+// NotFoundError is returned when a geocoder has no result for a query
+type NotFoundError struct {
+	Query string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no geocoding result for %q", e.Query)
+}
+
+// Chain tries each geocoder in turn and returns the first result, so an
+// offline fallback can back up a hosted provider
+type Chain []Geocoder
+
+// NewChain creates a chain from geocoders in priority order
+func NewChain(geocoders ...Geocoder) Chain {
+	return Chain(geocoders)
+}
+
+// Forward returns the first geocoder's successful result
+func (c Chain) Forward(ctx context.Context, address domain.Address) (*Result, error) {
+	return c.first(func(g Geocoder) (*Result, error) { return g.Forward(ctx, address) })
+}
+
+// Reverse returns the first geocoder's successful result
+func (c Chain) Reverse(ctx context.Context, point domain.GeoPoint) (*Result, error) {
+	return c.first(func(g Geocoder) (*Result, error) { return g.Reverse(ctx, point) })
+}
+
+func (c Chain) first(call func(Geocoder) (*Result, error)) (*Result, error) {
+	var lastErr error = &NotFoundError{}
+	for _, g := range c {
+		result, err := call(g)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// FormatAddress renders an address as a single-line query
+func FormatAddress(address domain.Address) string {
+	parts := make([]string, 0, 5)
+	for _, part := range []string{address.Street, address.City, address.State, address.PostalCode, address.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
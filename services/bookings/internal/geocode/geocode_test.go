@@ -0,0 +1,71 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestOfflineGeocoder_Forward(t *testing.T) {
+	g, err := NewOfflineGeocoder()
+	if err != nil {
+		t.Fatalf("NewOfflineGeocoder() error = %v", err)
+	}
+
+	result, err := g.Forward(context.Background(), domain.Address{Street: "1 Main St", City: "austin", State: "TX"})
+	if err != nil {
+		t.Fatalf("Forward() error = %v", err)
+	}
+	if result.Accuracy != AccuracyCity || result.Address.City != "Austin" {
+		t.Errorf("Forward() = %+v, want Austin city centre", result)
+	}
+
+	if _, err := g.Forward(context.Background(), domain.Address{City: "Austin", State: "MN"}); err == nil {
+		t.Error("Forward() should not match a city in another state")
+	}
+}
+
+func TestOfflineGeocoder_Reverse(t *testing.T) {
+	g, _ := NewOfflineGeocoder()
+
+	result, err := g.Reverse(context.Background(), domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376})
+	if err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+	if result.Address.City != "San Francisco" || result.Address.State != "CA" || result.Address.Country != "US" {
+		t.Errorf("Reverse() address = %+v, want San Francisco", result.Address)
+	}
+
+	if _, err := g.Reverse(context.Background(), domain.GeoPoint{Latitude: 0.5, Longitude: -30}); err == nil {
+		t.Error("Reverse() should fail far from any known city")
+	}
+}
+
+type failingGeocoder struct{}
+
+func (failingGeocoder) Forward(ctx context.Context, address domain.Address) (*Result, error) {
+	return nil, errors.New("provider down")
+}
+
+func (failingGeocoder) Reverse(ctx context.Context, point domain.GeoPoint) (*Result, error) {
+	return nil, errors.New("provider down")
+}
+
+func TestChain_FallsBack(t *testing.T) {
+	offline, _ := NewOfflineGeocoder()
+	chain := NewChain(failingGeocoder{}, offline)
+
+	result, err := chain.Forward(context.Background(), domain.Address{City: "Denver", State: "CO"})
+	if err != nil {
+		t.Fatalf("Forward() error = %v", err)
+	}
+	if result.Address.City != "Denver" {
+		t.Errorf("Forward() = %+v, want offline result", result)
+	}
+
+	if _, err := NewChain(failingGeocoder{}).Reverse(context.Background(), domain.GeoPoint{}); err == nil {
+		t.Error("Reverse() should return the last error when every geocoder fails")
+	}
+}
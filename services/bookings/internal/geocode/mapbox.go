@@ -0,0 +1,159 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// DefaultMapboxURL is the Mapbox API endpoint
+const DefaultMapboxURL = "https://api.mapbox.com"
+
+// MapboxGeocoder uses the Mapbox Geocoding API (v5)
+type MapboxGeocoder struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewMapboxGeocoder creates a Mapbox geocoder. An empty baseURL uses the
+// public endpoint and a nil client a default with a timeout.
+func NewMapboxGeocoder(baseURL, token string, client *http.Client) *MapboxGeocoder {
+	if baseURL == "" {
+		baseURL = DefaultMapboxURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &MapboxGeocoder{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  client,
+	}
+}
+
+type mapboxContext struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	ShortCode string `json:"short_code"`
+}
+
+type mapboxFeature struct {
+	PlaceType []string        `json:"place_type"`
+	Text      string          `json:"text"`
+	Address   string          `json:"address"`
+	Center    []float64       `json:"center"`
+	Context   []mapboxContext `json:"context"`
+}
+
+type mapboxResponse struct {
+	Features []mapboxFeature `json:"features"`
+}
+
+// Forward geocodes an address to its best match
+func (g *MapboxGeocoder) Forward(ctx context.Context, address domain.Address) (*Result, error) {
+	query := FormatAddress(address)
+	if query == "" {
+		return nil, &NotFoundError{Query: query}
+	}
+
+	params := url.Values{"types": {"address,poi,place"}}
+	if len(address.Country) == 2 {
+		params.Set("country", strings.ToLower(address.Country))
+	}
+	return g.lookup(ctx, query, params)
+}
+
+// Reverse geocodes a point to the nearest address
+func (g *MapboxGeocoder) Reverse(ctx context.Context, point domain.GeoPoint) (*Result, error) {
+	query := strconv.FormatFloat(point.Longitude, 'f', 6, 64) + "," + strconv.FormatFloat(point.Latitude, 'f', 6, 64)
+	result, err := g.lookup(ctx, query, url.Values{"types": {"address,place"}})
+	if err != nil {
+		return nil, err
+	}
+	result.Location = point
+	return result, nil
+}
+
+func (g *MapboxGeocoder) lookup(ctx context.Context, query string, params url.Values) (*Result, error) {
+	params.Set("access_token", g.token)
+	params.Set("limit", "1")
+	endpoint := g.baseURL + "/geocoding/v5/mapbox.places/" + url.PathEscape(query) + ".json?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build geocoding request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to geocode: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mapbox returned status %d", resp.StatusCode)
+	}
+
+	var body mapboxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(body.Features) == 0 || len(body.Features[0].Center) != 2 {
+		return nil, &NotFoundError{Query: query}
+	}
+
+	return mapFeature(&body.Features[0]), nil
+}
+
+// mapFeature converts a Mapbox feature and its context into a result
+func mapFeature(feature *mapboxFeature) *Result {
+	lat, lng := feature.Center[1], feature.Center[0]
+	result := &Result{
+		Location: domain.GeoPoint{
+			Latitude:  lat,
+			Longitude: lng,
+			Geohash:   domain.EncodeGeohash(lat, lng, 6),
+		},
+		Accuracy: AccuracyCity,
+	}
+
+	for _, placeType := range feature.PlaceType {
+		switch placeType {
+		case "address", "poi":
+			result.Accuracy = AccuracyAddress
+			if placeType == "address" {
+				result.Address.Street = strings.TrimSpace(feature.Address + " " + feature.Text)
+			}
+		case "place":
+			result.Address.City = feature.Text
+		}
+	}
+
+	for _, c := range feature.Context {
+		switch strings.SplitN(c.ID, ".", 2)[0] {
+		case "postcode":
+			result.Address.PostalCode = c.Text
+		case "place":
+			result.Address.City = c.Text
+		case "region":
+			// Region short codes look like "US-CA"
+			if _, state, ok := strings.Cut(c.ShortCode, "-"); ok {
+				result.Address.State = state
+			} else {
+				result.Address.State = c.Text
+			}
+		case "country":
+			result.Address.Country = strings.ToUpper(c.ShortCode)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,99 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const mapboxAddressResponse = `{
+  "type": "FeatureCollection",
+  "features": [{
+    "place_type": ["address"],
+    "relevance": 1,
+    "address": "628",
+    "text": "Divisadero Street",
+    "center": [-122.4376, 37.7755],
+    "context": [
+      {"id": "neighborhood.1", "text": "Western Addition"},
+      {"id": "postcode.2", "text": "94117"},
+      {"id": "place.3", "text": "San Francisco"},
+      {"id": "region.4", "short_code": "US-CA", "text": "California"},
+      {"id": "country.5", "short_code": "us", "text": "United States"}
+    ]
+  }]
+}`
+
+func newMapboxStub(t *testing.T, body string) (*httptest.Server, *string) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &path
+}
+
+func TestMapboxGeocoder_Forward(t *testing.T) {
+	server, path := newMapboxStub(t, mapboxAddressResponse)
+	g := NewMapboxGeocoder(server.URL, "token", server.Client())
+
+	result, err := g.Forward(context.Background(), domain.Address{Street: "628 Divisadero St", City: "San Francisco", State: "CA"})
+	if err != nil {
+		t.Fatalf("Forward() error = %v", err)
+	}
+
+	if !strings.Contains(*path, "628 Divisadero St, San Francisco, CA") {
+		t.Errorf("request path = %v, want the formatted address", *path)
+	}
+	if result.Location.Latitude != 37.7755 || result.Location.Longitude != -122.4376 {
+		t.Errorf("Forward() location = %+v", result.Location)
+	}
+	if result.Location.Geohash == "" {
+		t.Error("Forward() should set a geohash")
+	}
+	if result.Accuracy != AccuracyAddress {
+		t.Errorf("Forward() accuracy = %v, want address", result.Accuracy)
+	}
+	want := domain.Address{Street: "628 Divisadero Street", City: "San Francisco", State: "CA", PostalCode: "94117", Country: "US"}
+	if result.Address != want {
+		t.Errorf("Forward() address = %+v, want %+v", result.Address, want)
+	}
+}
+
+func TestMapboxGeocoder_Reverse(t *testing.T) {
+	server, path := newMapboxStub(t, mapboxAddressResponse)
+	g := NewMapboxGeocoder(server.URL, "token", server.Client())
+
+	point := domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376}
+	result, err := g.Reverse(context.Background(), point)
+	if err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+
+	if !strings.HasSuffix(*path, "/-122.437600,37.775500.json") {
+		t.Errorf("request path = %v, want lng,lat query", *path)
+	}
+	if result.Location != point || result.Address.City != "San Francisco" {
+		t.Errorf("Reverse() = %+v", result)
+	}
+}
+
+func TestMapboxGeocoder_NoResult(t *testing.T) {
+	server, _ := newMapboxStub(t, `{"type": "FeatureCollection", "features": []}`)
+	g := NewMapboxGeocoder(server.URL, "token", server.Client())
+
+	_, err := g.Forward(context.Background(), domain.Address{City: "Nowhere"})
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Forward() error = %v, want NotFoundError", err)
+	}
+}
@@ -0,0 +1,98 @@
+package geocode
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// offlineMaxReverseKm is how far a point may be from a known city centre
+// for reverse geocoding to name that city
+const offlineMaxReverseKm = 40.0
+
+//go:embed cities.csv
+var citiesCSV string
+
+type city struct {
+	address  domain.Address
+	location domain.GeoPoint
+}
+
+// OfflineGeocoder resolves cities from an embedded gazetteer of city
+// centres. It needs no network access and is used when the hosted
+// geocoder is unavailable; results are city-accurate at best.
+type OfflineGeocoder struct {
+	cities []city
+}
+
+// NewOfflineGeocoder loads the embedded gazetteer
+func NewOfflineGeocoder() (*OfflineGeocoder, error) {
+	records, err := csv.NewReader(strings.NewReader(citiesCSV)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gazetteer: %w", err)
+	}
+
+	g := &OfflineGeocoder{cities: make([]city, 0, len(records))}
+	for _, record := range records[1:] {
+		lat, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latitude for %s: %w", record[0], err)
+		}
+		lng, err := strconv.ParseFloat(record[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid longitude for %s: %w", record[0], err)
+		}
+		g.cities = append(g.cities, city{
+			address:  domain.Address{City: record[0], State: record[1], Country: record[2]},
+			location: domain.GeoPoint{Latitude: lat, Longitude: lng},
+		})
+	}
+
+	return g, nil
+}
+
+// Forward returns the centre of the address's city
+func (g *OfflineGeocoder) Forward(ctx context.Context, address domain.Address) (*Result, error) {
+	for _, c := range g.cities {
+		if !strings.EqualFold(c.address.City, strings.TrimSpace(address.City)) {
+			continue
+		}
+		if address.State != "" && c.address.State != "" && !strings.EqualFold(c.address.State, strings.TrimSpace(address.State)) {
+			continue
+		}
+		if address.Country != "" && !strings.EqualFold(c.address.Country, strings.TrimSpace(address.Country)) {
+			continue
+		}
+
+		location := c.location
+		location.Geohash = domain.EncodeGeohash(location.Latitude, location.Longitude, 6)
+		return &Result{Location: location, Address: c.address, Accuracy: AccuracyCity}, nil
+	}
+
+	return nil, &NotFoundError{Query: FormatAddress(address)}
+}
+
+// Reverse returns the nearest known city within offlineMaxReverseKm
+func (g *OfflineGeocoder) Reverse(ctx context.Context, point domain.GeoPoint) (*Result, error) {
+	var nearest *city
+	nearestKm := offlineMaxReverseKm
+
+	for i := range g.cities {
+		c := &g.cities[i]
+		distance := domain.CalculateDistance(point.Latitude, point.Longitude, c.location.Latitude, c.location.Longitude)
+		if distance <= nearestKm {
+			nearest = c
+			nearestKm = distance
+		}
+	}
+
+	if nearest == nil {
+		return nil, &NotFoundError{Query: fmt.Sprintf("%f,%f", point.Latitude, point.Longitude)}
+	}
+	return &Result{Location: point, Address: nearest.address, Accuracy: AccuracyCity}, nil
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ClaimHandler struct {
+	service *service.ClaimService
+}
+
+func NewClaimHandler(service *service.ClaimService) *ClaimHandler {
+	return &ClaimHandler{service: service}
+}
+
+// CreateClaimRequest represents the request body for claiming a venue
+type CreateClaimRequest struct {
+	Channel domain.ClaimChannel `json:"channel,omitempty"`
+}
+
+// ConfirmClaimRequest represents the request body for confirming a claim
+type ConfirmClaimRequest struct {
+	Code string `json:"code"`
+}
+
+// Create starts a claim on a venue and sends a verification code to the
+// venue's listed email address or phone
+// POST /api/v1/venues/{id}/claims
+func (h *ClaimHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateClaimRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Channel != "" && req.Channel != domain.ClaimByEmail && req.Channel != domain.ClaimBySMS {
+		http.Error(w, "channel must be email or sms", http.StatusBadRequest)
+		return
+	}
+
+	claim, err := h.service.Request(r.Context(), chi.URLParam(r, "id"), userID, req.Channel)
+	if err != nil {
+		writeClaimError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(claim); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Confirm checks a claim's verification code and, if it matches, makes the
+// caller an owner of the venue and marks the venue verified
+// POST /api/v1/venue-claims/{id}/confirm
+func (h *ClaimHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	var req ConfirmClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	venue, err := h.service.Confirm(r.Context(), chi.URLParam(r, "id"), userID, req.Code)
+	if err != nil {
+		writeClaimError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
+	if err := json.NewEncoder(w).Encode(venue); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeClaimError maps claim errors to HTTP status codes
+func writeClaimError(w http.ResponseWriter, err error) {
+	var venueNotFound *repository.VenueNotFoundError
+	var claimNotFound *repository.ClaimNotFoundError
+	switch {
+	case errors.As(err, &venueNotFound):
+		http.Error(w, "venue not found", http.StatusNotFound)
+	case errors.As(err, &claimNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrNoClaimContact):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, service.ErrAlreadyOwner), errors.Is(err, service.ErrClaimClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidClaimCode):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrTooManyClaims):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		writeVenueSaveError(w, err)
+	}
+}
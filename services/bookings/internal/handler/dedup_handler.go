@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type DedupHandler struct {
+	service *service.DedupService
+	venues  *service.VenueService
+}
+
+func NewDedupHandler(service *service.DedupService, venues *service.VenueService) *DedupHandler {
+	return &DedupHandler{service: service, venues: venues}
+}
+
+// MergeVenueRequest represents the request body for merging a venue into another
+type MergeVenueRequest struct {
+	VenueID string `json:"venue_id"`
+}
+
+// ResolveDuplicateRequest represents the request body for merging a queued pair
+type ResolveDuplicateRequest struct {
+	SurvivorID string `json:"survivor_id,omitempty"`
+}
+
+// FindDuplicates scores likely duplicates of a venue without queueing them
+// GET /api/v1/venues/{id}/duplicates
+func (h *DedupHandler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	venue, err := h.venues.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "venue not found", http.StatusNotFound)
+		return
+	}
+
+	candidates, err := h.service.FindDuplicates(r.Context(), venue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(candidates); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Scan queues ambiguous duplicates of a venue for review and merges
+// near-certain ones. Admins only, as it can merge.
+// POST /api/v1/venues/{id}/duplicates/scan
+func (h *DedupHandler) Scan(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	result, err := h.service.Scan(r.Context(), chi.URLParam(r, "id"), userIDFromRequest(r))
+	if err != nil {
+		var notFound *repository.VenueNotFoundError
+		if errors.As(err, &notFound) {
+			http.Error(w, "venue not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Merge folds the venue named in the body into the venue in the URL. Admins only.
+// POST /api/v1/venues/{id}/merge
+func (h *DedupHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req MergeVenueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.VenueID == "" {
+		http.Error(w, "venue_id is required", http.StatusBadRequest)
+		return
+	}
+
+	survivor, err := h.service.Merge(r.Context(), chi.URLParam(r, "id"), req.VenueID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(survivor); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ListQueue lists duplicate pairs by review status
+// GET /api/v1/venue-duplicates
+func (h *DedupHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	status := domain.DuplicatePending
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = domain.DuplicateStatus(s)
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	candidates, err := h.service.ListQueue(r.Context(), status, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(candidates); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// MergeCandidate resolves a queued pair by merging it. Admins only.
+// POST /api/v1/venue-duplicates/{id}/merge
+func (h *DedupHandler) MergeCandidate(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req ResolveDuplicateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	survivor, err := h.service.MergeCandidate(r.Context(), chi.URLParam(r, "id"), req.SurvivorID, userIDFromRequest(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(survivor); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Dismiss resolves a queued pair as not being duplicates. Admins only.
+// POST /api/v1/venue-duplicates/{id}/dismiss
+func (h *DedupHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	candidate, err := h.service.Dismiss(r.Context(), chi.URLParam(r, "id"), userIDFromRequest(r))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(candidate); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeError maps dedup errors to HTTP status codes
+func (h *DedupHandler) writeError(w http.ResponseWriter, err error) {
+	var venueNotFound *repository.VenueNotFoundError
+	var candidateNotFound *repository.DuplicateNotFoundError
+	switch {
+	case errors.As(err, &venueNotFound), errors.As(err, &candidateNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
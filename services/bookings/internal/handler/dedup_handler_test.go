@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+func newTestDedupHandler() (*DedupHandler, *service.VenueService, *repository.MockDuplicateRepository) {
+	venueRepo := repository.NewMockVenueRepository()
+	venues := service.NewVenueService(venueRepo)
+	duplicates := repository.NewMockDuplicateRepository()
+	venueRepo.SetRedirects(duplicates)
+	venues.SetRedirectResolver(duplicates)
+	svc := service.NewDedupService(venues, repository.NewMockBookingRepository(), duplicates)
+	return NewDedupHandler(svc, venues), venues, duplicates
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestDedupHandler_Merge(t *testing.T) {
+	handler, venues, _ := newTestDedupHandler()
+	ctx := context.Background()
+
+	survivor := domain.NewVenue("The Fillmore", domain.GeoPoint{Latitude: 37.784, Longitude: -122.433},
+		domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceGooglePlaces)
+	merged := domain.NewVenue("Fillmore", domain.GeoPoint{Latitude: 37.784, Longitude: -122.433},
+		domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeTheater}, domain.SourceSongkick)
+	merged.SongkickID = "sk-1"
+	_ = venues.Create(ctx, survivor)
+	_ = venues.Create(ctx, merged)
+
+	body, _ := json.Marshal(MergeVenueRequest{VenueID: merged.ID})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/venues/"+survivor.ID+"/merge", bytes.NewReader(body))
+	req.Header.Set("X-User-Role", "admin")
+	req = withURLParam(req, "id", survivor.ID)
+	w := httptest.NewRecorder()
+
+	handler.Merge(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Merge() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var result domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.SongkickID != "sk-1" || len(result.VenueTypes) != 2 {
+		t.Errorf("Merge() = %+v, want combined external IDs and venue types", result)
+	}
+}
+
+func TestDedupHandler_Merge_NotFound(t *testing.T) {
+	handler, _, _ := newTestDedupHandler()
+
+	body, _ := json.Marshal(MergeVenueRequest{VenueID: "missing-2"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/venues/missing-1/merge", bytes.NewReader(body))
+	req.Header.Set("X-User-Role", "admin")
+	req = withURLParam(req, "id", "missing-1")
+	w := httptest.NewRecorder()
+
+	handler.Merge(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Merge() status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestDedupHandler_QueueAndDismiss(t *testing.T) {
+	handler, _, duplicates := newTestDedupHandler()
+
+	a := domain.NewVenue("A", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	b := domain.NewVenue("B", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	candidate := domain.NewDuplicateCandidate(a, b, domain.DuplicateScore{Total: 0.7})
+	_, _ = duplicates.SaveCandidate(context.Background(), candidate)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/venue-duplicates?status=pending", nil)
+	w := httptest.NewRecorder()
+	handler.ListQueue(w, req)
+
+	var queue []domain.DuplicateCandidate
+	if err := json.NewDecoder(w.Body).Decode(&queue); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(queue) != 1 {
+		t.Fatalf("ListQueue() returned %v candidates, want 1", len(queue))
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/venue-duplicates/"+candidate.ID+"/dismiss", nil)
+	req.Header.Set("X-User-ID", "moderator-1")
+	req.Header.Set("X-User-Role", "admin")
+	req = withURLParam(req, "id", candidate.ID)
+	w = httptest.NewRecorder()
+	handler.Dismiss(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Dismiss() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var dismissed domain.DuplicateCandidate
+	if err := json.NewDecoder(w.Body).Decode(&dismissed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if dismissed.Status != domain.DuplicateDismissed || dismissed.ResolvedBy != "moderator-1" {
+		t.Errorf("Dismiss() = %v by %v, want dismissed by moderator-1", dismissed.Status, dismissed.ResolvedBy)
+	}
+}
+
+func TestDedupHandler_RequiresAdmin(t *testing.T) {
+	handler, venues, duplicates := newTestDedupHandler()
+	ctx := context.Background()
+
+	a := domain.NewVenue("The Fillmore", domain.GeoPoint{Latitude: 37.784, Longitude: -122.433},
+		domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceGooglePlaces)
+	b := domain.NewVenue("Fillmore", domain.GeoPoint{Latitude: 37.784, Longitude: -122.433},
+		domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceSongkick)
+	_ = venues.Create(ctx, a)
+	_ = venues.Create(ctx, b)
+	candidate := domain.NewDuplicateCandidate(a, b, domain.DuplicateScore{Total: 0.7})
+	_, _ = duplicates.SaveCandidate(ctx, candidate)
+
+	actions := []struct {
+		name   string
+		id     string
+		body   []byte
+		handle http.HandlerFunc
+	}{
+		{"Scan", a.ID, nil, handler.Scan},
+		{"Merge", a.ID, []byte(`{"venue_id":"` + b.ID + `"}`), handler.Merge},
+		{"MergeCandidate", candidate.ID, nil, handler.MergeCandidate},
+		{"Dismiss", candidate.ID, nil, handler.Dismiss},
+	}
+	for _, action := range actions {
+		for _, role := range []string{"", "contributor"} {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(action.body))
+			req.Header.Set("X-User-ID", "user-1")
+			if role != "" {
+				req.Header.Set("X-User-Role", role)
+			}
+			req = withURLParam(req, "id", action.id)
+			w := httptest.NewRecorder()
+
+			action.handle(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("%s() as %q status = %v, want %v", action.name, role, w.Code, http.StatusForbidden)
+			}
+		}
+	}
+
+	if _, err := venues.GetByID(ctx, b.ID); err != nil {
+		t.Errorf("venue B should survive refused merges: %v", err)
+	}
+	pending, _ := duplicates.ListCandidates(ctx, domain.DuplicatePending, 10)
+	if len(pending) != 1 {
+		t.Errorf("pending candidates = %v, want the pair left unresolved", len(pending))
+	}
+}
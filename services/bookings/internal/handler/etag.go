@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// etag renders a stored version as a strong entity tag
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setETag advertises the version a client must send back in If-Match
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// checkIfMatch requires an If-Match header naming the current version. It
+// writes 428 when the header is missing and 412 when it is stale, and
+// reports whether the request may proceed.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, version int) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		http.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		return false
+	}
+
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == current {
+			return true
+		}
+	}
+
+	http.Error(w, "resource has been modified", http.StatusPreconditionFailed)
+	return false
+}
+
+// isVersionConflict reports whether a save lost a race with another writer
+func isVersionConflict(err error) bool {
+	var conflict *repository.VersionConflictError
+	return errors.As(err, &conflict)
+}
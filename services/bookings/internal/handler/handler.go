@@ -2,25 +2,29 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
 	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
 	"github.com/go-chi/chi/v5"
 )
 
 type BookingHandler struct {
-	repo *repository.BookingRepository
+	repo   repository.BookingRepository
+	venues *service.VenueService
 }
 
-func NewBookingHandler(repo *repository.BookingRepository) *BookingHandler {
-	return &BookingHandler{repo: repo}
+func NewBookingHandler(repo repository.BookingRepository, venues *service.VenueService) *BookingHandler {
+	return &BookingHandler{repo: repo, venues: venues}
 }
 
 type CreateBookingRequest struct {
 	ArtistID  string    `json:"artist_id"`
 	VenueID   string    `json:"venue_id"`
+	RoomID    string    `json:"room_id,omitempty"`
 	EventDate time.Time `json:"event_date"`
 	Fee       float64   `json:"fee"`
 }
@@ -32,13 +36,42 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	booking := domain.NewBooking(req.ArtistID, req.VenueID, req.EventDate, req.Fee)
+	// Venues with rooms are booked a room at a time
+	venue, err := h.venues.GetByID(r.Context(), req.VenueID)
+	if err != nil {
+		var notFound *repository.VenueNotFoundError
+		if errors.As(err, &notFound) {
+			http.Error(w, "venue not found", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := venue.CheckRoom(req.RoomID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A room, or a venue without rooms, takes one booking a day
+	existing, err := h.repo.ListByVenue(r.Context(), venue.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if domain.RoomBooked(existing, req.RoomID, req.EventDate) {
+		http.Error(w, "room is already booked on that date", http.StatusConflict)
+		return
+	}
+
+	booking := domain.NewBooking(req.ArtistID, venue.ID, req.EventDate, req.Fee)
+	booking.RoomID = req.RoomID
 	if err := h.repo.Create(r.Context(), booking); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, booking.Version)
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(booking); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -61,6 +94,7 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, booking.Version)
 	if err := json.NewEncoder(w).Encode(booking); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -80,14 +114,22 @@ func (h *BookingHandler) Confirm(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "booking not found", http.StatusNotFound)
 		return
 	}
+	if !checkIfMatch(w, r, booking.Version) {
+		return
+	}
 
 	booking.Confirm()
 	if err := h.repo.Update(r.Context(), booking); err != nil {
+		if isVersionConflict(err) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, booking.Version)
 	if err := json.NewEncoder(w).Encode(booking); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestBookingHandler_Confirm_IfMatch(t *testing.T) {
+	repo := repository.NewMockBookingRepository()
+	handler := NewBookingHandler(repo, service.NewVenueService(repository.NewMockVenueRepository()))
+
+	booking := domain.NewBooking("artist-1", "venue-1", time.Now().Add(24*time.Hour), 500)
+	_ = repo.Create(context.Background(), booking)
+
+	confirm := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+booking.ID+"/confirm", nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", booking.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.Confirm(w, req)
+		return w
+	}
+
+	if w := confirm(""); w.Code != http.StatusPreconditionRequired {
+		t.Errorf("Confirm() without If-Match status = %v, want %v", w.Code, http.StatusPreconditionRequired)
+	}
+
+	w := confirm(`"1"`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Confirm() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("Confirm() ETag = %v, want \"2\"", etag)
+	}
+
+	// A second agent still holding version 1 must not clobber the confirmation
+	if w := confirm(`"1"`); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("Confirm() with stale If-Match status = %v, want %v", w.Code, http.StatusPreconditionFailed)
+	}
+}
+
+func TestBookingHandler_Create_Room(t *testing.T) {
+	venueRepo := repository.NewMockVenueRepository()
+	handler := NewBookingHandler(repository.NewMockBookingRepository(), service.NewVenueService(venueRepo))
+
+	hall := domain.NewVenue("Hall", domain.GeoPoint{Latitude: 40.7, Longitude: -74}, domain.Address{City: "New York", State: "NY"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	sideStage := domain.NewRoom("Side Stage", 150)
+	hall.Rooms = []domain.Room{*domain.NewRoom("Main Room", 800), *sideStage}
+	bar := domain.NewVenue("Bar", domain.GeoPoint{Latitude: 40.7, Longitude: -74}, domain.Address{City: "New York", State: "NY"}, []domain.VenueType{domain.VenueTypeBar}, domain.SourceManual)
+	for _, v := range []*domain.Venue{hall, bar} {
+		if err := venueRepo.Create(context.Background(), v); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	create := func(venueID, roomID string) *httptest.ResponseRecorder {
+		body := `{"artist_id":"artist-1","venue_id":"` + venueID + `","room_id":"` + roomID + `","event_date":"2030-05-01T20:00:00Z","fee":500}`
+		w := httptest.NewRecorder()
+		handler.Create(w, httptest.NewRequest(http.MethodPost, "/api/v1/bookings", strings.NewReader(body)))
+		return w
+	}
+
+	tests := []struct {
+		name    string
+		venueID string
+		roomID  string
+		want    int
+	}{
+		{"room at venue with rooms", hall.ID, sideStage.ID, http.StatusCreated},
+		{"no room at venue with rooms", hall.ID, "", http.StatusBadRequest},
+		{"unknown room", hall.ID, "basement", http.StatusBadRequest},
+		{"venue without rooms", bar.ID, "", http.StatusCreated},
+		{"room at venue without rooms", bar.ID, sideStage.ID, http.StatusBadRequest},
+		{"unknown venue", "missing", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := create(tt.venueID, tt.roomID)
+			if w.Code != tt.want {
+				t.Fatalf("Create() status = %v, want %v. Body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if w.Code != http.StatusCreated {
+				return
+			}
+			var booking domain.Booking
+			if err := json.NewDecoder(w.Body).Decode(&booking); err != nil {
+				t.Fatalf("decode booking: %v", err)
+			}
+			if booking.RoomID != tt.roomID {
+				t.Errorf("RoomID = %q, want %q", booking.RoomID, tt.roomID)
+			}
+		})
+	}
+}
+
+func TestBookingHandler_Create_RoomAlreadyBooked(t *testing.T) {
+	venueRepo := repository.NewMockVenueRepository()
+	bookingRepo := repository.NewMockBookingRepository()
+	handler := NewBookingHandler(bookingRepo, service.NewVenueService(venueRepo))
+
+	hall := domain.NewVenue("Hall", domain.GeoPoint{Latitude: 40.7, Longitude: -74}, domain.Address{City: "New York", State: "NY"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	mainRoom := domain.NewRoom("Main Room", 800)
+	sideStage := domain.NewRoom("Side Stage", 150)
+	hall.Rooms = []domain.Room{*mainRoom, *sideStage}
+	bar := domain.NewVenue("Bar", domain.GeoPoint{Latitude: 40.7, Longitude: -74}, domain.Address{City: "New York", State: "NY"}, []domain.VenueType{domain.VenueTypeBar}, domain.SourceManual)
+	for _, v := range []*domain.Venue{hall, bar} {
+		if err := venueRepo.Create(context.Background(), v); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	cancelled := domain.NewBooking("artist-0", hall.ID, time.Date(2030, 5, 2, 20, 0, 0, 0, time.UTC), 500)
+	cancelled.RoomID = sideStage.ID
+	cancelled.Cancel()
+	_ = bookingRepo.Create(context.Background(), cancelled)
+
+	create := func(venueID, roomID, eventDate string) int {
+		body := `{"artist_id":"artist-1","venue_id":"` + venueID + `","room_id":"` + roomID + `","event_date":"` + eventDate + `","fee":500}`
+		w := httptest.NewRecorder()
+		handler.Create(w, httptest.NewRequest(http.MethodPost, "/api/v1/bookings", strings.NewReader(body)))
+		return w.Code
+	}
+
+	tests := []struct {
+		name      string
+		venueID   string
+		roomID    string
+		eventDate string
+		want      int
+	}{
+		{"free room", hall.ID, mainRoom.ID, "2030-05-01T20:00:00Z", http.StatusCreated},
+		{"same room later that day", hall.ID, mainRoom.ID, "2030-05-01T23:00:00Z", http.StatusConflict},
+		{"other room that day", hall.ID, sideStage.ID, "2030-05-01T20:00:00Z", http.StatusCreated},
+		{"same room next day", hall.ID, mainRoom.ID, "2030-05-02T20:00:00Z", http.StatusCreated},
+		{"room freed by a cancellation", hall.ID, sideStage.ID, "2030-05-02T20:00:00Z", http.StatusCreated},
+		{"venue without rooms", bar.ID, "", "2030-05-01T20:00:00Z", http.StatusCreated},
+		{"venue without rooms booked that day", bar.ID, "", "2030-05-01T21:00:00Z", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if got := create(tt.venueID, tt.roomID, tt.eventDate); got != tt.want {
+			t.Errorf("Create() %s status = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
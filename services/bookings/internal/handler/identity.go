@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// userIDHeader carries the authenticated caller's user ID, set by the API gateway
+const userIDHeader = "X-User-ID"
+
+// userRoleHeader carries the caller's role, set by the API gateway
+const userRoleHeader = "X-User-Role"
+
+// userIDFromRequest returns the caller's user ID, or "" when the request is anonymous
+func userIDFromRequest(r *http.Request) string {
+	return r.Header.Get(userIDHeader)
+}
+
+// userRoleFromRequest returns the caller's role, defaulting to contributor
+func userRoleFromRequest(r *http.Request) (domain.Role, error) {
+	return domain.ParseRole(r.Header.Get(userRoleHeader))
+}
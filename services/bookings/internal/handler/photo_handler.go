@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/imaging"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// photoFormField is the multipart form field holding an uploaded photo
+const photoFormField = "photo"
+
+type PhotoHandler struct {
+	service *service.PhotoService
+}
+
+func NewPhotoHandler(service *service.PhotoService) *PhotoHandler {
+	return &PhotoHandler{service: service}
+}
+
+// ReorderPhotosRequest represents the request body for reordering photos
+type ReorderPhotosRequest struct {
+	PhotoIDs []string `json:"photo_ids"`
+}
+
+// Upload adds a photo to a venue's gallery from a multipart form
+// POST /api/v1/venues/{id}/photos
+func (h *PhotoHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxPhotoBytes+1<<20)
+	file, _, err := r.FormFile(photoFormField)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writePhotoError(w, service.ErrPhotoTooLarge)
+			return
+		}
+		http.Error(w, "multipart field \"photo\" is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxPhotoBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	photo, err := h.service.Upload(r.Context(), chi.URLParam(r, "id"), userID, data)
+	if err != nil {
+		writePhotoError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(photo); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete removes a photo from a venue's gallery and from storage
+// DELETE /api/v1/venues/{id}/photos/{photoID}
+func (h *PhotoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+	role, err := userRoleFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "photoID"), userID, role); err != nil {
+		writePhotoError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reorder sets the order of a venue's gallery; the first photo is the cover
+// PUT /api/v1/venues/{id}/photos/order
+func (h *PhotoHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+	role, err := userRoleFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	gallery, err := h.service.Reorder(r.Context(), chi.URLParam(r, "id"), req.PhotoIDs, userID, role)
+	if err != nil {
+		writePhotoError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(gallery); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writePhotoError maps photo errors to HTTP status codes
+func writePhotoError(w http.ResponseWriter, err error) {
+	var venueNotFound *repository.VenueNotFoundError
+	var unsupported *imaging.UnsupportedTypeError
+	var invalid *service.InvalidPhotoError
+	switch {
+	case errors.As(err, &venueNotFound):
+		http.Error(w, "venue not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrPhotoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrPhotoTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.As(err, &unsupported):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.As(err, &invalid), errors.Is(err, domain.ErrPhotoOrder):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrPhotoForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrTooManyPhotos):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeVenueSaveError(w, err)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestPhotoHandler_Upload(t *testing.T) {
+	venueService := service.NewVenueService(repository.NewMockVenueRepository())
+	handler := NewPhotoHandler(service.NewPhotoService(venueService, storage.NewLocalStore(t.TempDir(), "http://media.test")))
+
+	venue := domain.NewVenue("Bottom of the Hill", domain.GeoPoint{Latitude: 37.76, Longitude: -122.39}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	_ = venueService.Create(context.Background(), venue)
+
+	var img bytes.Buffer
+	_ = png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 64, 48)))
+
+	upload := func(userID string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("photo", "stage.png")
+		_, _ = part.Write(data)
+		_ = form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/venues/"+venue.ID+"/photos", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		if userID != "" {
+			req.Header.Set("X-User-ID", userID)
+		}
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", venue.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.Upload(w, req)
+		return w
+	}
+
+	if w := upload("", img.Bytes()); w.Code != http.StatusUnauthorized {
+		t.Errorf("Upload() without user status = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+	if w := upload("user-1", []byte("%PDF-1.4")); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Upload() of a PDF status = %v, want %v", w.Code, http.StatusUnsupportedMediaType)
+	}
+
+	w := upload("user-1", img.Bytes())
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Upload() status = %v, want %v: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var photo domain.VenuePhoto
+	if err := json.NewDecoder(w.Body).Decode(&photo); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if photo.Width != 64 || photo.Height != 48 || photo.ThumbnailURL == "" {
+		t.Errorf("Upload() = %+v", photo)
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type RecommendationHandler struct {
+	service *service.RecommendationService
+}
+
+func NewRecommendationHandler(service *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: service}
+}
+
+// RecommendedVenues ranks venues the artist has not played yet
+// GET /api/v1/artists/{id}/recommended-venues
+func (h *RecommendationHandler) RecommendedVenues(w http.ResponseWriter, r *http.Request) {
+	artistID := chi.URLParam(r, "id")
+
+	limit := 10 // Default limit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	recommendations, err := h.service.RecommendVenues(r.Context(), artistID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(recommendations); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRecommendationHandler_RecommendedVenues(t *testing.T) {
+	ctx := context.Background()
+	venues := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	handler := NewRecommendationHandler(service.NewRecommendationService(bookings, venues))
+
+	played := domain.NewVenue(
+		"Played Club",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceManual,
+	)
+	nearby := domain.NewVenue(
+		"Nearby Club",
+		domain.GeoPoint{Latitude: 37.7849, Longitude: -122.4094, Geohash: "9q8yym"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceManual,
+	)
+	_ = venues.Create(ctx, played)
+	_ = venues.Create(ctx, nearby)
+	booking := domain.NewBooking("artist-1", played.ID, time.Now().Add(-48*time.Hour), 300)
+	booking.Confirm()
+	_ = bookings.Create(ctx, booking)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/artists/artist-1/recommended-venues?limit=5", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "artist-1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.RecommendedVenues(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("RecommendedVenues() status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var recs []domain.VenueRecommendation
+	if err := json.NewDecoder(w.Body).Decode(&recs); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(recs) != 1 || recs[0].Venue.ID != nearby.ID {
+		t.Errorf("RecommendedVenues() = %v, want %v", recs, nearby.Name)
+	}
+	if len(recs[0].Reasons) == 0 {
+		t.Error("RecommendedVenues() should include explanations")
+	}
+}
+
+func TestRecommendationHandler_RecommendedVenues_InvalidLimit(t *testing.T) {
+	handler := NewRecommendationHandler(service.NewRecommendationService(
+		repository.NewMockBookingRepository(), repository.NewMockVenueRepository()))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/artists/artist-1/recommended-venues?limit=abc", nil)
+	w := httptest.NewRecorder()
+
+	handler.RecommendedVenues(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("RecommendedVenues() status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewHandler struct {
+	service *service.ReviewService
+}
+
+func NewReviewHandler(service *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// SubmitReviewRequest represents the request body for reviewing a venue
+type SubmitReviewRequest struct {
+	Scores  domain.ReviewScores `json:"scores"`
+	Comment string              `json:"comment,omitempty"`
+}
+
+// Submit creates or revises the caller's review of a venue they have played
+// POST /api/v1/venues/{id}/reviews
+func (h *ReviewHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	artistID := userIDFromRequest(r)
+	if artistID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	var req SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.service.Submit(r.Context(), chi.URLParam(r, "id"), artistID, req.Scores, req.Comment)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// List returns a page of a venue's reviews, newest first, with its aggregate
+// rating and per-category breakdown
+// GET /api/v1/venues/{id}/reviews
+func (h *ReviewHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 100 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	page, err := h.service.List(r.Context(), chi.URLParam(r, "id"), limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeReviewError maps review errors to HTTP status codes
+func writeReviewError(w http.ResponseWriter, err error) {
+	var venueNotFound *repository.VenueNotFoundError
+	var invalidCursor *repository.InvalidCursorError
+	var invalidReview *service.InvalidReviewError
+	switch {
+	case errors.As(err, &venueNotFound):
+		http.Error(w, "venue not found", http.StatusNotFound)
+	case errors.As(err, &invalidCursor), errors.As(err, &invalidReview):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrNoPlayedBooking):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type SavedSearchHandler struct {
+	service *service.SavedSearchService
+}
+
+func NewSavedSearchHandler(service *service.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: service}
+}
+
+// CreateSavedSearchRequest represents the request body for saving a search
+type CreateSavedSearchRequest struct {
+	Name     string                     `json:"name"`
+	Criteria domain.VenueSearchCriteria `json:"criteria"`
+	Notify   *domain.NotificationTarget `json:"notify,omitempty"`
+}
+
+// Create saves a venue search for the caller
+// POST /api/v1/saved-searches
+func (h *SavedSearchHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateSavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	search := domain.NewSavedSearch(userID, req.Name, req.Criteria, req.Notify)
+	if err := h.service.Create(r.Context(), search); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(search); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// List lists the caller's saved searches
+// GET /api/v1/saved-searches
+func (h *SavedSearchHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return
+	}
+
+	searches, err := h.service.ListByUser(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(searches); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetByID retrieves one of the caller's saved searches
+// GET /api/v1/saved-searches/{id}
+func (h *SavedSearchHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	search, ok := h.ownedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(search); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete deletes one of the caller's saved searches
+// DELETE /api/v1/saved-searches/{id}
+func (h *SavedSearchHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	search, ok := h.ownedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), search.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Matches lists the venues recorded as matching a saved search
+// GET /api/v1/saved-searches/{id}/matches
+func (h *SavedSearchHandler) Matches(w http.ResponseWriter, r *http.Request) {
+	search, ok := h.ownedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	matches, err := h.service.ListMatches(r.Context(), search.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(matches); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// ownedSearch loads the saved search named in the URL and checks that the caller owns it
+func (h *SavedSearchHandler) ownedSearch(w http.ResponseWriter, r *http.Request) (*domain.SavedSearch, bool) {
+	userID := userIDFromRequest(r)
+	if userID == "" {
+		http.Error(w, "user id is required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	search, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		var notFound *repository.SavedSearchNotFoundError
+		if errors.As(err, &notFound) {
+			http.Error(w, "saved search not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	// Report other users' searches as missing rather than leaking their existence
+	if search.UserID != userID {
+		http.Error(w, "saved search not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	return search, true
+}
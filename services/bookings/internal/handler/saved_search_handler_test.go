@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+func newTestSavedSearchHandler() (*SavedSearchHandler, *repository.MockSavedSearchRepository) {
+	venueSvc := service.NewVenueService(repository.NewMockVenueRepository())
+	repo := repository.NewMockSavedSearchRepository()
+	svc := service.NewSavedSearchService(repo, venueSvc, notify.NewMemorySink())
+	return NewSavedSearchHandler(svc), repo
+}
+
+func TestSavedSearchHandler_Create(t *testing.T) {
+	handler, _ := newTestSavedSearchHandler()
+
+	reqBody := CreateSavedSearchRequest{
+		Name: "SF clubs",
+		Criteria: domain.VenueSearchCriteria{
+			City:       "San Francisco",
+			State:      "CA",
+			VenueTypes: []domain.VenueType{domain.VenueTypeClub},
+		},
+		Notify: &domain.NotificationTarget{Channel: "email", Address: "agent@example.com"},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user-1")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create() status = %v, want %v. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var result domain.SavedSearch
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.UserID != "user-1" {
+		t.Errorf("Create() user_id = %v, want user-1", result.UserID)
+	}
+	if result.Criteria.City != "San Francisco" {
+		t.Errorf("Create() criteria city = %v, want San Francisco", result.Criteria.City)
+	}
+}
+
+func TestSavedSearchHandler_Create_RequiresUser(t *testing.T) {
+	handler, _ := newTestSavedSearchHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/saved-searches", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Create() status = %v, want %v", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestSavedSearchHandler_GetByID_OtherUser(t *testing.T) {
+	handler, repo := newTestSavedSearchHandler()
+
+	search := domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}, nil)
+	_ = repo.Create(context.Background(), search)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/saved-searches/"+search.ID, nil)
+	req.Header.Set("X-User-ID", "user-2")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", search.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.GetByID(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("GetByID() status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestSavedSearchHandler_Matches(t *testing.T) {
+	handler, repo := newTestSavedSearchHandler()
+
+	search := domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}, nil)
+	_ = repo.Create(context.Background(), search)
+	venue := domain.NewVenue("Test Venue", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	_, _ = repo.RecordMatch(context.Background(), domain.NewSavedSearchMatch(search.ID, venue, false))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/saved-searches/"+search.ID+"/matches", nil)
+	req.Header.Set("X-User-ID", "user-1")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", search.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.Matches(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Matches() status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var matches []domain.SavedSearchMatch
+	if err := json.NewDecoder(w.Body).Decode(&matches); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(matches) != 1 || matches[0].VenueID != venue.ID {
+		t.Errorf("Matches() = %v, want one match for %v", matches, venue.ID)
+	}
+}
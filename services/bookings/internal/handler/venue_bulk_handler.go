@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/bulk"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// maxImportBytes bounds the size of a bulk import upload
+const maxImportBytes = 20 << 20
+
+// Import creates venues in bulk from a CSV or NDJSON body and reports what
+// happened to each row. Admins only.
+// POST /api/v1/venues/import
+func (h *VenueHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	format, ok := bulk.FormatForContentType(r.Header.Get("Content-Type"))
+	if !ok {
+		http.Error(w, "content type must be text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+	dryRun := false
+	if s := r.URL.Query().Get("dry_run"); s != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			http.Error(w, "invalid dry_run", http.StatusBadRequest)
+			return
+		}
+	}
+
+	rows, err := bulk.Read(http.MaxBytesReader(w, r.Body, maxImportBytes), format)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "import is larger than 20 MB", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), rows, userIDFromRequest(r), dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Export streams every stored venue as CSV or NDJSON. Admins only.
+// GET /api/v1/venues/export
+func (h *VenueHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	format := bulk.FormatNDJSON
+	if s := r.URL.Query().Get("format"); s != "" {
+		var err error
+		if format, err = bulk.ParseFormat(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="venues.`+string(format)+`"`)
+
+	// Once a venue is written the status has been sent, so later failures can
+	// only cut the response short
+	writer := bulk.NewWriter(w, format)
+	written := 0
+	err := h.service.Export(r.Context(), func(v *domain.Venue) error {
+		written++
+		return writer.Write(v)
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		log.Printf("exporting venues after %d: %v", written, err)
+		if written == 0 {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
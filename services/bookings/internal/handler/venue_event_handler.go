@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type VenueEventHandler struct {
+	service *service.EventService
+}
+
+func NewVenueEventHandler(service *service.EventService) *VenueEventHandler {
+	return &VenueEventHandler{service: service}
+}
+
+// List returns a page of the shows a venue has hosted, latest first, with
+// shows per month and the genres it books over the last year
+// GET /api/v1/venues/{id}/events
+func (h *VenueEventHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 100 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	page, err := h.service.List(r.Context(), chi.URLParam(r, "id"), limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		var venueNotFound *repository.VenueNotFoundError
+		var invalidCursor *repository.InvalidCursorError
+		switch {
+		case errors.As(err, &venueNotFound):
+			http.Error(w, "venue not found", http.StatusNotFound)
+		case errors.As(err, &invalidCursor):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -2,11 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/mergepatch"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
 	"github.com/crowdunlocked/services/bookings/internal/service"
 	"github.com/go-chi/chi/v5"
 )
@@ -167,6 +173,16 @@ func (h *VenueHandler) Search(w http.ResponseWriter, r *http.Request) {
 		criteria.ActiveOnly = true
 	}
 
+	// Parse the booking date
+	if dateStr := query.Get("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			http.Error(w, "invalid date", http.StatusBadRequest)
+			return
+		}
+		criteria.Date = &date
+	}
+
 	// Parse pagination
 	if limitStr := query.Get("limit"); limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
@@ -215,13 +231,15 @@ func (h *VenueHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
 	if err := json.NewEncoder(w).Encode(venue); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-// Create creates a new venue
+// Create creates a new venue. Venues submitted by anyone but an admin wait
+// in the moderation queue until they are approved.
 // POST /api/v1/venues
 func (h *VenueHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateVenueRequest
@@ -235,8 +253,10 @@ func (h *VenueHandler) Create(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "name is required", http.StatusBadRequest)
 		return
 	}
-	if req.Location.Latitude == 0 || req.Location.Longitude == 0 {
-		http.Error(w, "location is required", http.StatusBadRequest)
+	// Without coordinates the address is geocoded, so it needs at least a city
+	hasLocation := req.Location.Latitude != 0 || req.Location.Longitude != 0
+	if !hasLocation && req.Address.City == "" {
+		http.Error(w, "location or address is required", http.StatusBadRequest)
 		return
 	}
 	if len(req.VenueTypes) == 0 {
@@ -271,13 +291,23 @@ func (h *VenueHandler) Create(w http.ResponseWriter, r *http.Request) {
 	venue.Capacity = req.Capacity
 	venue.Genres = req.Genres
 	venue.Description = req.Description
+	userID := userIDFromRequest(r)
+	venue.RecordChanges(nil, domain.SourceUserSubmitted, userID)
 
-	if err := h.service.Create(r.Context(), venue); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// Admins list venues directly; everyone else's go to moderation
+	var err error
+	if role, _ := userRoleFromRequest(r); role == domain.RoleAdmin {
+		err = h.service.Create(r.Context(), venue)
+	} else {
+		err = h.service.Submit(r.Context(), venue, userID)
+	}
+	if err != nil {
+		writeVenueSaveError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(venue); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -285,7 +315,7 @@ func (h *VenueHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Update updates an existing venue
+// Update updates an existing venue. If-Match must carry the venue's ETag.
 // PUT /api/v1/venues/{id}
 func (h *VenueHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -295,6 +325,9 @@ func (h *VenueHandler) Update(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "venue not found", http.StatusNotFound)
 		return
 	}
+	if !checkIfMatch(w, r, venue.Version) {
+		return
+	}
 
 	var req UpdateVenueRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -303,6 +336,7 @@ func (h *VenueHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Apply updates
+	before := *venue
 	if req.Name != nil {
 		venue.Name = *req.Name
 	}
@@ -335,28 +369,147 @@ func (h *VenueHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Description != nil {
 		venue.Description = *req.Description
 	}
+	venue.RecordChanges(&before, domain.SourceUserSubmitted, userIDFromRequest(r))
 
 	if err := h.service.Update(r.Context(), venue); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeVenueSaveError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
 	if err := json.NewEncoder(w).Encode(venue); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
-// Delete deletes a venue
+// Patch applies a JSON merge patch (RFC 7396) to a venue. If-Match must
+// carry the venue's ETag, and the caller's role limits which fields it may
+// set. Owners of the venue get the owner role.
+// PATCH /api/v1/venues/{id}
+func (h *VenueHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	role, err := userRoleFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != mergepatch.ContentType && contentType != "application/json" {
+		http.Error(w, "patch must be "+mergepatch.ContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	venue, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "venue not found", http.StatusNotFound)
+		return
+	}
+	if !checkIfMatch(w, r, venue.Version) {
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := userIDFromRequest(r)
+	patched, err := h.service.Patch(r.Context(), venue, patch, venue.RoleFor(userID, role), userID)
+	if err != nil {
+		writeVenuePatchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setETag(w, patched.Version)
+	if err := json.NewEncoder(w).Encode(patched); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeVenuePatchError maps patch errors to HTTP status codes
+func writeVenuePatchError(w http.ResponseWriter, err error) {
+	var forbidden *service.PatchForbiddenError
+	var invalid *service.InvalidPatchError
+	switch {
+	case errors.As(err, &forbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.As(err, &invalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeVenueSaveError(w, err)
+	}
+}
+
+// VenueProvenanceResponse lists where each tracked field of a venue came from
+type VenueProvenanceResponse struct {
+	VenueID string                                       `json:"venue_id"`
+	Fields  map[domain.VenueField]domain.FieldProvenance `json:"fields"`
+}
+
+// Provenance reports the source, actor, time and confidence of each venue field
+// GET /api/v1/venues/{id}/provenance
+func (h *VenueHandler) Provenance(w http.ResponseWriter, r *http.Request) {
+	venue, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "venue not found", http.StatusNotFound)
+		return
+	}
+
+	response := VenueProvenanceResponse{
+		VenueID: venue.ID,
+		Fields:  venue.ProvenanceReport(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Delete soft-deletes a venue; it can be restored until it is purged
 // DELETE /api/v1/venues/{id}
 func (h *VenueHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.service.Delete(r.Context(), id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	role, err := userRoleFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id, userIDFromRequest(r), role); err != nil {
+		var notFound *repository.VenueNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			http.Error(w, "venue not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrVenueStatusForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			writeVenueSaveError(w, err)
+		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// writeVenueSaveError reports an unlocatable venue as a client error and a
+// lost update race as a failed precondition
+func writeVenueSaveError(w http.ResponseWriter, err error) {
+	var unresolved *service.LocationUnresolvedError
+	if errors.As(err, &unresolved) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if isVersionConflict(err) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/geocode"
 	"github.com/crowdunlocked/services/bookings/internal/repository"
 	"github.com/crowdunlocked/services/bookings/internal/service"
 	"github.com/go-chi/chi/v5"
@@ -138,6 +141,56 @@ func TestVenueHandler_Search_WithFilters(t *testing.T) {
 	}
 }
 
+func TestVenueHandler_Search_ByDate(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	svc := service.NewVenueService(repo)
+	svc.SetBookings(bookings)
+	handler := NewVenueHandler(svc)
+
+	club := domain.NewVenue(
+		"Test Club",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	_ = repo.Create(context.Background(), club)
+	_ = bookings.Create(context.Background(), domain.NewBooking("artist-1", club.ID, time.Date(2030, 5, 1, 20, 0, 0, 0, time.UTC), 500))
+
+	search := func(date string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/venues/search?city=San+Francisco&state=CA&date="+date, nil)
+		w := httptest.NewRecorder()
+		handler.Search(w, req)
+		return w
+	}
+
+	tests := []struct {
+		date   string
+		venues int
+	}{
+		{"2030-05-01", 0},
+		{"2030-05-02", 1},
+	}
+	for _, tt := range tests {
+		w := search(tt.date)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Search() on %s status = %v, want %v", tt.date, w.Code, http.StatusOK)
+		}
+		var result domain.VenueSearchResult
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(result.Venues) != tt.venues {
+			t.Errorf("Search() on %s returned %v venues, want %v", tt.date, len(result.Venues), tt.venues)
+		}
+	}
+
+	if w := search("May+1"); w.Code != http.StatusBadRequest {
+		t.Errorf("Search() with an invalid date status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
 func TestVenueHandler_GetByID(t *testing.T) {
 	repo := repository.NewMockVenueRepository()
 	svc := service.NewVenueService(repo)
@@ -174,6 +227,9 @@ func TestVenueHandler_GetByID(t *testing.T) {
 	if result.ID != venue.ID {
 		t.Errorf("GetByID() ID = %v, want %v", result.ID, venue.ID)
 	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("GetByID() ETag = %v, want \"1\"", etag)
+	}
 }
 
 func TestVenueHandler_GetByID_NotFound(t *testing.T) {
@@ -240,6 +296,83 @@ func TestVenueHandler_Create(t *testing.T) {
 	}
 }
 
+func TestVenueHandler_Create_GeocodesAddress(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	svc := service.NewVenueService(repo)
+	offline, err := geocode.NewOfflineGeocoder()
+	if err != nil {
+		t.Fatalf("NewOfflineGeocoder() error = %v", err)
+	}
+	svc.SetGeocoder(offline)
+	handler := NewVenueHandler(svc)
+
+	reqBody := CreateVenueRequest{
+		Name:       "Address Only",
+		Address:    AddressRequest{City: "Nashville", State: "TN", Country: "US"},
+		VenueTypes: []string{"bar"},
+	}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/venues", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create() status = %v, want %v. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var result domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Location.Latitude == 0 || result.Location.Geohash == "" {
+		t.Errorf("Create() location = %+v, want geocoded", result.Location)
+	}
+}
+
+func TestVenueHandler_Create_LocationOrAddressRequired(t *testing.T) {
+	handler := NewVenueHandler(service.NewVenueService(repository.NewMockVenueRepository()))
+
+	tests := []struct {
+		name    string
+		address AddressRequest
+	}{
+		{"no address", AddressRequest{}},
+		{"address without geocoder", AddressRequest{City: "Nashville", State: "TN"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(CreateVenueRequest{Name: "Nowhere", Address: tt.address, VenueTypes: []string{"bar"}})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/venues", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Create() status = %v, want %v", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestVenueHandler_Create_AllowsEquator(t *testing.T) {
+	handler := NewVenueHandler(service.NewVenueService(repository.NewMockVenueRepository()))
+
+	body, _ := json.Marshal(CreateVenueRequest{
+		Name:       "Equator Bar",
+		Location:   LocationRequest{Latitude: 0, Longitude: -78.4558},
+		VenueTypes: []string{"bar"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/venues", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Create() status = %v, want %v. Body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+}
+
 func TestVenueHandler_Update(t *testing.T) {
 	repo := repository.NewMockVenueRepository()
 	svc := service.NewVenueService(repo)
@@ -262,6 +395,7 @@ func TestVenueHandler_Update(t *testing.T) {
 	body, _ := json.Marshal(reqBody)
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/venues/"+venue.ID, bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
 	w := httptest.NewRecorder()
 
 	rctx := chi.NewRouteContext()
@@ -285,15 +419,18 @@ func TestVenueHandler_Update(t *testing.T) {
 	if result.Capacity != 200 {
 		t.Errorf("Update() capacity = %v, want 200", result.Capacity)
 	}
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("Update() ETag = %v, want \"2\"", etag)
+	}
 }
 
-func TestVenueHandler_Delete(t *testing.T) {
+func TestVenueHandler_Update_RequiresCurrentETag(t *testing.T) {
 	repo := repository.NewMockVenueRepository()
 	svc := service.NewVenueService(repo)
 	handler := NewVenueHandler(svc)
 
 	venue := domain.NewVenue(
-		"To Delete",
+		"Original Name",
 		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
 		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
 		[]domain.VenueType{domain.VenueTypeClub},
@@ -301,23 +438,248 @@ func TestVenueHandler_Delete(t *testing.T) {
 	)
 	_ = repo.Create(context.Background(), venue)
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/venues/"+venue.ID, nil)
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"missing", "", http.StatusPreconditionRequired},
+		{"stale", `"7"`, http.StatusPreconditionFailed},
+		{"weak match", `W/"1"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(UpdateVenueRequest{Name: stringPtr("Updated Name")})
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/venues/"+venue.ID, bytes.NewReader(body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", venue.ID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Update() status = %v, want %v", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestVenueHandler_Provenance(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	svc := service.NewVenueService(repo)
+	handler := NewVenueHandler(svc)
+
+	venue := domain.NewVenue(
+		"Original Name",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceGooglePlaces,
+	)
+	_ = repo.Create(context.Background(), venue)
+
+	body, _ := json.Marshal(UpdateVenueRequest{Capacity: intPtr(200)})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/venues/"+venue.ID, bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user-1")
+	req.Header.Set("If-Match", `"1"`)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", venue.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	handler.Update(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/venues/"+venue.ID+"/provenance", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 	w := httptest.NewRecorder()
 
+	handler.Provenance(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Provenance() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var result VenueProvenanceResponse
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	capacity := result.Fields[domain.FieldCapacity]
+	if capacity.Source != domain.SourceUserSubmitted || capacity.Actor != "user-1" || capacity.Inferred {
+		t.Errorf("Provenance() capacity = %+v, want recorded user-1 edit", capacity)
+	}
+	name := result.Fields[domain.FieldName]
+	if name.Source != domain.SourceGooglePlaces || !name.Inferred {
+		t.Errorf("Provenance() name = %+v, want inferred google_places", name)
+	}
+}
+
+func TestVenueHandler_History(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	svc := service.NewVenueService(repo)
+	svc.SetHistory(repository.NewMockVenueHistoryRepository())
+	handler := NewVenueHandler(svc)
+
+	venue := domain.NewVenue(
+		"Original Name",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	_ = svc.Create(context.Background(), venue)
+	venue.Name = "Renamed"
+	_ = svc.Update(context.Background(), venue)
+
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", venue.ID)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/venues/"+venue.ID+"/history", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.History(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("History() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var versions []domain.VenueVersion
+	if err := json.NewDecoder(w.Body).Decode(&versions); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[0].Snapshot != nil {
+		t.Errorf("History() = %+v, want two versions newest first without snapshots", versions)
+	}
+
+	rctx.URLParams.Add("version", "1")
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/venues/"+venue.ID+"/history/1/revert", nil)
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+
+	handler.Revert(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Revert() status = %v, want %v. Body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var result domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Name != "Original Name" {
+		t.Errorf("Revert() name = %v, want Original Name", result.Name)
+	}
+}
+
+func TestVenueHandler_Delete(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	svc := service.NewVenueService(repo)
+	handler := NewVenueHandler(svc)
+
+	venue := domain.NewVenue(
+		"To Delete",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	venue.OwnerIDs = []string{"owner-1"}
+	_ = repo.Create(context.Background(), venue)
+
+	call := func(userID, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/venues/"+venue.ID, nil)
+		req.Header.Set("X-User-ID", userID)
+		req.Header.Set("X-User-Role", role)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", venue.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.Delete(w, req)
+		return w
+	}
 
-	handler.Delete(w, req)
+	// Only owners and admins may delete, whatever role a caller asserts
+	for _, caller := range []struct{ userID, role string }{{"", ""}, {"user-2", ""}, {"user-2", "owner"}} {
+		if w := call(caller.userID, caller.role); w.Code != http.StatusForbidden {
+			t.Errorf("Delete() by %q as %q status = %v, want %v", caller.userID, caller.role, w.Code, http.StatusForbidden)
+		}
+	}
+	if stored, _ := repo.GetByID(context.Background(), venue.ID); stored.Deleted() {
+		t.Fatal("a forbidden Delete() should not delete the venue")
+	}
 
+	w := call("owner-1", "owner")
 	if w.Code != http.StatusNoContent {
 		t.Errorf("Delete() status = %v, want %v", w.Code, http.StatusNoContent)
 	}
 
-	// Verify deletion
-	_, err := repo.GetByID(context.Background(), venue.ID)
-	if err == nil {
-		t.Error("Delete() should delete the venue")
+	// Deletes are soft: the venue is hidden but kept until it is purged
+	if _, err := svc.GetByID(context.Background(), venue.ID); err == nil {
+		t.Error("Delete() should hide the venue")
+	}
+	stored, err := repo.GetByID(context.Background(), venue.ID)
+	if err != nil || !stored.Deleted() {
+		t.Errorf("Delete() should keep the venue until it is purged, got %v", err)
+	}
+
+	if w := call("admin-1", "admin"); w.Code != http.StatusNotFound {
+		t.Errorf("Delete() of a deleted venue status = %v, want %v", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestVenueHandler_DeactivateReactivate(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	handler := NewVenueHandler(service.NewVenueService(repo))
+
+	venue := domain.NewVenue(
+		"Rickshaw Stop",
+		domain.GeoPoint{Latitude: 37.7763, Longitude: -122.4206, Geohash: "9q8yym"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	venue.OwnerIDs = []string{"owner-1"}
+	_ = repo.Create(context.Background(), venue)
+
+	call := func(fn http.HandlerFunc, userID, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/venues/"+venue.ID+"/deactivate", nil)
+		req.Header.Set("X-User-ID", userID)
+		req.Header.Set("X-User-Role", role)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", venue.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w
+	}
+
+	if w := call(handler.Deactivate, "user-2", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Deactivate() by a contributor status = %v, want %v", w.Code, http.StatusForbidden)
+	}
+
+	w := call(handler.Deactivate, "owner-1", "owner")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Deactivate() status = %v, want %v: %s", w.Code, http.StatusOK, w.Body)
+	}
+	if stored, _ := repo.GetByID(context.Background(), venue.ID); stored.Active {
+		t.Error("Deactivate() should make the venue inactive")
+	}
+
+	// A deleted venue is restored by reactivating it
+	if err := handler.service.Delete(context.Background(), venue.ID, "admin-1", domain.RoleAdmin); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	w = call(handler.Reactivate, "admin-1", "admin")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Reactivate() status = %v, want %v: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var restored domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&restored); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !restored.Active || restored.Deleted() {
+		t.Errorf("Reactivate() = active %v, deleted %v; want an active, undeleted venue", restored.Active, restored.Deleted())
 	}
 }
 
@@ -328,3 +690,261 @@ func stringPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestVenueHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		owner       bool
+		contentType string
+		patch       string
+		wantStatus  int
+		check       func(t *testing.T, v *domain.Venue)
+	}{
+		{
+			name:        "clears genres with null",
+			contentType: "application/merge-patch+json",
+			patch:       `{"genres": null, "capacity": 250}`,
+			wantStatus:  http.StatusOK,
+			check: func(t *testing.T, v *domain.Venue) {
+				if len(v.Genres) != 0 {
+					t.Errorf("Patch() genres = %v, want empty", v.Genres)
+				}
+				if v.Capacity != 250 {
+					t.Errorf("Patch() capacity = %v, want 250", v.Capacity)
+				}
+				if v.Name != "Original Name" {
+					t.Errorf("Patch() name = %v, want unchanged", v.Name)
+				}
+			},
+		},
+		{
+			name:        "owner sets pay range and contact info",
+			owner:       true,
+			contentType: "application/merge-patch+json",
+			patch:       `{"pay_range": {"min": 100, "max": 500, "currency": "USD", "type": "guarantee"}, "contact_info": {"email": "booking@example.com"}}`,
+			wantStatus:  http.StatusOK,
+			check: func(t *testing.T, v *domain.Venue) {
+				if v.PayRange == nil || v.PayRange.Max != 500 {
+					t.Errorf("Patch() pay range = %+v, want max 500", v.PayRange)
+				}
+				if v.ContactInfo.Email != "booking@example.com" {
+					t.Errorf("Patch() email = %v", v.ContactInfo.Email)
+				}
+			},
+		},
+		{
+			name:        "owner role without a claim",
+			role:        "owner",
+			contentType: "application/merge-patch+json",
+			patch:       `{"pay_range": {"min": 100, "max": 500, "currency": "USD", "type": "guarantee"}}`,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "contributor cannot set pay range",
+			contentType: "application/merge-patch+json",
+			patch:       `{"pay_range": {"min": 100, "max": 500, "currency": "USD", "type": "guarantee"}}`,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "read-only field",
+			role:        "admin",
+			contentType: "application/merge-patch+json",
+			patch:       `{"rating": 5}`,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:        "unknown field",
+			contentType: "application/merge-patch+json",
+			patch:       `{"colour": "red"}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "invalid value",
+			contentType: "application/merge-patch+json",
+			patch:       `{"venue_types": []}`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			patch:       `{"capacity": 250}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMockVenueRepository()
+			handler := NewVenueHandler(service.NewVenueService(repo))
+
+			venue := domain.NewVenue(
+				"Original Name",
+				domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+				domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+				[]domain.VenueType{domain.VenueTypeClub},
+				domain.SourceUserSubmitted,
+			)
+			venue.Genres = []string{"rock"}
+			if tt.owner {
+				venue.AddOwner("user-1")
+			}
+			_ = repo.Create(context.Background(), venue)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/venues/"+venue.ID, strings.NewReader(tt.patch))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("If-Match", `"1"`)
+			req.Header.Set("X-User-ID", "user-1")
+			if tt.role != "" {
+				req.Header.Set("X-User-Role", tt.role)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", venue.ID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.Patch(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Patch() status = %v, want %v. Body: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.check == nil {
+				return
+			}
+
+			stored, err := repo.GetByID(context.Background(), venue.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			tt.check(t, stored)
+			if stored.Provenance[domain.FieldCapacity].Actor == "" && stored.Capacity != 0 {
+				t.Error("Patch() did not record who changed the capacity")
+			}
+			if etag := w.Header().Get("ETag"); etag != `"2"` {
+				t.Errorf("Patch() ETag = %v, want \"2\"", etag)
+			}
+		})
+	}
+}
+
+func TestVenueHandler_Moderation(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	handler := NewVenueHandler(service.NewVenueService(repo))
+
+	body, _ := json.Marshal(CreateVenueRequest{
+		Name:       "Community Pick",
+		Location:   LocationRequest{Latitude: 37.7749, Longitude: -122.4194},
+		Address:    AddressRequest{City: "San Francisco", State: "CA"},
+		VenueTypes: []string{"bar"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/venues", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user-1")
+	w := httptest.NewRecorder()
+	handler.Create(w, req)
+
+	var created domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if created.ModerationStatus != domain.ModerationPending {
+		t.Fatalf("Create() moderation status = %v, want pending", created.ModerationStatus)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/moderation/venues", nil)
+	w = httptest.NewRecorder()
+	handler.ModerationQueue(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("ModerationQueue() without admin role status = %v, want %v", w.Code, http.StatusForbidden)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/moderation/venues", nil)
+	req.Header.Set("X-User-Role", "admin")
+	w = httptest.NewRecorder()
+	handler.ModerationQueue(w, req)
+	var queue []domain.Venue
+	if err := json.NewDecoder(w.Body).Decode(&queue); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(queue) != 1 || queue[0].ID != created.ID {
+		t.Fatalf("ModerationQueue() = %d venues, want the submission", len(queue))
+	}
+
+	decide := func(action, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/moderation/venues/"+created.ID+"/"+action, strings.NewReader(body))
+		req.Header.Set("X-User-Role", "admin")
+		req.Header.Set("X-User-ID", "mod-1")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", created.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		if action == "approve" {
+			handler.Approve(w, req)
+		} else {
+			handler.Reject(w, req)
+		}
+		return w
+	}
+
+	if w := decide("reject", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Reject() without reason status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+	if w := decide("approve", ``); w.Code != http.StatusOK {
+		t.Fatalf("Approve() status = %v. Body: %s", w.Code, w.Body.String())
+	}
+	if w := decide("reject", `{"reason": "spam"}`); w.Code != http.StatusConflict {
+		t.Errorf("Reject() after approval status = %v, want %v", w.Code, http.StatusConflict)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), created.ID)
+	if !stored.Listed() || !stored.Active {
+		t.Error("Approve() should list the venue")
+	}
+}
+
+func TestVenueHandler_ImportExport(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	handler := NewVenueHandler(service.NewVenueService(repo))
+
+	csv := "name,latitude,longitude,city,state,venue_types\n" +
+		"The Chapel,37.7603,-122.4213,San Francisco,CA,club\n" +
+		"Nameless,37.77,-122.41,San Francisco,CA,\n"
+
+	importCSV := func(role, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/venues/import", strings.NewReader(csv))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-User-Role", role)
+		w := httptest.NewRecorder()
+		handler.Import(w, req)
+		return w
+	}
+
+	if w := importCSV("contributor", "text/csv"); w.Code != http.StatusForbidden {
+		t.Errorf("Import() by a contributor status = %v, want %v", w.Code, http.StatusForbidden)
+	}
+	if w := importCSV("admin", "application/json"); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Import() of JSON status = %v, want %v", w.Code, http.StatusUnsupportedMediaType)
+	}
+
+	w := importCSV("admin", "text/csv; charset=utf-8")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Import() status = %v, want %v: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var report service.BulkImportReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if report.Created != 1 || report.Invalid != 1 || len(report.Rows) != 2 {
+		t.Fatalf("Import() report = %+v", report)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/venues/export?format=csv", nil)
+	req.Header.Set("X-User-Role", "admin")
+	w = httptest.NewRecorder()
+	handler.Export(w, req)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("Export() status = %v, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "The Chapel") {
+		t.Errorf("Export() = %q, want a header and the imported venue", w.Body.String())
+	}
+}
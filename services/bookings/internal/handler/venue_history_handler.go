@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// History lists a venue's changes, newest first, without snapshots
+// GET /api/v1/venues/{id}/history
+func (h *VenueHandler) History(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	versions, err := h.service.History(r.Context(), chi.URLParam(r, "id"), limit)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	summaries := make([]domain.VenueVersion, len(versions))
+	for i, v := range versions {
+		summaries[i] = *v
+		summaries[i].Snapshot = nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summaries); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetVersion retrieves one version of a venue with its full snapshot
+// GET /api/v1/venues/{id}/history/{version}
+func (h *VenueHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+
+	v, err := h.service.GetVersion(r.Context(), chi.URLParam(r, "id"), version)
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Revert restores a venue to one of its versions
+// POST /api/v1/venues/{id}/history/{version}/revert
+func (h *VenueHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		http.Error(w, "invalid version", http.StatusBadRequest)
+		return
+	}
+
+	venue, err := h.service.Revert(r.Context(), chi.URLParam(r, "id"), version, userIDFromRequest(r))
+	if err != nil {
+		writeHistoryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(venue); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeHistoryError maps history errors to HTTP status codes
+func writeHistoryError(w http.ResponseWriter, err error) {
+	var versionNotFound *repository.VenueVersionNotFoundError
+	var conflict *repository.ExternalIDConflictError
+	switch {
+	case errors.Is(err, service.ErrHistoryDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.As(err, &versionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.As(err, &conflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeVenueSaveError(w, err)
+	}
+}
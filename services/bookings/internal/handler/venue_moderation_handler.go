@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// ModerationDecisionRequest represents the request body for approving or
+// rejecting a submitted venue
+type ModerationDecisionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ModerationQueue lists submitted venues awaiting a decision, oldest first
+// GET /api/v1/moderation/venues
+func (h *VenueHandler) ModerationQueue(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	status := domain.ModerationPending
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = domain.ModerationStatus(s)
+		if status != domain.ModerationPending && status != domain.ModerationApproved && status != domain.ModerationRejected {
+			http.Error(w, "invalid status", http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	venues, err := h.service.ModerationQueue(r.Context(), status, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(venues); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// Approve lists a submitted venue
+// POST /api/v1/moderation/venues/{id}/approve
+func (h *VenueHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.service.Approve)
+}
+
+// Reject keeps a submitted venue out of search; the body must give a reason
+// POST /api/v1/moderation/venues/{id}/reject
+func (h *VenueHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, h.service.Reject)
+}
+
+// moderate applies a moderator's decision to the venue in the URL
+func (h *VenueHandler) moderate(w http.ResponseWriter, r *http.Request,
+	decide func(ctx context.Context, id, moderator, reason string) (*domain.Venue, error)) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var req ModerationDecisionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	venue, err := decide(r.Context(), chi.URLParam(r, "id"), userIDFromRequest(r), req.Reason)
+	if err != nil {
+		var notFound *repository.VenueNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			http.Error(w, "venue not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrNotPendingModeration):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, service.ErrRejectionReasonRequired):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeVenueSaveError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
+	if err := json.NewEncoder(w).Encode(venue); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// requireAdmin writes 403 and reports false unless the caller is an admin
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if role, err := userRoleFromRequest(r); err != nil || role != domain.RoleAdmin {
+		http.Error(w, "admin role required", http.StatusForbidden)
+		return false
+	}
+	return true
+}
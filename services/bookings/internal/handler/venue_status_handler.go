@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// Deactivate takes a venue out of search without deleting it
+// POST /api/v1/venues/{id}/deactivate
+func (h *VenueHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, h.service.Deactivate)
+}
+
+// Reactivate lists a deactivated venue again, restoring it if it was deleted
+// and has not been purged yet
+// POST /api/v1/venues/{id}/reactivate
+func (h *VenueHandler) Reactivate(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, h.service.Reactivate)
+}
+
+// setStatus applies an owner's or admin's status change to the venue in the URL
+func (h *VenueHandler) setStatus(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, id, userID string, role domain.Role) (*domain.Venue, error)) {
+	role, err := userRoleFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	venue, err := change(r.Context(), chi.URLParam(r, "id"), userIDFromRequest(r), role)
+	if err != nil {
+		var notFound *repository.VenueNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			http.Error(w, "venue not found", http.StatusNotFound)
+		case errors.Is(err, service.ErrVenueStatusForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrVenueNotListed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeVenueSaveError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	setETag(w, venue.Version)
+	if err := json.NewEncoder(w).Encode(venue); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,132 @@
+// Package imaging validates uploaded photos, re-encodes them without their
+// metadata and makes thumbnails
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+// Limits on accepted images
+const (
+	// MaxPixels rejects images that would take too much memory to decode
+	MaxPixels = 40_000_000
+	// ThumbnailSize is the longest side of a thumbnail in pixels
+	ThumbnailSize = 400
+
+	jpegQuality = 88
+)
+
+// ErrTooManyPixels is returned for images larger than MaxPixels
+var ErrTooManyPixels = fmt.Errorf("image is larger than %d pixels", MaxPixels)
+
+// UnsupportedTypeError is returned for files that are not JPEG or PNG images
+type UnsupportedTypeError struct {
+	ContentType string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported image type %s, use JPEG or PNG", e.ContentType)
+}
+
+// Image is an encoded image
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Ext returns the file extension for the image's type
+func (i *Image) Ext() string {
+	if i.ContentType == "image/png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// Process decodes an uploaded JPEG or PNG and returns it re-encoded along
+// with a thumbnail. Re-encoding drops all metadata, including EXIF GPS
+// coordinates; the EXIF orientation is applied to the pixels first so the
+// photo still displays the right way up.
+func Process(data []byte) (photo, thumbnail *Image, err error) {
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, nil, &UnsupportedTypeError{ContentType: contentType}
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	photo, err = encode(img, contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	thumbnail, err = encode(thumbnailOf(img), contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return photo, thumbnail, nil
+}
+
+// thumbnailOf scales img to fit within ThumbnailSize, never enlarging it
+func thumbnailOf(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= ThumbnailSize && h <= ThumbnailSize {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*ThumbnailSize/w)
+		w = ThumbnailSize
+	} else {
+		w = max(1, w*ThumbnailSize/h)
+		h = ThumbnailSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// encode writes img in the given format. The standard encoders write no
+// metadata, which is what strips EXIF and PNG text chunks.
+func encode(img image.Image, contentType string) (*Image, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	b := img.Bounds()
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		Width:       b.Dx(),
+		Height:      b.Dy(),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// withExifOrientation inserts an EXIF segment holding an orientation tag
+// directly after a JPEG's start-of-image marker
+func withExifOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app1...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcess_JPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(800, 600), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := withExifOrientation(buf.Bytes(), 6)
+	if jpegOrientation(data) != 6 {
+		t.Fatalf("jpegOrientation() = %v, want 6", jpegOrientation(data))
+	}
+
+	photo, thumb, err := Process(data)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if photo.ContentType != "image/jpeg" || photo.Ext() != ".jpg" {
+		t.Errorf("content type = %v ext %v", photo.ContentType, photo.Ext())
+	}
+	if photo.Width != 600 || photo.Height != 800 {
+		t.Errorf("photo is %dx%d, want the rotated 600x800", photo.Width, photo.Height)
+	}
+	if thumb.Width != 300 || thumb.Height != ThumbnailSize {
+		t.Errorf("thumbnail is %dx%d, want 300x%d", thumb.Width, thumb.Height, ThumbnailSize)
+	}
+	if bytes.Contains(photo.Data, []byte("Exif")) {
+		t.Error("processed photo still carries EXIF data")
+	}
+	if jpegOrientation(photo.Data) != 1 {
+		t.Error("processed photo should have no orientation left to apply")
+	}
+}
+
+func TestProcess_SmallPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(120, 80)); err != nil {
+		t.Fatal(err)
+	}
+
+	photo, thumb, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if photo.ContentType != "image/png" || photo.Ext() != ".png" {
+		t.Errorf("content type = %v ext %v", photo.ContentType, photo.Ext())
+	}
+	if thumb.Width != 120 || thumb.Height != 80 {
+		t.Errorf("thumbnail is %dx%d, small images should not be enlarged", thumb.Width, thumb.Height)
+	}
+}
+
+func TestProcess_Rejects(t *testing.T) {
+	var unsupported *UnsupportedTypeError
+	if _, _, err := Process([]byte("GIF89a not really")); !errors.As(err, &unsupported) {
+		t.Errorf("Process() error = %v, want UnsupportedTypeError", err)
+	}
+	if _, _, err := Process([]byte("%PDF-1.4")); !errors.As(err, &unsupported) {
+		t.Errorf("Process() error = %v, want UnsupportedTypeError", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	img := testImage(3, 2)
+	rotated := orient(img, 6)
+	if b := rotated.Bounds(); b.Dx() != 2 || b.Dy() != 3 {
+		t.Fatalf("rotated bounds = %v, want 2x3", b)
+	}
+	// Rotating clockwise moves the bottom-left pixel to the top-left
+	if rotated.At(0, 0) != img.At(0, 1) {
+		t.Errorf("rotated top-left = %v, want %v", rotated.At(0, 0), img.At(0, 1))
+	}
+}
@@ -0,0 +1,66 @@
+// Package mergepatch applies JSON merge patches as defined by RFC 7396
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ContentType is the media type of a JSON merge patch document
+const ContentType = "application/merge-patch+json"
+
+// ErrNotObject is returned when a patch is valid JSON but not an object
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// Apply merges patch into doc and returns the result. Members of the patch
+// replace those of the document, null removes a member, and nested objects
+// are merged recursively. Arrays are replaced whole.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	p, err := Parse(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// Parse decodes a patch document, which for resources that are JSON
+// objects must itself be an object
+func Parse(patch []byte) (map[string]interface{}, error) {
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	obj, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObject
+	}
+	return obj, nil
+}
+
+// merge implements the MergePatch function from RFC 7396 section 2
+func merge(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{})
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = merge(targetObj[key], value)
+	}
+	return targetObj
+}
@@ -0,0 +1,352 @@
+// Package migrate creates and updates DynamoDB tables from declarations in
+// code, so that local and test databases match what the repositories query,
+// and records which numbered migrations have been applied
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Client is the part of the DynamoDB API migrations use
+type Client interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// Key is a key attribute
+type Key struct {
+	Name string
+	Type types.ScalarAttributeType
+}
+
+// S and N declare string and number keys
+func S(name string) Key { return Key{Name: name, Type: types.ScalarAttributeTypeS} }
+func N(name string) Key { return Key{Name: name, Type: types.ScalarAttributeTypeN} }
+
+// Index is a secondary index projecting all attributes. Local indexes share
+// the table's hash key, so only their range key is used.
+type Index struct {
+	Name     string
+	HashKey  Key
+	RangeKey Key // Optional for global indexes
+}
+
+// Table declares a pay-per-request table
+type Table struct {
+	Name          string
+	HashKey       Key
+	RangeKey      Key // Optional
+	GlobalIndexes []Index
+	LocalIndexes  []Index
+	// StreamViewType enables the table's stream when set
+	StreamViewType types.StreamViewType
+}
+
+// Migration is one numbered schema change: the tables it declares are
+// created, or given the global indexes they lack
+type Migration struct {
+	Version     int
+	Description string
+	Tables      []Table
+	// Apply, when set, runs after the tables are ensured, for changes made
+	// outside DynamoDB. The migration is recorded only if it succeeds.
+	Apply func(ctx context.Context) error
+}
+
+// MissingLocalIndexError is returned for an existing table that lacks a
+// declared local index, which DynamoDB can only add by recreating the table
+type MissingLocalIndexError struct {
+	Table string
+	Index string
+}
+
+func (e *MissingLocalIndexError) Error() string {
+	return fmt.Sprintf("table %s has no local index %s; local indexes can only be added by recreating the table", e.Table, e.Index)
+}
+
+// Migrator applies migrations for one service, recording each in a table
+// keyed by service and version
+type Migrator struct {
+	client  Client
+	table   string
+	service string
+	// PollInterval is how often table status is checked while waiting for
+	// a table or index to become active
+	PollInterval time.Duration
+}
+
+// NewMigrator creates a migrator that records applied versions in table
+func NewMigrator(client Client, table, service string) *Migrator {
+	return &Migrator{
+		client:       client,
+		table:        table,
+		service:      service,
+		PollInterval: time.Second,
+	}
+}
+
+// recordTable declares the table applied migrations are recorded in
+func (m *Migrator) recordTable() Table {
+	return Table{Name: m.table, HashKey: S("service"), RangeKey: N("version")}
+}
+
+// Applied returns the versions already applied for the service
+func (m *Migrator) Applied(ctx context.Context) (map[int]bool, error) {
+	applied := make(map[int]bool)
+	paginator := dynamodb.NewQueryPaginator(m.client, &dynamodb.QueryInput{
+		TableName:              aws.String(m.table),
+		KeyConditionExpression: aws.String("service = :service"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":service": &types.AttributeValueMemberS{Value: m.service},
+		},
+		ProjectionExpression: aws.String("version"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		for _, item := range page.Items {
+			n, ok := item["version"].(*types.AttributeValueMemberN)
+			if !ok {
+				continue
+			}
+			version, err := strconv.Atoi(n.Value)
+			if err != nil {
+				return nil, fmt.Errorf("applied migration has version %q: %w", n.Value, err)
+			}
+			applied[version] = true
+		}
+	}
+	return applied, nil
+}
+
+// Up applies the migrations not yet recorded, lowest version first, and
+// returns those it applied. It stops at the first failure; migrations
+// applied before it stay recorded.
+func (m *Migrator) Up(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	if err := m.Ensure(ctx, m.recordTable()); err != nil {
+		return nil, err
+	}
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]Migration, 0, len(migrations))
+	seen := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		if seen[migration.Version] {
+			return nil, fmt.Errorf("migration version %d is declared twice", migration.Version)
+		}
+		seen[migration.Version] = true
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+
+	done := make([]Migration, 0, len(pending))
+	for _, migration := range pending {
+		for _, table := range migration.Tables {
+			if err := m.Ensure(ctx, table); err != nil {
+				return done, fmt.Errorf("migration %d: %w", migration.Version, err)
+			}
+		}
+		if migration.Apply != nil {
+			if err := migration.Apply(ctx); err != nil {
+				return done, fmt.Errorf("migration %d: %w", migration.Version, err)
+			}
+		}
+		if err := m.record(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// record marks a migration as applied
+func (m *Migrator) record(ctx context.Context, migration Migration) error {
+	_, err := m.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.table),
+		Item: map[string]types.AttributeValue{
+			"service":     &types.AttributeValueMemberS{Value: m.service},
+			"version":     &types.AttributeValueMemberN{Value: strconv.Itoa(migration.Version)},
+			"description": &types.AttributeValueMemberS{Value: migration.Description},
+			"applied_at":  &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+// Ensure creates the table if it does not exist, or else adds the global
+// indexes it lacks one at a time, as DynamoDB requires. It waits for the
+// table and its indexes to become active. Indexes the table has but the
+// declaration omits are left alone.
+func (m *Migrator) Ensure(ctx context.Context, table Table) error {
+	desc, err := m.describe(ctx, table.Name)
+	if err != nil {
+		return err
+	}
+	if desc == nil {
+		if _, err := m.client.CreateTable(ctx, createTableInput(table)); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", table.Name, err)
+		}
+		return m.waitActive(ctx, table.Name)
+	}
+
+	existing := make(map[string]bool)
+	for _, index := range desc.LocalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = true
+	}
+	for _, index := range table.LocalIndexes {
+		if !existing[index.Name] {
+			return &MissingLocalIndexError{Table: table.Name, Index: index.Name}
+		}
+	}
+
+	for _, index := range desc.GlobalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = true
+	}
+	for _, index := range table.GlobalIndexes {
+		if existing[index.Name] {
+			continue
+		}
+		_, err := m.client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            aws.String(table.Name),
+			AttributeDefinitions: attributeDefinitions(table.HashKey, table.RangeKey, index),
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:  aws.String(index.Name),
+					KeySchema:  keySchema(index.HashKey, index.RangeKey),
+					Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add index %s to table %s: %w", index.Name, table.Name, err)
+		}
+		if err := m.waitActive(ctx, table.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describe returns the table's description, or nil if it does not exist
+func (m *Migrator) describe(ctx context.Context, name string) (*types.TableDescription, error) {
+	out, err := m.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(name)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe table %s: %w", name, err)
+	}
+	return out.Table, nil
+}
+
+// waitActive polls until the table and all its global indexes are active
+func (m *Migrator) waitActive(ctx context.Context, name string) error {
+	for {
+		desc, err := m.describe(ctx, name)
+		if err != nil {
+			return err
+		}
+		if desc != nil && active(desc) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for table %s: %w", name, ctx.Err())
+		case <-time.After(m.PollInterval):
+		}
+	}
+}
+
+func active(desc *types.TableDescription) bool {
+	if desc.TableStatus != types.TableStatusActive {
+		return false
+	}
+	for _, index := range desc.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
+
+// createTableInput builds the request that creates a declared table
+func createTableInput(table Table) *dynamodb.CreateTableInput {
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(table.Name),
+		BillingMode:          types.BillingModePayPerRequest,
+		KeySchema:            keySchema(table.HashKey, table.RangeKey),
+		AttributeDefinitions: attributeDefinitions(table.HashKey, table.RangeKey, append(append([]Index{}, table.GlobalIndexes...), table.LocalIndexes...)...),
+	}
+	for _, index := range table.GlobalIndexes {
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, types.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.HashKey, index.RangeKey),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+	for _, index := range table.LocalIndexes {
+		input.LocalSecondaryIndexes = append(input.LocalSecondaryIndexes, types.LocalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(table.HashKey, index.RangeKey),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+	if table.StreamViewType != "" {
+		input.StreamSpecification = &types.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: table.StreamViewType,
+		}
+	}
+	return input
+}
+
+func keySchema(hash, rangeKey Key) []types.KeySchemaElement {
+	schema := []types.KeySchemaElement{{AttributeName: aws.String(hash.Name), KeyType: types.KeyTypeHash}}
+	if rangeKey.Name != "" {
+		schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(rangeKey.Name), KeyType: types.KeyTypeRange})
+	}
+	return schema
+}
+
+// attributeDefinitions lists each key attribute of the table and indexes once
+func attributeDefinitions(hash, rangeKey Key, indexes ...Index) []types.AttributeDefinition {
+	keys := []Key{hash, rangeKey}
+	for _, index := range indexes {
+		keys = append(keys, index.HashKey, index.RangeKey)
+	}
+
+	defs := make([]types.AttributeDefinition, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.Name == "" || seen[key.Name] {
+			continue
+		}
+		seen[key.Name] = true
+		defs = append(defs, types.AttributeDefinition{AttributeName: aws.String(key.Name), AttributeType: key.Type})
+	}
+	return defs
+}
@@ -0,0 +1,315 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeClient keeps table descriptions and migration records in memory.
+// Tables and indexes become active as soon as they are created.
+type fakeClient struct {
+	tables  map[string]*types.TableDescription
+	records map[string][]map[string]types.AttributeValue
+	creates []string
+	updates []string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		tables:  make(map[string]*types.TableDescription),
+		records: make(map[string][]map[string]types.AttributeValue),
+	}
+}
+
+func (c *fakeClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	desc, ok := c.tables[aws.ToString(params.TableName)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}
+	}
+	return &dynamodb.DescribeTableOutput{Table: desc}, nil
+}
+
+func (c *fakeClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	name := aws.ToString(params.TableName)
+	desc := &types.TableDescription{TableName: params.TableName, TableStatus: types.TableStatusActive}
+	for _, index := range params.GlobalSecondaryIndexes {
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+			IndexName:   index.IndexName,
+			IndexStatus: types.IndexStatusActive,
+		})
+	}
+	for _, index := range params.LocalSecondaryIndexes {
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, types.LocalSecondaryIndexDescription{
+			IndexName: index.IndexName,
+		})
+	}
+	c.tables[name] = desc
+	c.creates = append(c.creates, name)
+	return &dynamodb.CreateTableOutput{TableDescription: desc}, nil
+}
+
+func (c *fakeClient) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	desc := c.tables[aws.ToString(params.TableName)]
+	for _, update := range params.GlobalSecondaryIndexUpdates {
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+			IndexName:   update.Create.IndexName,
+			IndexStatus: types.IndexStatusActive,
+		})
+		c.updates = append(c.updates, aws.ToString(update.Create.IndexName))
+	}
+	return &dynamodb.UpdateTableOutput{TableDescription: desc}, nil
+}
+
+func (c *fakeClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	name := aws.ToString(params.TableName)
+	c.records[name] = append(c.records[name], params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *fakeClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	service := params.ExpressionAttributeValues[":service"].(*types.AttributeValueMemberS).Value
+	var items []map[string]types.AttributeValue
+	for _, item := range c.records[aws.ToString(params.TableName)] {
+		if item["service"].(*types.AttributeValueMemberS).Value == service {
+			items = append(items, item)
+		}
+	}
+	return &dynamodb.QueryOutput{Items: items}, nil
+}
+
+func venuesTable() Table {
+	return Table{
+		Name:    "venues",
+		HashKey: S("id"),
+		GlobalIndexes: []Index{
+			{Name: "CityIndex", HashKey: S("city_state"), RangeKey: S("name")},
+			{Name: "VenueTypeIndex", HashKey: S("venue_type"), RangeKey: S("rating_id")},
+		},
+	}
+}
+
+func TestEnsure_CreatesMissingTable(t *testing.T) {
+	client := newFakeClient()
+	migrator := NewMigrator(client, "migrations", "bookings")
+
+	if err := migrator.Ensure(context.Background(), venuesTable()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.creates) != 1 || client.creates[0] != "venues" {
+		t.Fatalf("expected venues to be created, got %v", client.creates)
+	}
+	if got := len(client.tables["venues"].GlobalSecondaryIndexes); got != 2 {
+		t.Errorf("expected 2 global indexes, got %d", got)
+	}
+}
+
+func TestEnsure_AddsMissingGlobalIndex(t *testing.T) {
+	client := newFakeClient()
+	client.tables["venues"] = &types.TableDescription{
+		TableName:   aws.String("venues"),
+		TableStatus: types.TableStatusActive,
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+			{IndexName: aws.String("CityIndex"), IndexStatus: types.IndexStatusActive},
+		},
+	}
+	migrator := NewMigrator(client, "migrations", "bookings")
+
+	if err := migrator.Ensure(context.Background(), venuesTable()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.creates) != 0 {
+		t.Errorf("expected no tables to be created, got %v", client.creates)
+	}
+	if len(client.updates) != 1 || client.updates[0] != "VenueTypeIndex" {
+		t.Errorf("expected VenueTypeIndex to be added, got %v", client.updates)
+	}
+}
+
+func TestEnsure_UpToDate(t *testing.T) {
+	client := newFakeClient()
+	migrator := NewMigrator(client, "migrations", "bookings")
+	ctx := context.Background()
+
+	if err := migrator.Ensure(ctx, venuesTable()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := migrator.Ensure(ctx, venuesTable()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.creates) != 1 || len(client.updates) != 0 {
+		t.Errorf("expected one create and no updates, got %v and %v", client.creates, client.updates)
+	}
+}
+
+func TestEnsure_MissingLocalIndex(t *testing.T) {
+	client := newFakeClient()
+	client.tables["venue-reviews"] = &types.TableDescription{
+		TableName:   aws.String("venue-reviews"),
+		TableStatus: types.TableStatusActive,
+	}
+	migrator := NewMigrator(client, "migrations", "bookings")
+
+	err := migrator.Ensure(context.Background(), Table{
+		Name:         "venue-reviews",
+		HashKey:      S("venue_id"),
+		RangeKey:     S("artist_id"),
+		LocalIndexes: []Index{{Name: "CreatedIndex", RangeKey: S("created_at")}},
+	})
+
+	var missing *MissingLocalIndexError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected MissingLocalIndexError, got %v", err)
+	}
+	if missing.Index != "CreatedIndex" {
+		t.Errorf("expected CreatedIndex, got %s", missing.Index)
+	}
+}
+
+func TestUp_AppliesPendingInOrder(t *testing.T) {
+	client := newFakeClient()
+	migrator := NewMigrator(client, "migrations", "bookings")
+	ctx := context.Background()
+
+	first := Migration{Version: 1, Description: "venues", Tables: []Table{{Name: "venues", HashKey: S("id")}}}
+	second := Migration{Version: 2, Description: "venue type index", Tables: []Table{venuesTable()}}
+
+	done, err := migrator.Up(ctx, []Migration{first})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(done) != 1 {
+		t.Fatalf("expected 1 migration applied, got %d", len(done))
+	}
+
+	done, err = migrator.Up(ctx, []Migration{second, first})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(done) != 1 || done[0].Version != 2 {
+		t.Fatalf("expected only migration 2 applied, got %v", done)
+	}
+	if len(client.updates) != 2 {
+		t.Errorf("expected both indexes added to the existing table, got %v", client.updates)
+	}
+
+	applied, err := migrator.Applied(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, version := range []int{1, 2} {
+		if !applied[version] {
+			t.Errorf("expected migration %d to be recorded", version)
+		}
+	}
+
+	// Versions are recorded per service
+	other := NewMigrator(client, "migrations", "releases")
+	applied, err = other.Applied(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no migrations for another service, got %v", applied)
+	}
+}
+
+func TestUp_DuplicateVersion(t *testing.T) {
+	client := newFakeClient()
+	migrator := NewMigrator(client, "migrations", "bookings")
+
+	_, err := migrator.Up(context.Background(), []Migration{
+		{Version: 1, Description: "one"},
+		{Version: 1, Description: "again"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a duplicate version")
+	}
+	if len(client.records["migrations"]) != 0 {
+		t.Errorf("expected nothing recorded, got %d records", len(client.records["migrations"]))
+	}
+}
+
+func TestUp_RecordsApplyOnlyOnSuccess(t *testing.T) {
+	client := newFakeClient()
+	migrator := NewMigrator(client, "migrations", "bookings-postgres")
+	ctx := context.Background()
+
+	runs := 0
+	failing := Migration{Version: 1, Description: "venues", Apply: func(ctx context.Context) error {
+		runs++
+		return errors.New("connection refused")
+	}}
+	if _, err := migrator.Up(ctx, []Migration{failing}); err == nil {
+		t.Fatal("expected the apply error")
+	}
+	applied, _ := migrator.Applied(ctx)
+	if applied[1] {
+		t.Error("expected a failed migration to stay unrecorded")
+	}
+
+	succeeding := Migration{Version: 1, Description: "venues", Apply: func(ctx context.Context) error {
+		runs++
+		return nil
+	}}
+	for i := 0; i < 2; i++ {
+		if _, err := migrator.Up(ctx, []Migration{succeeding}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if runs != 2 {
+		t.Errorf("expected apply to run once more after the failure, ran %d times", runs)
+	}
+	applied, _ = migrator.Applied(ctx)
+	if !applied[1] {
+		t.Error("expected migration 1 to be recorded")
+	}
+}
+
+func TestCreateTableInput(t *testing.T) {
+	input := createTableInput(Table{
+		Name:     "venue-events",
+		HashKey:  S("venue_id"),
+		RangeKey: S("event_key"),
+		GlobalIndexes: []Index{
+			{Name: "VenueIndex", HashKey: S("venue_id"), RangeKey: S("date")},
+		},
+		LocalIndexes:   []Index{{Name: "DateIndex", RangeKey: S("date")}},
+		StreamViewType: types.StreamViewTypeNewAndOldImages,
+	})
+
+	// venue_id and date are each defined once
+	if len(input.AttributeDefinitions) != 3 {
+		t.Errorf("expected 3 attribute definitions, got %d", len(input.AttributeDefinitions))
+	}
+	lsi := input.LocalSecondaryIndexes[0].KeySchema
+	if aws.ToString(lsi[0].AttributeName) != "venue_id" || aws.ToString(lsi[1].AttributeName) != "date" {
+		t.Errorf("expected local index keyed by venue_id and date, got %v", lsi)
+	}
+	if input.StreamSpecification == nil || !aws.ToBool(input.StreamSpecification.StreamEnabled) {
+		t.Error("expected the stream to be enabled")
+	}
+	if input.BillingMode != types.BillingModePayPerRequest {
+		t.Errorf("expected pay-per-request billing, got %s", input.BillingMode)
+	}
+}
+
+func TestApplied_BadVersion(t *testing.T) {
+	client := newFakeClient()
+	client.records["migrations"] = []map[string]types.AttributeValue{{
+		"service": &types.AttributeValueMemberS{Value: "bookings"},
+		"version": &types.AttributeValueMemberN{Value: "1.5"},
+	}}
+	migrator := NewMigrator(client, "migrations", "bookings")
+
+	if _, err := migrator.Applied(context.Background()); err == nil {
+		t.Fatal("expected an error for a non-integer version")
+	}
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Channel identifies how a notification is delivered
+type Channel string
+
+const (
+	ChannelWebhook Channel = "webhook"
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+)
+
+// Message is a single notification addressed to one recipient
+type Message struct {
+	Channel Channel        `json:"channel"`
+	To      string         `json:"to"`
+	Subject string         `json:"subject"`
+	Body    string         `json:"body"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Sender delivers notifications
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Mux routes messages to a sender registered for their channel
+type Mux struct {
+	senders map[Channel]Sender
+}
+
+// NewMux creates an empty sender mux
+func NewMux() *Mux {
+	return &Mux{senders: make(map[Channel]Sender)}
+}
+
+// Handle registers the sender used for a channel
+func (m *Mux) Handle(channel Channel, sender Sender) {
+	m.senders[channel] = sender
+}
+
+// Send dispatches the message to the sender registered for its channel
+func (m *Mux) Send(ctx context.Context, msg Message) error {
+	sender, ok := m.senders[msg.Channel]
+	if !ok {
+		return fmt.Errorf("no sender registered for channel %q", msg.Channel)
+	}
+	return sender.Send(ctx, msg)
+}
+
+// Supports reports whether a sender is registered for the channel
+func (m *Mux) Supports(channel Channel) bool {
+	_, ok := m.senders[channel]
+	return ok
+}
+
+// LogSender writes notifications to the process log, for local development
+type LogSender struct{}
+
+// Send logs the message
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("notify[%s] to=%s subject=%q body=%q", msg.Channel, msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// MemorySink records notifications in memory so tests can assert on them
+type MemorySink struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemorySink creates an empty memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Send records the message
+func (s *MemorySink) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of all recorded messages
+func (s *MemorySink) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMux_RoutesByChannel(t *testing.T) {
+	email := NewMemorySink()
+	webhook := NewMemorySink()
+
+	mux := NewMux()
+	mux.Handle(ChannelEmail, email)
+	mux.Handle(ChannelWebhook, webhook)
+
+	err := mux.Send(context.Background(), Message{Channel: ChannelEmail, To: "agent@example.com", Subject: "hi"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if len(email.Messages()) != 1 {
+		t.Errorf("email sink received %v messages, want 1", len(email.Messages()))
+	}
+	if len(webhook.Messages()) != 0 {
+		t.Errorf("webhook sink received %v messages, want 0", len(webhook.Messages()))
+	}
+}
+
+func TestMux_UnknownChannel(t *testing.T) {
+	mux := NewMux()
+
+	if mux.Supports(ChannelEmail) {
+		t.Error("Supports() should be false for unregistered channel")
+	}
+	if err := mux.Send(context.Background(), Message{Channel: ChannelEmail}); err == nil {
+		t.Error("Send() should return error for unregistered channel")
+	}
+}
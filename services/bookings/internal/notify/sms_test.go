@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			t.Errorf("path = %v", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "secret" {
+			t.Errorf("basic auth = %v/%v, want AC123/secret", user, pass)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm() error = %v", err)
+		}
+		if r.Form.Get("To") != "+14155550100" || r.Form.Get("From") != "+14155550199" {
+			t.Errorf("To/From = %v/%v", r.Form.Get("To"), r.Form.Get("From"))
+		}
+		if r.Form.Get("Body") != "Your code is 123456" {
+			t.Errorf("Body = %v", r.Form.Get("Body"))
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(server.URL, "AC123", "secret", "+14155550199", server.Client())
+	err := sender.Send(context.Background(), Message{
+		Channel: ChannelSMS,
+		To:      "+14155550100",
+		Subject: "ignored",
+		Body:    "Your code is 123456",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
+
+func TestTwilioSender_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(server.URL, "AC123", "secret", "+14155550199", server.Client())
+	if err := sender.Send(context.Background(), Message{Channel: ChannelSMS, To: "+1"}); err == nil {
+		t.Error("Send() should fail when the provider rejects the message")
+	}
+}
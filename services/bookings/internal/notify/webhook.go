@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrUnsafeWebhookURL is returned for webhook URLs that are not https or
+// that reach loopback, private or link-local addresses, so that saved
+// searches cannot be used to make requests into the service's own network
+var ErrUnsafeWebhookURL = errors.New("webhook URL must use https and a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, which net.IP does not
+// count as private
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// WebhookSender POSTs notifications as JSON to the recipient URL
+type WebhookSender struct {
+	client *http.Client
+	// checkURL vets each recipient URL before it is posted to
+	checkURL func(ctx context.Context, rawURL string) error
+}
+
+// NewWebhookSender creates a webhook sender. A nil client uses a default with
+// a timeout that refuses to connect to non-public addresses or follow
+// redirects, which also guards against hosts that resolve differently
+// after CheckWebhookURL.
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: 5 * time.Second, Control: refusePrivateAddresses}
+		client = &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &WebhookSender{client: client, checkURL: CheckWebhookURL}
+}
+
+// CheckWebhookURL returns ErrUnsafeWebhookURL unless rawURL uses https and
+// its host resolves only to public addresses
+func CheckWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrUnsafeWebhookURL
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrUnsafeWebhookURL, u.Hostname(), addr.IP)
+		}
+	}
+	return nil
+}
+
+// refusePrivateAddresses is a dialer control that stops connections to
+// non-public addresses once the host has been resolved
+func refusePrivateAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: refusing to connect to %s", ErrUnsafeWebhookURL, host)
+	}
+	return nil
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() && !sharedAddressSpace.Contains(ip)
+}
+
+// Send posts the message to msg.To
+func (s *WebhookSender) Send(ctx context.Context, msg Message) error {
+	if err := s.checkURL(ctx, msg.To); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.To, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newLocalWebhookSender sends to a test server, which is on a loopback
+// address that real senders refuse
+func newLocalWebhookSender(server *httptest.Server) *WebhookSender {
+	sender := NewWebhookSender(server.Client())
+	sender.checkURL = func(context.Context, string) error { return nil }
+	return sender
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %v, want application/json", r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := newLocalWebhookSender(server)
+	err := sender.Send(context.Background(), Message{
+		Channel: ChannelWebhook,
+		To:      server.URL,
+		Subject: "New venue",
+		Data:    map[string]any{"venue_id": "venue-1"},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if received.Subject != "New venue" {
+		t.Errorf("payload subject = %v, want New venue", received.Subject)
+	}
+	if received.Data["venue_id"] != "venue-1" {
+		t.Errorf("payload venue_id = %v, want venue-1", received.Data["venue_id"])
+	}
+}
+
+func TestWebhookSender_Send_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := newLocalWebhookSender(server)
+	if err := sender.Send(context.Background(), Message{To: server.URL}); err == nil {
+		t.Error("Send() should return error for non-2xx response")
+	}
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		safe bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"https://[2606:2800:220:1:248:1893:25c8:1946]/hook", true},
+		{"http://93.184.216.34/hook", false},
+		{"https://localhost/hook", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://10.0.0.5/hook", false},
+		{"https://192.168.1.1/hook", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://100.64.0.1/hook", false},
+		{"https://[::1]/hook", false},
+		{"https://[fd00::1]/hook", false},
+		{"https://[::ffff:127.0.0.1]/hook", false},
+		{"https:///hook", false},
+	}
+	for _, tt := range tests {
+		err := CheckWebhookURL(context.Background(), tt.url)
+		if tt.safe && err != nil {
+			t.Errorf("CheckWebhookURL(%s) error = %v, want nil", tt.url, err)
+		}
+		if !tt.safe && !errors.Is(err, ErrUnsafeWebhookURL) {
+			t.Errorf("CheckWebhookURL(%s) error = %v, want ErrUnsafeWebhookURL", tt.url, err)
+		}
+	}
+}
+
+func TestWebhookSender_Send_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The URL check is skipped so the default client's dialer is what
+	// refuses, as it would for a host that resolved to a public address
+	// when checked
+	sender := NewWebhookSender(nil)
+	sender.checkURL = func(context.Context, string) error { return nil }
+	if err := sender.Send(context.Background(), Message{To: server.URL}); !errors.Is(err, ErrUnsafeWebhookURL) {
+		t.Errorf("Send() to a loopback server error = %v, want ErrUnsafeWebhookURL", err)
+	}
+	if called {
+		t.Error("Send() should not reach a loopback server")
+	}
+}
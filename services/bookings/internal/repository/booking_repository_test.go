@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestBookingRepository_ListByArtist(t *testing.T) {
+	repo := NewMockBookingRepository()
+	ctx := context.Background()
+
+	later := domain.NewBooking("artist-1", "venue-1", time.Now().Add(48*time.Hour), 500)
+	sooner := domain.NewBooking("artist-1", "venue-2", time.Now().Add(24*time.Hour), 500)
+	_ = repo.Create(ctx, later)
+	_ = repo.Create(ctx, sooner)
+	_ = repo.Create(ctx, domain.NewBooking("artist-2", "venue-1", time.Now(), 500))
+
+	results, err := repo.ListByArtist(ctx, "artist-1")
+	if err != nil {
+		t.Fatalf("ListByArtist() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("ListByArtist() returned %v bookings, want 2", len(results))
+	}
+	if results[0].ID != sooner.ID {
+		t.Errorf("ListByArtist() first booking = %v, want %v", results[0].ID, sooner.ID)
+	}
+}
+
+func TestBookingRepository_ListByVenue(t *testing.T) {
+	repo := NewMockBookingRepository()
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, domain.NewBooking("artist-1", "venue-1", time.Now(), 500))
+	_ = repo.Create(ctx, domain.NewBooking("artist-2", "venue-1", time.Now(), 500))
+	_ = repo.Create(ctx, domain.NewBooking("artist-2", "venue-2", time.Now(), 500))
+
+	results, err := repo.ListByVenue(ctx, "venue-1")
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("ListByVenue() returned %v bookings, want 2", len(results))
+	}
+}
+
+func TestBookingRepository_GetByID_NotFound(t *testing.T) {
+	repo := NewMockBookingRepository()
+
+	booking, err := repo.GetByID(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if booking != nil {
+		t.Error("GetByID() should return nil for missing booking")
+	}
+}
@@ -0,0 +1,415 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/cache"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// venueCacheStats counts cache hits, misses and store errors, and is
+// published at /debug/vars
+var venueCacheStats = expvar.NewMap("venue_cache")
+
+// CachedVenueRepository is a read-through cache in front of another venue
+// repository. It caches venues by ID and external ID, and search results by
+// geohash prefix, city and venue type, and drops the entries a write may
+// have changed.
+//
+// Writes made around it, such as review ratings saved by ReviewRepository,
+// must call Invalidate. Entries are otherwise only as fresh as the TTL: a
+// read racing a write can put back what the write replaced. Cache failures
+// are logged and counted, and fall back to the wrapped repository.
+type CachedVenueRepository struct {
+	next  VenueRepository
+	store cache.Store
+	ttl   time.Duration
+}
+
+// NewCachedVenueRepository wraps next with a cache holding entries for ttl
+func NewCachedVenueRepository(next VenueRepository, store cache.Store, ttl time.Duration) *CachedVenueRepository {
+	return &CachedVenueRepository{next: next, store: store, ttl: ttl}
+}
+
+// cachedSearch is a cached search result. A result shorter than the limit
+// it was read with holds every match, so it can answer any limit.
+type cachedSearch struct {
+	Limit  int               `json:"limit"`
+	Venues []json.RawMessage `json:"venues"`
+}
+
+func venueKey(id string) string {
+	return "venue:id:" + id
+}
+
+func cachedExternalIDKey(source domain.DataSource, externalID string) string {
+	return fmt.Sprintf("venue:ext:%s:%s", source, externalID)
+}
+
+func geohashKey(prefix string) string {
+	return "venue:geo:" + prefix
+}
+
+func cityKey(city, state string) string {
+	return fmt.Sprintf("venue:city:%s#%s", city, state)
+}
+
+func venueTypeKey(venueType domain.VenueType) string {
+	return fmt.Sprintf("venue:type:%s", venueType)
+}
+
+// Create saves a venue, dropping the searches it now appears in
+func (r *CachedVenueRepository) Create(ctx context.Context, venue *domain.Venue) error {
+	if err := r.next.Create(ctx, venue); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, venue)
+	return nil
+}
+
+// BatchCreate saves venues, dropping the searches they now appear in. Venues
+// that failed are invalidated too, which is harmless.
+func (r *CachedVenueRepository) BatchCreate(ctx context.Context, venues []*domain.Venue) error {
+	err := r.next.BatchCreate(ctx, venues)
+	r.Invalidate(ctx, venues...)
+	return err
+}
+
+// GetByID returns the cached venue, reading it through on a miss
+func (r *CachedVenueRepository) GetByID(ctx context.Context, id string) (*domain.Venue, error) {
+	if data, ok := r.get(ctx, venueKey(id)); ok {
+		venue, err := unmarshalVenueDocument(data)
+		if err == nil {
+			return venue, nil
+		}
+		r.fail("decode", venueKey(id), err)
+	}
+
+	venue, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.setVenue(ctx, venue)
+	return venue, nil
+}
+
+// Update saves a venue, dropping the entries for what it was and what it
+// is now. The stored venue is read first, as the caller's copy may have
+// moved city, type or location. A version conflict also drops the entries,
+// so that a retry reads the current version.
+func (r *CachedVenueRepository) Update(ctx context.Context, venue *domain.Venue) error {
+	previous, err := r.next.GetByID(ctx, venue.ID)
+	var notFound *VenueNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+
+	err = r.next.Update(ctx, venue)
+	var conflict *VersionConflictError
+	if err != nil && !errors.As(err, &conflict) {
+		return err
+	}
+	if previous != nil {
+		r.Invalidate(ctx, previous, venue)
+	} else {
+		r.Invalidate(ctx, venue)
+	}
+	return err
+}
+
+// Delete removes a venue and drops its entries
+func (r *CachedVenueRepository) Delete(ctx context.Context, id string) error {
+	previous, err := r.next.GetByID(ctx, id)
+	var notFound *VenueNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	if previous != nil {
+		r.Invalidate(ctx, previous)
+	} else {
+		r.delete(ctx, venueKey(id))
+	}
+	return nil
+}
+
+// Merge merges two venues and drops the entries of both. Like Update, a
+// version conflict also drops them.
+func (r *CachedVenueRepository) Merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error {
+	previous, err := r.next.GetByID(ctx, survivor.ID)
+	var notFound *VenueNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+
+	err = r.next.Merge(ctx, survivor, merged, redirect)
+	var conflict *VersionConflictError
+	if err != nil && !errors.As(err, &conflict) {
+		return err
+	}
+	if previous != nil {
+		r.Invalidate(ctx, previous, survivor, merged)
+	} else {
+		r.Invalidate(ctx, survivor, merged)
+	}
+	return err
+}
+
+// SearchByGeohash answers each prefix from its own cached result, so that
+// overlapping searches share entries, and reads missing prefixes through up
+// to maxGeohashQueries at once
+func (r *CachedVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefixes []string, limit int) ([]*domain.Venue, error) {
+	if limit <= 0 {
+		return r.next.SearchByGeohash(ctx, geohashPrefixes, limit)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]*domain.Venue, len(geohashPrefixes))
+	errs := make([]error, len(geohashPrefixes))
+	sem := make(chan struct{}, maxGeohashQueries)
+	var wg sync.WaitGroup
+	for i, prefix := range geohashPrefixes {
+		wg.Add(1)
+		go func(i int, prefix string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				errs[i] = ctx.Err()
+				return
+			}
+
+			results[i], errs[i] = r.search(ctx, geohashKey(prefix), limit, func() ([]*domain.Venue, error) {
+				return r.next.SearchByGeohash(ctx, []string{prefix}, limit)
+			})
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, prefix)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mergeVenues(results, limit), nil
+}
+
+// SearchNearby passes distance searches through uncached, as points and
+// radii rarely repeat. Without a wrapped NearbySearcher it searches the
+// cached geohash cells around the point and keeps the venues in range.
+func (r *CachedVenueRepository) SearchNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*domain.Venue, error) {
+	if nearby, ok := r.next.(NearbySearcher); ok {
+		return nearby.SearchNearby(ctx, lat, lng, radiusKm, limit)
+	}
+
+	venues, err := r.SearchByGeohash(ctx, domain.GetGeohashPrefixes(lat, lng, radiusKm), limit)
+	if err != nil {
+		return nil, err
+	}
+	distances := make(map[string]float64, len(venues))
+	inRange := make([]*domain.Venue, 0, len(venues))
+	for _, venue := range venues {
+		distance := domain.CalculateDistance(lat, lng, venue.Location.Latitude, venue.Location.Longitude)
+		if distance <= radiusKm {
+			distances[venue.ID] = distance
+			inRange = append(inRange, venue)
+		}
+	}
+	sort.SliceStable(inRange, func(i, j int) bool { return distances[inRange[i].ID] < distances[inRange[j].ID] })
+	return inRange, nil
+}
+
+// SearchByCity returns the cached search, reading it through on a miss
+func (r *CachedVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
+	if limit <= 0 {
+		return r.next.SearchByCity(ctx, city, state, limit)
+	}
+	return r.search(ctx, cityKey(city, state), limit, func() ([]*domain.Venue, error) {
+		return r.next.SearchByCity(ctx, city, state, limit)
+	})
+}
+
+// SearchByType returns the cached search, reading it through on a miss
+func (r *CachedVenueRepository) SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error) {
+	if limit <= 0 {
+		return r.next.SearchByType(ctx, venueType, limit)
+	}
+	return r.search(ctx, venueTypeKey(venueType), limit, func() ([]*domain.Venue, error) {
+		return r.next.SearchByType(ctx, venueType, limit)
+	})
+}
+
+// GetByExternalID caches which venue holds an external ID and reads the
+// venue by ID, so both lookups share the venue's entry. A mapping to a
+// venue that no longer holds the ID is read through again.
+func (r *CachedVenueRepository) GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error) {
+	key := cachedExternalIDKey(source, externalID)
+	if id, ok := r.get(ctx, key); ok {
+		venue, err := r.GetByID(ctx, string(id))
+		if err == nil && venue.ExternalIDs()[source] == externalID {
+			return venue, nil
+		}
+	}
+
+	venue, err := r.next.GetByExternalID(ctx, source, externalID)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, key, []byte(venue.ID))
+	r.setVenue(ctx, venue)
+	return venue, nil
+}
+
+// ScanAll reads every venue from the wrapped repository
+func (r *CachedVenueRepository) ScanAll(ctx context.Context, fn func(*domain.Venue) error) error {
+	return r.next.ScanAll(ctx, fn)
+}
+
+// ListByModerationStatus reads from the wrapped repository, as moderators
+// need the queue as it is
+func (r *CachedVenueRepository) ListByModerationStatus(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error) {
+	return r.next.ListByModerationStatus(ctx, status, limit)
+}
+
+// Invalidate drops the entries holding the venues: their own, their
+// external IDs', and those of every search they appear in
+func (r *CachedVenueRepository) Invalidate(ctx context.Context, venues ...*domain.Venue) {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for _, venue := range venues {
+		add(venueKey(venue.ID))
+		for source, externalID := range venue.ExternalIDs() {
+			add(cachedExternalIDKey(source, externalID))
+		}
+		for n := 1; n <= len(venue.Location.Geohash); n++ {
+			add(geohashKey(venue.Location.Geohash[:n]))
+		}
+		add(cityKey(venue.Address.City, venue.Address.State))
+		for _, venueType := range venue.VenueTypes {
+			add(venueTypeKey(venueType))
+		}
+	}
+	r.delete(ctx, keys...)
+}
+
+// search returns a cached search result that can answer limit, or runs
+// read and caches what it returns
+func (r *CachedVenueRepository) search(ctx context.Context, key string, limit int, read func() ([]*domain.Venue, error)) ([]*domain.Venue, error) {
+	if data, ok := r.get(ctx, key); ok {
+		if venues, ok := decodeSearch(data, limit); ok {
+			return venues, nil
+		}
+		venueCacheStats.Add("misses", 1)
+	}
+
+	venues, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := cachedSearch{Limit: limit, Venues: make([]json.RawMessage, 0, len(venues))}
+	for _, venue := range venues {
+		data, err := marshalVenueDocument(venue)
+		if err != nil {
+			r.fail("encode", key, err)
+			return venues, nil
+		}
+		entry.Venues = append(entry.Venues, data)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		r.fail("encode", key, err)
+		return venues, nil
+	}
+	r.set(ctx, key, data)
+	return venues, nil
+}
+
+// decodeSearch returns the first limit venues of a cached search, unless
+// it was read with a smaller limit and may be missing some
+func decodeSearch(data []byte, limit int) ([]*domain.Venue, bool) {
+	var entry cachedSearch
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Limit < limit && len(entry.Venues) >= entry.Limit {
+		return nil, false
+	}
+	if len(entry.Venues) > limit {
+		entry.Venues = entry.Venues[:limit]
+	}
+
+	venues := make([]*domain.Venue, 0, len(entry.Venues))
+	for _, raw := range entry.Venues {
+		venue, err := unmarshalVenueDocument(raw)
+		if err != nil {
+			return nil, false
+		}
+		venues = append(venues, venue)
+	}
+	return venues, true
+}
+
+func (r *CachedVenueRepository) setVenue(ctx context.Context, venue *domain.Venue) {
+	data, err := marshalVenueDocument(venue)
+	if err != nil {
+		r.fail("encode", venueKey(venue.ID), err)
+		return
+	}
+	r.set(ctx, venueKey(venue.ID), data)
+}
+
+// get reads an entry, treating store errors as misses
+func (r *CachedVenueRepository) get(ctx context.Context, key string) ([]byte, bool) {
+	data, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		r.fail("get", key, err)
+		return nil, false
+	}
+	if !ok {
+		venueCacheStats.Add("misses", 1)
+		return nil, false
+	}
+	venueCacheStats.Add("hits", 1)
+	return data, true
+}
+
+func (r *CachedVenueRepository) set(ctx context.Context, key string, data []byte) {
+	if err := r.store.Set(ctx, key, data, r.ttl); err != nil {
+		r.fail("set", key, err)
+	}
+}
+
+func (r *CachedVenueRepository) delete(ctx context.Context, keys ...string) {
+	if err := r.store.Delete(ctx, keys...); err != nil {
+		r.fail("delete", fmt.Sprint(keys), err)
+	}
+}
+
+func (r *CachedVenueRepository) fail(op, key string, err error) {
+	venueCacheStats.Add("errors", 1)
+	log.Printf("venue cache %s %s: %v", op, key, err)
+}
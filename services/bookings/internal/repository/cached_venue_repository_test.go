@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/cache"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// countingVenueRepository counts the reads that reach the mock
+type countingVenueRepository struct {
+	*MockVenueRepository
+	gets     int
+	searches int
+}
+
+func (r *countingVenueRepository) GetByID(ctx context.Context, id string) (*domain.Venue, error) {
+	r.gets++
+	return r.MockVenueRepository.GetByID(ctx, id)
+}
+
+func (r *countingVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
+	r.searches++
+	return r.MockVenueRepository.SearchByCity(ctx, city, state, limit)
+}
+
+func newCachedTestRepo() (*CachedVenueRepository, *countingVenueRepository) {
+	next := &countingVenueRepository{MockVenueRepository: NewMockVenueRepository()}
+	return NewCachedVenueRepository(next, cache.NewLRU(100), time.Minute), next
+}
+
+func TestCachedVenueRepository_GetByIDReadsThrough(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedTestRepo()
+	venue := newTestVenue("The Fillmore")
+	if err := repo.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.GetByID(ctx, venue.ID); err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+	}
+	if next.gets != 1 {
+		t.Errorf("GetByID() read the repository %d times, want 1", next.gets)
+	}
+
+	venue.Name = "The New Fillmore"
+	if err := repo.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Name != venue.Name || got.Version != venue.Version {
+		t.Errorf("GetByID() after Update() = %q version %d, want %q version %d", got.Name, got.Version, venue.Name, venue.Version)
+	}
+}
+
+func TestCachedVenueRepository_UpdateInvalidatesOldAndNewSearches(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newCachedTestRepo()
+	venue := newTestVenue("The Fillmore")
+	if err := repo.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, city := range []string{"San Francisco", "Oakland"} {
+		if _, err := repo.SearchByCity(ctx, city, "CA", 10); err != nil {
+			t.Fatalf("SearchByCity(%s) error = %v", city, err)
+		}
+	}
+
+	venue.Address.City = "Oakland"
+	if err := repo.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tests := map[string]int{"San Francisco": 0, "Oakland": 1}
+	for city, want := range tests {
+		got, err := repo.SearchByCity(ctx, city, "CA", 10)
+		if err != nil {
+			t.Fatalf("SearchByCity(%s) error = %v", city, err)
+		}
+		if len(got) != want {
+			t.Errorf("SearchByCity(%s) after moving the venue returned %d venues, want %d", city, len(got), want)
+		}
+	}
+}
+
+func TestCachedVenueRepository_SearchLimits(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedTestRepo()
+	for _, name := range []string{"A", "B", "C"} {
+		if err := repo.Create(ctx, newTestVenue(name)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		limit    int
+		want     int
+		searches int
+	}{
+		{limit: 2, want: 2, searches: 1},
+		{limit: 1, want: 1, searches: 1},  // A larger limit answers a smaller one
+		{limit: 5, want: 3, searches: 2},  // A full result may be missing venues
+		{limit: 50, want: 3, searches: 2}, // A short result holds every venue
+	}
+	for _, tt := range tests {
+		got, err := repo.SearchByCity(ctx, "San Francisco", "CA", tt.limit)
+		if err != nil {
+			t.Fatalf("SearchByCity(limit %d) error = %v", tt.limit, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("SearchByCity(limit %d) returned %d venues, want %d", tt.limit, len(got), tt.want)
+		}
+		if next.searches != tt.searches {
+			t.Errorf("after SearchByCity(limit %d) the repository was searched %d times, want %d", tt.limit, next.searches, tt.searches)
+		}
+	}
+}
+
+func TestCachedVenueRepository_ConflictInvalidates(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedTestRepo()
+	venue := newTestVenue("The Fillmore")
+	if err := repo.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stale, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	// Another writer updates the venue around the cache
+	venue.Name = "Renamed elsewhere"
+	if err := next.MockVenueRepository.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	stale.Capacity = 100
+	var conflict *VersionConflictError
+	if err := repo.Update(ctx, stale); !errors.As(err, &conflict) {
+		t.Fatalf("Update() of a stale venue error = %v, want VersionConflictError", err)
+	}
+	got, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Version != venue.Version {
+		t.Errorf("GetByID() after a conflict returned version %d, want %d", got.Version, venue.Version)
+	}
+}
+
+func TestCachedVenueRepository_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedTestRepo()
+	venue := newTestVenue("The Fillmore")
+	if err := repo.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, venue.ID); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	venue.Rating = 4.5
+	if err := next.MockVenueRepository.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	repo.Invalidate(ctx, venue)
+
+	got, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Rating != 4.5 {
+		t.Errorf("GetByID() after Invalidate() Rating = %v, want 4.5", got.Rating)
+	}
+}
+
+func TestCachedVenueRepository_SearchNearbyFiltersByDistance(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newCachedTestRepo()
+	points := map[string][2]float64{
+		"Oakland":  {37.8044, -122.2712},
+		"Mission":  {37.7700, -122.4150},
+		"Downtown": {37.7749, -122.4194},
+	}
+	for name, p := range points {
+		venue := newTestVenue(name)
+		venue.Location = domain.GeoPoint{Latitude: p[0], Longitude: p[1], Geohash: domain.EncodeGeohash(p[0], p[1], 7)}
+		if err := repo.Create(ctx, venue); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	got, err := repo.SearchNearby(ctx, 37.7749, -122.4194, 5, 10)
+	if err != nil {
+		t.Fatalf("SearchNearby() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "Downtown" || got[1].Name != "Mission" {
+		names := make([]string, len(got))
+		for i, venue := range got {
+			names[i] = venue.Name
+		}
+		t.Errorf("SearchNearby() = %v, want [Downtown Mission]", names)
+	}
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// ClaimRepository stores venue ownership claims
+type ClaimRepository interface {
+	Create(ctx context.Context, claim *domain.VenueClaim) error
+	GetByID(ctx context.Context, id string) (*domain.VenueClaim, error)
+	Update(ctx context.Context, claim *domain.VenueClaim) error
+	RecordAttempt(ctx context.Context, id string) (*domain.VenueClaim, error)
+	ListByUser(ctx context.Context, userID string, since time.Time) ([]*domain.VenueClaim, error)
+}
+
+// DynamoDBClaimRepository implements ClaimRepository using DynamoDB
+type DynamoDBClaimRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBClaimRepository creates a new DynamoDB claim repository
+func NewDynamoDBClaimRepository(client *dynamodb.Client, tableName string) *DynamoDBClaimRepository {
+	return &DynamoDBClaimRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Create stores a new claim
+func (r *DynamoDBClaimRepository) Create(ctx context.Context, claim *domain.VenueClaim) error {
+	av, err := attributevalue.MarshalMap(claim)
+	if err != nil {
+		return fmt.Errorf("failed to marshal venue claim: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create venue claim: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a claim by ID
+func (r *DynamoDBClaimRepository) GetByID(ctx context.Context, id string) (*domain.VenueClaim, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue claim: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &ClaimNotFoundError{}
+	}
+
+	var claim domain.VenueClaim
+	if err := attributevalue.UnmarshalMap(result.Item, &claim); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue claim: %w", err)
+	}
+
+	return &claim, nil
+}
+
+// Update saves a pending claim's new status. Claims only leave the pending
+// status once, so Update fails with ClaimClosedError if another request
+// has already confirmed or failed the claim.
+func (r *DynamoDBClaimRepository) Update(ctx context.Context, claim *domain.VenueClaim) error {
+	av, err := attributevalue.MarshalMap(claim)
+	if err != nil {
+		return fmt.Errorf("failed to marshal venue claim: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(id) AND #status = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: string(domain.ClaimPending)},
+		},
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return &ClaimClosedError{}
+		}
+		return fmt.Errorf("failed to update venue claim: %w", err)
+	}
+
+	return nil
+}
+
+// RecordAttempt counts an attempt at a claim's code and returns the claim
+// as it stands afterwards. The count is raised atomically and only while
+// the claim is pending with attempts left, so concurrent requests cannot
+// make more than domain.MaxClaimAttempts guesses between them.
+func (r *DynamoDBClaimRepository) RecordAttempt(ctx context.Context, id string) (*domain.VenueClaim, error) {
+	result, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:    aws.String("ADD attempts :one"),
+		ConditionExpression: aws.String("attribute_exists(id) AND attempts < :max AND #status = :pending"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":     &types.AttributeValueMemberN{Value: "1"},
+			":max":     &types.AttributeValueMemberN{Value: strconv.Itoa(domain.MaxClaimAttempts)},
+			":pending": &types.AttributeValueMemberS{Value: string(domain.ClaimPending)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return nil, &ClaimClosedError{}
+		}
+		return nil, fmt.Errorf("failed to record venue claim attempt: %w", err)
+	}
+
+	var claim domain.VenueClaim
+	if err := attributevalue.UnmarshalMap(result.Attributes, &claim); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue claim: %w", err)
+	}
+
+	return &claim, nil
+}
+
+// ListByUser lists the claims a user has opened since a time, on any venue
+func (r *DynamoDBClaimRepository) ListByUser(ctx context.Context, userID string, since time.Time) ([]*domain.VenueClaim, error) {
+	claims := make([]*domain.VenueClaim, 0)
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("UserIndex"),
+		KeyConditionExpression: aws.String("user_id = :user_id AND created_at >= :since"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":user_id": &types.AttributeValueMemberS{Value: userID},
+			":since":   &types.AttributeValueMemberS{Value: since.UTC().Format(time.RFC3339)},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query venue claims by user: %w", err)
+		}
+
+		var batch []*domain.VenueClaim
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal venue claims: %w", err)
+		}
+		for _, claim := range batch {
+			// The key compares timestamps as text, so check them as times
+			if !claim.CreatedAt.Before(since) {
+				claims = append(claims, claim)
+			}
+		}
+	}
+
+	return claims, nil
+}
+
+// ClaimNotFoundError is returned when a venue claim is not found
+type ClaimNotFoundError struct{}
+
+func (e *ClaimNotFoundError) Error() string {
+	return "venue claim not found"
+}
+
+// ClaimClosedError is returned when a claim is no longer pending or has no
+// attempts left
+type ClaimClosedError struct{}
+
+func (e *ClaimClosedError) Error() string {
+	return "venue claim is no longer open"
+}
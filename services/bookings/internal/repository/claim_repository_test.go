@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestClaimRepository_UpdateKeepsCopy(t *testing.T) {
+	repo := NewMockClaimRepository()
+	ctx := context.Background()
+
+	claim, _, err := domain.NewVenueClaim("venue-1", "user-1", domain.ClaimByEmail, "a@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("NewVenueClaim() error = %v", err)
+	}
+	if err := repo.Create(ctx, claim); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	claim.Attempts++
+	stored, _ := repo.GetByID(ctx, claim.ID)
+	if stored.Attempts != 0 {
+		t.Errorf("stored attempts = %v before Update, want 0", stored.Attempts)
+	}
+
+	if err := repo.Update(ctx, claim); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	stored, _ = repo.GetByID(ctx, claim.ID)
+	if stored.Attempts != 1 {
+		t.Errorf("stored attempts = %v, want 1", stored.Attempts)
+	}
+
+	if _, err := repo.GetByID(ctx, "missing"); err == nil {
+		t.Error("GetByID() should fail for a missing claim")
+	}
+}
+
+func TestClaimRepository_RecordAttemptStopsAtLimit(t *testing.T) {
+	repo := NewMockClaimRepository()
+	ctx := context.Background()
+
+	claim, _, err := domain.NewVenueClaim("venue-1", "user-1", domain.ClaimByEmail, "a@example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("NewVenueClaim() error = %v", err)
+	}
+	if err := repo.Create(ctx, claim); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for i := 1; i <= domain.MaxClaimAttempts; i++ {
+		got, err := repo.RecordAttempt(ctx, claim.ID)
+		if err != nil {
+			t.Fatalf("RecordAttempt() %d error = %v", i, err)
+		}
+		if got.Attempts != i {
+			t.Errorf("RecordAttempt() %d attempts = %d, want %d", i, got.Attempts, i)
+		}
+	}
+	var closed *ClaimClosedError
+	if _, err := repo.RecordAttempt(ctx, claim.ID); !errors.As(err, &closed) {
+		t.Errorf("RecordAttempt() past the limit error = %v, want ClaimClosedError", err)
+	}
+}
@@ -0,0 +1,30 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/cache"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/repository/repositorytest"
+)
+
+// The DynamoDB repositories run the same suites in tests/integration
+
+func TestMockVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		return repository.NewMockVenueRepository()
+	})
+}
+
+func TestMockBookingRepository_Conformance(t *testing.T) {
+	repositorytest.TestBookingRepository(t, func(t *testing.T) repository.BookingRepository {
+		return repository.NewMockBookingRepository()
+	})
+}
+
+func TestCachedVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		return repository.NewCachedVenueRepository(repository.NewMockVenueRepository(), cache.NewLRU(1000), time.Minute)
+	})
+}
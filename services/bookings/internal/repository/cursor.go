@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// InvalidCursorError is returned when a pagination cursor cannot be decoded
+type InvalidCursorError struct{}
+
+func (e *InvalidCursorError) Error() string {
+	return "invalid cursor"
+}
+
+// encodeCursor turns a query's LastEvaluatedKey into an opaque token. Only
+// string key attributes are supported, which covers every table here that
+// is paged. An empty key means there are no more pages.
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	values := make(map[string]string, len(key))
+	for name, av := range key {
+		s, ok := av.(*types.AttributeValueMemberS)
+		if !ok {
+			return "", fmt.Errorf("cursor key %s is not a string", name)
+		}
+		values[name] = s.Value
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor turns a token from encodeCursor back into an ExclusiveStartKey
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, &InvalidCursorError{}
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil || len(values) == 0 {
+		return nil, &InvalidCursorError{}
+	}
+
+	key := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return key, nil
+}
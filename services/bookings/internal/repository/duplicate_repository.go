@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// DuplicateRepository stores the duplicate review queue and merged venue redirects
+type DuplicateRepository interface {
+	// SaveCandidate queues a candidate and reports false if the pair was already queued
+	SaveCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) (bool, error)
+	GetCandidate(ctx context.Context, id string) (*domain.DuplicateCandidate, error)
+	ListCandidates(ctx context.Context, status domain.DuplicateStatus, limit int) ([]*domain.DuplicateCandidate, error)
+	UpdateCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) error
+	CreateRedirect(ctx context.Context, redirect *domain.VenueRedirect) error
+	GetRedirect(ctx context.Context, fromID string) (*domain.VenueRedirect, error)
+}
+
+// DynamoDBDuplicateRepository implements DuplicateRepository using DynamoDB
+type DynamoDBDuplicateRepository struct {
+	client         *dynamodb.Client
+	tableName      string
+	redirectsTable string
+}
+
+// NewDynamoDBDuplicateRepository creates a new DynamoDB duplicate repository
+func NewDynamoDBDuplicateRepository(client *dynamodb.Client, tableName, redirectsTable string) *DynamoDBDuplicateRepository {
+	return &DynamoDBDuplicateRepository{
+		client:         client,
+		tableName:      tableName,
+		redirectsTable: redirectsTable,
+	}
+}
+
+// SaveCandidate stores a candidate unless the pair has been queued before
+func (r *DynamoDBDuplicateRepository) SaveCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) (bool, error) {
+	av, err := attributevalue.MarshalMap(candidate)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal duplicate candidate: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to save duplicate candidate: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetCandidate retrieves a candidate pair by ID
+func (r *DynamoDBDuplicateRepository) GetCandidate(ctx context.Context, id string) (*domain.DuplicateCandidate, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate candidate: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &DuplicateNotFoundError{}
+	}
+
+	var candidate domain.DuplicateCandidate
+	if err := attributevalue.UnmarshalMap(result.Item, &candidate); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal duplicate candidate: %w", err)
+	}
+
+	return &candidate, nil
+}
+
+// ListCandidates lists candidates in a review state, oldest first
+func (r *DynamoDBDuplicateRepository) ListCandidates(ctx context.Context, status domain.DuplicateStatus, limit int) ([]*domain.DuplicateCandidate, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("StatusIndex"),
+		KeyConditionExpression: aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status": &types.AttributeValueMemberS{Value: string(status)},
+		},
+		Limit: aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate candidates: %w", err)
+	}
+
+	candidates := make([]*domain.DuplicateCandidate, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal duplicate candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// UpdateCandidate overwrites a candidate, typically after it is resolved
+func (r *DynamoDBDuplicateRepository) UpdateCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) error {
+	av, err := attributevalue.MarshalMap(candidate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal duplicate candidate: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update duplicate candidate: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRedirect records that a merged venue ID now resolves to another venue
+func (r *DynamoDBDuplicateRepository) CreateRedirect(ctx context.Context, redirect *domain.VenueRedirect) error {
+	av, err := attributevalue.MarshalMap(redirect)
+	if err != nil {
+		return fmt.Errorf("failed to marshal venue redirect: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.redirectsTable),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create venue redirect: %w", err)
+	}
+
+	return nil
+}
+
+// GetRedirect looks up where a merged venue ID points, returning nil if it was never merged
+func (r *DynamoDBDuplicateRepository) GetRedirect(ctx context.Context, fromID string) (*domain.VenueRedirect, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.redirectsTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: fromID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue redirect: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var redirect domain.VenueRedirect
+	if err := attributevalue.UnmarshalMap(result.Item, &redirect); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue redirect: %w", err)
+	}
+
+	return &redirect, nil
+}
+
+// DuplicateNotFoundError is returned when a duplicate candidate is not found
+type DuplicateNotFoundError struct{}
+
+func (e *DuplicateNotFoundError) Error() string {
+	return "duplicate candidate not found"
+}
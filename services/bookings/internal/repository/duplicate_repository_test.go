@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestDuplicateRepository_SaveCandidate_OncePerPair(t *testing.T) {
+	repo := NewMockDuplicateRepository()
+	ctx := context.Background()
+
+	a := domain.NewVenue("A", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	b := domain.NewVenue("B", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+
+	created, err := repo.SaveCandidate(ctx, domain.NewDuplicateCandidate(a, b, domain.DuplicateScore{Total: 0.7}))
+	if err != nil {
+		t.Fatalf("SaveCandidate() error = %v", err)
+	}
+	if !created {
+		t.Error("SaveCandidate() should report first save as created")
+	}
+
+	created, _ = repo.SaveCandidate(ctx, domain.NewDuplicateCandidate(b, a, domain.DuplicateScore{Total: 0.7}))
+	if created {
+		t.Error("SaveCandidate() should not queue the same pair twice")
+	}
+}
+
+func TestDuplicateRepository_ListCandidates_ByStatus(t *testing.T) {
+	repo := NewMockDuplicateRepository()
+	ctx := context.Background()
+
+	venues := make([]*domain.Venue, 4)
+	for i := range venues {
+		venues[i] = domain.NewVenue("V", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	}
+	pending := domain.NewDuplicateCandidate(venues[0], venues[1], domain.DuplicateScore{})
+	dismissed := domain.NewDuplicateCandidate(venues[2], venues[3], domain.DuplicateScore{})
+	dismissed.Resolve(domain.DuplicateDismissed, "moderator-1", "")
+	_, _ = repo.SaveCandidate(ctx, pending)
+	_, _ = repo.SaveCandidate(ctx, dismissed)
+
+	results, err := repo.ListCandidates(ctx, domain.DuplicatePending, 10)
+	if err != nil {
+		t.Fatalf("ListCandidates() error = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != pending.ID {
+		t.Errorf("ListCandidates() = %v, want only %v", results, pending.ID)
+	}
+}
+
+func TestDuplicateRepository_Redirects(t *testing.T) {
+	repo := NewMockDuplicateRepository()
+	ctx := context.Background()
+
+	_ = repo.CreateRedirect(ctx, &domain.VenueRedirect{FromID: "old", ToID: "new", CreatedAt: time.Now()})
+
+	redirect, err := repo.GetRedirect(ctx, "old")
+	if err != nil {
+		t.Fatalf("GetRedirect() error = %v", err)
+	}
+	if redirect == nil || redirect.ToID != "new" {
+		t.Errorf("GetRedirect() = %v, want redirect to new", redirect)
+	}
+
+	redirect, _ = repo.GetRedirect(ctx, "unknown")
+	if redirect != nil {
+		t.Error("GetRedirect() should return nil for a venue that was never merged")
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -10,19 +12,31 @@ import (
 	"github.com/crowdunlocked/services/bookings/internal/domain"
 )
 
-type BookingRepository struct {
+// BookingRepository defines the interface for booking data access
+type BookingRepository interface {
+	Create(ctx context.Context, booking *domain.Booking) error
+	GetByID(ctx context.Context, id string) (*domain.Booking, error)
+	Update(ctx context.Context, booking *domain.Booking) error
+	ListByArtist(ctx context.Context, artistID string) ([]*domain.Booking, error)
+	ListByVenue(ctx context.Context, venueID string) ([]*domain.Booking, error)
+}
+
+// DynamoDBBookingRepository implements BookingRepository using DynamoDB
+type DynamoDBBookingRepository struct {
 	client    *dynamodb.Client
 	tableName string
 }
 
-func NewBookingRepository(client *dynamodb.Client, tableName string) *BookingRepository {
-	return &BookingRepository{
+// NewDynamoDBBookingRepository creates a new DynamoDB booking repository
+func NewDynamoDBBookingRepository(client *dynamodb.Client, tableName string) *DynamoDBBookingRepository {
+	return &DynamoDBBookingRepository{
 		client:    client,
 		tableName: tableName,
 	}
 }
 
-func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
+func (r *DynamoDBBookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
+	booking.Version = 1
 	item, err := attributevalue.MarshalMap(booking)
 	if err != nil {
 		return err
@@ -35,7 +49,7 @@ func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking)
 	return err
 }
 
-func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Booking, error) {
+func (r *DynamoDBBookingRepository) GetByID(ctx context.Context, id string) (*domain.Booking, error) {
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
 		Key: map[string]types.AttributeValue{
@@ -55,15 +69,72 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Boo
 	return &booking, err
 }
 
-func (r *BookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+// Update saves a booking if the stored one is still at booking.Version, and
+// increments the version
+func (r *DynamoDBBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	expected := booking.Version
+	booking.Version = expected + 1
 	item, err := attributevalue.MarshalMap(booking)
 	if err != nil {
+		booking.Version = expected
 		return err
 	}
 
+	condition, names, values := versionCondition(expected)
 	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      item,
+		TableName:                 aws.String(r.tableName),
+		Item:                      item,
+		ConditionExpression:       condition,
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
 	})
+	if err != nil {
+		booking.Version = expected
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return &VersionConflictError{ID: booking.ID, Version: expected}
+		}
+	}
 	return err
 }
+
+// ListByArtist lists an artist's bookings in event date order
+func (r *DynamoDBBookingRepository) ListByArtist(ctx context.Context, artistID string) ([]*domain.Booking, error) {
+	return r.queryIndex(ctx, "ArtistIndex", "artist_id", artistID)
+}
+
+// ListByVenue lists a venue's bookings in event date order
+func (r *DynamoDBBookingRepository) ListByVenue(ctx context.Context, venueID string) ([]*domain.Booking, error) {
+	return r.queryIndex(ctx, "VenueIndex", "venue_id", venueID)
+}
+
+// queryIndex reads every booking with the given key value from a GSI
+func (r *DynamoDBBookingRepository) queryIndex(ctx context.Context, indexName, keyName, keyValue string) ([]*domain.Booking, error) {
+	bookings := make([]*domain.Booking, 0)
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String("#key = :value"),
+		ExpressionAttributeNames: map[string]string{
+			"#key": keyName,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":value": &types.AttributeValueMemberS{Value: keyValue},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query bookings by %s: %w", keyName, err)
+		}
+
+		var batch []*domain.Booking
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal bookings: %w", err)
+		}
+		bookings = append(bookings, batch...)
+	}
+
+	return bookings, nil
+}
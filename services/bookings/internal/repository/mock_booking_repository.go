@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockBookingRepository is an in-memory implementation for testing
+type MockBookingRepository struct {
+	mu       sync.Mutex
+	bookings map[string]*domain.Booking
+}
+
+// NewMockBookingRepository creates a new mock repository
+func NewMockBookingRepository() *MockBookingRepository {
+	return &MockBookingRepository{
+		bookings: make(map[string]*domain.Booking),
+	}
+}
+
+func (r *MockBookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	booking.Version = 1
+	stored := *booking
+	r.bookings[booking.ID] = &stored
+	return nil
+}
+
+func (r *MockBookingRepository) GetByID(ctx context.Context, id string) (*domain.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	booking, ok := r.bookings[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *booking
+	return &found, nil
+}
+
+func (r *MockBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// A missing booking is stored at version 0, as in DynamoDB
+	current, ok := r.bookings[booking.ID]
+	if (ok && current.Version != booking.Version) || (!ok && booking.Version != 0) {
+		return &VersionConflictError{ID: booking.ID, Version: booking.Version}
+	}
+	booking.Version++
+	stored := *booking
+	r.bookings[booking.ID] = &stored
+	return nil
+}
+
+func (r *MockBookingRepository) ListByArtist(ctx context.Context, artistID string) ([]*domain.Booking, error) {
+	return r.filter(func(b *domain.Booking) bool { return b.ArtistID == artistID }), nil
+}
+
+func (r *MockBookingRepository) ListByVenue(ctx context.Context, venueID string) ([]*domain.Booking, error) {
+	return r.filter(func(b *domain.Booking) bool { return b.VenueID == venueID }), nil
+}
+
+func (r *MockBookingRepository) filter(match func(*domain.Booking) bool) []*domain.Booking {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.Booking, 0)
+	for _, booking := range r.bookings {
+		if match(booking) {
+			found := *booking
+			results = append(results, &found)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].EventDate.Before(results[j].EventDate) })
+	return results
+}
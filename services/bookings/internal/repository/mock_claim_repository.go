@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockClaimRepository is an in-memory implementation for testing
+type MockClaimRepository struct {
+	mu     sync.Mutex
+	claims map[string]domain.VenueClaim
+}
+
+// NewMockClaimRepository creates a new mock repository
+func NewMockClaimRepository() *MockClaimRepository {
+	return &MockClaimRepository{
+		claims: make(map[string]domain.VenueClaim),
+	}
+}
+
+func (r *MockClaimRepository) Create(ctx context.Context, claim *domain.VenueClaim) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claims[claim.ID] = *claim
+	return nil
+}
+
+func (r *MockClaimRepository) GetByID(ctx context.Context, id string) (*domain.VenueClaim, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	claim, ok := r.claims[id]
+	if !ok {
+		return nil, &ClaimNotFoundError{}
+	}
+	return &claim, nil
+}
+
+func (r *MockClaimRepository) Update(ctx context.Context, claim *domain.VenueClaim) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.claims[claim.ID]
+	if !ok {
+		return &ClaimNotFoundError{}
+	}
+	if stored.Status != domain.ClaimPending {
+		return &ClaimClosedError{}
+	}
+	r.claims[claim.ID] = *claim
+	return nil
+}
+
+func (r *MockClaimRepository) RecordAttempt(ctx context.Context, id string) (*domain.VenueClaim, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	claim, ok := r.claims[id]
+	if !ok || claim.Status != domain.ClaimPending || claim.Attempts >= domain.MaxClaimAttempts {
+		return nil, &ClaimClosedError{}
+	}
+	claim.Attempts++
+	r.claims[id] = claim
+	return &claim, nil
+}
+
+func (r *MockClaimRepository) ListByUser(ctx context.Context, userID string, since time.Time) ([]*domain.VenueClaim, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	claims := make([]*domain.VenueClaim, 0)
+	for _, claim := range r.claims {
+		if claim.UserID == userID && !claim.CreatedAt.Before(since) {
+			claim := claim
+			claims = append(claims, &claim)
+		}
+	}
+	return claims, nil
+}
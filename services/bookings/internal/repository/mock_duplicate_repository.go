@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockDuplicateRepository is an in-memory implementation for testing
+type MockDuplicateRepository struct {
+	mu         sync.Mutex
+	candidates map[string]*domain.DuplicateCandidate
+	redirects  map[string]*domain.VenueRedirect
+}
+
+// NewMockDuplicateRepository creates a new mock repository
+func NewMockDuplicateRepository() *MockDuplicateRepository {
+	return &MockDuplicateRepository{
+		candidates: make(map[string]*domain.DuplicateCandidate),
+		redirects:  make(map[string]*domain.VenueRedirect),
+	}
+}
+
+func (r *MockDuplicateRepository) SaveCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.candidates[candidate.ID]; ok {
+		return false, nil
+	}
+	r.candidates[candidate.ID] = candidate
+	return true, nil
+}
+
+func (r *MockDuplicateRepository) GetCandidate(ctx context.Context, id string) (*domain.DuplicateCandidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	candidate, ok := r.candidates[id]
+	if !ok {
+		return nil, &DuplicateNotFoundError{}
+	}
+	return candidate, nil
+}
+
+func (r *MockDuplicateRepository) ListCandidates(ctx context.Context, status domain.DuplicateStatus, limit int) ([]*domain.DuplicateCandidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.DuplicateCandidate, 0)
+	for _, candidate := range r.candidates {
+		if candidate.Status == status {
+			results = append(results, candidate)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].CreatedAt.Before(results[j].CreatedAt) })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (r *MockDuplicateRepository) UpdateCandidate(ctx context.Context, candidate *domain.DuplicateCandidate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.candidates[candidate.ID]; !ok {
+		return &DuplicateNotFoundError{}
+	}
+	r.candidates[candidate.ID] = candidate
+	return nil
+}
+
+func (r *MockDuplicateRepository) CreateRedirect(ctx context.Context, redirect *domain.VenueRedirect) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redirects[redirect.FromID] = redirect
+	return nil
+}
+
+func (r *MockDuplicateRepository) GetRedirect(ctx context.Context, fromID string) (*domain.VenueRedirect, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.redirects[fromID], nil
+}
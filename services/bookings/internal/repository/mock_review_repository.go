@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockReviewRepository is an in-memory implementation for testing. Saves
+// update venues in the given mock venue repository.
+type MockReviewRepository struct {
+	mu      sync.Mutex
+	reviews map[string]map[string]domain.VenueReview
+	venues  *MockVenueRepository
+}
+
+// NewMockReviewRepository creates a new mock repository
+func NewMockReviewRepository(venues *MockVenueRepository) *MockReviewRepository {
+	return &MockReviewRepository{
+		reviews: make(map[string]map[string]domain.VenueReview),
+		venues:  venues,
+	}
+}
+
+func (r *MockReviewRepository) Get(ctx context.Context, venueID, artistID string) (*domain.VenueReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	review, ok := r.reviews[venueID][artistID]
+	if !ok {
+		return nil, &ReviewNotFoundError{}
+	}
+	return &review, nil
+}
+
+func (r *MockReviewRepository) Save(ctx context.Context, review *domain.VenueReview, venue *domain.Venue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.venues.Update(ctx, venue); err != nil {
+		return err
+	}
+	if r.reviews[review.VenueID] == nil {
+		r.reviews[review.VenueID] = make(map[string]domain.VenueReview)
+	}
+	r.reviews[review.VenueID][review.ArtistID] = *review
+	return nil
+}
+
+// ListByVenue pages by offset; the cursor is the offset of the next page
+func (r *MockReviewRepository) ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueReview, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, "", &InvalidCursorError{}
+		}
+	}
+
+	all := make([]*domain.VenueReview, 0, len(r.reviews[venueID]))
+	for _, review := range r.reviews[venueID] {
+		review := review
+		all = append(all, &review)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := min(offset+limit, len(all))
+	next := ""
+	if end < len(all) {
+		next = strconv.Itoa(end)
+	}
+	return all[offset:end], next, nil
+}
+
+func (r *MockReviewRepository) Move(ctx context.Context, review *domain.VenueReview, venueID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reviews[review.VenueID], review.ArtistID)
+	review.VenueID = venueID
+	if r.reviews[venueID] == nil {
+		r.reviews[venueID] = make(map[string]domain.VenueReview)
+	}
+	r.reviews[venueID][review.ArtistID] = *review
+	return nil
+}
+
+func (r *MockReviewRepository) Delete(ctx context.Context, venueID, artistID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reviews[venueID], artistID)
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockSavedSearchRepository is an in-memory implementation for testing
+type MockSavedSearchRepository struct {
+	mu       sync.Mutex
+	searches map[string]*domain.SavedSearch
+	matches  map[string]map[string]*domain.SavedSearchMatch
+}
+
+// NewMockSavedSearchRepository creates a new mock repository
+func NewMockSavedSearchRepository() *MockSavedSearchRepository {
+	return &MockSavedSearchRepository{
+		searches: make(map[string]*domain.SavedSearch),
+		matches:  make(map[string]map[string]*domain.SavedSearchMatch),
+	}
+}
+
+func (r *MockSavedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.searches[search.ID] = search
+	return nil
+}
+
+func (r *MockSavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	search, ok := r.searches[id]
+	if !ok {
+		return nil, &SavedSearchNotFoundError{}
+	}
+	return search, nil
+}
+
+func (r *MockSavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.SavedSearch, 0)
+	for _, search := range r.searches {
+		if search.UserID == userID {
+			results = append(results, search)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].CreatedAt.Before(results[j].CreatedAt) })
+	return results, nil
+}
+
+func (r *MockSavedSearchRepository) ListAll(ctx context.Context) ([]*domain.SavedSearch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.SavedSearch, 0, len(r.searches))
+	for _, search := range r.searches {
+		results = append(results, search)
+	}
+	return results, nil
+}
+
+func (r *MockSavedSearchRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.searches[id]; !ok {
+		return &SavedSearchNotFoundError{}
+	}
+	delete(r.searches, id)
+	delete(r.matches, id)
+	return nil
+}
+
+func (r *MockSavedSearchRepository) RecordMatch(ctx context.Context, match *domain.SavedSearchMatch) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.matches[match.SavedSearchID] == nil {
+		r.matches[match.SavedSearchID] = make(map[string]*domain.SavedSearchMatch)
+	}
+	if _, ok := r.matches[match.SavedSearchID][match.VenueID]; ok {
+		return false, nil
+	}
+	r.matches[match.SavedSearchID][match.VenueID] = match
+	return true, nil
+}
+
+func (r *MockSavedSearchRepository) GetMatch(ctx context.Context, savedSearchID, venueID string) (*domain.SavedSearchMatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match, ok := r.matches[savedSearchID][venueID]
+	if !ok {
+		return nil, &SavedSearchMatchNotFoundError{}
+	}
+	copied := *match
+	return &copied, nil
+}
+
+func (r *MockSavedSearchRepository) UpdateMatch(ctx context.Context, match *domain.SavedSearchMatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.matches[match.SavedSearchID] == nil {
+		r.matches[match.SavedSearchID] = make(map[string]*domain.SavedSearchMatch)
+	}
+	r.matches[match.SavedSearchID][match.VenueID] = match
+	return nil
+}
+
+func (r *MockSavedSearchRepository) ListMatches(ctx context.Context, savedSearchID string) ([]*domain.SavedSearchMatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.SavedSearchMatch, 0)
+	for _, match := range r.matches[savedSearchID] {
+		results = append(results, match)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].MatchedAt.Before(results[j].MatchedAt) })
+	return results, nil
+}
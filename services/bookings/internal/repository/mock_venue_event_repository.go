@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockVenueEventRepository is an in-memory implementation for testing
+type MockVenueEventRepository struct {
+	mu     sync.Mutex
+	events map[string]map[string]domain.VenueEvent
+}
+
+// NewMockVenueEventRepository creates a new mock repository
+func NewMockVenueEventRepository() *MockVenueEventRepository {
+	return &MockVenueEventRepository{
+		events: make(map[string]map[string]domain.VenueEvent),
+	}
+}
+
+func (r *MockVenueEventRepository) Save(ctx context.Context, events []*domain.VenueEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range events {
+		if r.events[event.VenueID] == nil {
+			r.events[event.VenueID] = make(map[string]domain.VenueEvent)
+		}
+		r.events[event.VenueID][event.EventKey] = *event
+	}
+	return nil
+}
+
+// ListByVenue pages by offset; the cursor is the offset of the next page
+func (r *MockVenueEventRepository) ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueEvent, string, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, "", &InvalidCursorError{}
+		}
+	}
+
+	all := r.sorted(venueID, time.Time{})
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := min(offset+limit, len(all))
+	next := ""
+	if end < len(all) {
+		next = strconv.Itoa(end)
+	}
+	return all[offset:end], next, nil
+}
+
+func (r *MockVenueEventRepository) ListSince(ctx context.Context, venueID string, since time.Time) ([]*domain.VenueEvent, error) {
+	return r.sorted(venueID, since), nil
+}
+
+// sorted returns a venue's events on or after since, latest first
+func (r *MockVenueEventRepository) sorted(venueID string, since time.Time) []*domain.VenueEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]*domain.VenueEvent, 0, len(r.events[venueID]))
+	for _, event := range r.events[venueID] {
+		if event.Date.Before(since) {
+			continue
+		}
+		event := event
+		events = append(events, &event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Date.Equal(events[j].Date) {
+			return events[i].Date.After(events[j].Date)
+		}
+		return events[i].EventKey < events[j].EventKey
+	})
+	return events
+}
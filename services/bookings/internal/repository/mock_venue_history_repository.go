@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// MockVenueHistoryRepository is an in-memory implementation for testing
+type MockVenueHistoryRepository struct {
+	mu       sync.Mutex
+	versions map[string][]*domain.VenueVersion
+}
+
+// NewMockVenueHistoryRepository creates a new mock repository
+func NewMockVenueHistoryRepository() *MockVenueHistoryRepository {
+	return &MockVenueHistoryRepository{
+		versions: make(map[string][]*domain.VenueVersion),
+	}
+}
+
+func (r *MockVenueHistoryRepository) Append(ctx context.Context, version *domain.VenueVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	version.Version = len(r.versions[version.VenueID]) + 1
+	r.versions[version.VenueID] = append(r.versions[version.VenueID], version)
+	return nil
+}
+
+func (r *MockVenueHistoryRepository) List(ctx context.Context, venueID string, limit int) ([]*domain.VenueVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.versions[venueID]
+	results := make([]*domain.VenueVersion, 0, len(stored))
+	for i := len(stored) - 1; i >= 0 && len(results) < limit; i-- {
+		results = append(results, stored[i])
+	}
+	return results, nil
+}
+
+func (r *MockVenueHistoryRepository) Get(ctx context.Context, venueID string, version int) (*domain.VenueVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.versions[venueID]
+	if version < 1 || version > len(stored) {
+		return nil, &VenueVersionNotFoundError{}
+	}
+	return stored[version-1], nil
+}
@@ -2,13 +2,16 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
 )
 
 // MockVenueRepository is an in-memory implementation for testing
 type MockVenueRepository struct {
-	venues map[string]*domain.Venue
+	venues    map[string]*domain.Venue
+	redirects RedirectWriter
 }
 
 // NewMockVenueRepository creates a new mock repository
@@ -19,7 +22,24 @@ func NewMockVenueRepository() *MockVenueRepository {
 }
 
 func (r *MockVenueRepository) Create(ctx context.Context, venue *domain.Venue) error {
-	r.venues[venue.ID] = venue
+	if err := r.checkExternalIDs(venue); err != nil {
+		return err
+	}
+	venue.Version = 1
+	r.venues[venue.ID] = venue.Clone()
+	return nil
+}
+
+func (r *MockVenueRepository) BatchCreate(ctx context.Context, venues []*domain.Venue) error {
+	failed := make(map[string]error)
+	for _, venue := range venues {
+		if err := r.Create(ctx, venue); err != nil {
+			failed[venue.ID] = err
+		}
+	}
+	if len(failed) > 0 {
+		return &BatchWriteError{Failed: failed}
+	}
 	return nil
 }
 
@@ -28,21 +48,71 @@ func (r *MockVenueRepository) GetByID(ctx context.Context, id string) (*domain.V
 	if !ok {
 		return nil, &VenueNotFoundError{}
 	}
-	return venue, nil
+	return venue.Clone(), nil
 }
 
 func (r *MockVenueRepository) Update(ctx context.Context, venue *domain.Venue) error {
-	if _, ok := r.venues[venue.ID]; !ok {
+	stored, ok := r.venues[venue.ID]
+	if !ok {
 		return &VenueNotFoundError{}
 	}
-	r.venues[venue.ID] = venue
+	if stored.Version != venue.Version {
+		return &VersionConflictError{ID: venue.ID, Version: venue.Version}
+	}
+	if err := r.checkExternalIDs(venue); err != nil {
+		return err
+	}
+	venue.Version++
+	r.venues[venue.ID] = venue.Clone()
 	return nil
 }
 
-func (r *MockVenueRepository) Delete(ctx context.Context, id string) error {
-	if _, ok := r.venues[id]; !ok {
+// SetRedirects sets where Merge records redirects, standing in for the
+// redirects table shared with the duplicate repository
+func (r *MockVenueRepository) SetRedirects(redirects RedirectWriter) {
+	r.redirects = redirects
+}
+
+// Merge saves the survivor and deletes the merged venue. The redirect is
+// recorded first, as the stores it stands in for write it in the same
+// transaction.
+func (r *MockVenueRepository) Merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error {
+	if r.redirects == nil {
+		return fmt.Errorf("venue redirects are not configured")
+	}
+	stored, ok := r.venues[survivor.ID]
+	if !ok {
 		return &VenueNotFoundError{}
 	}
+	if stored.Version != survivor.Version {
+		return &VersionConflictError{ID: survivor.ID, Version: survivor.Version}
+	}
+	storedMerged, ok := r.venues[merged.ID]
+	if !ok {
+		return &VenueNotFoundError{}
+	}
+	if storedMerged.Version != merged.Version {
+		return &VersionConflictError{ID: merged.ID, Version: merged.Version}
+	}
+
+	// The merged venue's external IDs pass to the survivor
+	delete(r.venues, merged.ID)
+	if err := r.checkExternalIDs(survivor); err != nil {
+		r.venues[merged.ID] = storedMerged
+		return err
+	}
+	if err := r.redirects.CreateRedirect(ctx, redirect); err != nil {
+		r.venues[merged.ID] = storedMerged
+		return err
+	}
+	survivor.Version++
+	r.venues[survivor.ID] = survivor.Clone()
+	return nil
+}
+
+// Delete removes a venue. Like DynamoDB, deleting a missing venue is not
+// an error.
+func (r *MockVenueRepository) Delete(ctx context.Context, id string) error {
 	delete(r.venues, id)
 	return nil
 }
@@ -53,7 +123,7 @@ func (r *MockVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefix
 		for _, prefix := range geohashPrefixes {
 			if len(venue.Location.Geohash) >= len(prefix) &&
 				venue.Location.Geohash[:len(prefix)] == prefix {
-				results = append(results, venue)
+				results = append(results, venue.Clone())
 				break
 			}
 		}
@@ -64,53 +134,87 @@ func (r *MockVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefix
 	return results, nil
 }
 
+// SearchByCity returns venues in a city in name order, like CityIndex
 func (r *MockVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
 	results := make([]*domain.Venue, 0)
 	for _, venue := range r.venues {
 		if venue.Address.City == city && venue.Address.State == state {
-			results = append(results, venue)
-			if len(results) >= limit {
-				break
-			}
+			results = append(results, venue.Clone())
 		}
 	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	if len(results) > limit {
+		results = results[:limit]
+	}
 	return results, nil
 }
 
+// SearchByType returns venues of a type best rated first, like
+// VenueTypeIndex, which breaks rating ties by descending ID
 func (r *MockVenueRepository) SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error) {
 	results := make([]*domain.Venue, 0)
 	for _, venue := range r.venues {
 		for _, vt := range venue.VenueTypes {
 			if vt == venueType {
-				results = append(results, venue)
+				results = append(results, venue.Clone())
 				break
 			}
 		}
-		if len(results) >= limit {
-			break
-		}
+	}
+	sort.Slice(results, func(i, j int) bool { return ratingID(results[i]) > ratingID(results[j]) })
+	if len(results) > limit {
+		results = results[:limit]
 	}
 	return results, nil
 }
 
 func (r *MockVenueRepository) GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error) {
 	for _, venue := range r.venues {
-		if venue.Source == source {
-			switch source {
-			case domain.SourceSongkick:
-				if venue.SongkickID == externalID {
-					return venue, nil
-				}
-			case domain.SourceBandsintown:
-				if venue.BandsintownID == externalID {
-					return venue, nil
-				}
-			case domain.SourceGooglePlaces:
-				if venue.GooglePlaceID == externalID {
-					return venue, nil
-				}
-			}
+		if id, ok := venue.ExternalIDs()[source]; ok && id == externalID {
+			return venue.Clone(), nil
 		}
 	}
 	return nil, &VenueNotFoundError{}
 }
+
+func (r *MockVenueRepository) ScanAll(ctx context.Context, fn func(*domain.Venue) error) error {
+	ids := make([]string, 0, len(r.venues))
+	for id := range r.venues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := fn(r.venues[id].Clone()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MockVenueRepository) ListByModerationStatus(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error) {
+	results := make([]*domain.Venue, 0)
+	for _, venue := range r.venues {
+		if venue.ModerationStatus == status {
+			results = append(results, venue.Clone())
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].CreatedAt.Before(results[j].CreatedAt) })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// checkExternalIDs mirrors the DynamoDB lookup items: an external ID may
+// only belong to one venue
+func (r *MockVenueRepository) checkExternalIDs(venue *domain.Venue) error {
+	for source, id := range venue.ExternalIDs() {
+		for _, other := range r.venues {
+			if other.ID != venue.ID && other.ExternalIDs()[source] == id {
+				return &ExternalIDConflictError{Source: source, ExternalID: id}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresReviewRepository implements ReviewRepository using PostgreSQL.
+// Reviews live beside the venues of a PostgresVenueRepository so that a
+// review and the rating it produces are saved in one transaction.
+type PostgresReviewRepository struct {
+	pool   *pgxpool.Pool
+	table  string
+	venues *PostgresVenueRepository
+	cache  VenueInvalidator
+}
+
+// NewPostgresReviewRepository creates a new PostgreSQL review repository.
+// Venue ratings are written through venues in the same transaction as
+// reviews.
+func NewPostgresReviewRepository(pool *pgxpool.Pool, table string, venues *PostgresVenueRepository) *PostgresReviewRepository {
+	return &PostgresReviewRepository{
+		pool:   pool,
+		table:  pgx.Identifier{table}.Sanitize(),
+		venues: venues,
+	}
+}
+
+// SetVenueCache sets the cache to invalidate when a review changes a
+// venue's rating
+func (r *PostgresReviewRepository) SetVenueCache(cache VenueInvalidator) {
+	r.cache = cache
+}
+
+// Get retrieves an artist's review of a venue
+func (r *PostgresReviewRepository) Get(ctx context.Context, venueID, artistID string) (*domain.VenueReview, error) {
+	var doc []byte
+	err := r.pool.QueryRow(ctx,
+		fmt.Sprintf("SELECT document FROM %s WHERE venue_id = $1 AND artist_id = $2", r.table),
+		venueID, artistID).Scan(&doc)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &ReviewNotFoundError{}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	var review domain.VenueReview
+	if err := json.Unmarshal(doc, &review); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review: %w", err)
+	}
+	return &review, nil
+}
+
+// Save updates the venue under its version condition and upserts the review
+// in one transaction
+func (r *PostgresReviewRepository) Save(ctx context.Context, review *domain.VenueReview, venue *domain.Venue) error {
+	doc, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	expected := venue.Version
+	venue.Version = expected + 1
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := r.venues.update(ctx, tx, venue, expected); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s (venue_id, artist_id, created_at, document) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (venue_id, artist_id) DO UPDATE SET created_at = EXCLUDED.created_at, document = EXCLUDED.document`, r.table),
+			review.VenueID, review.ArtistID, review.CreatedAt, doc)
+		if err != nil {
+			return fmt.Errorf("failed to save review: %w", err)
+		}
+		return nil
+	})
+
+	// A conflict means the cached venue is stale too, so a retry must not
+	// read it again
+	var conflict *VersionConflictError
+	if r.cache != nil && (err == nil || errors.As(err, &conflict)) {
+		r.cache.Invalidate(ctx, venue)
+	}
+	if err != nil {
+		venue.Version = expected
+	}
+	return err
+}
+
+// ListByVenue pages through a venue's reviews, newest first. The cursor
+// holds the creation time and artist of the last review returned.
+func (r *PostgresReviewRepository) ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueReview, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	var after *time.Time
+	afterArtist := ""
+	if startKey != nil {
+		createdAt, createdOK := startKey["created_at"].(*types.AttributeValueMemberS)
+		artist, artistOK := startKey["artist_id"].(*types.AttributeValueMemberS)
+		if !createdOK || !artistOK {
+			return nil, "", &InvalidCursorError{}
+		}
+		t, err := time.Parse(time.RFC3339Nano, createdAt.Value)
+		if err != nil {
+			return nil, "", &InvalidCursorError{}
+		}
+		after, afterArtist = &t, artist.Value
+	}
+
+	// One extra row tells whether there is a next page
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT created_at, artist_id, document FROM %s
+		WHERE venue_id = $1 AND ($2::timestamptz IS NULL OR (created_at, artist_id) < ($2, $3))
+		ORDER BY created_at DESC, artist_id DESC
+		LIMIT $4`, r.table), venueID, after, afterArtist, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.VenueReview, 0, limit)
+	var lastCreated time.Time
+	var lastArtist string
+	more := false
+	for rows.Next() {
+		if len(reviews) == limit {
+			more = true
+			break
+		}
+		var doc []byte
+		if err := rows.Scan(&lastCreated, &lastArtist, &doc); err != nil {
+			return nil, "", fmt.Errorf("failed to query reviews: %w", err)
+		}
+		var review domain.VenueReview
+		if err := json.Unmarshal(doc, &review); err != nil {
+			return nil, "", fmt.Errorf("failed to unmarshal review: %w", err)
+		}
+		reviews = append(reviews, &review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to query reviews: %w", err)
+	}
+	if !more {
+		return reviews, "", nil
+	}
+
+	next, err := encodeCursor(map[string]types.AttributeValue{
+		"created_at": &types.AttributeValueMemberS{Value: lastCreated.UTC().Format(time.RFC3339Nano)},
+		"artist_id":  &types.AttributeValueMemberS{Value: lastArtist},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return reviews, next, nil
+}
+
+// Move re-keys the review under its new venue in one transaction
+func (r *PostgresReviewRepository) Move(ctx context.Context, review *domain.VenueReview, venueID string) error {
+	moved := *review
+	moved.VenueID = venueID
+	doc, err := json.Marshal(&moved)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE venue_id = $1 AND artist_id = $2", r.table),
+			venueID, review.ArtistID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(
+			"UPDATE %s SET venue_id = $1, document = $2 WHERE venue_id = $3 AND artist_id = $4", r.table),
+			venueID, doc, review.VenueID, review.ArtistID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move review: %w", err)
+	}
+
+	review.VenueID = venueID
+	return nil
+}
+
+// Delete removes an artist's review of a venue
+func (r *PostgresReviewRepository) Delete(ctx context.Context, venueID, artistID string) error {
+	_, err := r.pool.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE venue_id = $1 AND artist_id = $2", r.table),
+		venueID, artistID)
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+	return nil
+}
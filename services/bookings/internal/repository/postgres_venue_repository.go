@@ -0,0 +1,438 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// pgUniqueViolation is the SQLSTATE of a unique constraint violation
+	pgUniqueViolation = "23505"
+	// pgScanPageSize is how many venues ScanAll reads per query
+	pgScanPageSize = 500
+)
+
+// NearbySearcher is implemented by repositories that can search by distance
+// from a point directly, rather than through geohash cells
+type NearbySearcher interface {
+	// SearchNearby returns venues within radiusKm of a point, nearest first
+	SearchNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*domain.Venue, error)
+}
+
+// PostgresVenueRepository implements VenueRepository using PostgreSQL with
+// PostGIS. Each venue is stored as a document alongside the columns its
+// searches filter and sort on, and its external IDs are kept in a second
+// table whose primary key makes each ID unique. The tables are created by
+// schema.PostgresMigrations.
+type PostgresVenueRepository struct {
+	pool        *pgxpool.Pool
+	table       string
+	externalIDs string
+	redirects   RedirectWriter
+}
+
+// NewPostgresVenueRepository creates a new PostgreSQL venue repository
+// storing venues in table and their external IDs in table_external_ids
+func NewPostgresVenueRepository(pool *pgxpool.Pool, table string) *PostgresVenueRepository {
+	return &PostgresVenueRepository{
+		pool:        pool,
+		table:       pgx.Identifier{table}.Sanitize(),
+		externalIDs: pgx.Identifier{table + "_external_ids"}.Sanitize(),
+	}
+}
+
+// venueColumns are the columns written for every venue, in venueArgs order.
+// The location is built from the longitude and latitude arguments.
+const venueColumns = "id, name, city_state, geohash, location, venue_types, rating, moderation_status, created_at, version, document"
+
+const venueValues = "$1, $2, $3, $4, ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography, $7, $8, $9, $10, $11, $12"
+
+func venueArgs(venue *domain.Venue) ([]interface{}, error) {
+	doc, err := marshalVenueDocument(venue)
+	if err != nil {
+		return nil, err
+	}
+	var moderationStatus *string
+	if venue.ModerationStatus != "" {
+		status := string(venue.ModerationStatus)
+		moderationStatus = &status
+	}
+	venueTypes := make([]string, len(venue.VenueTypes))
+	for i, venueType := range venue.VenueTypes {
+		venueTypes[i] = string(venueType)
+	}
+	return []interface{}{
+		venue.ID,
+		venue.Name,
+		fmt.Sprintf("%s#%s", venue.Address.City, venue.Address.State),
+		venue.Location.Geohash,
+		venue.Location.Longitude,
+		venue.Location.Latitude,
+		venueTypes,
+		venue.Rating,
+		moderationStatus,
+		venue.CreatedAt,
+		venue.Version,
+		doc,
+	}, nil
+}
+
+// Create creates a new venue at version 1 with its external IDs
+func (r *PostgresVenueRepository) Create(ctx context.Context, venue *domain.Venue) error {
+	previous := venue.Version
+	venue.Version = 1
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return r.insert(ctx, tx, venue)
+	})
+	if err != nil {
+		venue.Version = previous
+	}
+	return err
+}
+
+// BatchCreate saves venues in one transaction, each under its own savepoint
+// so that one failure does not undo the others
+func (r *PostgresVenueRepository) BatchCreate(ctx context.Context, venues []*domain.Venue) error {
+	failed := make(map[string]error)
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, venue := range venues {
+			previous := venue.Version
+			venue.Version = 1
+			err := pgx.BeginFunc(ctx, tx, func(savepoint pgx.Tx) error {
+				return r.insert(ctx, savepoint, venue)
+			})
+			if err != nil {
+				venue.Version = previous
+				failed[venue.ID] = err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to batch create venues: %w", err)
+	}
+	if len(failed) > 0 {
+		return &BatchWriteError{Failed: failed}
+	}
+	return nil
+}
+
+func (r *PostgresVenueRepository) insert(ctx context.Context, tx pgx.Tx, venue *domain.Venue) error {
+	args, err := venueArgs(venue)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", r.table, venueColumns, venueValues), args...)
+	if err != nil {
+		return fmt.Errorf("failed to create venue: %w", err)
+	}
+	return r.insertExternalIDs(ctx, tx, venue)
+}
+
+// insertExternalIDs claims the venue's external IDs, failing with
+// ExternalIDConflictError if another venue holds one
+func (r *PostgresVenueRepository) insertExternalIDs(ctx context.Context, tx pgx.Tx, venue *domain.Venue) error {
+	for source, externalID := range venue.ExternalIDs() {
+		_, err := tx.Exec(ctx,
+			fmt.Sprintf("INSERT INTO %s (source, external_id, venue_id) VALUES ($1, $2, $3)", r.externalIDs),
+			string(source), externalID, venue.ID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return &ExternalIDConflictError{Source: source, ExternalID: externalID}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save external ID: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves a venue by ID
+func (r *PostgresVenueRepository) GetByID(ctx context.Context, id string) (*domain.Venue, error) {
+	var doc []byte
+	err := r.pool.QueryRow(ctx, fmt.Sprintf("SELECT document FROM %s WHERE id = $1", r.table), id).Scan(&doc)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &VenueNotFoundError{}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue: %w", err)
+	}
+	return unmarshalVenueDocument(doc)
+}
+
+// Update saves a venue if its version still matches the stored one, and
+// increments the version. External IDs the venue dropped are released.
+func (r *PostgresVenueRepository) Update(ctx context.Context, venue *domain.Venue) error {
+	expected := venue.Version
+	venue.Version = expected + 1
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		return r.update(ctx, tx, venue, expected)
+	})
+	if err != nil {
+		venue.Version = expected
+	}
+	return err
+}
+
+// update writes a venue already at its new version inside tx
+func (r *PostgresVenueRepository) update(ctx context.Context, tx pgx.Tx, venue *domain.Venue, expected int) error {
+	args, err := venueArgs(venue)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, fmt.Sprintf(
+		"UPDATE %s SET (%s) = (%s) WHERE id = $1 AND version = $13",
+		r.table, venueColumns, venueValues), append(args, expected)...)
+	if err != nil {
+		return fmt.Errorf("failed to update venue: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1)", r.table), venue.ID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to update venue: %w", err)
+		}
+		if !exists {
+			return &VenueNotFoundError{}
+		}
+		return &VersionConflictError{ID: venue.ID, Version: expected}
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE venue_id = $1", r.externalIDs), venue.ID); err != nil {
+		return fmt.Errorf("failed to release external IDs: %w", err)
+	}
+	return r.insertExternalIDs(ctx, tx, venue)
+}
+
+// SetRedirects sets where Merge records redirects. They are kept in
+// DynamoDB with the rest of the duplicate data.
+func (r *PostgresVenueRepository) SetRedirects(redirects RedirectWriter) {
+	r.redirects = redirects
+}
+
+// Merge deletes the merged venue and saves the survivor, which claims the
+// released external IDs, in one transaction. The redirect lives in another
+// store, so it is recorded first: until the merged venue is gone it is never
+// followed, and a failed merge leaves both venues as they were.
+func (r *PostgresVenueRepository) Merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error {
+	if r.redirects == nil {
+		return fmt.Errorf("venue redirects are not configured")
+	}
+	if err := r.redirects.CreateRedirect(ctx, redirect); err != nil {
+		return err
+	}
+
+	expected := survivor.Version
+	survivor.Version = expected + 1
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND version = $2", r.table), merged.ID, merged.Version)
+		if err != nil {
+			return fmt.Errorf("failed to delete merged venue: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return &VersionConflictError{ID: merged.ID, Version: merged.Version}
+		}
+		return r.update(ctx, tx, survivor, expected)
+	})
+	if err != nil {
+		survivor.Version = expected
+	}
+	return err
+}
+
+// Delete removes a venue and, by cascade, its external IDs. Deleting a
+// missing venue is not an error.
+func (r *PostgresVenueRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.pool.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", r.table), id); err != nil {
+		return fmt.Errorf("failed to delete venue: %w", err)
+	}
+	return nil
+}
+
+// SearchByGeohash searches venues by geohash prefixes. Like the DynamoDB
+// repository it reads up to limit venues per prefix in geohash order and
+// merges them in prefix order. Each prefix is a range scan, as geohashes
+// sort below their prefix followed by "{".
+func (r *PostgresVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefixes []string, limit int) ([]*domain.Venue, error) {
+	if limit <= 0 || len(geohashPrefixes) == 0 {
+		return make([]*domain.Venue, 0), nil
+	}
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`
+		SELECT p.n, v.id, v.document
+		FROM unnest($1::text[]) WITH ORDINALITY AS p(prefix, n)
+		CROSS JOIN LATERAL (
+			SELECT id, geohash, document FROM %s
+			WHERE geohash COLLATE "C" >= p.prefix AND geohash COLLATE "C" < p.prefix || '{'
+			ORDER BY geohash COLLATE "C", id COLLATE "C"
+			LIMIT $2
+		) v
+		ORDER BY p.n, v.geohash COLLATE "C", v.id COLLATE "C"`, r.table), geohashPrefixes, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search venues by geohash: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([][]*domain.Venue, len(geohashPrefixes))
+	for rows.Next() {
+		var n int
+		var id string
+		var doc []byte
+		if err := rows.Scan(&n, &id, &doc); err != nil {
+			return nil, fmt.Errorf("failed to search venues by geohash: %w", err)
+		}
+		venue, err := unmarshalVenueDocument(doc)
+		if err != nil {
+			reportCorruptID(r.table, id, err)
+			continue
+		}
+		results[n-1] = append(results[n-1], venue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search venues by geohash: %w", err)
+	}
+	return mergeVenues(results, limit), nil
+}
+
+// SearchNearby returns venues within radiusKm of a point, nearest first,
+// using the GIST index on location
+func (r *PostgresVenueRepository) SearchNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, fmt.Sprintf(`
+		SELECT id, document FROM %s
+		WHERE ST_DWithin(location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+		ORDER BY location <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, id
+		LIMIT $4`, r.table), lng, lat, radiusKm*1000, limit)
+}
+
+// SearchByCity searches venues by city and state in name order
+func (r *PostgresVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, fmt.Sprintf(`
+		SELECT id, document FROM %s
+		WHERE city_state = $1
+		ORDER BY name COLLATE "C", id COLLATE "C"
+		LIMIT $2`, r.table), fmt.Sprintf("%s#%s", city, state), limit)
+}
+
+// SearchByType searches venues of a type, best rated first with ties
+// broken by descending ID, like VenueTypeIndex
+func (r *PostgresVenueRepository) SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, fmt.Sprintf(`
+		SELECT id, document FROM %s
+		WHERE venue_types @> ARRAY[$1::text]
+		ORDER BY rating DESC, id COLLATE "C" DESC
+		LIMIT $2`, r.table), string(venueType), limit)
+}
+
+// GetByExternalID retrieves the venue holding an external ID
+func (r *PostgresVenueRepository) GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error) {
+	var doc []byte
+	err := r.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT v.document FROM %s e JOIN %s v ON v.id = e.venue_id
+		WHERE e.source = $1 AND e.external_id = $2`, r.externalIDs, r.table), string(source), externalID).Scan(&doc)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, &VenueNotFoundError{}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue by external ID: %w", err)
+	}
+	return unmarshalVenueDocument(doc)
+}
+
+// ScanAll calls fn for every venue in ID order. Venues are read a page at a
+// time, so no connection is held while fn runs.
+func (r *PostgresVenueRepository) ScanAll(ctx context.Context, fn func(*domain.Venue) error) error {
+	after := ""
+	for {
+		venues, last, err := r.scanPage(ctx, after)
+		if err != nil {
+			return err
+		}
+		for _, venue := range venues {
+			if err := fn(venue); err != nil {
+				return err
+			}
+		}
+		if last == "" {
+			return nil
+		}
+		after = last
+	}
+}
+
+// scanPage reads up to pgScanPageSize venues with IDs after after, and
+// returns the last ID read, which is empty once the table is exhausted
+func (r *PostgresVenueRepository) scanPage(ctx context.Context, after string) ([]*domain.Venue, string, error) {
+	rows, err := r.pool.Query(ctx,
+		fmt.Sprintf("SELECT id, document FROM %s WHERE id > $1 ORDER BY id LIMIT $2", r.table),
+		after, pgScanPageSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan venues: %w", err)
+	}
+	defer rows.Close()
+
+	venues := make([]*domain.Venue, 0)
+	last, read := "", 0
+	for rows.Next() {
+		var doc []byte
+		if err := rows.Scan(&last, &doc); err != nil {
+			return nil, "", fmt.Errorf("failed to scan venues: %w", err)
+		}
+		read++
+		venue, err := unmarshalVenueDocument(doc)
+		if err != nil {
+			reportCorruptID(r.table, last, err)
+			continue
+		}
+		venues = append(venues, venue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to scan venues: %w", err)
+	}
+	if read < pgScanPageSize {
+		last = ""
+	}
+	return venues, last, nil
+}
+
+// ListByModerationStatus returns venues in a moderation state, oldest first
+func (r *PostgresVenueRepository) ListByModerationStatus(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, fmt.Sprintf(`
+		SELECT id, document FROM %s
+		WHERE moderation_status = $1
+		ORDER BY created_at, id
+		LIMIT $2`, r.table), string(status), limit)
+}
+
+// queryVenues runs a query selecting id and document. Corrupt documents are
+// reported and skipped.
+func (r *PostgresVenueRepository) queryVenues(ctx context.Context, sql string, args ...interface{}) ([]*domain.Venue, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query venues: %w", err)
+	}
+	defer rows.Close()
+
+	venues := make([]*domain.Venue, 0)
+	for rows.Next() {
+		var id string
+		var doc []byte
+		if err := rows.Scan(&id, &doc); err != nil {
+			return nil, fmt.Errorf("failed to query venues: %w", err)
+		}
+		venue, err := unmarshalVenueDocument(doc)
+		if err != nil {
+			reportCorruptID(r.table, id, err)
+			continue
+		}
+		venues = append(venues, venue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query venues: %w", err)
+	}
+	return venues, nil
+}
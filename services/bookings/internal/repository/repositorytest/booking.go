@@ -0,0 +1,161 @@
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// TestBookingRepository runs the booking repository suite. newRepo must
+// return an empty repository each time it is called.
+func TestBookingRepository(t *testing.T, newRepo func(t *testing.T) repository.BookingRepository) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo repository.BookingRepository)
+	}{
+		{"CreateAndGet", testBookingCreateAndGet},
+		{"GetByIDNotFound", testBookingGetByIDNotFound},
+		{"Update", testBookingUpdate},
+		{"ListByArtistAndVenue", testBookingListByArtistAndVenue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepo(t))
+		})
+	}
+}
+
+// eventDate returns a whole day in UTC, so that stored dates sort as
+// strings the same way they sort as times
+func eventDate(day int) time.Time {
+	return time.Date(2026, 6, day, 20, 0, 0, 0, time.UTC)
+}
+
+func assertSameBooking(t *testing.T, got, want *domain.Booking) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("booking = %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func testBookingCreateAndGet(t *testing.T, repo repository.BookingRepository) {
+	ctx := context.Background()
+	booking := domain.NewBooking("artist-1", "venue-1", eventDate(1), 750)
+	booking.RoomID = "room-1"
+
+	if err := repo.Create(ctx, booking); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if booking.Version != 1 {
+		t.Errorf("Create() set Version = %d, want 1", booking.Version)
+	}
+
+	got, err := repo.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("GetByID() returned nil for a stored booking")
+	}
+	assertSameBooking(t, got, booking)
+}
+
+func testBookingGetByIDNotFound(t *testing.T, repo repository.BookingRepository) {
+	got, err := repo.GetByID(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("GetByID() = %v for a missing booking, want nil", got)
+	}
+}
+
+func testBookingUpdate(t *testing.T, repo repository.BookingRepository) {
+	ctx := context.Background()
+	booking := domain.NewBooking("artist-1", "venue-1", eventDate(1), 750)
+	if err := repo.Create(ctx, booking); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stale, err := repo.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	booking.Confirm()
+	if err := repo.Update(ctx, booking); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if booking.Version != 2 {
+		t.Errorf("Update() set Version = %d, want 2", booking.Version)
+	}
+	got, err := repo.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	assertSameBooking(t, got, booking)
+
+	var conflict *repository.VersionConflictError
+	stale.Cancel()
+	if err := repo.Update(ctx, stale); !errors.As(err, &conflict) {
+		t.Errorf("Update() with a stale version error = %v, want VersionConflictError", err)
+	}
+	if stale.Version != 1 {
+		t.Errorf("failed Update() left Version = %d, want 1", stale.Version)
+	}
+
+	missing := domain.NewBooking("artist-1", "venue-1", eventDate(2), 750)
+	missing.Version = 1
+	if err := repo.Update(ctx, missing); !errors.As(err, &conflict) {
+		t.Errorf("Update() of a missing booking error = %v, want VersionConflictError", err)
+	}
+}
+
+func testBookingListByArtistAndVenue(t *testing.T, repo repository.BookingRepository) {
+	ctx := context.Background()
+	third := domain.NewBooking("artist-1", "venue-1", eventDate(3), 500)
+	first := domain.NewBooking("artist-1", "venue-2", eventDate(1), 500)
+	second := domain.NewBooking("artist-2", "venue-1", eventDate(2), 500)
+	for _, booking := range []*domain.Booking{third, first, second} {
+		if err := repo.Create(ctx, booking); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		list func() ([]*domain.Booking, error)
+		want []*domain.Booking
+	}{
+		{"ListByArtist(artist-1)", func() ([]*domain.Booking, error) { return repo.ListByArtist(ctx, "artist-1") }, []*domain.Booking{first, third}},
+		{"ListByArtist(artist-3)", func() ([]*domain.Booking, error) { return repo.ListByArtist(ctx, "artist-3") }, []*domain.Booking{}},
+		{"ListByVenue(venue-1)", func() ([]*domain.Booking, error) { return repo.ListByVenue(ctx, "venue-1") }, []*domain.Booking{second, third}},
+	}
+	for _, tt := range tests {
+		got, err := tt.list()
+		if err != nil {
+			t.Fatalf("%s error = %v", tt.name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s returned %d bookings, want %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i].ID != tt.want[i].ID {
+				t.Errorf("%s[%d] = booking for day %d, want day %d", tt.name, i, got[i].EventDate.Day(), tt.want[i].EventDate.Day())
+			}
+		}
+	}
+}
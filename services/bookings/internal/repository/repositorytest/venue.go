@@ -0,0 +1,481 @@
+// Package repositorytest provides conformance suites that every repository
+// implementation must pass, so that the in-memory mocks the service tests
+// rely on behave like the DynamoDB repositories
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// TestVenueRepository runs the venue repository suite. newRepo must return
+// an empty repository each time it is called.
+func TestVenueRepository(t *testing.T, newRepo func(t *testing.T) repository.VenueRepository) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo repository.VenueRepository)
+	}{
+		{"CreateAndGet", testVenueCreateAndGet},
+		{"GetByIDNotFound", testVenueGetByIDNotFound},
+		{"ExternalIDs", testVenueExternalIDs},
+		{"Update", testVenueUpdate},
+		{"UpdateNotFound", testVenueUpdateNotFound},
+		{"Delete", testVenueDelete},
+		{"SearchByGeohash", testVenueSearchByGeohash},
+		{"SearchByCity", testVenueSearchByCity},
+		{"SearchByType", testVenueSearchByType},
+		{"ScanAll", testVenueScanAll},
+		{"ListByModerationStatus", testVenueListByModerationStatus},
+		{"BatchCreate", testVenueBatchCreate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepo(t))
+		})
+	}
+}
+
+// newVenue returns a venue in a city with a geohash. Every venue needs a
+// geohash, as DynamoDB rejects empty index keys.
+func newVenue(name, geohash, city, state string, venueTypes ...domain.VenueType) *domain.Venue {
+	return domain.NewVenue(
+		name,
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: geohash},
+		domain.Address{Street: "1 Main St", City: city, State: state, PostalCode: "94103", Country: "US"},
+		venueTypes,
+		domain.SourceManual,
+	)
+}
+
+func mustCreate(t *testing.T, repo repository.VenueRepository, venues ...*domain.Venue) {
+	t.Helper()
+	for _, venue := range venues {
+		if err := repo.Create(context.Background(), venue); err != nil {
+			t.Fatalf("Create(%s) error = %v", venue.Name, err)
+		}
+	}
+}
+
+// assertSameVenue compares venues by their JSON form, which covers every
+// field clients see
+func assertSameVenue(t *testing.T, got, want *domain.Venue) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("venue = %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+// names lists venue names in order
+func names(venues []*domain.Venue) []string {
+	list := make([]string, len(venues))
+	for i, venue := range venues {
+		list[i] = venue.Name
+	}
+	return list
+}
+
+// sortedNames lists venue names alphabetically, for results in no set order
+func sortedNames(venues []*domain.Venue) []string {
+	list := names(venues)
+	sort.Strings(list)
+	return list
+}
+
+func assertNames(t *testing.T, method string, got, want []string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s returned %v, want %v", method, got, want)
+	}
+}
+
+func assertNotFound(t *testing.T, method string, err error) {
+	t.Helper()
+	var notFound *repository.VenueNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("%s error = %v, want VenueNotFoundError", method, err)
+	}
+}
+
+func testVenueCreateAndGet(t *testing.T, repo repository.VenueRepository) {
+	venue := newVenue("The Fillmore", "9q8yyk8", "San Francisco", "CA", domain.VenueTypeClub, domain.VenueTypeTheater)
+	venue.Capacity = 1150
+	venue.Genres = []string{"rock", "soul"}
+	venue.Amenities = []domain.Amenity{domain.AmenitySoundSystem, domain.AmenityGreenRoom}
+	venue.PayRange = &domain.PayRange{Min: 500, Max: 5000, Currency: "USD", Type: domain.PaymentGuarantee}
+	venue.ContactInfo = domain.ContactInfo{Email: "booking@example.com", Website: "https://example.com"}
+	venue.Rooms = []domain.Room{*domain.NewRoom("Main Room", 1150)}
+	venue.SongkickID = "sk-1"
+	venue.Rating = 4.5
+	venue.ReviewCount = 2
+
+	mustCreate(t, repo, venue)
+	if venue.Version != 1 {
+		t.Errorf("Create() set Version = %d, want 1", venue.Version)
+	}
+
+	got, err := repo.GetByID(context.Background(), venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	assertSameVenue(t, got, venue)
+
+	// Changing the returned venue must not change the stored one
+	got.Name = "Changed"
+	again, err := repo.GetByID(context.Background(), venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if again.Name != venue.Name {
+		t.Errorf("GetByID() Name = %q after changing an earlier result, want %q", again.Name, venue.Name)
+	}
+}
+
+func testVenueGetByIDNotFound(t *testing.T, repo repository.VenueRepository) {
+	_, err := repo.GetByID(context.Background(), "missing")
+	assertNotFound(t, "GetByID()", err)
+}
+
+func testVenueExternalIDs(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	venue := newVenue("Bottom of the Hill", "9q8yyh2", "San Francisco", "CA", domain.VenueTypeBar)
+	venue.SongkickID = "sk-1"
+	venue.BandsintownID = "bit-1"
+	mustCreate(t, repo, venue)
+
+	for source, id := range venue.ExternalIDs() {
+		got, err := repo.GetByExternalID(ctx, source, id)
+		if err != nil {
+			t.Fatalf("GetByExternalID(%s) error = %v", source, err)
+		}
+		if got.ID != venue.ID {
+			t.Errorf("GetByExternalID(%s) = %s, want %s", source, got.ID, venue.ID)
+		}
+	}
+
+	_, err := repo.GetByExternalID(ctx, domain.SourceSongkick, "sk-unknown")
+	assertNotFound(t, "GetByExternalID()", err)
+
+	// Another venue may not take an ID that is already owned
+	other := newVenue("Other", "9q8yyh3", "San Francisco", "CA", domain.VenueTypeBar)
+	other.SongkickID = "sk-1"
+	var conflict *repository.ExternalIDConflictError
+	if err := repo.Create(ctx, other); !errors.As(err, &conflict) {
+		t.Fatalf("Create() with a taken external ID error = %v, want ExternalIDConflictError", err)
+	}
+	if conflict.Source != domain.SourceSongkick || conflict.ExternalID != "sk-1" {
+		t.Errorf("ExternalIDConflictError = %s#%s, want songkick#sk-1", conflict.Source, conflict.ExternalID)
+	}
+	_, err = repo.GetByID(ctx, other.ID)
+	assertNotFound(t, "GetByID() after a rejected Create()", err)
+}
+
+func testVenueUpdate(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	venue := newVenue("Slim's", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeClub, domain.VenueTypeBar)
+	venue.SongkickID = "sk-old"
+	mustCreate(t, repo, venue)
+
+	stale, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	venue.Name = "Slim's SF"
+	venue.VenueTypes = []domain.VenueType{domain.VenueTypeClub}
+	venue.SongkickID = "sk-new"
+	if err := repo.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if venue.Version != 2 {
+		t.Errorf("Update() set Version = %d, want 2", venue.Version)
+	}
+
+	got, err := repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	assertSameVenue(t, got, venue)
+
+	// The old external ID is released and the new one indexed
+	_, err = repo.GetByExternalID(ctx, domain.SourceSongkick, "sk-old")
+	assertNotFound(t, "GetByExternalID() for a replaced ID", err)
+	if got, err := repo.GetByExternalID(ctx, domain.SourceSongkick, "sk-new"); err != nil || got.ID != venue.ID {
+		t.Errorf("GetByExternalID() for the new ID = %v, %v, want %s", got, err, venue.ID)
+	}
+	reuse := newVenue("Reuse", "9q8yyk2", "San Francisco", "CA", domain.VenueTypeBar)
+	reuse.SongkickID = "sk-old"
+	mustCreate(t, repo, reuse)
+
+	// A dropped type no longer finds the venue
+	bars, err := repo.SearchByType(ctx, domain.VenueTypeBar, 10)
+	if err != nil {
+		t.Fatalf("SearchByType() error = %v", err)
+	}
+	assertNames(t, "SearchByType(bar)", names(bars), []string{"Reuse"})
+
+	// Saving a copy read before the update is a conflict
+	stale.Name = "Stale"
+	var conflict *repository.VersionConflictError
+	if err := repo.Update(ctx, stale); !errors.As(err, &conflict) {
+		t.Fatalf("Update() with a stale version error = %v, want VersionConflictError", err)
+	}
+	if stale.Version != 1 {
+		t.Errorf("failed Update() left Version = %d, want 1", stale.Version)
+	}
+	got, err = repo.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Name != venue.Name {
+		t.Errorf("GetByID() Name = %q after a conflicting update, want %q", got.Name, venue.Name)
+	}
+}
+
+func testVenueUpdateNotFound(t *testing.T, repo repository.VenueRepository) {
+	venue := newVenue("Missing", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar)
+	venue.Version = 1
+	assertNotFound(t, "Update()", repo.Update(context.Background(), venue))
+}
+
+func testVenueDelete(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	venue := newVenue("Great American Music Hall", "9q8yyk3", "San Francisco", "CA", domain.VenueTypeTheater)
+	venue.GooglePlaceID = "place-1"
+	mustCreate(t, repo, venue)
+
+	if err := repo.Delete(ctx, venue.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	_, err := repo.GetByID(ctx, venue.ID)
+	assertNotFound(t, "GetByID() after Delete()", err)
+	_, err = repo.GetByExternalID(ctx, domain.SourceGooglePlaces, "place-1")
+	assertNotFound(t, "GetByExternalID() after Delete()", err)
+	theaters, err := repo.SearchByType(ctx, domain.VenueTypeTheater, 10)
+	if err != nil {
+		t.Fatalf("SearchByType() error = %v", err)
+	}
+	assertNames(t, "SearchByType() after Delete()", names(theaters), []string{})
+
+	if err := repo.Delete(ctx, venue.ID); err != nil {
+		t.Errorf("Delete() of a missing venue error = %v, want nil", err)
+	}
+}
+
+func testVenueSearchByGeohash(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo,
+		newVenue("Mission A", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar),
+		newVenue("Mission B", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar),
+		newVenue("Brooklyn", "dr5rsp1", "New York", "NY", domain.VenueTypeBar),
+	)
+
+	tests := []struct {
+		cells []string
+		limit int
+		want  []string
+	}{
+		{[]string{"9q8yyk1"}, 10, []string{"Mission A", "Mission B"}},
+		{[]string{"9q8yyk1", "dr5rsp1"}, 10, []string{"Brooklyn", "Mission A", "Mission B"}},
+		{[]string{"9q8zzzz"}, 10, []string{}},
+		// Shorter prefixes cover larger areas
+		{[]string{"9q8yy"}, 10, []string{"Mission A", "Mission B"}},
+		{[]string{"9q8", "dr5"}, 10, []string{"Brooklyn", "Mission A", "Mission B"}},
+		// Overlapping prefixes return each venue once
+		{[]string{"9q8yyk", "9q8yyk1"}, 10, []string{"Mission A", "Mission B"}},
+		// Prefixes finer than the stored geohash match nothing
+		{[]string{"9q8yyk12"}, 10, []string{}},
+	}
+	for _, tt := range tests {
+		venues, err := repo.SearchByGeohash(ctx, tt.cells, tt.limit)
+		if err != nil {
+			t.Fatalf("SearchByGeohash(%v) error = %v", tt.cells, err)
+		}
+		assertNames(t, fmt.Sprintf("SearchByGeohash(%v)", tt.cells), sortedNames(venues), tt.want)
+	}
+
+	venues, err := repo.SearchByGeohash(ctx, []string{"9q8yyk1"}, 1)
+	if err != nil {
+		t.Fatalf("SearchByGeohash() error = %v", err)
+	}
+	if len(venues) != 1 {
+		t.Errorf("SearchByGeohash() with limit 1 returned %d venues", len(venues))
+	}
+}
+
+func testVenueSearchByCity(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo,
+		newVenue("Cafe Du Nord", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar),
+		newVenue("Bimbo's 365", "9q8zn21", "San Francisco", "CA", domain.VenueTypeClub),
+		newVenue("Rickshaw Stop", "9q8yym1", "San Francisco", "CA", domain.VenueTypeClub),
+		newVenue("Fox Theater", "9q9p3v1", "Oakland", "CA", domain.VenueTypeTheater),
+		newVenue("Portland House", "c20fbr1", "Portland", "OR", domain.VenueTypeBar),
+		newVenue("Maine House", "drtd211", "Portland", "ME", domain.VenueTypeBar),
+	)
+
+	venues, err := repo.SearchByCity(ctx, "San Francisco", "CA", 10)
+	if err != nil {
+		t.Fatalf("SearchByCity() error = %v", err)
+	}
+	assertNames(t, "SearchByCity(San Francisco)", names(venues), []string{"Bimbo's 365", "Cafe Du Nord", "Rickshaw Stop"})
+
+	venues, err = repo.SearchByCity(ctx, "San Francisco", "CA", 2)
+	if err != nil {
+		t.Fatalf("SearchByCity() error = %v", err)
+	}
+	assertNames(t, "SearchByCity() with limit 2", names(venues), []string{"Bimbo's 365", "Cafe Du Nord"})
+
+	venues, err = repo.SearchByCity(ctx, "Portland", "ME", 10)
+	if err != nil {
+		t.Fatalf("SearchByCity() error = %v", err)
+	}
+	assertNames(t, "SearchByCity(Portland, ME)", names(venues), []string{"Maine House"})
+}
+
+func testVenueSearchByType(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	top := newVenue("Top", "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar, domain.VenueTypeRestaurant)
+	top.Rating = 4.5
+	middle := newVenue("Middle", "9q8yyk2", "San Francisco", "CA", domain.VenueTypeBar)
+	middle.Rating = 3
+	unrated := newVenue("Unrated", "9q8yyk3", "San Francisco", "CA", domain.VenueTypeBar)
+	club := newVenue("Club", "9q8yyk4", "San Francisco", "CA", domain.VenueTypeClub)
+	mustCreate(t, repo, unrated, middle, top, club)
+
+	tests := []struct {
+		venueType domain.VenueType
+		limit     int
+		want      []string
+	}{
+		{domain.VenueTypeBar, 10, []string{"Top", "Middle", "Unrated"}},
+		{domain.VenueTypeBar, 2, []string{"Top", "Middle"}},
+		{domain.VenueTypeRestaurant, 10, []string{"Top"}},
+		{domain.VenueTypeWinery, 10, []string{}},
+	}
+	for _, tt := range tests {
+		venues, err := repo.SearchByType(ctx, tt.venueType, tt.limit)
+		if err != nil {
+			t.Fatalf("SearchByType(%s) error = %v", tt.venueType, err)
+		}
+		assertNames(t, fmt.Sprintf("SearchByType(%s, %d)", tt.venueType, tt.limit), names(venues), tt.want)
+	}
+}
+
+func testVenueScanAll(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	// External IDs and types give the venues lookup and index items, which
+	// a scan must not return as venues
+	for i, name := range []string{"One", "Two", "Three"} {
+		venue := newVenue(name, "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar, domain.VenueTypeClub)
+		venue.SongkickID = fmt.Sprintf("sk-%d", i)
+		mustCreate(t, repo, venue)
+	}
+
+	var seen []*domain.Venue
+	err := repo.ScanAll(ctx, func(venue *domain.Venue) error {
+		seen = append(seen, venue)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanAll() error = %v", err)
+	}
+	assertNames(t, "ScanAll()", sortedNames(seen), []string{"One", "Three", "Two"})
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.ScanAll(ctx, func(venue *domain.Venue) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("ScanAll() error = %v, want the callback's error", err)
+	}
+	if calls != 1 {
+		t.Errorf("ScanAll() called back %d times after an error, want 1", calls)
+	}
+}
+
+func testVenueListByModerationStatus(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	submitted := func(name string, status domain.ModerationStatus, age time.Duration) *domain.Venue {
+		venue := newVenue(name, "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBar)
+		venue.ModerationStatus = status
+		venue.CreatedAt = base.Add(-age)
+		return venue
+	}
+	mustCreate(t, repo,
+		submitted("Newer", domain.ModerationPending, time.Hour),
+		submitted("Older", domain.ModerationPending, 2*time.Hour),
+		submitted("Approved", domain.ModerationApproved, 3*time.Hour),
+		newVenue("Imported", "9q8yyk2", "San Francisco", "CA", domain.VenueTypeBar),
+	)
+
+	tests := []struct {
+		status domain.ModerationStatus
+		limit  int
+		want   []string
+	}{
+		{domain.ModerationPending, 10, []string{"Older", "Newer"}},
+		{domain.ModerationPending, 1, []string{"Older"}},
+		{domain.ModerationApproved, 10, []string{"Approved"}},
+		{domain.ModerationRejected, 10, []string{}},
+	}
+	for _, tt := range tests {
+		venues, err := repo.ListByModerationStatus(ctx, tt.status, tt.limit)
+		if err != nil {
+			t.Fatalf("ListByModerationStatus(%s) error = %v", tt.status, err)
+		}
+		assertNames(t, fmt.Sprintf("ListByModerationStatus(%s, %d)", tt.status, tt.limit), names(venues), tt.want)
+	}
+}
+
+func testVenueBatchCreate(t *testing.T, repo repository.VenueRepository) {
+	ctx := context.Background()
+	// More venues than fit in one DynamoDB batch
+	venues := make([]*domain.Venue, 30)
+	for i := range venues {
+		venues[i] = newVenue(fmt.Sprintf("Venue %02d", i), "9q8yyk1", "San Francisco", "CA", domain.VenueTypeBrewery)
+		venues[i].BandsintownID = fmt.Sprintf("bit-%d", i)
+	}
+
+	if err := repo.BatchCreate(ctx, venues); err != nil {
+		t.Fatalf("BatchCreate() error = %v", err)
+	}
+
+	for _, venue := range venues {
+		if venue.Version != 1 {
+			t.Errorf("BatchCreate() set %s Version = %d, want 1", venue.Name, venue.Version)
+		}
+		got, err := repo.GetByExternalID(ctx, domain.SourceBandsintown, venue.BandsintownID)
+		if err != nil {
+			t.Fatalf("GetByExternalID(%s) error = %v", venue.BandsintownID, err)
+		}
+		assertSameVenue(t, got, venue)
+	}
+
+	breweries, err := repo.SearchByType(ctx, domain.VenueTypeBrewery, 100)
+	if err != nil {
+		t.Fatalf("SearchByType() error = %v", err)
+	}
+	if len(breweries) != len(venues) {
+		t.Errorf("SearchByType() returned %d venues, want %d", len(breweries), len(venues))
+	}
+}
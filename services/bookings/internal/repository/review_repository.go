@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// ReviewRepository stores artists' reviews of venues
+type ReviewRepository interface {
+	Get(ctx context.Context, venueID, artistID string) (*domain.VenueReview, error)
+	// Save writes a review together with the venue carrying the aggregate
+	// rating it produces. Both are written or neither is, and the write fails
+	// with VersionConflictError if the venue changed since it was read. On
+	// success the venue carries its new version, so the caller can record
+	// the change.
+	Save(ctx context.Context, review *domain.VenueReview, venue *domain.Venue) error
+	// ListByVenue returns a page of a venue's reviews, newest first, and the
+	// cursor for the next page, which is empty on the last page
+	ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueReview, string, error)
+	// Move re-keys a review under another venue, replacing any review the
+	// artist has there, when venues are merged. Venue ratings are left to
+	// the caller.
+	Move(ctx context.Context, review *domain.VenueReview, venueID string) error
+	// Delete removes a review without touching its venue's rating
+	Delete(ctx context.Context, venueID, artistID string) error
+}
+
+// DynamoDBReviewRepository implements ReviewRepository using DynamoDB, keyed
+// by venue ID and artist ID with a local index on creation time
+type DynamoDBReviewRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	venues    *DynamoDBVenueRepository
+	cache     VenueInvalidator
+}
+
+// VenueInvalidator drops cached copies of venues written around the cache
+type VenueInvalidator interface {
+	Invalidate(ctx context.Context, venues ...*domain.Venue)
+}
+
+// NewDynamoDBReviewRepository creates a new DynamoDB review repository. Venue
+// ratings are written through venues in the same transaction as reviews.
+func NewDynamoDBReviewRepository(client *dynamodb.Client, tableName string, venues *DynamoDBVenueRepository) *DynamoDBReviewRepository {
+	return &DynamoDBReviewRepository{
+		client:    client,
+		tableName: tableName,
+		venues:    venues,
+	}
+}
+
+// SetVenueCache sets the cache to invalidate when a review changes a
+// venue's rating
+func (r *DynamoDBReviewRepository) SetVenueCache(cache VenueInvalidator) {
+	r.cache = cache
+}
+
+// Get retrieves an artist's review of a venue
+func (r *DynamoDBReviewRepository) Get(ctx context.Context, venueID, artistID string) (*domain.VenueReview, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       reviewKey(venueID, artistID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &ReviewNotFoundError{}
+	}
+
+	var review domain.VenueReview
+	if err := attributevalue.UnmarshalMap(result.Item, &review); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review: %w", err)
+	}
+
+	return &review, nil
+}
+
+// Save puts the review and the venue in one transaction. The venue's version
+// condition also serialises concurrent reviews of the same venue, so the
+// review put needs no condition of its own.
+func (r *DynamoDBReviewRepository) Save(ctx context.Context, review *domain.VenueReview, venue *domain.Venue) error {
+	expected := venue.Version
+	venue.Version = expected + 1
+	putVenue, err := r.venues.putVenue(venue)
+	if err != nil {
+		venue.Version = expected
+		return err
+	}
+	putVenue.Put.ConditionExpression, putVenue.Put.ExpressionAttributeNames, putVenue.Put.ExpressionAttributeValues = versionCondition(expected)
+
+	av, err := attributevalue.MarshalMap(review)
+	if err != nil {
+		venue.Version = expected
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+	// The rating moved, so the type index items need its new sort key
+	typeWrites, err := r.venues.typeIndexWrites(venue, venue)
+	if err != nil {
+		venue.Version = expected
+		return err
+	}
+
+	items := []types.TransactWriteItem{
+		putVenue,
+		{Put: &types.Put{TableName: aws.String(r.tableName), Item: av}},
+	}
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: append(items, typeWrites...),
+	})
+	// A conflict means the cached venue is stale too, so a retry must not
+	// read it again
+	if r.cache != nil && (err == nil || versionConflict(err)) {
+		r.cache.Invalidate(ctx, venue)
+	}
+	if err != nil {
+		venue.Version = expected
+		if versionConflict(err) {
+			return &VersionConflictError{ID: venue.ID, Version: expected}
+		}
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+
+	return nil
+}
+
+// ListByVenue pages through a venue's reviews on the CreatedIndex, newest first
+func (r *DynamoDBReviewRepository) ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueReview, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("CreatedIndex"),
+		KeyConditionExpression: aws.String("venue_id = :venue_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_id": &types.AttributeValueMemberS{Value: venueID},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int32(int32(limit)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query reviews: %w", err)
+	}
+
+	reviews := make([]*domain.VenueReview, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &reviews); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal reviews: %w", err)
+	}
+
+	next, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return reviews, next, nil
+}
+
+// Move puts the review under its new venue and deletes the original in one
+// transaction
+func (r *DynamoDBReviewRepository) Move(ctx context.Context, review *domain.VenueReview, venueID string) error {
+	moved := *review
+	moved.VenueID = venueID
+	av, err := attributevalue.MarshalMap(&moved)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review: %w", err)
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: &types.Put{TableName: aws.String(r.tableName), Item: av}},
+			{Delete: &types.Delete{TableName: aws.String(r.tableName), Key: reviewKey(review.VenueID, review.ArtistID)}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move review: %w", err)
+	}
+
+	review.VenueID = venueID
+	return nil
+}
+
+// Delete removes an artist's review of a venue
+func (r *DynamoDBReviewRepository) Delete(ctx context.Context, venueID, artistID string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       reviewKey(venueID, artistID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+	return nil
+}
+
+func reviewKey(venueID, artistID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"venue_id":  &types.AttributeValueMemberS{Value: venueID},
+		"artist_id": &types.AttributeValueMemberS{Value: artistID},
+	}
+}
+
+// ReviewNotFoundError is returned when an artist has not reviewed a venue
+type ReviewNotFoundError struct{}
+
+func (e *ReviewNotFoundError) Error() string {
+	return "review not found"
+}
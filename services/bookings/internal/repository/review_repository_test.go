@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestReviewRepository_ListByVenuePages(t *testing.T) {
+	venues := NewMockVenueRepository()
+	repo := NewMockReviewRepository(venues)
+	ctx := context.Background()
+
+	venue := domain.NewVenue("Bottom of the Hill", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceUserSubmitted)
+	if err := venues.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	start := time.Now()
+	for i, artistID := range []string{"artist-1", "artist-2", "artist-3"} {
+		review := &domain.VenueReview{VenueID: venue.ID, ArtistID: artistID, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := repo.Save(ctx, review, venue); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	page, next, err := repo.ListByVenue(ctx, venue.ID, 2, "")
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ArtistID != "artist-3" || page[1].ArtistID != "artist-2" {
+		t.Fatalf("first page = %+v, want artist-3 then artist-2", page)
+	}
+	if next == "" {
+		t.Fatal("first page should have a next cursor")
+	}
+
+	page, next, err = repo.ListByVenue(ctx, venue.ID, 2, next)
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(page) != 1 || page[0].ArtistID != "artist-1" || next != "" {
+		t.Errorf("last page = %+v next %q, want artist-1 and no cursor", page, next)
+	}
+
+	var invalid *InvalidCursorError
+	if _, _, err := repo.ListByVenue(ctx, venue.ID, 2, "nope"); !errors.As(err, &invalid) {
+		t.Errorf("ListByVenue() error = %v, want InvalidCursorError", err)
+	}
+}
+
+func TestReviewRepository_SaveChecksVenueVersion(t *testing.T) {
+	venues := NewMockVenueRepository()
+	repo := NewMockReviewRepository(venues)
+	ctx := context.Background()
+
+	venue := domain.NewVenue("Hotel Utah", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeBar}, domain.SourceUserSubmitted)
+	_ = venues.Create(ctx, venue)
+	stale := venue.Clone()
+	_ = venues.Update(ctx, venue)
+
+	review := &domain.VenueReview{VenueID: venue.ID, ArtistID: "artist-1"}
+	var conflict *VersionConflictError
+	if err := repo.Save(ctx, review, stale); !errors.As(err, &conflict) {
+		t.Fatalf("Save() error = %v, want VersionConflictError", err)
+	}
+	if _, err := repo.Get(ctx, venue.ID, "artist-1"); err == nil {
+		t.Error("a review whose venue write failed should not be stored")
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor, err := encodeCursor(nil)
+	if err != nil || cursor != "" {
+		t.Fatalf("encodeCursor(nil) = %q, %v, want empty", cursor, err)
+	}
+
+	key, err := decodeCursor("")
+	if err != nil || key != nil {
+		t.Errorf("decodeCursor(\"\") = %v, %v, want nil", key, err)
+	}
+	if _, err := decodeCursor("not base64!"); err == nil {
+		t.Error("decodeCursor() should reject garbage")
+	}
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// SavedSearchRepository defines the interface for saved search data access
+type SavedSearchRepository interface {
+	Create(ctx context.Context, search *domain.SavedSearch) error
+	GetByID(ctx context.Context, id string) (*domain.SavedSearch, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error)
+	ListAll(ctx context.Context) ([]*domain.SavedSearch, error)
+	Delete(ctx context.Context, id string) error
+	// RecordMatch stores a match and reports false if it was already recorded
+	RecordMatch(ctx context.Context, match *domain.SavedSearchMatch) (bool, error)
+	GetMatch(ctx context.Context, savedSearchID, venueID string) (*domain.SavedSearchMatch, error)
+	UpdateMatch(ctx context.Context, match *domain.SavedSearchMatch) error
+	ListMatches(ctx context.Context, savedSearchID string) ([]*domain.SavedSearchMatch, error)
+}
+
+// DynamoDBSavedSearchRepository implements SavedSearchRepository using DynamoDB
+type DynamoDBSavedSearchRepository struct {
+	client       *dynamodb.Client
+	tableName    string
+	matchesTable string
+}
+
+// NewDynamoDBSavedSearchRepository creates a new DynamoDB saved search repository
+func NewDynamoDBSavedSearchRepository(client *dynamodb.Client, tableName, matchesTable string) *DynamoDBSavedSearchRepository {
+	return &DynamoDBSavedSearchRepository{
+		client:       client,
+		tableName:    tableName,
+		matchesTable: matchesTable,
+	}
+}
+
+// Create stores a new saved search
+func (r *DynamoDBSavedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	av, err := attributevalue.MarshalMap(search)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved search: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create saved search: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a saved search by ID
+func (r *DynamoDBSavedSearchRepository) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &SavedSearchNotFoundError{}
+	}
+
+	var search domain.SavedSearch
+	if err := attributevalue.UnmarshalMap(result.Item, &search); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved search: %w", err)
+	}
+
+	return &search, nil
+}
+
+// ListByUser lists the saved searches owned by a user
+func (r *DynamoDBSavedSearchRepository) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	searches := make([]*domain.SavedSearch, 0)
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("UserIndex"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query saved searches by user: %w", err)
+		}
+
+		var batch []*domain.SavedSearch
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal saved searches: %w", err)
+		}
+		searches = append(searches, batch...)
+	}
+
+	return searches, nil
+}
+
+// ListAll lists every saved search, used when re-evaluating searches against a changed venue
+func (r *DynamoDBSavedSearchRepository) ListAll(ctx context.Context) ([]*domain.SavedSearch, error) {
+	searches := make([]*domain.SavedSearch, 0)
+
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved searches: %w", err)
+		}
+
+		var batch []*domain.SavedSearch
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal saved searches: %w", err)
+		}
+		searches = append(searches, batch...)
+	}
+
+	return searches, nil
+}
+
+// Delete deletes a saved search by ID
+func (r *DynamoDBSavedSearchRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	return nil
+}
+
+// RecordMatch stores a match unless one already exists for the search and venue
+func (r *DynamoDBSavedSearchRepository) RecordMatch(ctx context.Context, match *domain.SavedSearchMatch) (bool, error) {
+	av, err := attributevalue.MarshalMap(match)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal saved search match: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.matchesTable),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(venue_id)"),
+	})
+	if err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record saved search match: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetMatch retrieves the match recorded for a saved search and venue
+func (r *DynamoDBSavedSearchRepository) GetMatch(ctx context.Context, savedSearchID, venueID string) (*domain.SavedSearchMatch, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.matchesTable),
+		Key: map[string]types.AttributeValue{
+			"saved_search_id": &types.AttributeValueMemberS{Value: savedSearchID},
+			"venue_id":        &types.AttributeValueMemberS{Value: venueID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search match: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &SavedSearchMatchNotFoundError{}
+	}
+
+	var match domain.SavedSearchMatch
+	if err := attributevalue.UnmarshalMap(result.Item, &match); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved search match: %w", err)
+	}
+
+	return &match, nil
+}
+
+// UpdateMatch overwrites an existing match record
+func (r *DynamoDBSavedSearchRepository) UpdateMatch(ctx context.Context, match *domain.SavedSearchMatch) error {
+	av, err := attributevalue.MarshalMap(match)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved search match: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.matchesTable),
+		Item:      av,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update saved search match: %w", err)
+	}
+
+	return nil
+}
+
+// ListMatches lists the venues recorded as matching a saved search
+func (r *DynamoDBSavedSearchRepository) ListMatches(ctx context.Context, savedSearchID string) ([]*domain.SavedSearchMatch, error) {
+	matches := make([]*domain.SavedSearchMatch, 0)
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.matchesTable),
+		KeyConditionExpression: aws.String("saved_search_id = :saved_search_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":saved_search_id": &types.AttributeValueMemberS{Value: savedSearchID},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query saved search matches: %w", err)
+		}
+
+		var batch []*domain.SavedSearchMatch
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal saved search matches: %w", err)
+		}
+		matches = append(matches, batch...)
+	}
+
+	return matches, nil
+}
+
+// SavedSearchNotFoundError is returned when a saved search is not found
+type SavedSearchNotFoundError struct{}
+
+func (e *SavedSearchNotFoundError) Error() string {
+	return "saved search not found"
+}
+
+// SavedSearchMatchNotFoundError is returned when no match is recorded for a saved search and venue
+type SavedSearchMatchNotFoundError struct{}
+
+func (e *SavedSearchMatchNotFoundError) Error() string {
+	return "saved search match not found"
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestSavedSearchRepository_ListByUser(t *testing.T) {
+	repo := NewMockSavedSearchRepository()
+	ctx := context.Background()
+
+	criteria := domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}
+	_ = repo.Create(ctx, domain.NewSavedSearch("user-1", "First", criteria, nil))
+	_ = repo.Create(ctx, domain.NewSavedSearch("user-1", "Second", criteria, nil))
+	_ = repo.Create(ctx, domain.NewSavedSearch("user-2", "Other", criteria, nil))
+
+	results, err := repo.ListByUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("ListByUser() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("ListByUser() returned %v searches, want 2", len(results))
+	}
+}
+
+func TestSavedSearchRepository_RecordMatch_Idempotent(t *testing.T) {
+	repo := NewMockSavedSearchRepository()
+	ctx := context.Background()
+
+	venue := domain.NewVenue("Test Venue", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+
+	created, err := repo.RecordMatch(ctx, domain.NewSavedSearchMatch("search-1", venue, false))
+	if err != nil {
+		t.Fatalf("RecordMatch() error = %v", err)
+	}
+	if !created {
+		t.Error("RecordMatch() should report first match as created")
+	}
+
+	created, _ = repo.RecordMatch(ctx, domain.NewSavedSearchMatch("search-1", venue, false))
+	if created {
+		t.Error("RecordMatch() should not report duplicate match as created")
+	}
+
+	matches, _ := repo.ListMatches(ctx, "search-1")
+	if len(matches) != 1 {
+		t.Errorf("ListMatches() returned %v matches, want 1", len(matches))
+	}
+}
+
+func TestSavedSearchRepository_Delete(t *testing.T) {
+	repo := NewMockSavedSearchRepository()
+	ctx := context.Background()
+
+	search := domain.NewSavedSearch("user-1", "First", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}, nil)
+	_ = repo.Create(ctx, search)
+
+	if err := repo.Delete(ctx, search.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, search.ID); err == nil {
+		t.Error("GetByID() should return error after deletion")
+	}
+}
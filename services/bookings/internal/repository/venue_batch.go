@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const (
+	// maxBatchWriteItems is the most items one BatchWriteItem call accepts
+	maxBatchWriteItems = 25
+	// maxBatchWriteAttempts bounds retries of items DynamoDB leaves unprocessed
+	maxBatchWriteAttempts = 5
+	// batchWriteBackoff is the first wait before retrying unprocessed items
+	batchWriteBackoff = 100 * time.Millisecond
+)
+
+// BatchWriteError lists the venues a batch write could not save, by ID
+type BatchWriteError struct {
+	Failed map[string]error
+}
+
+func (e *BatchWriteError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("failed to write %d venues: %s", len(ids), strings.Join(ids, ", "))
+}
+
+// BatchCreate saves new venues with BatchWriteItem, many to a request. Unlike
+// Create it cannot make the external ID lookups conditional, so callers must
+// check external IDs are free first. Venues whose items were not all written
+// are reported in a BatchWriteError; the rest are saved.
+func (r *DynamoDBVenueRepository) BatchCreate(ctx context.Context, venues []*domain.Venue) error {
+	failed := make(map[string]error)
+	var batch []types.WriteRequest
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		unprocessed, err := batchWrite(ctx, r.client, r.tableName, batch)
+		for _, request := range unprocessed {
+			failed[requestVenueID(request)] = err
+		}
+		batch = nil
+	}
+
+	for _, venue := range venues {
+		venue.Version = 1
+		requests, err := r.venueWriteRequests(venue)
+		if err != nil {
+			failed[venue.ID] = err
+			continue
+		}
+		// Keep each venue's items in one request
+		if len(batch)+len(requests) > maxBatchWriteItems {
+			flush()
+		}
+		batch = append(batch, requests...)
+	}
+	flush()
+
+	if len(failed) > 0 {
+		return &BatchWriteError{Failed: failed}
+	}
+	return nil
+}
+
+// venueWriteRequests returns the puts for a venue, its external ID lookups
+// and its type index items
+func (r *DynamoDBVenueRepository) venueWriteRequests(venue *domain.Venue) ([]types.WriteRequest, error) {
+	av, err := attributevalue.MarshalMap(toVenueItem(venue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal venue: %w", err)
+	}
+
+	requests := []types.WriteRequest{{PutRequest: &types.PutRequest{Item: av}}}
+	for _, ext := range externalIDList(venue.ExternalIDs()) {
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{
+			Item: map[string]types.AttributeValue{
+				"id":       &types.AttributeValueMemberS{Value: externalIDKey(ext.source, ext.id)},
+				"venue_id": &types.AttributeValueMemberS{Value: venue.ID},
+			},
+		}})
+	}
+	typeRequests, err := typeIndexRequests(venue)
+	if err != nil {
+		return nil, err
+	}
+	return append(requests, typeRequests...), nil
+}
+
+// batchWrite sends one BatchWriteItem request to a table, retrying
+// unprocessed items with exponential backoff. On failure it returns the
+// requests that were not written.
+func batchWrite(ctx context.Context, client *dynamodb.Client, tableName string, requests []types.WriteRequest) ([]types.WriteRequest, error) {
+	pending := requests
+	backoff := batchWriteBackoff
+
+	for attempt := 1; ; attempt++ {
+		result, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{tableName: pending},
+		})
+		if err != nil {
+			return pending, fmt.Errorf("failed to batch write to %s: %w", tableName, err)
+		}
+		pending = result.UnprocessedItems[tableName]
+		if len(pending) == 0 {
+			return nil, nil
+		}
+		if attempt >= maxBatchWriteAttempts {
+			return pending, fmt.Errorf("items still unprocessed after %d attempts", attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return pending, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// requestVenueID returns the venue a write request belongs to: lookup items
+// name it in venue_id and venue items are keyed by it
+func requestVenueID(request types.WriteRequest) string {
+	item := request.PutRequest.Item
+	if v, ok := item["venue_id"].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	if v, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
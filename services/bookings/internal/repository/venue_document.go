@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// marshalVenueDocument encodes a venue as a JSON document keyed by its
+// stored field names. Unlike the venue's API form, the document keeps the
+// fields clients never see, such as review totals and photo keys, and keeps
+// empty lists distinct from missing ones.
+func marshalVenueDocument(venue *domain.Venue) ([]byte, error) {
+	item, err := attributevalue.MarshalMap(venue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal venue: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := attributevalue.UnmarshalMap(item, &doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal venue: %w", err)
+	}
+	return json.Marshal(doc)
+}
+
+// unmarshalVenueDocument decodes a document written by marshalVenueDocument
+func unmarshalVenueDocument(data []byte) (*domain.Venue, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue: %w", err)
+	}
+	item, err := attributevalue.MarshalMap(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue: %w", err)
+	}
+	var venue domain.Venue
+	if err := attributevalue.UnmarshalMap(item, &venue); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue: %w", err)
+	}
+	return &venue, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestVenueDocument_KeepsStoredFields(t *testing.T) {
+	venue := newTestVenue("The Fillmore")
+	venue.ReviewTotals = domain.ReviewScores{Sound: 9, Turnout: 7}
+	venue.Provenance = map[domain.VenueField]domain.FieldProvenance{
+		domain.FieldName: {Source: domain.SourceManual, Actor: "user-1", Confidence: 1},
+	}
+	venue.Gallery = []domain.VenuePhoto{{ID: "photo-1", Key: "photos/1.jpg", ThumbnailKey: "photos/1-thumb.jpg"}}
+
+	data, err := marshalVenueDocument(venue)
+	if err != nil {
+		t.Fatalf("marshalVenueDocument() error = %v", err)
+	}
+	got, err := unmarshalVenueDocument(data)
+	if err != nil {
+		t.Fatalf("unmarshalVenueDocument() error = %v", err)
+	}
+
+	if got.ReviewTotals != venue.ReviewTotals {
+		t.Errorf("ReviewTotals = %+v, want %+v", got.ReviewTotals, venue.ReviewTotals)
+	}
+	if got.Provenance[domain.FieldName].Actor != "user-1" {
+		t.Errorf("Provenance = %+v, want the name's actor kept", got.Provenance)
+	}
+	if got.Gallery[0].Key != "photos/1.jpg" || got.Gallery[0].ThumbnailKey != "photos/1-thumb.jpg" {
+		t.Errorf("Gallery[0] = %+v, want the object keys kept", got.Gallery[0])
+	}
+
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(venue)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("decoded venue = %s\nwant %s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// VenueEventRepository stores the shows each venue has hosted
+type VenueEventRepository interface {
+	// Save writes events, replacing any stored under the same venue, source
+	// and source ID
+	Save(ctx context.Context, events []*domain.VenueEvent) error
+	// ListByVenue returns a page of a venue's events, latest first, and the
+	// cursor for the next page, which is empty on the last page
+	ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueEvent, string, error)
+	// ListSince returns every event at a venue on or after since, latest first
+	ListSince(ctx context.Context, venueID string, since time.Time) ([]*domain.VenueEvent, error)
+}
+
+// DynamoDBVenueEventRepository implements VenueEventRepository using
+// DynamoDB, keyed by venue ID and event key with a local index on date
+type DynamoDBVenueEventRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBVenueEventRepository creates a new DynamoDB venue event repository
+func NewDynamoDBVenueEventRepository(client *dynamodb.Client, tableName string) *DynamoDBVenueEventRepository {
+	return &DynamoDBVenueEventRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Save puts events in batches of up to 25
+func (r *DynamoDBVenueEventRepository) Save(ctx context.Context, events []*domain.VenueEvent) error {
+	for start := 0; start < len(events); start += maxBatchWriteItems {
+		batch := events[start:min(start+maxBatchWriteItems, len(events))]
+
+		requests := make([]types.WriteRequest, 0, len(batch))
+		for _, event := range batch {
+			av, err := attributevalue.MarshalMap(event)
+			if err != nil {
+				return fmt.Errorf("failed to marshal event: %w", err)
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		}
+
+		if _, err := batchWrite(ctx, r.client, r.tableName, requests); err != nil {
+			return fmt.Errorf("failed to save events: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListByVenue pages through a venue's events on the DateIndex, latest first
+func (r *DynamoDBVenueEventRepository) ListByVenue(ctx context.Context, venueID string, limit int, cursor string) ([]*domain.VenueEvent, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("DateIndex"),
+		KeyConditionExpression: aws.String("venue_id = :venue_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_id": &types.AttributeValueMemberS{Value: venueID},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int32(int32(limit)),
+		ExclusiveStartKey: startKey,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query events: %w", err)
+	}
+
+	events := make([]*domain.VenueEvent, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &events); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal events: %w", err)
+	}
+
+	next, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return events, next, nil
+}
+
+// ListSince queries the DateIndex from since onwards, following every page
+func (r *DynamoDBVenueEventRepository) ListSince(ctx context.Context, venueID string, since time.Time) ([]*domain.VenueEvent, error) {
+	since = since.UTC().Truncate(time.Second)
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("DateIndex"),
+		KeyConditionExpression: aws.String("venue_id = :venue_id AND #date >= :since"),
+		ExpressionAttributeNames: map[string]string{
+			"#date": "date",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_id": &types.AttributeValueMemberS{Value: venueID},
+			":since":    &types.AttributeValueMemberS{Value: since.Format(time.RFC3339)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	events := make([]*domain.VenueEvent, 0)
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query events: %w", err)
+		}
+
+		var batch []*domain.VenueEvent
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal events: %w", err)
+		}
+		events = append(events, batch...)
+	}
+	return events, nil
+}
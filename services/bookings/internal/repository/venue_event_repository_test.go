@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestVenueEventRepository_SaveReplacesAndPages(t *testing.T) {
+	repo := NewMockVenueEventRepository()
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)
+
+	var events []*domain.VenueEvent
+	for i, id := range []string{"1", "2", "3"} {
+		events = append(events, domain.NewVenueEvent("venue-1", domain.SourceSongkick, id, start.AddDate(0, i, 0)))
+	}
+	if err := repo.Save(ctx, events); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A later sync of the same event replaces it
+	renamed := domain.NewVenueEvent("venue-1", domain.SourceSongkick, "1", start)
+	renamed.ArtistName = "Thee Oh Sees"
+	if err := repo.Save(ctx, []*domain.VenueEvent{renamed}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	page, next, err := repo.ListByVenue(ctx, "venue-1", 2, "")
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(page) != 2 || page[0].SourceID != "3" || page[1].SourceID != "2" || next == "" {
+		t.Fatalf("first page = %+v next %q, want events 3 and 2 and a cursor", page, next)
+	}
+	page, next, _ = repo.ListByVenue(ctx, "venue-1", 2, next)
+	if len(page) != 1 || page[0].ArtistName != "Thee Oh Sees" || next != "" {
+		t.Errorf("last page = %+v next %q, want the replaced event 1 only", page, next)
+	}
+
+	recent, _ := repo.ListSince(ctx, "venue-1", start.AddDate(0, 1, 0))
+	if len(recent) != 2 {
+		t.Errorf("ListSince() returned %d events, want 2", len(recent))
+	}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// maxAppendAttempts bounds retries when concurrent writers race for the
+// same version number
+const maxAppendAttempts = 5
+
+// VenueHistoryRepository stores the append-only change history of venues
+type VenueHistoryRepository interface {
+	// Append stores a version as the venue's next version number and sets
+	// version.Version to it
+	Append(ctx context.Context, version *domain.VenueVersion) error
+	// List returns a venue's versions, newest first
+	List(ctx context.Context, venueID string, limit int) ([]*domain.VenueVersion, error)
+	Get(ctx context.Context, venueID string, version int) (*domain.VenueVersion, error)
+}
+
+// DynamoDBVenueHistoryRepository implements VenueHistoryRepository using
+// DynamoDB, keyed by venue ID and version number
+type DynamoDBVenueHistoryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBVenueHistoryRepository creates a new DynamoDB venue history repository
+func NewDynamoDBVenueHistoryRepository(client *dynamodb.Client, tableName string) *DynamoDBVenueHistoryRepository {
+	return &DynamoDBVenueHistoryRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Append writes the version after the venue's latest one. Items are never
+// overwritten; if another writer takes the number first it tries the next.
+func (r *DynamoDBVenueHistoryRepository) Append(ctx context.Context, version *domain.VenueVersion) error {
+	latest, err := r.latestVersion(ctx, version.VenueID)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < maxAppendAttempts; attempt++ {
+		version.Version = latest + 1 + attempt
+
+		av, err := attributevalue.MarshalMap(version)
+		if err != nil {
+			return fmt.Errorf("failed to marshal venue version: %w", err)
+		}
+
+		_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(r.tableName),
+			Item:                av,
+			ConditionExpression: aws.String("attribute_not_exists(version)"),
+		})
+		if err == nil {
+			return nil
+		}
+
+		var conditionErr *types.ConditionalCheckFailedException
+		if !errors.As(err, &conditionErr) {
+			return fmt.Errorf("failed to append venue version: %w", err)
+		}
+	}
+
+	return fmt.Errorf("failed to append venue version: version %d already taken", version.Version)
+}
+
+// List returns a venue's versions, newest first
+func (r *DynamoDBVenueHistoryRepository) List(ctx context.Context, venueID string, limit int) ([]*domain.VenueVersion, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("venue_id = :venue_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_id": &types.AttributeValueMemberS{Value: venueID},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query venue history: %w", err)
+	}
+
+	versions := make([]*domain.VenueVersion, 0, len(result.Items))
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &versions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue history: %w", err)
+	}
+
+	return versions, nil
+}
+
+// Get retrieves one version of a venue
+func (r *DynamoDBVenueHistoryRepository) Get(ctx context.Context, venueID string, version int) (*domain.VenueVersion, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"venue_id": &types.AttributeValueMemberS{Value: venueID},
+			"version":  &types.AttributeValueMemberN{Value: strconv.Itoa(version)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get venue version: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, &VenueVersionNotFoundError{}
+	}
+
+	var v domain.VenueVersion
+	if err := attributevalue.UnmarshalMap(result.Item, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal venue version: %w", err)
+	}
+
+	return &v, nil
+}
+
+// latestVersion returns a venue's highest version number, or 0 if it has none
+func (r *DynamoDBVenueHistoryRepository) latestVersion(ctx context.Context, venueID string) (int, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("venue_id = :venue_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_id": &types.AttributeValueMemberS{Value: venueID},
+		},
+		ProjectionExpression: aws.String("version"),
+		ScanIndexForward:     aws.Bool(false),
+		Limit:                aws.Int32(1),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query latest venue version: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return 0, nil
+	}
+
+	var latest struct {
+		Version int `dynamodbav:"version"`
+	}
+	if err := attributevalue.UnmarshalMap(result.Items[0], &latest); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal venue version: %w", err)
+	}
+
+	return latest.Version, nil
+}
+
+// VenueVersionNotFoundError is returned when a venue version is not found
+type VenueVersionNotFoundError struct{}
+
+func (e *VenueVersionNotFoundError) Error() string {
+	return "venue version not found"
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestVenueHistoryRepository_AppendNumbersVersions(t *testing.T) {
+	repo := NewMockVenueHistoryRepository()
+	ctx := context.Background()
+
+	venue := domain.NewVenue("A", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	for _, action := range []domain.VenueChangeAction{domain.VenueCreated, domain.VenueUpdated, domain.VenueUpdated} {
+		if err := repo.Append(ctx, domain.NewVenueVersion(nil, venue, action)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	versions, err := repo.List(ctx, venue.ID, 2)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 3 || versions[1].Version != 2 {
+		t.Errorf("List() should return the newest two versions first, got %d", len(versions))
+	}
+
+	if _, err := repo.Get(ctx, venue.ID, 4); err == nil {
+		t.Error("Get() should fail for a version that does not exist")
+	}
+}
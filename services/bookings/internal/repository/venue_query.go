@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const (
+	// geohashCellLength is the geohash precision GeohashIndex partitions
+	// venues by. Searches may use this precision or finer.
+	geohashCellLength = 3
+	// maxGeohashQueries bounds how many geohash prefixes are queried at once
+	maxGeohashQueries = 4
+)
+
+// corruptItems counts items that could not be read back, by index, and is
+// published at /debug/vars
+var corruptItems = expvar.NewMap("venue_repository_corrupt_items")
+
+// CorruptItemError describes a stored item that could not be unmarshalled
+type CorruptItemError struct {
+	Index string
+	ID    string
+	Err   error
+}
+
+func (e *CorruptItemError) Error() string {
+	return fmt.Sprintf("corrupt item %q in %s: %v", e.ID, e.Index, e.Err)
+}
+
+func (e *CorruptItemError) Unwrap() error {
+	return e.Err
+}
+
+// reportCorrupt logs and counts an item a query had to skip. Searches skip
+// such items rather than fail, so one bad item cannot break every search
+// that reaches it.
+func reportCorrupt(index string, item map[string]types.AttributeValue, err error) {
+	id := ""
+	if v, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		id = v.Value
+	}
+	reportCorruptID(index, id, err)
+}
+
+// reportCorruptID logs and counts a skipped item by its ID
+func reportCorruptID(index, id string, err error) {
+	corruptItems.Add(index, 1)
+	log.Printf("skipping %v", &CorruptItemError{Index: index, ID: id, Err: err})
+}
+
+// geohashCell returns the GeohashIndex partition of a geohash
+func geohashCell(geohash string) string {
+	if len(geohash) > geohashCellLength {
+		return geohash[:geohashCellLength]
+	}
+	return geohash
+}
+
+// geohashSort is the GeohashIndex sort key, which orders venues by geohash
+func geohashSort(v *domain.Venue) string {
+	return fmt.Sprintf("%s#%s", v.Location.Geohash, v.ID)
+}
+
+// queryVenues pages through a venue index query until it has limit venues
+// or the index has no more. Corrupt items are reported and skipped.
+func (r *DynamoDBVenueRepository) queryVenues(ctx context.Context, input *dynamodb.QueryInput, limit int) ([]*domain.Venue, error) {
+	venues := make([]*domain.Venue, 0)
+	if limit <= 0 {
+		return venues, nil
+	}
+	input.TableName = aws.String(r.tableName)
+	input.Limit = aws.Int32(int32(limit))
+
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
+	for paginator.HasMorePages() && len(venues) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %w", aws.ToString(input.IndexName), err)
+		}
+		for _, item := range page.Items {
+			var venueItem venueItem
+			if err := attributevalue.UnmarshalMap(item, &venueItem); err != nil {
+				reportCorrupt(aws.ToString(input.IndexName), item, err)
+				continue
+			}
+			venues = append(venues, venueItem.Venue)
+		}
+	}
+
+	if len(venues) > limit {
+		venues = venues[:limit]
+	}
+	return venues, nil
+}
+
+// SearchByGeohash searches venues by geohash prefixes, querying up to
+// maxGeohashQueries prefixes at once. Venues come back in prefix order.
+func (r *DynamoDBVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefixes []string, limit int) ([]*domain.Venue, error) {
+	for _, prefix := range geohashPrefixes {
+		if len(prefix) < geohashCellLength {
+			return nil, fmt.Errorf("geohash prefix %q is shorter than %d characters", prefix, geohashCellLength)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]*domain.Venue, len(geohashPrefixes))
+	errs := make([]error, len(geohashPrefixes))
+	sem := make(chan struct{}, maxGeohashQueries)
+	var wg sync.WaitGroup
+	for i, prefix := range geohashPrefixes {
+		wg.Add(1)
+		go func(i int, prefix string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				errs[i] = ctx.Err()
+				return
+			}
+
+			// Each prefix may supply every result, so each is read up to limit
+			results[i], errs[i] = r.queryVenues(ctx, &dynamodb.QueryInput{
+				IndexName:              aws.String("GeohashIndex"),
+				KeyConditionExpression: aws.String("geohash = :cell AND begins_with(geohash_sort, :prefix)"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":cell":   &types.AttributeValueMemberS{Value: geohashCell(prefix)},
+					":prefix": &types.AttributeValueMemberS{Value: prefix},
+				},
+			}, limit)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i, prefix)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("geohash prefix %s: %w", geohashPrefixes[i], err)
+		}
+	}
+	return mergeVenues(results, limit), nil
+}
+
+// mergeVenues concatenates result sets in order, dropping venues already
+// seen, up to limit venues. Overlapping prefixes can return a venue twice.
+func mergeVenues(results [][]*domain.Venue, limit int) []*domain.Venue {
+	venues := make([]*domain.Venue, 0)
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, venue := range result {
+			if len(venues) >= limit {
+				return venues
+			}
+			if seen[venue.ID] {
+				continue
+			}
+			seen[venue.ID] = true
+			venues = append(venues, venue)
+		}
+	}
+	return venues
+}
+
+// SearchByCity searches venues by city and state in name order
+func (r *DynamoDBVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, &dynamodb.QueryInput{
+		IndexName:              aws.String("CityIndex"),
+		KeyConditionExpression: aws.String("city_state = :city_state"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":city_state": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", city, state)},
+		},
+	}, limit)
+}
+
+// BackfillGeohashIndex moves venues saved before GeohashIndex was
+// partitioned by cell into their cell. It is safe to run repeatedly and
+// returns how many venues it moved.
+func (r *DynamoDBVenueRepository) BackfillGeohashIndex(ctx context.Context) (int, error) {
+	moved := 0
+	err := r.ScanAll(ctx, func(venue *domain.Venue) error {
+		cell := geohashCell(venue.Location.Geohash)
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: venue.ID},
+			},
+			UpdateExpression:    aws.String("SET geohash = :cell, geohash_sort = :sort"),
+			ConditionExpression: aws.String("attribute_exists(id) AND geohash <> :cell"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":cell": &types.AttributeValueMemberS{Value: cell},
+				":sort": &types.AttributeValueMemberS{Value: geohashSort(venue)},
+			},
+		})
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("venue %s: failed to move geohash index entry: %w", venue.ID, err)
+		}
+		moved++
+		return nil
+	})
+	return moved, err
+}
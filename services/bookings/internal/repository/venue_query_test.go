@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func TestGeohashCell(t *testing.T) {
+	tests := map[string]string{
+		"9q8yyk": "9q8",
+		"9q8":    "9q8",
+		"9q":     "9q",
+	}
+	for geohash, want := range tests {
+		if got := geohashCell(geohash); got != want {
+			t.Errorf("geohashCell(%q) = %q, want %q", geohash, got, want)
+		}
+	}
+}
+
+func TestMergeVenues(t *testing.T) {
+	a := &domain.Venue{ID: "a"}
+	b := &domain.Venue{ID: "b"}
+	c := &domain.Venue{ID: "c"}
+
+	merged := mergeVenues([][]*domain.Venue{{a, b}, {b, c}, nil}, 10)
+	if len(merged) != 3 || merged[0] != a || merged[1] != b || merged[2] != c {
+		t.Errorf("mergeVenues() = %v, want a, b, c once each in order", merged)
+	}
+
+	merged = mergeVenues([][]*domain.Venue{{a}, {b, c}}, 2)
+	if len(merged) != 2 || merged[1] != b {
+		t.Errorf("mergeVenues() with limit 2 = %v, want a, b", merged)
+	}
+}
+
+func TestReportCorrupt(t *testing.T) {
+	count := func() int64 {
+		if v, ok := corruptItems.Get("TestIndex").(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := count()
+
+	item := map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "venue-1"}}
+	reportCorrupt("TestIndex", item, errors.New("bad"))
+
+	if got := count(); got != before+1 {
+		t.Errorf("corrupt item count = %d, want %d", got, before+1)
+	}
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -21,12 +23,31 @@ type VenueRepository interface {
 	SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error)
 	SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error)
 	GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error)
+	ScanAll(ctx context.Context, fn func(*domain.Venue) error) error
+	// ListByModerationStatus returns community submissions in a moderation
+	// state, oldest first
+	ListByModerationStatus(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error)
+	// BatchCreate saves many new venues at once. Venues that could not be
+	// saved are listed in a BatchWriteError.
+	BatchCreate(ctx context.Context, venues []*domain.Venue) error
+	// Merge saves the survivor of a merge and deletes the merged venue as one
+	// write, handing the merged venue's external IDs to the survivor and
+	// recording the redirect between them. Both venues must still be at
+	// their versions; the survivor's is then incremented.
+	Merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error
+}
+
+// RedirectWriter records where a merged venue ID points, for venue stores
+// that keep redirects apart from venues
+type RedirectWriter interface {
+	CreateRedirect(ctx context.Context, redirect *domain.VenueRedirect) error
 }
 
 // DynamoDBVenueRepository implements VenueRepository using DynamoDB
 type DynamoDBVenueRepository struct {
-	client    *dynamodb.Client
-	tableName string
+	client         *dynamodb.Client
+	tableName      string
+	redirectsTable string
 }
 
 // NewDynamoDBVenueRepository creates a new DynamoDB venue repository
@@ -37,66 +58,79 @@ func NewDynamoDBVenueRepository(client *dynamodb.Client, tableName string) *Dyna
 	}
 }
 
+// SetRedirectsTable sets the table Merge records venue redirects in, so
+// they are written in the same transaction as the venues
+func (r *DynamoDBVenueRepository) SetRedirectsTable(tableName string) {
+	r.redirectsTable = tableName
+}
+
 // venueItem represents the DynamoDB item structure with GSI attributes
 type venueItem struct {
 	*domain.Venue
-	// GSI attributes
-	Geohash           string `dynamodbav:"geohash"`
-	GeohashSort       string `dynamodbav:"geohash_sort"`
-	CityState         string `dynamodbav:"city_state"`
-	VenueType         string `dynamodbav:"venue_type"`
-	RatingID          string `dynamodbav:"rating_id"`
-	ExternalSourceID  string `dynamodbav:"external_source_id,omitempty"`
+	// GSI attributes. GeohashIndex partitions venues by a coarse geohash
+	// cell and sorts them by full geohash, so any longer prefix is a range.
+	Geohash     string `dynamodbav:"geohash"`
+	GeohashSort string `dynamodbav:"geohash_sort"`
+	CityState   string `dynamodbav:"city_state"`
+	RatingID    string `dynamodbav:"rating_id"`
+}
+
+// externalIDItem maps one external source ID to the venue that owns it.
+// Lookup items share the venues table and carry no GSI attributes.
+type externalIDItem struct {
+	ID      string `dynamodbav:"id"`
+	VenueID string `dynamodbav:"venue_id"`
 }
 
-// toVenueItem converts a domain.Venue to a venueItem with GSI attributes
+// externalIDKey returns the lookup item key for an external source ID
+func externalIDKey(source domain.DataSource, externalID string) string {
+	return fmt.Sprintf("EXT#%s#%s", source, externalID)
+}
+
+// toVenueItem converts a domain.Venue to a venueItem with GSI attributes.
+// Venue types are indexed by separate items; see typeIndexItem.
 func toVenueItem(v *domain.Venue) *venueItem {
-	item := &venueItem{
+	return &venueItem{
 		Venue:       v,
-		Geohash:     v.Location.Geohash,
-		GeohashSort: fmt.Sprintf("%s#%s", v.Location.Geohash, v.ID),
+		Geohash:     geohashCell(v.Location.Geohash),
+		GeohashSort: geohashSort(v),
 		CityState:   fmt.Sprintf("%s#%s", v.Address.City, v.Address.State),
-		RatingID:    fmt.Sprintf("%010.2f#%s", v.Rating, v.ID),
-	}
-
-	// Set primary venue type for GSI3
-	if len(v.VenueTypes) > 0 {
-		item.VenueType = string(v.VenueTypes[0])
-	}
-
-	// Set external source ID for GSI4
-	switch v.Source {
-	case domain.SourceSongkick:
-		if v.SongkickID != "" {
-			item.ExternalSourceID = fmt.Sprintf("%s#%s", v.Source, v.SongkickID)
-		}
-	case domain.SourceBandsintown:
-		if v.BandsintownID != "" {
-			item.ExternalSourceID = fmt.Sprintf("%s#%s", v.Source, v.BandsintownID)
-		}
-	case domain.SourceGooglePlaces:
-		if v.GooglePlaceID != "" {
-			item.ExternalSourceID = fmt.Sprintf("%s#%s", v.Source, v.GooglePlaceID)
-		}
+		RatingID:    ratingID(v),
 	}
+}
 
-	return item
+// ratingID is the sort key that orders venues by rating
+func ratingID(v *domain.Venue) string {
+	return fmt.Sprintf("%010.2f#%s", v.Rating, v.ID)
 }
 
-// Create creates a new venue in DynamoDB
+// Create creates a new venue in DynamoDB at version 1 along with a lookup
+// item for each of its external IDs and an index item for each of its types
 func (r *DynamoDBVenueRepository) Create(ctx context.Context, venue *domain.Venue) error {
-	item := toVenueItem(venue)
-
-	av, err := attributevalue.MarshalMap(item)
+	venue.Version = 1
+	put, err := r.putVenue(venue)
 	if err != nil {
-		return fmt.Errorf("failed to marshal venue: %w", err)
+		return err
+	}
+	typeWrites, err := r.typeIndexWrites(nil, venue)
+	if err != nil {
+		return err
 	}
 
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+	items := []types.TransactWriteItem{put}
+	lookups := externalIDList(venue.ExternalIDs())
+	for _, ext := range lookups {
+		items = append(items, r.putLookup(ext, venue.ID))
+	}
+	items = append(items, typeWrites...)
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
 	})
 	if err != nil {
+		if conflict := lookupConflict(err, lookups, 1); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to create venue: %w", err)
 	}
 
@@ -128,33 +162,116 @@ func (r *DynamoDBVenueRepository) GetByID(ctx context.Context, id string) (*doma
 	return item.Venue, nil
 }
 
-// Update updates an existing venue
+// Update updates an existing venue, adding lookup items for new external IDs
+// and removing those for IDs the venue no longer carries, and likewise for
+// type index items. The write only succeeds if the stored venue is still at
+// venue.Version, which is then incremented.
 func (r *DynamoDBVenueRepository) Update(ctx context.Context, venue *domain.Venue) error {
-	item := toVenueItem(venue)
+	current, err := r.GetByID(ctx, venue.ID)
+	if err != nil {
+		return err
+	}
+	expected := venue.Version
+	if current.Version != expected {
+		return &VersionConflictError{ID: venue.ID, Version: expected}
+	}
 
-	av, err := attributevalue.MarshalMap(item)
+	// The version is raised for the write and restored unless it succeeds,
+	// so a caller can retry with the same venue
+	venue.Version = expected + 1
+	written := false
+	defer func() {
+		if !written {
+			venue.Version = expected
+		}
+	}()
+	put, err := r.putVenue(venue)
 	if err != nil {
-		return fmt.Errorf("failed to marshal venue: %w", err)
+		return err
 	}
+	put.Put.ConditionExpression, put.Put.ExpressionAttributeNames, put.Put.ExpressionAttributeValues = versionCondition(expected)
 
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      av,
+	items := []types.TransactWriteItem{put}
+	oldIDs := current.ExternalIDs()
+	newIDs := venue.ExternalIDs()
+
+	added := make([]externalID, 0)
+	for _, ext := range externalIDList(newIDs) {
+		if oldIDs[ext.source] != ext.id {
+			added = append(added, ext)
+			items = append(items, r.putLookup(ext, venue.ID))
+		}
+	}
+	removed := make([]externalID, 0)
+	for _, ext := range externalIDList(oldIDs) {
+		if newIDs[ext.source] != ext.id {
+			removed = append(removed, ext)
+		}
+	}
+	removed, err = r.ownedLookups(ctx, removed, venue.ID)
+	if err != nil {
+		return err
+	}
+	for _, ext := range removed {
+		items = append(items, r.deleteLookup(ext, venue.ID))
+	}
+	typeWrites, err := r.typeIndexWrites(current, venue)
+	if err != nil {
+		return err
+	}
+	items = append(items, typeWrites...)
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
 	})
 	if err != nil {
+		if versionConflict(err) {
+			return &VersionConflictError{ID: venue.ID, Version: expected}
+		}
+		if conflict := lookupConflict(err, added, 1); conflict != nil {
+			return conflict
+		}
 		return fmt.Errorf("failed to update venue: %w", err)
 	}
 
+	written = true
 	return nil
 }
 
-// Delete deletes a venue by ID
+// Delete deletes a venue by ID together with its external ID lookup items
+// and type index items
 func (r *DynamoDBVenueRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		if _, ok := err.(*VenueNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+
+	items := []types.TransactWriteItem{{
+		Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: id},
+			},
 		},
+	}}
+	owned, err := r.ownedLookups(ctx, externalIDList(current.ExternalIDs()), id)
+	if err != nil {
+		return err
+	}
+	for _, ext := range owned {
+		items = append(items, r.deleteLookup(ext, id))
+	}
+	typeWrites, err := r.typeIndexWrites(current, nil)
+	if err != nil {
+		return err
+	}
+	items = append(items, typeWrites...)
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete venue: %w", err)
@@ -163,98 +280,335 @@ func (r *DynamoDBVenueRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// SearchByGeohash searches venues by geohash prefixes
-func (r *DynamoDBVenueRepository) SearchByGeohash(ctx context.Context, geohashPrefixes []string, limit int) ([]*domain.Venue, error) {
-	venues := make([]*domain.Venue, 0)
+// Merge saves the survivor and deletes the merged venue in one transaction,
+// which also moves the merged venue's external ID lookups to the survivor,
+// brings both venues' type index items up to date and puts the redirect.
+// Nothing is written unless every item succeeds, so a failed merge leaves
+// both venues as they were.
+func (r *DynamoDBVenueRepository) Merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error {
+	if r.redirectsTable == "" {
+		return fmt.Errorf("venue redirects table is not configured")
+	}
+	current, err := r.GetByID(ctx, survivor.ID)
+	if err != nil {
+		return err
+	}
+	expected := survivor.Version
+	if current.Version != expected {
+		return &VersionConflictError{ID: survivor.ID, Version: expected}
+	}
+	currentMerged, err := r.GetByID(ctx, merged.ID)
+	if err != nil {
+		return err
+	}
+	if currentMerged.Version != merged.Version {
+		return &VersionConflictError{ID: merged.ID, Version: merged.Version}
+	}
 
-	for _, prefix := range geohashPrefixes {
-		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
-			TableName:              aws.String(r.tableName),
-			IndexName:              aws.String("GeohashIndex"),
-			KeyConditionExpression: aws.String("geohash = :geohash"),
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":geohash": &types.AttributeValueMemberS{Value: prefix},
-			},
-			Limit: aws.Int32(int32(limit)),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to query by geohash: %w", err)
+	survivor.Version = expected + 1
+	written := false
+	defer func() {
+		if !written {
+			survivor.Version = expected
+		}
+	}()
+	put, err := r.putVenue(survivor)
+	if err != nil {
+		return err
+	}
+	put.Put.ConditionExpression, put.Put.ExpressionAttributeNames, put.Put.ExpressionAttributeValues = versionCondition(expected)
+	remove := &types.Delete{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: merged.ID},
+		},
+	}
+	remove.ConditionExpression, remove.ExpressionAttributeNames, remove.ExpressionAttributeValues = versionCondition(merged.Version)
+	items := []types.TransactWriteItem{put, {Delete: remove}}
+
+	// IDs the survivor takes over from the merged venue change owner in
+	// place; the merged venue's other IDs are released
+	mergedOwned, err := r.ownedLookups(ctx, externalIDList(currentMerged.ExternalIDs()), merged.ID)
+	if err != nil {
+		return err
+	}
+	transfer := make(map[externalID]bool, len(mergedOwned))
+	for _, ext := range mergedOwned {
+		transfer[ext] = true
+	}
+
+	oldIDs := current.ExternalIDs()
+	newIDs := survivor.ExternalIDs()
+	added := make([]externalID, 0)
+	moved := make([]types.TransactWriteItem, 0)
+	for _, ext := range externalIDList(newIDs) {
+		switch {
+		case oldIDs[ext.source] == ext.id:
+		case transfer[ext]:
+			delete(transfer, ext)
+			moved = append(moved, r.transferLookup(ext, merged.ID, survivor.ID))
+		default:
+			added = append(added, ext)
+			items = append(items, r.putLookup(ext, survivor.ID))
 		}
+	}
+	items = append(items, moved...)
 
-		for _, item := range result.Items {
-			var venueItem venueItem
-			err = attributevalue.UnmarshalMap(item, &venueItem)
-			if err != nil {
-				continue
-			}
-			venues = append(venues, venueItem.Venue)
+	removed := make([]externalID, 0)
+	for _, ext := range externalIDList(oldIDs) {
+		if newIDs[ext.source] != ext.id {
+			removed = append(removed, ext)
 		}
+	}
+	removed, err = r.ownedLookups(ctx, removed, survivor.ID)
+	if err != nil {
+		return err
+	}
+	for _, ext := range removed {
+		items = append(items, r.deleteLookup(ext, survivor.ID))
+	}
+	for _, ext := range mergedOwned {
+		if transfer[ext] {
+			items = append(items, r.deleteLookup(ext, merged.ID))
+		}
+	}
+
+	typeWrites, err := r.typeIndexWrites(current, survivor)
+	if err != nil {
+		return err
+	}
+	items = append(items, typeWrites...)
+	typeWrites, err = r.typeIndexWrites(currentMerged, nil)
+	if err != nil {
+		return err
+	}
+	items = append(items, typeWrites...)
+
+	av, err := attributevalue.MarshalMap(redirect)
+	if err != nil {
+		return fmt.Errorf("failed to marshal venue redirect: %w", err)
+	}
+	items = append(items, types.TransactWriteItem{
+		Put: &types.Put{TableName: aws.String(r.redirectsTable), Item: av},
+	})
 
-		if len(venues) >= limit {
-			break
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		if versionConflict(err) {
+			return &VersionConflictError{ID: survivor.ID, Version: expected}
+		}
+		if conditionFailed(err, 1) {
+			return &VersionConflictError{ID: merged.ID, Version: merged.Version}
 		}
+		if conflict := lookupConflict(err, added, 2); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("failed to merge venues: %w", err)
 	}
 
-	return venues, nil
+	written = true
+	return nil
 }
 
-// SearchByCity searches venues by city and state
-func (r *DynamoDBVenueRepository) SearchByCity(ctx context.Context, city, state string, limit int) ([]*domain.Venue, error) {
-	cityState := fmt.Sprintf("%s#%s", city, state)
+// putVenue builds the transactional put for the venue item itself
+func (r *DynamoDBVenueRepository) putVenue(venue *domain.Venue) (types.TransactWriteItem, error) {
+	av, err := attributevalue.MarshalMap(toVenueItem(venue))
+	if err != nil {
+		return types.TransactWriteItem{}, fmt.Errorf("failed to marshal venue: %w", err)
+	}
 
-	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("CityIndex"),
-		KeyConditionExpression: aws.String("city_state = :city_state"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":city_state": &types.AttributeValueMemberS{Value: cityState},
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item:      av,
+		},
+	}, nil
+}
+
+// putLookup claims an external ID for a venue. The put fails if another
+// venue already owns the ID.
+func (r *DynamoDBVenueRepository) putLookup(ext externalID, venueID string) types.TransactWriteItem {
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item: map[string]types.AttributeValue{
+				"id":       &types.AttributeValueMemberS{Value: externalIDKey(ext.source, ext.id)},
+				"venue_id": &types.AttributeValueMemberS{Value: venueID},
+			},
+			ConditionExpression: aws.String("attribute_not_exists(id) OR venue_id = :venue_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":venue_id": &types.AttributeValueMemberS{Value: venueID},
+			},
+		},
+	}
+}
+
+// transferLookup hands an external ID from one venue to another. The put
+// fails unless the first venue still owns the ID.
+func (r *DynamoDBVenueRepository) transferLookup(ext externalID, fromID, toID string) types.TransactWriteItem {
+	return types.TransactWriteItem{
+		Put: &types.Put{
+			TableName: aws.String(r.tableName),
+			Item: map[string]types.AttributeValue{
+				"id":       &types.AttributeValueMemberS{Value: externalIDKey(ext.source, ext.id)},
+				"venue_id": &types.AttributeValueMemberS{Value: toID},
+			},
+			ConditionExpression: aws.String("venue_id = :venue_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":venue_id": &types.AttributeValueMemberS{Value: fromID},
+			},
+		},
+	}
+}
+
+// deleteLookup releases an external ID owned by a venue
+func (r *DynamoDBVenueRepository) deleteLookup(ext externalID, venueID string) types.TransactWriteItem {
+	return types.TransactWriteItem{
+		Delete: &types.Delete{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: externalIDKey(ext.source, ext.id)},
+			},
+			ConditionExpression: aws.String("venue_id = :venue_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":venue_id": &types.AttributeValueMemberS{Value: venueID},
+			},
+		},
+	}
+}
+
+// ownedLookups filters external IDs down to those whose lookup item points
+// at venueID. A merged venue may still carry IDs the survivor has claimed,
+// and releasing those would break the survivor's lookups.
+func (r *DynamoDBVenueRepository) ownedLookups(ctx context.Context, ids []externalID, venueID string) ([]externalID, error) {
+	owned := make([]externalID, 0, len(ids))
+	for _, ext := range ids {
+		owner, err := r.lookupOwner(ctx, ext.source, ext.id)
+		if err != nil {
+			return nil, err
+		}
+		if owner == venueID {
+			owned = append(owned, ext)
+		}
+	}
+	return owned, nil
+}
+
+// lookupOwner returns the ID of the venue owning an external ID, or an
+// empty string if no venue does
+func (r *DynamoDBVenueRepository) lookupOwner(ctx context.Context, source domain.DataSource, externalID string) (string, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: externalIDKey(source, externalID)},
 		},
-		Limit: aws.Int32(int32(limit)),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query by city: %w", err)
+		return "", fmt.Errorf("failed to get external ID lookup: %w", err)
+	}
+	if result.Item == nil {
+		return "", nil
 	}
 
-	venues := make([]*domain.Venue, 0, len(result.Items))
-	for _, item := range result.Items {
-		var venueItem venueItem
-		err = attributevalue.UnmarshalMap(item, &venueItem)
-		if err != nil {
+	var item externalIDItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return "", fmt.Errorf("failed to unmarshal external ID lookup: %w", err)
+	}
+	return item.VenueID, nil
+}
+
+// externalID is a single (source, ID) pair
+type externalID struct {
+	source domain.DataSource
+	id     string
+}
+
+// externalIDList flattens an external ID map in a stable order
+func externalIDList(ids map[domain.DataSource]string) []externalID {
+	list := make([]externalID, 0, len(ids))
+	for source, id := range ids {
+		list = append(list, externalID{source: source, id: id})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].source < list[j].source })
+	return list
+}
+
+// versionConflict reports whether a transaction was cancelled because the
+// venue put, always its first item, failed its version check
+func versionConflict(err error) bool {
+	return conditionFailed(err, 0)
+}
+
+// conditionFailed reports whether a transaction was cancelled because the
+// item at index failed its condition
+func conditionFailed(err error, index int) bool {
+	var cancelled *types.TransactionCanceledException
+	if !errors.As(err, &cancelled) || len(cancelled.CancellationReasons) <= index {
+		return false
+	}
+	return aws.ToString(cancelled.CancellationReasons[index].Code) == "ConditionalCheckFailed"
+}
+
+// lookupConflict reports which external ID put failed its ownership check
+// when a transaction is cancelled. Lookup puts start at index offset.
+func lookupConflict(err error, lookups []externalID, offset int) error {
+	var cancelled *types.TransactionCanceledException
+	if !errors.As(err, &cancelled) {
+		return nil
+	}
+	for i, reason := range cancelled.CancellationReasons {
+		idx := i - offset
+		if idx < 0 || idx >= len(lookups) {
 			continue
 		}
-		venues = append(venues, venueItem.Venue)
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return &ExternalIDConflictError{Source: lookups[idx].source, ExternalID: lookups[idx].id}
+		}
 	}
-
-	return venues, nil
+	return nil
 }
 
-// SearchByType searches venues by venue type
-func (r *DynamoDBVenueRepository) SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error) {
-	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("VenueTypeIndex"),
-		KeyConditionExpression: aws.String("venue_type = :venue_type"),
+// ListByModerationStatus queries the sparse moderation index, which only
+// holds venues that went through community submission
+func (r *DynamoDBVenueRepository) ListByModerationStatus(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error) {
+	return r.queryVenues(ctx, &dynamodb.QueryInput{
+		IndexName:              aws.String("ModerationIndex"),
+		KeyConditionExpression: aws.String("moderation_status = :status"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":venue_type": &types.AttributeValueMemberS{Value: string(venueType)},
+			":status": &types.AttributeValueMemberS{Value: string(status)},
 		},
-		Limit:            aws.Int32(int32(limit)),
-		ScanIndexForward: aws.Bool(false), // Sort by rating descending
+		ScanIndexForward: aws.Bool(true), // Oldest submissions first
+	}, limit)
+}
+
+// ScanAll calls fn for every venue in the table, stopping at the first
+// error fn returns. External ID lookup items are skipped.
+func (r *DynamoDBVenueRepository) ScanAll(ctx context.Context, fn func(*domain.Venue) error) error {
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: aws.String("attribute_exists(geohash)"),
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to query by type: %w", err)
-	}
 
-	venues := make([]*domain.Venue, 0, len(result.Items))
-	for _, item := range result.Items {
-		var venueItem venueItem
-		err = attributevalue.UnmarshalMap(item, &venueItem)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			continue
+			return fmt.Errorf("failed to scan venues: %w", err)
+		}
+
+		for _, item := range page.Items {
+			var venueItem venueItem
+			if err := attributevalue.UnmarshalMap(item, &venueItem); err != nil {
+				return fmt.Errorf("failed to unmarshal venue: %w", err)
+			}
+			if err := fn(venueItem.Venue); err != nil {
+				return err
+			}
 		}
-		venues = append(venues, venueItem.Venue)
 	}
 
-	return venues, nil
+	return nil
 }
 
 // VenueNotFoundError is returned when a venue is not found
@@ -264,32 +618,26 @@ func (e *VenueNotFoundError) Error() string {
 	return "venue not found"
 }
 
-// GetByExternalID retrieves a venue by external source ID
+// GetByExternalID retrieves a venue by any of its external source IDs
 func (r *DynamoDBVenueRepository) GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error) {
-	externalSourceID := fmt.Sprintf("%s#%s", source, externalID)
-
-	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("ExternalIdIndex"),
-		KeyConditionExpression: aws.String("external_source_id = :external_source_id"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":external_source_id": &types.AttributeValueMemberS{Value: externalSourceID},
-		},
-		Limit: aws.Int32(1),
-	})
+	venueID, err := r.lookupOwner(ctx, source, externalID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query by external ID: %w", err)
+		return nil, err
 	}
-
-	if len(result.Items) == 0 {
+	if venueID == "" {
 		return nil, &VenueNotFoundError{}
 	}
 
-	var venueItem venueItem
-	err = attributevalue.UnmarshalMap(result.Items[0], &venueItem)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal venue: %w", err)
-	}
+	return r.GetByID(ctx, venueID)
+}
+
+// ExternalIDConflictError is returned when an external ID is already
+// indexed to a different venue
+type ExternalIDConflictError struct {
+	Source     domain.DataSource
+	ExternalID string
+}
 
-	return venueItem.Venue, nil
+func (e *ExternalIDConflictError) Error() string {
+	return fmt.Sprintf("external ID %s#%s already belongs to another venue", e.Source, e.ExternalID)
 }
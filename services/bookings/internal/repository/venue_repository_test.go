@@ -252,3 +252,132 @@ func TestVenueRepository_GetByExternalID(t *testing.T) {
 }
 
 
+
+func TestVenueRepository_GetByExternalID_NonSourceID(t *testing.T) {
+	repo := NewMockVenueRepository()
+	ctx := context.Background()
+
+	venue := domain.NewVenue(
+		"Test Venue",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceGooglePlaces,
+	)
+	venue.GooglePlaceID = "place-1"
+	_ = repo.Create(ctx, venue)
+
+	// Enriched later with a Songkick ID
+	venue.SongkickID = "songkick-123"
+	if err := repo.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	retrieved, err := repo.GetByExternalID(ctx, domain.SourceSongkick, "songkick-123")
+	if err != nil {
+		t.Fatalf("GetByExternalID() error = %v", err)
+	}
+	if retrieved.ID != venue.ID {
+		t.Errorf("GetByExternalID() ID = %v, want %v", retrieved.ID, venue.ID)
+	}
+}
+
+func TestVenueRepository_ExternalIDConflict(t *testing.T) {
+	repo := NewMockVenueRepository()
+	ctx := context.Background()
+
+	first := domain.NewVenue(
+		"First",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceSongkick,
+	)
+	first.SongkickID = "songkick-123"
+	_ = repo.Create(ctx, first)
+
+	second := domain.NewVenue(
+		"Second",
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceSongkick,
+	)
+	second.SongkickID = "songkick-123"
+
+	err := repo.Create(ctx, second)
+	if _, ok := err.(*ExternalIDConflictError); !ok {
+		t.Errorf("Create() error = %v, want ExternalIDConflictError", err)
+	}
+}
+
+func TestVenueRepository_ScanAll(t *testing.T) {
+	repo := NewMockVenueRepository()
+	ctx := context.Background()
+
+	for _, name := range []string{"One", "Two", "Three"} {
+		_ = repo.Create(ctx, domain.NewVenue(
+			name,
+			domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk"},
+			domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+			[]domain.VenueType{domain.VenueTypeClub},
+			domain.SourceManual,
+		))
+	}
+
+	seen := 0
+	err := repo.ScanAll(ctx, func(v *domain.Venue) error {
+		seen++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanAll() error = %v", err)
+	}
+	if seen != 3 {
+		t.Errorf("ScanAll() visited %v venues, want 3", seen)
+	}
+}
+
+func TestVenueRepository_Update_VersionConflict(t *testing.T) {
+	repo := NewMockVenueRepository()
+	ctx := context.Background()
+
+	venue := domain.NewVenue("A", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	if err := repo.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	first, _ := repo.GetByID(ctx, venue.ID)
+	second, _ := repo.GetByID(ctx, venue.ID)
+
+	first.Capacity = 100
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Update() version = %d, want 2", first.Version)
+	}
+
+	second.Capacity = 200
+	err := repo.Update(ctx, second)
+	if _, ok := err.(*VersionConflictError); !ok {
+		t.Errorf("Update() with a stale version error = %v, want VersionConflictError", err)
+	}
+
+	stored, _ := repo.GetByID(ctx, venue.ID)
+	if stored.Capacity != 100 {
+		t.Errorf("stored capacity = %d, want the first writer's 100", stored.Capacity)
+	}
+}
+
+// newTestVenue builds a San Francisco club, the fixture shared by the
+// repository tests
+func newTestVenue(name string) *domain.Venue {
+	return domain.NewVenue(
+		name,
+		domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194, Geohash: "9q8yyk8"},
+		domain.Address{City: "San Francisco", State: "CA"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceManual,
+	)
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// maxBatchGetItems is the most keys one BatchGetItem call accepts
+const maxBatchGetItems = 100
+
+// typeIndexItem files a venue under one of its types in VenueTypeIndex.
+// Venues get one per type, so a venue that is both a bar and a restaurant
+// is found under either. Like external ID lookups they share the venues
+// table and carry no geohash, which keeps them out of scans.
+type typeIndexItem struct {
+	ID        string `dynamodbav:"id"`
+	VenueID   string `dynamodbav:"venue_id"`
+	VenueType string `dynamodbav:"venue_type"`
+	RatingID  string `dynamodbav:"rating_id"`
+}
+
+// typeIndexKey returns the index item key for one of a venue's types
+func typeIndexKey(venueType domain.VenueType, venueID string) string {
+	return fmt.Sprintf("TYPE#%s#%s", venueType, venueID)
+}
+
+// toTypeIndexItems returns the index items for each of a venue's types
+func toTypeIndexItems(v *domain.Venue) []typeIndexItem {
+	items := make([]typeIndexItem, 0, len(v.VenueTypes))
+	seen := make(map[domain.VenueType]bool, len(v.VenueTypes))
+	for _, t := range v.VenueTypes {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		items = append(items, typeIndexItem{
+			ID:        typeIndexKey(t, v.ID),
+			VenueID:   v.ID,
+			VenueType: string(t),
+			RatingID:  ratingID(v),
+		})
+	}
+	return items
+}
+
+// typeIndexWrites returns the writes that bring a venue's type index items
+// from the stored venue to the one being saved. Every current type is put
+// again so its rating sort key stays fresh. current is nil for a new venue
+// and venue is nil for a deleted one.
+func (r *DynamoDBVenueRepository) typeIndexWrites(current, venue *domain.Venue) ([]types.TransactWriteItem, error) {
+	writes := make([]types.TransactWriteItem, 0)
+	keep := make(map[domain.VenueType]bool)
+
+	if venue != nil {
+		for _, item := range toTypeIndexItems(venue) {
+			av, err := attributevalue.MarshalMap(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal venue type index item: %w", err)
+			}
+			writes = append(writes, types.TransactWriteItem{
+				Put: &types.Put{TableName: aws.String(r.tableName), Item: av},
+			})
+			keep[domain.VenueType(item.VenueType)] = true
+		}
+	}
+
+	if current != nil {
+		for _, item := range toTypeIndexItems(current) {
+			if keep[domain.VenueType(item.VenueType)] {
+				continue
+			}
+			writes = append(writes, types.TransactWriteItem{
+				Delete: &types.Delete{
+					TableName: aws.String(r.tableName),
+					Key: map[string]types.AttributeValue{
+						"id": &types.AttributeValueMemberS{Value: item.ID},
+					},
+				},
+			})
+		}
+	}
+
+	return writes, nil
+}
+
+// typeIndexRequests returns the batch puts for a new venue's type index items
+func typeIndexRequests(venue *domain.Venue) ([]types.WriteRequest, error) {
+	requests := make([]types.WriteRequest, 0, len(venue.VenueTypes))
+	for _, item := range toTypeIndexItems(venue) {
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal venue type index item: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+	}
+	return requests, nil
+}
+
+// SearchByType finds venues of a type through their type index items, best
+// rated first. It pages through the index until it has limit venue IDs.
+func (r *DynamoDBVenueRepository) SearchByType(ctx context.Context, venueType domain.VenueType, limit int) ([]*domain.Venue, error) {
+	ids := make([]string, 0)
+	if limit <= 0 {
+		return []*domain.Venue{}, nil
+	}
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String("VenueTypeIndex"),
+		KeyConditionExpression: aws.String("venue_type = :venue_type"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":venue_type": &types.AttributeValueMemberS{Value: string(venueType)},
+		},
+		ProjectionExpression: aws.String("id, venue_id"),
+		Limit:                aws.Int32(int32(limit)),
+		ScanIndexForward:     aws.Bool(false), // Sort by rating descending
+	})
+	seen := make(map[string]bool)
+	for paginator.HasMorePages() && len(ids) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query by type: %w", err)
+		}
+
+		for _, item := range page.Items {
+			var indexed typeIndexItem
+			if err := attributevalue.UnmarshalMap(item, &indexed); err != nil {
+				reportCorrupt("VenueTypeIndex", item, err)
+				continue
+			}
+			// Venues saved before type index items existed are indexed by
+			// their own item until they are next written or backfilled
+			id := indexed.VenueID
+			if id == "" {
+				id = indexed.ID
+			}
+			if !seen[id] && len(ids) < limit {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return r.getVenues(ctx, ids)
+}
+
+// getVenues reads venues by ID in as few requests as possible, in the
+// order given. IDs without a readable venue are skipped.
+func (r *DynamoDBVenueRepository) getVenues(ctx context.Context, ids []string) ([]*domain.Venue, error) {
+	byID := make(map[string]*domain.Venue, len(ids))
+
+	for start := 0; start < len(ids); start += maxBatchGetItems {
+		end := start + maxBatchGetItems
+		if end > len(ids) {
+			end = len(ids)
+		}
+		keys := make([]map[string]types.AttributeValue, 0, end-start)
+		for _, id := range ids[start:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: id},
+			})
+		}
+
+		pending := map[string]types.KeysAndAttributes{r.tableName: {Keys: keys}}
+		backoff := batchWriteBackoff
+		for attempt := 1; len(pending) > 0; attempt++ {
+			if attempt > maxBatchWriteAttempts {
+				return nil, fmt.Errorf("venues still unprocessed after %d attempts", maxBatchWriteAttempts)
+			}
+			if attempt > 1 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(backoff):
+				}
+				backoff *= 2
+			}
+
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: pending})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get venues: %w", err)
+			}
+			for _, item := range result.Responses[r.tableName] {
+				var venueItem venueItem
+				if err := attributevalue.UnmarshalMap(item, &venueItem); err != nil {
+					reportCorrupt(r.tableName, item, err)
+					continue
+				}
+				byID[venueItem.Venue.ID] = venueItem.Venue
+			}
+			pending = result.UnprocessedKeys
+		}
+	}
+
+	venues := make([]*domain.Venue, 0, len(ids))
+	for _, id := range ids {
+		if venue, ok := byID[id]; ok {
+			venues = append(venues, venue)
+		}
+	}
+	return venues, nil
+}
+
+// BackfillTypeIndex writes type index items for every stored venue and
+// drops the single type that venue items used to carry into the index. It
+// is safe to run repeatedly and returns how many venues it indexed.
+func (r *DynamoDBVenueRepository) BackfillTypeIndex(ctx context.Context) (int, error) {
+	indexed := 0
+	err := r.ScanAll(ctx, func(venue *domain.Venue) error {
+		requests, err := typeIndexRequests(venue)
+		if err != nil {
+			return err
+		}
+		if len(requests) > 0 {
+			if _, err := batchWrite(ctx, r.client, r.tableName, requests); err != nil {
+				return fmt.Errorf("venue %s: %w", venue.ID, err)
+			}
+		}
+
+		_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id": &types.AttributeValueMemberS{Value: venue.ID},
+			},
+			UpdateExpression:    aws.String("REMOVE venue_type"),
+			ConditionExpression: aws.String("attribute_exists(venue_type)"),
+		})
+		var conditionFailed *types.ConditionalCheckFailedException
+		if err != nil && !errors.As(err, &conditionFailed) {
+			return fmt.Errorf("venue %s: failed to remove legacy venue type: %w", venue.ID, err)
+		}
+
+		indexed++
+		return nil
+	})
+	return indexed, err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// typeIndexChanges sums up type index writes as the keys put and deleted
+func typeIndexChanges(t *testing.T, writes []types.TransactWriteItem) (puts, deletes []string) {
+	t.Helper()
+	for _, w := range writes {
+		switch {
+		case w.Put != nil:
+			puts = append(puts, w.Put.Item["id"].(*types.AttributeValueMemberS).Value)
+		case w.Delete != nil:
+			deletes = append(deletes, w.Delete.Key["id"].(*types.AttributeValueMemberS).Value)
+		}
+	}
+	sort.Strings(puts)
+	sort.Strings(deletes)
+	return puts, deletes
+}
+
+func TestTypeIndexWrites(t *testing.T) {
+	repo := NewDynamoDBVenueRepository(nil, "venues")
+	venue := domain.NewVenue("Zeitgeist", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeBar, domain.VenueTypeRestaurant, domain.VenueTypeBar}, domain.SourceManual)
+	key := func(venueType domain.VenueType) string { return typeIndexKey(venueType, venue.ID) }
+
+	writes, err := repo.typeIndexWrites(nil, venue)
+	if err != nil {
+		t.Fatalf("typeIndexWrites() error = %v", err)
+	}
+	puts, deletes := typeIndexChanges(t, writes)
+	if len(puts) != 2 || puts[0] != key(domain.VenueTypeBar) || puts[1] != key(domain.VenueTypeRestaurant) || len(deletes) != 0 {
+		t.Errorf("create puts %v and deletes %v, want one put per type", puts, deletes)
+	}
+	if rating := writes[0].Put.Item["rating_id"].(*types.AttributeValueMemberS).Value; rating != ratingID(venue) {
+		t.Errorf("rating_id = %q, want %q", rating, ratingID(venue))
+	}
+
+	changed := venue.Clone()
+	changed.VenueTypes = []domain.VenueType{domain.VenueTypeBar, domain.VenueTypeClub}
+	writes, _ = repo.typeIndexWrites(venue, changed)
+	puts, deletes = typeIndexChanges(t, writes)
+	if len(puts) != 2 || puts[0] != key(domain.VenueTypeBar) || puts[1] != key(domain.VenueTypeClub) {
+		t.Errorf("update puts %v, want bar and club", puts)
+	}
+	if len(deletes) != 1 || deletes[0] != key(domain.VenueTypeRestaurant) {
+		t.Errorf("update deletes %v, want restaurant", deletes)
+	}
+
+	writes, _ = repo.typeIndexWrites(changed, nil)
+	puts, deletes = typeIndexChanges(t, writes)
+	if len(puts) != 0 || len(deletes) != 2 {
+		t.Errorf("delete puts %v and deletes %v, want both types deleted", puts, deletes)
+	}
+}
+
+func TestVenueRepository_SearchByType_AnyType(t *testing.T) {
+	repo := NewMockVenueRepository()
+	ctx := context.Background()
+
+	venue := domain.NewVenue("Zeitgeist", domain.GeoPoint{}, domain.Address{}, []domain.VenueType{domain.VenueTypeBar, domain.VenueTypeRestaurant}, domain.SourceManual)
+	_ = repo.Create(ctx, venue)
+
+	for _, venueType := range venue.VenueTypes {
+		results, err := repo.SearchByType(ctx, venueType, 10)
+		if err != nil {
+			t.Fatalf("SearchByType(%s) error = %v", venueType, err)
+		}
+		if len(results) != 1 {
+			t.Errorf("SearchByType(%s) returned %d venues, want 1", venueType, len(results))
+		}
+	}
+}
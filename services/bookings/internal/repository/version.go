@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// VersionConflictError is returned when an update carries a version other
+// than the stored one, meaning someone else saved the item in between
+type VersionConflictError struct {
+	ID      string
+	Version int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s was modified since version %d", e.ID, e.Version)
+}
+
+// versionCondition is the condition that the stored item is still at
+// expected. Items saved before versioning have no version attribute and
+// count as version 0.
+func versionCondition(expected int) (*string, map[string]string, map[string]types.AttributeValue) {
+	names := map[string]string{"#version": "version"}
+	if expected == 0 {
+		return aws.String("attribute_not_exists(#version)"), names, nil
+	}
+	return aws.String("#version = :expected"), names, map[string]types.AttributeValue{
+		":expected": &types.AttributeValueMemberN{Value: strconv.Itoa(expected)},
+	}
+}
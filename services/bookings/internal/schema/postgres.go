@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crowdunlocked/services/bookings/internal/migrate"
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresTables names the tables of the PostgreSQL venue store. Venue
+// external IDs are kept in Venues + "_external_ids".
+type PostgresTables struct {
+	Venues       string
+	VenueReviews string
+}
+
+// DefaultPostgresTables returns the names the server uses when no
+// POSTGRES_*_TABLE variable overrides them
+func DefaultPostgresTables() PostgresTables {
+	return PostgresTables{
+		Venues:       "venues",
+		VenueReviews: "venue_reviews",
+	}
+}
+
+// PostgresDB is a connection or pool that migrations run transactions on
+type PostgresDB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// PostgresMigrations returns the PostgreSQL venue store's migrations, each
+// run against db in one transaction. They are numbered apart from the
+// DynamoDB migrations, so record them under their own service name.
+func PostgresMigrations(db PostgresDB, t PostgresTables) []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "create the venue store",
+			Apply:       postgresApply(db, createVenueStore(t)),
+		},
+	}
+}
+
+// postgresApply runs statements in one transaction
+func postgresApply(db PostgresDB, statements []string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+			for _, stmt := range statements {
+				if _, err := tx.Exec(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// createVenueStore creates the venue store and its indexes. The statements
+// are idempotent because databases created before migrations were recorded
+// already have the tables.
+func createVenueStore(t PostgresTables) []string {
+	venues := pgx.Identifier{t.Venues}.Sanitize()
+	externalIDs := pgx.Identifier{t.Venues + "_external_ids"}.Sanitize()
+	reviews := pgx.Identifier{t.VenueReviews}.Sanitize()
+	index := func(table, suffix string) string {
+		return pgx.Identifier{table + "_" + suffix}.Sanitize()
+	}
+
+	return []string{
+		"CREATE EXTENSION IF NOT EXISTS postgis",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id text PRIMARY KEY,
+			name text NOT NULL,
+			city_state text NOT NULL,
+			geohash text NOT NULL,
+			location geography(Point, 4326) NOT NULL,
+			venue_types text[] NOT NULL,
+			rating double precision NOT NULL,
+			moderation_status text,
+			created_at timestamptz NOT NULL,
+			version integer NOT NULL,
+			document jsonb NOT NULL
+		)`, venues),
+		// Nearby search
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIST (location)", index(t.Venues, "location"), venues),
+		// Geohash prefix search, as range scans in byte order
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (geohash COLLATE "C", id COLLATE "C")`, index(t.Venues, "geohash"), venues),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (city_state, name COLLATE "C", id COLLATE "C")`, index(t.Venues, "city"), venues),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (venue_types)", index(t.Venues, "venue_types"), venues),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (moderation_status, created_at, id) WHERE moderation_status IS NOT NULL",
+			index(t.Venues, "moderation"), venues),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			source text NOT NULL,
+			external_id text NOT NULL,
+			venue_id text NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+			PRIMARY KEY (source, external_id)
+		)`, externalIDs, venues),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (venue_id)", index(t.Venues+"_external_ids", "venue"), externalIDs),
+		// Reviews outlive purged venues, as they do in DynamoDB
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			venue_id text NOT NULL,
+			artist_id text NOT NULL,
+			created_at timestamptz NOT NULL,
+			document jsonb NOT NULL,
+			PRIMARY KEY (venue_id, artist_id)
+		)`, reviews),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (venue_id, created_at DESC, artist_id DESC)", index(t.VenueReviews, "created"), reviews),
+	}
+}
@@ -0,0 +1,153 @@
+// Package schema declares the bookings service's DynamoDB tables as numbered
+// migrations, and the tables of the optional PostgreSQL venue store, for
+// cmd/migrate and the integration tests
+package schema
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/crowdunlocked/services/bookings/internal/migrate"
+)
+
+// Tables names the service's tables
+type Tables struct {
+	Bookings           string
+	Venues             string
+	SavedSearches      string
+	SavedSearchMatches string
+	VenueDuplicates    string
+	VenueRedirects     string
+	VenueHistory       string
+	VenueClaims        string
+	VenueReviews       string
+	VenueEvents        string
+}
+
+// DefaultTables returns the names the server uses when no DYNAMODB_*_TABLE
+// variable overrides them
+func DefaultTables() Tables {
+	return Tables{
+		Bookings:           "bookings",
+		Venues:             "venues",
+		SavedSearches:      "saved-searches",
+		SavedSearchMatches: "saved-search-matches",
+		VenueDuplicates:    "venue-duplicates",
+		VenueRedirects:     "venue-redirects",
+		VenueHistory:       "venue-history",
+		VenueClaims:        "venue-claims",
+		VenueReviews:       "venue-reviews",
+		VenueEvents:        "venue-events",
+	}
+}
+
+// Prefixed returns the names with a prefix, so that several test runs can
+// share one database
+func (t Tables) Prefixed(prefix string) Tables {
+	return Tables{
+		Bookings:           prefix + t.Bookings,
+		Venues:             prefix + t.Venues,
+		SavedSearches:      prefix + t.SavedSearches,
+		SavedSearchMatches: prefix + t.SavedSearchMatches,
+		VenueDuplicates:    prefix + t.VenueDuplicates,
+		VenueRedirects:     prefix + t.VenueRedirects,
+		VenueHistory:       prefix + t.VenueHistory,
+		VenueClaims:        prefix + t.VenueClaims,
+		VenueReviews:       prefix + t.VenueReviews,
+		VenueEvents:        prefix + t.VenueEvents,
+	}
+}
+
+// Migrations declares the service's schema. Add a migration with the next
+// version for each change rather than editing applied ones, and mirror it
+// in infra/terraform.
+func Migrations(t Tables) []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version:     1,
+			Description: "create bookings and venue tables",
+			Tables: []migrate.Table{
+				{
+					Name:    t.Bookings,
+					HashKey: migrate.S("id"),
+					GlobalIndexes: []migrate.Index{
+						{Name: "ArtistIndex", HashKey: migrate.S("artist_id"), RangeKey: migrate.S("event_date")},
+						{Name: "VenueIndex", HashKey: migrate.S("venue_id"), RangeKey: migrate.S("event_date")},
+					},
+					StreamViewType: types.StreamViewTypeNewAndOldImages,
+				},
+				{
+					// Venue items share the table with external ID lookup
+					// and venue type index items
+					Name:    t.Venues,
+					HashKey: migrate.S("id"),
+					GlobalIndexes: []migrate.Index{
+						{Name: "GeohashIndex", HashKey: migrate.S("geohash"), RangeKey: migrate.S("geohash_sort")},
+						{Name: "CityIndex", HashKey: migrate.S("city_state"), RangeKey: migrate.S("name")},
+						{Name: "VenueTypeIndex", HashKey: migrate.S("venue_type"), RangeKey: migrate.S("rating_id")},
+						{Name: "ModerationIndex", HashKey: migrate.S("moderation_status"), RangeKey: migrate.S("created_at")},
+					},
+				},
+				{
+					Name:    t.SavedSearches,
+					HashKey: migrate.S("id"),
+					GlobalIndexes: []migrate.Index{
+						{Name: "UserIndex", HashKey: migrate.S("user_id")},
+					},
+				},
+				{
+					Name:     t.SavedSearchMatches,
+					HashKey:  migrate.S("saved_search_id"),
+					RangeKey: migrate.S("venue_id"),
+				},
+				{
+					Name:    t.VenueDuplicates,
+					HashKey: migrate.S("id"),
+					GlobalIndexes: []migrate.Index{
+						{Name: "StatusIndex", HashKey: migrate.S("status"), RangeKey: migrate.S("created_at")},
+					},
+				},
+				{
+					Name:    t.VenueRedirects,
+					HashKey: migrate.S("id"),
+				},
+				{
+					Name:     t.VenueHistory,
+					HashKey:  migrate.S("venue_id"),
+					RangeKey: migrate.N("version"),
+				},
+				{
+					Name:    t.VenueClaims,
+					HashKey: migrate.S("id"),
+				},
+				{
+					Name:     t.VenueReviews,
+					HashKey:  migrate.S("venue_id"),
+					RangeKey: migrate.S("artist_id"),
+					LocalIndexes: []migrate.Index{
+						{Name: "CreatedIndex", RangeKey: migrate.S("created_at")},
+					},
+				},
+				{
+					Name:     t.VenueEvents,
+					HashKey:  migrate.S("venue_id"),
+					RangeKey: migrate.S("event_key"),
+					LocalIndexes: []migrate.Index{
+						{Name: "DateIndex", RangeKey: migrate.S("date")},
+					},
+				},
+			},
+		},
+		{
+			Version:     2,
+			Description: "index venue claims by user",
+			Tables: []migrate.Table{
+				{
+					Name:    t.VenueClaims,
+					HashKey: migrate.S("id"),
+					GlobalIndexes: []migrate.Index{
+						{Name: "UserIndex", HashKey: migrate.S("user_id"), RangeKey: migrate.S("created_at")},
+					},
+				},
+			},
+		},
+	}
+}
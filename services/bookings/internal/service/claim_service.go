@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+const (
+	// claimCodeTTL is how long a claim's verification code stays valid
+	claimCodeTTL = 30 * time.Minute
+	// claimRequestLimit is how many claims a user may open on one venue in
+	// claimRequestWindow, which bounds the codes they can guess at
+	claimRequestLimit  = 3
+	claimRequestWindow = 24 * time.Hour
+)
+
+var (
+	// ErrNoClaimContact is returned when the venue lists no contact details
+	// for the requested channel, so a claim cannot be verified
+	ErrNoClaimContact = errors.New("venue has no contact details to verify a claim with")
+	// ErrAlreadyOwner is returned when the claimant already owns the venue
+	ErrAlreadyOwner = errors.New("user already owns this venue")
+	// ErrClaimClosed is returned when a claim has expired, failed or was
+	// already confirmed
+	ErrClaimClosed = errors.New("venue claim is no longer open")
+	// ErrInvalidClaimCode is returned when a verification code does not match
+	ErrInvalidClaimCode = errors.New("invalid verification code")
+	// ErrTooManyClaims is returned when a user has opened claimRequestLimit
+	// claims on a venue within claimRequestWindow
+	ErrTooManyClaims = errors.New("too many claims on this venue, try again later")
+)
+
+// ClaimService lets venue representatives take ownership of a venue by
+// proving they can receive messages at its listed email address or phone
+type ClaimService struct {
+	claims repository.ClaimRepository
+	venues *VenueService
+	sender notify.Sender
+}
+
+// NewClaimService creates a new claim service
+func NewClaimService(claims repository.ClaimRepository, venues *VenueService, sender notify.Sender) *ClaimService {
+	return &ClaimService{
+		claims: claims,
+		venues: venues,
+		sender: sender,
+	}
+}
+
+// Request opens a claim on a venue for a user and sends a verification code
+// to the venue's contact details. An empty channel prefers email over SMS.
+func (s *ClaimService) Request(ctx context.Context, venueID, userID string, channel domain.ClaimChannel) (*domain.VenueClaim, error) {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	if venue.IsOwner(userID) {
+		return nil, ErrAlreadyOwner
+	}
+
+	if channel == "" {
+		channel = domain.ClaimByEmail
+		if venue.ContactInfo.Email == "" {
+			channel = domain.ClaimBySMS
+		}
+	}
+
+	var destination string
+	var msgChannel notify.Channel
+	switch channel {
+	case domain.ClaimByEmail:
+		destination, msgChannel = venue.ContactInfo.Email, notify.ChannelEmail
+	case domain.ClaimBySMS:
+		destination, msgChannel = venue.ContactInfo.Phone, notify.ChannelSMS
+	default:
+		return nil, fmt.Errorf("unsupported claim channel %q", channel)
+	}
+	if destination == "" {
+		return nil, ErrNoClaimContact
+	}
+
+	recent, err := s.claims.ListByUser(ctx, userID, time.Now().Add(-claimRequestWindow))
+	if err != nil {
+		return nil, err
+	}
+	opened := 0
+	for _, claim := range recent {
+		if claim.VenueID == venue.ID {
+			opened++
+		}
+	}
+	if opened >= claimRequestLimit {
+		return nil, ErrTooManyClaims
+	}
+
+	claim, code, err := domain.NewVenueClaim(venue.ID, userID, channel, destination, claimCodeTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.claims.Create(ctx, claim); err != nil {
+		return nil, err
+	}
+
+	err = s.sender.Send(ctx, notify.Message{
+		Channel: msgChannel,
+		To:      destination,
+		Subject: fmt.Sprintf("Verify your claim to %s", venue.Name),
+		Body: fmt.Sprintf("Someone asked to manage %s on Crowd Unlocked. If that was you, "+
+			"your verification code is %s. It expires in %d minutes.", venue.Name, code, int(claimCodeTTL.Minutes())),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	return claim, nil
+}
+
+// Confirm checks a claim's verification code. On a match the claimant
+// becomes an owner of the venue and the venue is marked verified.
+func (s *ClaimService) Confirm(ctx context.Context, claimID, userID, code string) (*domain.Venue, error) {
+	claim, err := s.claims.GetByID(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UserID != userID {
+		// Other users' claims are not revealed
+		return nil, &repository.ClaimNotFoundError{}
+	}
+	if claim.Status != domain.ClaimPending || claim.Expired(time.Now()) {
+		return nil, ErrClaimClosed
+	}
+
+	// Counting the attempt before checking the code bounds the guesses that
+	// concurrent requests can make
+	claim, err = s.claims.RecordAttempt(ctx, claim.ID)
+	var closed *repository.ClaimClosedError
+	if errors.As(err, &closed) {
+		return nil, ErrClaimClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !claim.MatchesCode(code) {
+		if !claim.AttemptsLeft() {
+			claim.Fail()
+			if err := s.claims.Update(ctx, claim); err != nil && !errors.As(err, &closed) {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidClaimCode
+	}
+
+	venue, err := s.venues.GetByID(ctx, claim.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	venue.AddOwner(userID)
+	venue.Verify()
+	if err := s.venues.Update(ctx, venue); err != nil {
+		return nil, err
+	}
+
+	// A wrong code counted after ours may have failed the claim meanwhile,
+	// but ours matched within the attempts allowed
+	claim.Confirm()
+	if err := s.claims.Update(ctx, claim); err != nil && !errors.As(err, &closed) {
+		return nil, err
+	}
+
+	return venue, nil
+}
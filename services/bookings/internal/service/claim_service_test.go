@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+var claimCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+func newTestClaimService() (*ClaimService, *VenueService, *notify.MemorySink) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	sink := notify.NewMemorySink()
+	return NewClaimService(repository.NewMockClaimRepository(), venues, sink), venues, sink
+}
+
+func sentClaimCode(t *testing.T, sink *notify.MemorySink) string {
+	t.Helper()
+	messages := sink.Messages()
+	if len(messages) == 0 {
+		t.Fatal("no verification code was sent")
+	}
+	code := claimCodePattern.FindString(messages[len(messages)-1].Body)
+	if code == "" {
+		t.Fatalf("message has no code: %q", messages[len(messages)-1].Body)
+	}
+	return code
+}
+
+func TestClaimService_RequestAndConfirm(t *testing.T) {
+	svc, venues, sink := newTestClaimService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	venue.ContactInfo.Email = "booking@bottomofthehill.com"
+	_ = venues.Create(ctx, venue)
+
+	claim, err := svc.Request(ctx, venue.ID, "user-1", "")
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if claim.Channel != domain.ClaimByEmail || claim.SentTo != "b***@bottomofthehill.com" {
+		t.Errorf("Request() channel = %v sent to %v", claim.Channel, claim.SentTo)
+	}
+	msg := sink.Messages()[0]
+	if msg.Channel != notify.ChannelEmail || msg.To != "booking@bottomofthehill.com" {
+		t.Errorf("code sent over %v to %v", msg.Channel, msg.To)
+	}
+
+	if _, err := svc.Confirm(ctx, claim.ID, "user-2", sentClaimCode(t, sink)); err == nil {
+		t.Error("Confirm() should not accept another user's claim")
+	}
+
+	confirmed, err := svc.Confirm(ctx, claim.ID, "user-1", sentClaimCode(t, sink))
+	if err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+	if !confirmed.Verified || !confirmed.IsOwner("user-1") {
+		t.Errorf("Confirm() verified = %v, owner = %v", confirmed.Verified, confirmed.IsOwner("user-1"))
+	}
+
+	if _, err := svc.Confirm(ctx, claim.ID, "user-1", sentClaimCode(t, sink)); !errors.Is(err, ErrClaimClosed) {
+		t.Errorf("Confirm() twice error = %v, want ErrClaimClosed", err)
+	}
+	if _, err := svc.Request(ctx, venue.ID, "user-1", ""); !errors.Is(err, ErrAlreadyOwner) {
+		t.Errorf("Request() by owner error = %v, want ErrAlreadyOwner", err)
+	}
+}
+
+func TestClaimService_Request_FallsBackToSMS(t *testing.T) {
+	svc, venues, sink := newTestClaimService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Hotel Utah", 37.7749, -122.4194)
+	venue.ContactInfo.Phone = "+14155550123"
+	_ = venues.Create(ctx, venue)
+
+	claim, err := svc.Request(ctx, venue.ID, "user-1", "")
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if claim.Channel != domain.ClaimBySMS || claim.SentTo != "***0123" {
+		t.Errorf("Request() channel = %v sent to %v", claim.Channel, claim.SentTo)
+	}
+	if sink.Messages()[0].Channel != notify.ChannelSMS {
+		t.Errorf("code sent over %v, want sms", sink.Messages()[0].Channel)
+	}
+
+	if _, err := svc.Request(ctx, venue.ID, "user-1", domain.ClaimByEmail); !errors.Is(err, ErrNoClaimContact) {
+		t.Errorf("Request() by email error = %v, want ErrNoClaimContact", err)
+	}
+}
+
+func TestClaimService_Confirm_LocksAfterWrongCodes(t *testing.T) {
+	svc, venues, sink := newTestClaimService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Rickshaw Stop", 37.7749, -122.4194)
+	venue.ContactInfo.Email = "info@rickshawstop.com"
+	_ = venues.Create(ctx, venue)
+
+	claim, _ := svc.Request(ctx, venue.ID, "user-1", domain.ClaimByEmail)
+	code := sentClaimCode(t, sink)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	for i := 0; i < domain.MaxClaimAttempts; i++ {
+		if _, err := svc.Confirm(ctx, claim.ID, "user-1", wrong); !errors.Is(err, ErrInvalidClaimCode) {
+			t.Fatalf("Confirm() attempt %d error = %v, want ErrInvalidClaimCode", i+1, err)
+		}
+	}
+	if _, err := svc.Confirm(ctx, claim.ID, "user-1", code); !errors.Is(err, ErrClaimClosed) {
+		t.Errorf("Confirm() after too many attempts error = %v, want ErrClaimClosed", err)
+	}
+
+	stored, _ := venues.GetByID(ctx, venue.ID)
+	if stored.Verified || stored.IsOwner("user-1") {
+		t.Error("a failed claim should not grant ownership")
+	}
+}
+
+func TestClaimService_Confirm_CountsConcurrentAttempts(t *testing.T) {
+	svc, venues, sink := newTestClaimService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Rickshaw Stop", 37.7749, -122.4194)
+	venue.ContactInfo.Email = "info@rickshawstop.com"
+	_ = venues.Create(ctx, venue)
+
+	claim, _ := svc.Request(ctx, venue.ID, "user-1", domain.ClaimByEmail)
+	code := sentClaimCode(t, sink)
+
+	// Every guess but one is wrong; each may be checked only while
+	// attempts remain
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	checked := 0
+	for i := 0; i < 20; i++ {
+		guess := fmt.Sprintf("%06d", i)
+		if guess == code {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.Confirm(ctx, claim.ID, "user-1", guess); errors.Is(err, ErrInvalidClaimCode) {
+				mu.Lock()
+				checked++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if checked != domain.MaxClaimAttempts {
+		t.Errorf("%d concurrent guesses were checked, want %d", checked, domain.MaxClaimAttempts)
+	}
+	if _, err := svc.Confirm(ctx, claim.ID, "user-1", code); !errors.Is(err, ErrClaimClosed) {
+		t.Errorf("Confirm() after too many attempts error = %v, want ErrClaimClosed", err)
+	}
+}
+
+func TestClaimService_Request_LimitsClaimsPerVenue(t *testing.T) {
+	svc, venues, _ := newTestClaimService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Rickshaw Stop", 37.7749, -122.4194)
+	venue.ContactInfo.Email = "info@rickshawstop.com"
+	_ = venues.Create(ctx, venue)
+	other := newTestVenue("Cafe du Nord", 37.7749, -122.4194)
+	other.ContactInfo.Email = "info@cafedunord.com"
+	_ = venues.Create(ctx, other)
+
+	for i := 0; i < claimRequestLimit; i++ {
+		if _, err := svc.Request(ctx, venue.ID, "user-1", domain.ClaimByEmail); err != nil {
+			t.Fatalf("Request() %d error = %v", i+1, err)
+		}
+	}
+	if _, err := svc.Request(ctx, venue.ID, "user-1", domain.ClaimByEmail); !errors.Is(err, ErrTooManyClaims) {
+		t.Errorf("Request() over the limit error = %v, want ErrTooManyClaims", err)
+	}
+	if _, err := svc.Request(ctx, other.ID, "user-1", domain.ClaimByEmail); err != nil {
+		t.Errorf("Request() on another venue error = %v", err)
+	}
+	if _, err := svc.Request(ctx, venue.ID, "user-2", domain.ClaimByEmail); err != nil {
+		t.Errorf("Request() by another user error = %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+const (
+	// duplicateSearchRadiusKm is how far around a venue duplicates are looked for
+	duplicateSearchRadiusKm = 2.0
+	// duplicateSearchLimit bounds the nearby venues compared against
+	duplicateSearchLimit = 100
+	// reviewPageSize is how many reviews are read at a time when merging
+	reviewPageSize = 100
+)
+
+// DedupService finds duplicate venues across data sources, queues ambiguous
+// pairs for review and merges confirmed duplicates into a single survivor
+type DedupService struct {
+	venues     *VenueService
+	bookings   repository.BookingRepository
+	duplicates repository.DuplicateRepository
+	reviews    repository.ReviewRepository
+}
+
+// NewDedupService creates a new dedup service
+func NewDedupService(venues *VenueService, bookings repository.BookingRepository, duplicates repository.DuplicateRepository) *DedupService {
+	return &DedupService{
+		venues:     venues,
+		bookings:   bookings,
+		duplicates: duplicates,
+	}
+}
+
+// SetReviews sets the review repository whose reviews move to the survivor
+// when venues are merged
+func (s *DedupService) SetReviews(reviews repository.ReviewRepository) {
+	s.reviews = reviews
+}
+
+// ScanResult reports what a duplicate scan did with each candidate pair
+type ScanResult struct {
+	Queued []*domain.DuplicateCandidate `json:"queued"`
+	Merged []*domain.DuplicateCandidate `json:"merged"`
+}
+
+// FindDuplicates scores nearby and same-city venues against the venue and
+// returns the pairs at or above the review threshold, best first
+func (s *DedupService) FindDuplicates(ctx context.Context, venue *domain.Venue) ([]*domain.DuplicateCandidate, error) {
+	nearby := make(map[string]*domain.Venue)
+
+	if venue.Location.Latitude != 0 || venue.Location.Longitude != 0 {
+		result, err := s.venues.Search(ctx, &domain.VenueSearchCriteria{
+			Location: &domain.GeoPoint{Latitude: venue.Location.Latitude, Longitude: venue.Location.Longitude},
+			RadiusKm: duplicateSearchRadiusKm,
+			Limit:    duplicateSearchLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range result.Venues {
+			nearby[v.ID] = v.Venue
+		}
+	}
+
+	if venue.Address.City != "" && venue.Address.State != "" {
+		result, err := s.venues.Search(ctx, &domain.VenueSearchCriteria{
+			City:  venue.Address.City,
+			State: venue.Address.State,
+			Limit: duplicateSearchLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range result.Venues {
+			nearby[v.ID] = v.Venue
+		}
+	}
+
+	candidates := make([]*domain.DuplicateCandidate, 0)
+	for id, other := range nearby {
+		if id == venue.ID {
+			continue
+		}
+		score := domain.ScoreDuplicate(venue, other)
+		if score.Total >= domain.DuplicateReviewThreshold {
+			candidates = append(candidates, domain.NewDuplicateCandidate(venue, other, score))
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Score.Total > candidates[j].Score.Total
+	})
+
+	return candidates, nil
+}
+
+// Scan looks for duplicates of a venue, merging near-certain matches and
+// queueing ambiguous ones for review. Pairs already queued are left alone.
+func (s *DedupService) Scan(ctx context.Context, venueID, actor string) (*ScanResult, error) {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.FindDuplicates(ctx, venue)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{
+		Queued: make([]*domain.DuplicateCandidate, 0),
+		Merged: make([]*domain.DuplicateCandidate, 0),
+	}
+	for _, candidate := range candidates {
+		created, err := s.duplicates.SaveCandidate(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			continue
+		}
+
+		if candidate.Score.Total < domain.DuplicateAutoMergeThreshold {
+			result.Queued = append(result.Queued, candidate)
+			continue
+		}
+
+		survivor, err := s.MergeCandidate(ctx, candidate.ID, "", actor)
+		if err != nil {
+			return nil, err
+		}
+		candidate.Resolve(domain.DuplicateMerged, actor, survivor.ID)
+		result.Merged = append(result.Merged, candidate)
+
+		// The scanned venue may itself have been merged away
+		if survivor.ID != venue.ID {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// ListQueue lists candidate pairs awaiting review
+func (s *DedupService) ListQueue(ctx context.Context, status domain.DuplicateStatus, limit int) ([]*domain.DuplicateCandidate, error) {
+	return s.duplicates.ListCandidates(ctx, status, limit)
+}
+
+// MergeCandidate resolves a queued pair by merging it. When survivorID is empty
+// the survivor is chosen automatically.
+func (s *DedupService) MergeCandidate(ctx context.Context, candidateID, survivorID, actor string) (*domain.Venue, error) {
+	candidate, err := s.duplicates.GetCandidate(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	if candidate.Status != domain.DuplicatePending {
+		return nil, fmt.Errorf("duplicate candidate is already %s", candidate.Status)
+	}
+
+	a, err := s.venues.GetByID(ctx, candidate.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.venues.GetByID(ctx, candidate.OtherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if survivorID != "" && survivorID != a.ID && survivorID != b.ID &&
+		survivorID != candidate.VenueID && survivorID != candidate.OtherID {
+		return nil, fmt.Errorf("survivor must be one of the candidate venues")
+	}
+
+	var survivor, merged *domain.Venue
+	switch {
+	case a.ID == b.ID:
+		// The pair was merged before but the candidate was left pending;
+		// finish moving records in case that merge stopped partway through
+		mergedID := candidate.VenueID
+		if mergedID == a.ID {
+			mergedID = candidate.OtherID
+		}
+		if err := s.moveRecords(ctx, a.ID, mergedID); err != nil {
+			return nil, err
+		}
+		survivor = a
+	case survivorID == "":
+		survivor, merged = chooseSurvivor(a, b)
+	case survivorID == a.ID || survivorID == candidate.VenueID:
+		survivor, merged = a, b
+	default:
+		survivor, merged = b, a
+	}
+
+	if merged != nil {
+		if err := s.merge(ctx, survivor, merged); err != nil {
+			return nil, err
+		}
+	}
+
+	candidate.Resolve(domain.DuplicateMerged, actor, survivor.ID)
+	if err := s.duplicates.UpdateCandidate(ctx, candidate); err != nil {
+		return nil, err
+	}
+
+	return survivor, nil
+}
+
+// Dismiss resolves a queued pair as not being duplicates
+func (s *DedupService) Dismiss(ctx context.Context, candidateID, actor string) (*domain.DuplicateCandidate, error) {
+	candidate, err := s.duplicates.GetCandidate(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	if candidate.Status != domain.DuplicatePending {
+		return nil, fmt.Errorf("duplicate candidate is already %s", candidate.Status)
+	}
+
+	candidate.Resolve(domain.DuplicateDismissed, actor, "")
+	if err := s.duplicates.UpdateCandidate(ctx, candidate); err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+// Merge folds one venue into another directly, without a queued candidate
+func (s *DedupService) Merge(ctx context.Context, survivorID, mergedID string) (*domain.Venue, error) {
+	survivor, err := s.venues.GetByID(ctx, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := s.venues.GetByID(ctx, mergedID)
+	if err != nil {
+		return nil, err
+	}
+	if survivor.ID == merged.ID {
+		if survivorID == mergedID {
+			return nil, fmt.Errorf("cannot merge a venue into itself")
+		}
+		// The venues were merged before; finish moving records in case
+		// that merge stopped partway through
+		if err := s.moveRecords(ctx, survivor.ID, mergedID); err != nil {
+			return nil, err
+		}
+		return survivor, nil
+	}
+
+	if err := s.merge(ctx, survivor, merged); err != nil {
+		return nil, err
+	}
+
+	return survivor, nil
+}
+
+// merge combines the merged venue into the survivor, replaces the merged
+// venue with a redirect to the survivor in one write, and then moves its
+// bookings and reviews over. The moves can be repeated, so a merge that
+// stops partway through is finished by merging the pair again.
+func (s *DedupService) merge(ctx context.Context, survivor, merged *domain.Venue) error {
+	domain.MergeVenues(survivor, merged)
+	_, drops, replaced, err := s.planReviewMoves(ctx, survivor.ID, merged.ID)
+	if err != nil {
+		return err
+	}
+	// An artist who reviewed both venues keeps only their newer review
+	for _, review := range append(drops, replaced...) {
+		survivor.RemoveReview(review)
+	}
+
+	err = s.venues.merge(ctx, survivor, merged, &domain.VenueRedirect{
+		FromID:    merged.ID,
+		ToID:      survivor.ID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to merge venues: %w", err)
+	}
+
+	return s.moveRecords(ctx, survivor.ID, merged.ID)
+}
+
+// moveRecords moves the bookings and reviews still filed under a merged
+// venue to its survivor. The survivor's rating already accounts for them.
+func (s *DedupService) moveRecords(ctx context.Context, survivorID, mergedID string) error {
+	bookings, err := s.bookings.ListByVenue(ctx, mergedID)
+	if err != nil {
+		return err
+	}
+	for _, booking := range bookings {
+		booking.VenueID = survivorID
+		booking.UpdatedAt = time.Now()
+		if err := s.bookings.Update(ctx, booking); err != nil {
+			return fmt.Errorf("failed to move booking %s: %w", booking.ID, err)
+		}
+	}
+
+	moves, drops, _, err := s.planReviewMoves(ctx, survivorID, mergedID)
+	if err != nil {
+		return err
+	}
+	for _, review := range moves {
+		if err := s.reviews.Move(ctx, review, survivorID); err != nil {
+			return fmt.Errorf("failed to move review by %s: %w", review.ArtistID, err)
+		}
+	}
+	for _, review := range drops {
+		if err := s.reviews.Delete(ctx, review.VenueID, review.ArtistID); err != nil {
+			return fmt.Errorf("failed to delete review by %s: %w", review.ArtistID, err)
+		}
+	}
+
+	return nil
+}
+
+// planReviewMoves lists the merged venue's reviews to move to the survivor
+// and those to delete. An artist who reviewed both venues keeps their newer
+// review: either the merged venue's review is dropped, or it moves and
+// replaces the survivor's, which is listed in replaced.
+func (s *DedupService) planReviewMoves(ctx context.Context, survivorID, mergedID string) (moves, drops, replaced []*domain.VenueReview, err error) {
+	if s.reviews == nil {
+		return nil, nil, nil, nil
+	}
+
+	cursor := ""
+	for {
+		page, next, err := s.reviews.ListByVenue(ctx, mergedID, reviewPageSize, cursor)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to list merged venue reviews: %w", err)
+		}
+		for _, review := range page {
+			existing, err := s.reviews.Get(ctx, survivorID, review.ArtistID)
+			var notFound *repository.ReviewNotFoundError
+			switch {
+			case errors.As(err, &notFound):
+				moves = append(moves, review)
+			case err != nil:
+				return nil, nil, nil, err
+			case review.UpdatedAt.After(existing.UpdatedAt):
+				moves = append(moves, review)
+				replaced = append(replaced, existing)
+			default:
+				drops = append(drops, review)
+			}
+		}
+		if next == "" {
+			return moves, drops, replaced, nil
+		}
+		cursor = next
+	}
+}
+
+// chooseSurvivor prefers the verified venue, then the one with more external
+// IDs, then the older record
+func chooseSurvivor(a, b *domain.Venue) (survivor, merged *domain.Venue) {
+	if a.Verified != b.Verified {
+		if a.Verified {
+			return a, b
+		}
+		return b, a
+	}
+
+	if ea, eb := externalIDCount(a), externalIDCount(b); ea != eb {
+		if ea > eb {
+			return a, b
+		}
+		return b, a
+	}
+
+	if b.CreatedAt.Before(a.CreatedAt) {
+		return b, a
+	}
+	return a, b
+}
+
+func externalIDCount(v *domain.Venue) int {
+	n := 0
+	for _, id := range []string{v.SongkickID, v.BandsintownID, v.GooglePlaceID} {
+		if id != "" {
+			n++
+		}
+	}
+	return n
+}
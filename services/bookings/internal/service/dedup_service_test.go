@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+type dedupFixture struct {
+	svc        *DedupService
+	venues     *VenueService
+	venueRepo  *repository.MockVenueRepository
+	bookings   *repository.MockBookingRepository
+	duplicates *repository.MockDuplicateRepository
+	reviews    *repository.MockReviewRepository
+}
+
+func newDedupFixture() *dedupFixture {
+	venueRepo := repository.NewMockVenueRepository()
+	venues := NewVenueService(venueRepo)
+	bookings := repository.NewMockBookingRepository()
+	duplicates := repository.NewMockDuplicateRepository()
+	reviews := repository.NewMockReviewRepository(venueRepo)
+	venueRepo.SetRedirects(duplicates)
+	venues.SetRedirectResolver(duplicates)
+	svc := NewDedupService(venues, bookings, duplicates)
+	svc.SetReviews(reviews)
+	return &dedupFixture{
+		svc:        svc,
+		venues:     venues,
+		venueRepo:  venueRepo,
+		bookings:   bookings,
+		duplicates: duplicates,
+		reviews:    reviews,
+	}
+}
+
+func TestDedupService_Scan_AutoMergesNearCertainDuplicate(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	original := newTestVenue("The Fillmore", 37.7840, -122.4330)
+	original.Address.Street = "1805 Geary Boulevard"
+	original.Source = domain.SourceGooglePlaces
+	original.GooglePlaceID = "gp-1"
+	original.Photos = []string{"a.jpg"}
+	_ = f.venues.Create(ctx, original)
+
+	duplicate := newTestVenue("Fillmore", 37.7841, -122.4331)
+	duplicate.Address.Street = "1805 Geary Blvd"
+	duplicate.Source = domain.SourceSongkick
+	duplicate.SongkickID = "sk-1"
+	duplicate.Photos = []string{"b.jpg"}
+	_ = f.venues.Create(ctx, duplicate)
+
+	booking := domain.NewBooking("artist-1", duplicate.ID, time.Now(), 100)
+	_ = f.bookings.Create(ctx, booking)
+
+	result, err := f.svc.Scan(ctx, duplicate.ID, "system")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Merged) != 1 {
+		t.Fatalf("Scan() merged %v pairs, want 1", len(result.Merged))
+	}
+
+	survivor, err := f.venues.GetByID(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if survivor.SongkickID != "sk-1" || survivor.GooglePlaceID != "gp-1" {
+		t.Errorf("survivor external IDs = %v/%v, want sk-1/gp-1", survivor.SongkickID, survivor.GooglePlaceID)
+	}
+	if len(survivor.Photos) != 2 {
+		t.Errorf("survivor photos = %v, want 2", survivor.Photos)
+	}
+
+	// The merged venue's external ID now resolves to the survivor
+	bySongkick, err := f.venues.GetByExternalID(ctx, domain.SourceSongkick, "sk-1")
+	if err != nil {
+		t.Fatalf("GetByExternalID() error = %v", err)
+	}
+	if bySongkick.ID != original.ID {
+		t.Errorf("GetByExternalID() = %v, want survivor %v", bySongkick.ID, original.ID)
+	}
+
+	// The merged ID now redirects to the survivor
+	redirected, err := f.venues.GetByID(ctx, duplicate.ID)
+	if err != nil {
+		t.Fatalf("GetByID(merged) error = %v", err)
+	}
+	if redirected.ID != original.ID {
+		t.Errorf("GetByID(merged) = %v, want survivor %v", redirected.ID, original.ID)
+	}
+
+	moved, _ := f.bookings.GetByID(ctx, booking.ID)
+	if moved.VenueID != original.ID {
+		t.Errorf("booking venue = %v, want survivor %v", moved.VenueID, original.ID)
+	}
+}
+
+func TestDedupService_Scan_QueuesAmbiguousPair(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	a := newTestVenue("Bottom of the Hill", 37.7650, -122.3960)
+	a.Address.Street = "1233 17th Street"
+	a.Source = domain.SourceGooglePlaces
+	b := newTestVenue("Bottom Hill Bar", 37.7660, -122.3970)
+	_ = f.venues.Create(ctx, a)
+	_ = f.venues.Create(ctx, b)
+
+	result, err := f.svc.Scan(ctx, a.ID, "system")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(result.Queued) != 1 || len(result.Merged) != 0 {
+		t.Fatalf("Scan() queued %v merged %v, want 1 queued", len(result.Queued), len(result.Merged))
+	}
+
+	queue, _ := f.svc.ListQueue(ctx, domain.DuplicatePending, 10)
+	if len(queue) != 1 {
+		t.Fatalf("ListQueue() returned %v, want 1", len(queue))
+	}
+
+	// Scanning again must not re-queue the pair
+	result, _ = f.svc.Scan(ctx, a.ID, "system")
+	if len(result.Queued) != 0 {
+		t.Errorf("Scan() re-queued %v pairs, want 0", len(result.Queued))
+	}
+}
+
+func TestDedupService_MergeCandidate_ChosenSurvivor(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	a := newTestVenue("Bottom of the Hill", 37.7650, -122.3960)
+	a.Address.Street = "1233 17th Street"
+	a.Source = domain.SourceGooglePlaces
+	b := newTestVenue("Bottom Hill Bar", 37.7660, -122.3970)
+	_ = f.venues.Create(ctx, a)
+	_ = f.venues.Create(ctx, b)
+	candidate := domain.NewDuplicateCandidate(a, b, domain.ScoreDuplicate(a, b))
+	_, _ = f.duplicates.SaveCandidate(ctx, candidate)
+
+	survivor, err := f.svc.MergeCandidate(ctx, candidate.ID, b.ID, "moderator-1")
+	if err != nil {
+		t.Fatalf("MergeCandidate() error = %v", err)
+	}
+	if survivor.ID != b.ID {
+		t.Errorf("MergeCandidate() survivor = %v, want %v", survivor.ID, b.ID)
+	}
+	if survivor.Address.Street != "1233 17th Street" {
+		t.Errorf("survivor street = %v, want gap filled from merged venue", survivor.Address.Street)
+	}
+
+	resolved, _ := f.duplicates.GetCandidate(ctx, candidate.ID)
+	if resolved.Status != domain.DuplicateMerged || resolved.ResolvedBy != "moderator-1" {
+		t.Errorf("candidate = %v by %v, want merged by moderator-1", resolved.Status, resolved.ResolvedBy)
+	}
+
+	if _, err := f.svc.MergeCandidate(ctx, candidate.ID, "", "moderator-1"); err == nil {
+		t.Error("MergeCandidate() should refuse an already resolved candidate")
+	}
+}
+
+func TestDedupService_Dismiss(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	a := newTestVenue("A", 37.0, -122.0)
+	a.Source = domain.SourceManual
+	b := newTestVenue("B", 37.0, -122.0)
+	b.Source = domain.SourceManual
+	candidate := domain.NewDuplicateCandidate(a, b, domain.DuplicateScore{Total: 0.7})
+	_, _ = f.duplicates.SaveCandidate(ctx, candidate)
+
+	dismissed, err := f.svc.Dismiss(ctx, candidate.ID, "moderator-1")
+	if err != nil {
+		t.Fatalf("Dismiss() error = %v", err)
+	}
+	if dismissed.Status != domain.DuplicateDismissed {
+		t.Errorf("Dismiss() status = %v, want dismissed", dismissed.Status)
+	}
+
+	queue, _ := f.svc.ListQueue(ctx, domain.DuplicatePending, 10)
+	if len(queue) != 0 {
+		t.Errorf("ListQueue() returned %v pending, want 0", len(queue))
+	}
+}
+
+func TestDedupService_Merge_Self(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	a := newTestVenue("A", 37.0, -122.0)
+	a.Source = domain.SourceManual
+	_ = f.venues.Create(ctx, a)
+
+	if _, err := f.svc.Merge(ctx, a.ID, a.ID); err == nil {
+		t.Error("Merge() should refuse to merge a venue into itself")
+	}
+}
+
+func TestDedupService_Merge_MovesReviews(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	survivor := newTestVenue("The Fillmore", 37.7840, -122.4330)
+	survivor.Address.Street = "1805 Geary Boulevard"
+	survivor.Source = domain.SourceGooglePlaces
+	_ = f.venues.Create(ctx, survivor)
+	merged := newTestVenue("Fillmore", 37.7841, -122.4331)
+	merged.Address.Street = "1805 Geary Blvd"
+	merged.Source = domain.SourceSongkick
+	_ = f.venues.Create(ctx, merged)
+
+	start := time.Now()
+	review := func(venue *domain.Venue, artistID string, score int, at time.Time) {
+		t.Helper()
+		r := &domain.VenueReview{VenueID: venue.ID, ArtistID: artistID, CreatedAt: at, UpdatedAt: at,
+			Scores: domain.ReviewScores{Sound: score, Hospitality: score, PaymentReliability: score, Turnout: score}}
+		venue.ApplyReview(nil, r)
+		if err := f.reviews.Save(ctx, r, venue); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	review(survivor, "artist-1", 4, start)
+	review(merged, "artist-1", 2, start.Add(time.Hour)) // Newer, so it replaces the survivor's
+	review(merged, "artist-2", 3, start)
+
+	got, err := f.svc.Merge(ctx, survivor.ID, merged.ID)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got.ReviewCount != 2 || got.Rating != 2.5 {
+		t.Errorf("Merge() rating = %v from %d reviews, want 2.5 from 2", got.Rating, got.ReviewCount)
+	}
+
+	reviews, _, err := f.reviews.ListByVenue(ctx, survivor.ID, 10, "")
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	scores := make(map[string]int)
+	for _, r := range reviews {
+		scores[r.ArtistID] = r.Scores.Sound
+	}
+	if len(scores) != 2 || scores["artist-1"] != 2 || scores["artist-2"] != 3 {
+		t.Errorf("survivor reviews = %v, want artist-1: 2 and artist-2: 3", scores)
+	}
+	if left, _, _ := f.reviews.ListByVenue(ctx, merged.ID, 10, ""); len(left) != 0 {
+		t.Errorf("merged venue still has %d reviews", len(left))
+	}
+}
+
+// failingRedirects fails to record redirects
+type failingRedirects struct{}
+
+func (failingRedirects) CreateRedirect(ctx context.Context, redirect *domain.VenueRedirect) error {
+	return errors.New("redirects table unavailable")
+}
+
+func TestDedupService_Merge_FailedWriteLeavesVenues(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+
+	survivor := newTestVenue("The Fillmore", 37.7840, -122.4330)
+	survivor.GooglePlaceID = "gp-1"
+	_ = f.venues.Create(ctx, survivor)
+	merged := newTestVenue("Fillmore", 37.7841, -122.4331)
+	merged.SongkickID = "sk-1"
+	_ = f.venues.Create(ctx, merged)
+
+	f.venueRepo.SetRedirects(failingRedirects{})
+	if _, err := f.svc.Merge(ctx, survivor.ID, merged.ID); err == nil {
+		t.Fatal("Merge() should fail when the redirect cannot be recorded")
+	}
+
+	// The merged venue keeps its external IDs and the survivor is untouched
+	bySongkick, err := f.venues.GetByExternalID(ctx, domain.SourceSongkick, "sk-1")
+	if err != nil || bySongkick.ID != merged.ID {
+		t.Errorf("GetByExternalID() = %v, %v, want merged venue %v", bySongkick, err, merged.ID)
+	}
+	stored, _ := f.venues.GetByID(ctx, survivor.ID)
+	if stored.SongkickID != "" || stored.Version != 1 {
+		t.Errorf("survivor = %v at version %v, want it unchanged", stored.SongkickID, stored.Version)
+	}
+	if redirect, _ := f.duplicates.GetRedirect(ctx, merged.ID); redirect != nil {
+		t.Errorf("GetRedirect() = %v, want none", redirect)
+	}
+}
+
+// flakyBookingRepository fails a set number of booking updates
+type flakyBookingRepository struct {
+	*repository.MockBookingRepository
+	failures int
+}
+
+func (r *flakyBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("bookings table unavailable")
+	}
+	return r.MockBookingRepository.Update(ctx, booking)
+}
+
+func TestDedupService_Merge_ResumesAfterFailedMove(t *testing.T) {
+	f := newDedupFixture()
+	ctx := context.Background()
+	bookings := &flakyBookingRepository{MockBookingRepository: f.bookings, failures: 1}
+	f.svc.bookings = bookings
+
+	survivor := newTestVenue("The Fillmore", 37.7840, -122.4330)
+	_ = f.venues.Create(ctx, survivor)
+	merged := newTestVenue("Fillmore", 37.7841, -122.4331)
+	merged.SongkickID = "sk-1"
+	_ = f.venues.Create(ctx, merged)
+
+	booking := domain.NewBooking("artist-1", merged.ID, time.Now(), 100)
+	_ = f.bookings.Create(ctx, booking)
+	at := time.Now()
+	review := &domain.VenueReview{VenueID: merged.ID, ArtistID: "artist-2", CreatedAt: at, UpdatedAt: at,
+		Scores: domain.ReviewScores{Sound: 4, Hospitality: 4, PaymentReliability: 4, Turnout: 4}}
+	merged.ApplyReview(nil, review)
+	if err := f.reviews.Save(ctx, review, merged); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := f.svc.Merge(ctx, survivor.ID, merged.ID); err == nil {
+		t.Fatal("Merge() should report the failed booking move")
+	}
+
+	// The venues were merged as a whole even though the moves stopped
+	stored, err := f.venues.GetByID(ctx, merged.ID)
+	if err != nil || stored.ID != survivor.ID {
+		t.Fatalf("GetByID(merged) = %v, %v, want survivor %v", stored, err, survivor.ID)
+	}
+	if stored.SongkickID != "sk-1" || stored.ReviewCount != 1 {
+		t.Errorf("survivor = %v with %d reviews, want sk-1 with 1", stored.SongkickID, stored.ReviewCount)
+	}
+
+	// Merging again finishes the moves without counting the review twice
+	got, err := f.svc.Merge(ctx, survivor.ID, merged.ID)
+	if err != nil {
+		t.Fatalf("Merge() retry error = %v", err)
+	}
+	if got.ReviewCount != 1 {
+		t.Errorf("Merge() retry review count = %v, want 1", got.ReviewCount)
+	}
+	moved, _ := f.bookings.GetByID(ctx, booking.ID)
+	if moved.VenueID != survivor.ID {
+		t.Errorf("booking venue = %v, want survivor %v", moved.VenueID, survivor.ID)
+	}
+	if reviews, _, _ := f.reviews.ListByVenue(ctx, survivor.ID, 10, ""); len(reviews) != 1 {
+		t.Errorf("survivor has %d reviews, want 1", len(reviews))
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+)
+
+// maxReportedErrors caps the per-venue errors kept in a result
+const maxReportedErrors = 100
+
+// EnrichmentResult summarises an enrichment run
+type EnrichmentResult struct {
+	Source    domain.DataSource `json:"source"`
+	Scanned   int               `json:"scanned"`
+	Enriched  int               `json:"enriched"`
+	Unmatched int               `json:"unmatched"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Errors    []string          `json:"errors,omitempty"`
+}
+
+// enrichOutcome is what happened to a single venue
+type enrichOutcome int
+
+const (
+	outcomeEnriched enrichOutcome = iota
+	outcomeUnmatched
+	outcomeSkipped
+)
+
+// EnrichmentJob fills in venues we already hold from a secondary source
+// such as Songkick or Bandsintown
+type EnrichmentJob struct {
+	venues *VenueService
+	source source.VenueSearcher
+}
+
+// NewEnrichmentJob creates an enrichment job for one source
+func NewEnrichmentJob(venues *VenueService, src source.VenueSearcher) *EnrichmentJob {
+	return &EnrichmentJob{
+		venues: venues,
+		source: src,
+	}
+}
+
+// Run enriches every active venue. Failures on individual venues are
+// recorded and the run continues; it stops early only if ctx is done or the
+// venue scan itself fails.
+func (j *EnrichmentJob) Run(ctx context.Context) (*EnrichmentResult, error) {
+	result := &EnrichmentResult{Source: j.source.Source()}
+
+	err := j.venues.ScanAll(ctx, func(venue *domain.Venue) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !venue.Active {
+			return nil
+		}
+
+		result.Scanned++
+		outcome, err := j.Enrich(ctx, venue)
+		switch {
+		case err != nil:
+			result.Failed++
+			if len(result.Errors) < maxReportedErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", venue.ID, err))
+			}
+		case outcome == outcomeEnriched:
+			result.Enriched++
+		case outcome == outcomeUnmatched:
+			result.Unmatched++
+		default:
+			result.Skipped++
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// Enrich refreshes a venue already linked to the source, or looks for a
+// match by name and distance and links it. A venue whose match is already
+// linked to another venue is skipped; that pair is a likely duplicate.
+func (j *EnrichmentJob) Enrich(ctx context.Context, venue *domain.Venue) (enrichOutcome, error) {
+	var fetched *domain.Venue
+
+	if externalID := venue.ExternalIDs()[j.source.Source()]; externalID != "" {
+		details, err := j.source.FetchDetails(ctx, externalID)
+		if err != nil {
+			if _, ok := err.(*source.NotFoundError); ok {
+				return outcomeUnmatched, nil
+			}
+			return 0, err
+		}
+		fetched = details
+	} else {
+		candidates, err := j.source.SearchByName(ctx, venue.Name, venue.Location)
+		if err != nil {
+			return 0, err
+		}
+		match := domain.MatchForEnrichment(venue, candidates)
+		if match == nil {
+			return outcomeUnmatched, nil
+		}
+		fetched = match.Candidate
+	}
+
+	// Work on a copy so a rejected update leaves the caller's venue intact
+	enriched := *venue
+	domain.EnrichFromSource(&enriched, fetched)
+	if err := j.venues.Update(ctx, &enriched); err != nil {
+		if _, ok := err.(*repository.ExternalIDConflictError); ok {
+			return outcomeSkipped, nil
+		}
+		return 0, err
+	}
+
+	return outcomeEnriched, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+)
+
+// fakeSearcher serves Songkick venues from memory
+type fakeSearcher struct {
+	venues   []*domain.Venue
+	searches int
+}
+
+func (f *fakeSearcher) Source() domain.DataSource { return domain.SourceSongkick }
+
+func (f *fakeSearcher) SearchByName(ctx context.Context, name string, near domain.GeoPoint) ([]*domain.Venue, error) {
+	f.searches++
+	return f.venues, nil
+}
+
+func (f *fakeSearcher) FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error) {
+	for _, v := range f.venues {
+		if v.SongkickID == externalID {
+			return v, nil
+		}
+	}
+	return nil, &source.NotFoundError{Source: domain.SourceSongkick, ExternalID: externalID}
+}
+
+func TestEnrichmentJob_Run(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	ctx := context.Background()
+
+	independent := newTestVenue("The Independent", 37.7755, -122.4376)
+	verified := newTestVenue("The Chapel", 37.7603, -122.4212)
+	verified.Capacity = 400
+	verified.Verify()
+	unknown := newTestVenue("Tiny Backyard", 37.70, -122.40)
+	inactive := newTestVenue("Closed Club", 37.71, -122.41)
+	inactive.Deactivate()
+	for _, v := range []*domain.Venue{independent, verified, unknown, inactive} {
+		_ = repo.Create(ctx, v)
+	}
+
+	listedIndependent := newTestVenue("Independent", 37.7756, -122.4377)
+	listedIndependent.Source, listedIndependent.SongkickID, listedIndependent.Capacity = domain.SourceSongkick, "9001", 500
+	listedChapel := newTestVenue("Chapel", 37.7604, -122.4213)
+	listedChapel.Source, listedChapel.SongkickID, listedChapel.Capacity = domain.SourceSongkick, "9002", 450
+	searcher := &fakeSearcher{venues: []*domain.Venue{listedIndependent, listedChapel}}
+
+	result, err := NewEnrichmentJob(NewVenueService(repo), searcher).Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Scanned != 3 || result.Enriched != 2 || result.Unmatched != 1 {
+		t.Errorf("Run() = %+v, want 3 scanned, 2 enriched, 1 unmatched", result)
+	}
+
+	got, _ := repo.GetByExternalID(ctx, domain.SourceSongkick, "9001")
+	if got == nil || got.ID != independent.ID || got.Capacity != 500 {
+		t.Errorf("independent = %+v, want linked with capacity 500", got)
+	}
+	if got.Name != "The Independent" {
+		t.Errorf("enrichment renamed venue to %v", got.Name)
+	}
+
+	chapel, _ := repo.GetByID(ctx, verified.ID)
+	if chapel.SongkickID != "9002" || chapel.Capacity != 400 {
+		t.Errorf("verified venue = %v/%v, want linked with capacity kept at 400", chapel.SongkickID, chapel.Capacity)
+	}
+}
+
+func TestEnrichmentJob_Enrich_LinkedVenueSkipsSearch(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	ctx := context.Background()
+
+	venue := newTestVenue("Somewhere Else Entirely", 37.7755, -122.4376)
+	venue.SongkickID = "9001"
+	_ = repo.Create(ctx, venue)
+
+	listed := newTestVenue("The Independent", 37.7756, -122.4377)
+	listed.Source, listed.SongkickID, listed.Capacity = domain.SourceSongkick, "9001", 500
+	searcher := &fakeSearcher{venues: []*domain.Venue{listed}}
+	outcome, err := NewEnrichmentJob(NewVenueService(repo), searcher).Enrich(ctx, venue)
+	if err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	if outcome != outcomeEnriched || searcher.searches != 0 {
+		t.Errorf("Enrich() outcome = %v after %v searches, want enriched by ID", outcome, searcher.searches)
+	}
+}
+
+func TestEnrichmentJob_Enrich_SkipsIDOwnedByAnotherVenue(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	ctx := context.Background()
+
+	owner := newTestVenue("Independent SF", 37.7755, -122.4376)
+	owner.SongkickID = "9001"
+	_ = repo.Create(ctx, owner)
+	duplicate := newTestVenue("The Independent", 37.7755, -122.4376)
+	_ = repo.Create(ctx, duplicate)
+
+	listed := newTestVenue("The Independent", 37.7756, -122.4377)
+	listed.Source, listed.SongkickID, listed.Capacity = domain.SourceSongkick, "9001", 500
+	searcher := &fakeSearcher{venues: []*domain.Venue{listed}}
+	outcome, err := NewEnrichmentJob(NewVenueService(repo), searcher).Enrich(ctx, duplicate)
+	if err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+	if outcome != outcomeSkipped {
+		t.Errorf("Enrich() outcome = %v, want skipped", outcome)
+	}
+	if duplicate.SongkickID != "" {
+		t.Error("Enrich() should leave a skipped venue unchanged")
+	}
+}
+
+func TestEnrichmentJob_Run_RecordsFailures(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	_ = repo.Create(context.Background(), newTestVenue("The Independent", 37.7755, -122.4376))
+
+	result, err := NewEnrichmentJob(NewVenueService(repo), &failingSearcher{}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("Run() = %+v, want 1 failure", result)
+	}
+}
+
+type failingSearcher struct{ fakeSearcher }
+
+func (*failingSearcher) SearchByName(ctx context.Context, name string, near domain.GeoPoint) ([]*domain.Venue, error) {
+	return nil, fmt.Errorf("songkick unavailable")
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+)
+
+// ImportResult summarises an import run
+type ImportResult struct {
+	Source  domain.DataSource `json:"source"`
+	Found   int               `json:"found"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Errors  []string          `json:"errors,omitempty"`
+}
+
+// ImportRunner pulls venues from an external source and upserts them,
+// matching existing records by external ID
+type ImportRunner struct {
+	venues *VenueService
+	source source.VenueSource
+}
+
+// NewImportRunner creates an import runner for one data source
+func NewImportRunner(venues *VenueService, src source.VenueSource) *ImportRunner {
+	return &ImportRunner{
+		venues: venues,
+		source: src,
+	}
+}
+
+// ImportArea searches the source for venues in an area and imports each
+// one. A failed search aborts the run; failures on individual venues are
+// recorded in the result and the run continues.
+func (r *ImportRunner) ImportArea(ctx context.Context, area source.Area) (*ImportResult, error) {
+	listings, err := r.source.SearchArea(ctx, area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", r.source.Source(), err)
+	}
+
+	result := &ImportResult{Source: r.source.Source(), Found: len(listings)}
+	for _, listing := range listings {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		_, created, err := r.Import(ctx, listing.ExternalID)
+		switch {
+		case err != nil:
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", listing.ExternalID, err))
+		case created:
+			result.Created++
+		default:
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+// Import fetches one venue from the source and creates or refreshes the
+// matching record. It reports whether a new venue was created.
+func (r *ImportRunner) Import(ctx context.Context, externalID string) (*domain.Venue, bool, error) {
+	fetched, err := r.source.FetchDetails(ctx, externalID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := r.venues.GetByExternalID(ctx, r.source.Source(), externalID)
+	if err != nil {
+		if _, ok := err.(*repository.VenueNotFoundError); !ok {
+			return nil, false, err
+		}
+
+		fetched.RecordChanges(nil, r.source.Source(), "")
+		fetched.MarkSynced()
+		if err := r.venues.Create(ctx, fetched); err != nil {
+			return nil, false, err
+		}
+		return fetched, true, nil
+	}
+
+	domain.RefreshFromSource(existing, fetched)
+	if err := r.venues.Update(ctx, existing); err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+	"github.com/crowdunlocked/services/bookings/internal/source/sourcetest"
+)
+
+func newGooglePlacesStub(places ...sourcetest.Place) (*sourcetest.GooglePlacesServer, *source.GooglePlacesSource) {
+	server := sourcetest.NewGooglePlacesServer("key", places...)
+	return server, source.NewGooglePlacesSource(server.URL, "key", server.Client())
+}
+
+func TestImportRunner_ImportArea_CreatesThenUpdates(t *testing.T) {
+	place := sourcetest.Place{
+		ID:              "place-1",
+		DisplayName:     sourcetest.Text{Text: "The Chapel"},
+		Location:        sourcetest.LatLng{Latitude: 37.7603, Longitude: -122.4212},
+		Types:           []string{"bar"},
+		Rating:          4.5,
+		UserRatingCount: 100,
+	}
+	server, src := newGooglePlacesStub(place)
+	defer server.Close()
+
+	repo := repository.NewMockVenueRepository()
+	runner := NewImportRunner(NewVenueService(repo), src)
+	ctx := context.Background()
+	area := source.Area{Center: domain.GeoPoint{Latitude: 37.76, Longitude: -122.42}, RadiusKm: 2}
+
+	result, err := runner.ImportArea(ctx, area)
+	if err != nil {
+		t.Fatalf("ImportArea() error = %v", err)
+	}
+	if result.Found != 1 || result.Created != 1 || result.Updated != 0 {
+		t.Errorf("first run = %+v, want 1 created", result)
+	}
+
+	venue, err := repo.GetByExternalID(ctx, domain.SourceGooglePlaces, "place-1")
+	if err != nil {
+		t.Fatalf("GetByExternalID() error = %v", err)
+	}
+	if venue.LastSyncedAt == nil {
+		t.Error("imported venue should be marked synced")
+	}
+	if venue.Location.Geohash == "" {
+		t.Error("imported venue should get a geohash")
+	}
+
+	result, err = runner.ImportArea(ctx, area)
+	if err != nil {
+		t.Fatalf("ImportArea() error = %v", err)
+	}
+	if result.Created != 0 || result.Updated != 1 {
+		t.Errorf("second run = %+v, want 1 updated", result)
+	}
+}
+
+func TestImportRunner_Import_RefreshesEnrichedVenue(t *testing.T) {
+	place := sourcetest.Place{
+		ID:          "place-1",
+		DisplayName: sourcetest.Text{Text: "Google Name"},
+		Location:    sourcetest.LatLng{Latitude: 37.7603, Longitude: -122.4212},
+		WebsiteURI:  "https://chapel.example",
+	}
+	server, src := newGooglePlacesStub(place)
+	defer server.Close()
+
+	repo := repository.NewMockVenueRepository()
+	existing := domain.NewVenue(
+		"The Chapel",
+		domain.GeoPoint{Latitude: 37.7603, Longitude: -122.4212, Geohash: "9q8yy9"},
+		domain.Address{City: "San Francisco", State: "CA"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceSongkick,
+	)
+	existing.GooglePlaceID = "place-1"
+	_ = repo.Create(context.Background(), existing)
+
+	venue, created, err := NewImportRunner(NewVenueService(repo), src).Import(context.Background(), "place-1")
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if created {
+		t.Error("Import() should update the venue already linked to the place")
+	}
+	if venue.ID != existing.ID || venue.Name != "The Chapel" {
+		t.Errorf("Import() = %v (%v), want existing venue unchanged name", venue.ID, venue.Name)
+	}
+	if venue.ContactInfo.Website != "https://chapel.example" {
+		t.Errorf("Import() website = %v, want gap filled", venue.ContactInfo.Website)
+	}
+}
+
+func TestImportRunner_ImportArea_RecordsFailures(t *testing.T) {
+	server, src := newGooglePlacesStub()
+	defer server.Close()
+
+	runner := NewImportRunner(NewVenueService(repository.NewMockVenueRepository()), &listingOnlySource{src})
+	result, err := runner.ImportArea(context.Background(), source.Area{RadiusKm: 1})
+	if err != nil {
+		t.Fatalf("ImportArea() error = %v", err)
+	}
+	if result.Failed != 1 || len(result.Errors) != 1 {
+		t.Errorf("ImportArea() = %+v, want 1 failure", result)
+	}
+}
+
+// listingOnlySource returns a listing the underlying stub cannot resolve
+type listingOnlySource struct {
+	*source.GooglePlacesSource
+}
+
+func (s *listingOnlySource) SearchArea(ctx context.Context, area source.Area) ([]source.Listing, error) {
+	return []source.Listing{{ExternalID: "gone", Name: "Closed Venue"}}, nil
+}
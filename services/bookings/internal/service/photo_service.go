@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/imaging"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+)
+
+// MaxPhotoBytes is the largest photo upload accepted
+const MaxPhotoBytes = 10 << 20
+
+var (
+	ErrPhotoNotFound  = errors.New("photo not found")
+	ErrPhotoTooLarge  = fmt.Errorf("photo is larger than %d MB", MaxPhotoBytes>>20)
+	ErrTooManyPhotos  = fmt.Errorf("a venue can have at most %d photos", domain.MaxVenuePhotos)
+	ErrPhotoForbidden = errors.New("only the uploader, a venue owner or an admin can change this photo")
+)
+
+// InvalidPhotoError is returned when an upload is not a usable image
+type InvalidPhotoError struct {
+	Err error
+}
+
+func (e *InvalidPhotoError) Error() string {
+	return "invalid photo: " + e.Err.Error()
+}
+
+func (e *InvalidPhotoError) Unwrap() error {
+	return e.Err
+}
+
+// PhotoService stores venue photos and their thumbnails in an object store
+// and keeps each venue's gallery in step with it
+type PhotoService struct {
+	venues *VenueService
+	store  storage.ObjectStore
+}
+
+// NewPhotoService creates a new photo service
+func NewPhotoService(venues *VenueService, store storage.ObjectStore) *PhotoService {
+	return &PhotoService{
+		venues: venues,
+		store:  store,
+	}
+}
+
+// Upload validates a photo, strips its metadata, stores it with a thumbnail
+// and adds it to the end of the venue's gallery
+func (s *PhotoService) Upload(ctx context.Context, venueID, uploader string, data []byte) (*domain.VenuePhoto, error) {
+	if len(data) > MaxPhotoBytes {
+		return nil, ErrPhotoTooLarge
+	}
+
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(venue.Gallery) >= domain.MaxVenuePhotos {
+		return nil, ErrTooManyPhotos
+	}
+
+	img, thumb, err := imaging.Process(data)
+	if err != nil {
+		return nil, &InvalidPhotoError{Err: err}
+	}
+
+	id := domain.NewPhotoID()
+	photo := domain.VenuePhoto{
+		ID:           id,
+		Key:          fmt.Sprintf("venues/%s/photos/%s%s", venue.ID, id, img.Ext()),
+		ThumbnailKey: fmt.Sprintf("venues/%s/photos/%s_thumb%s", venue.ID, id, thumb.Ext()),
+		ContentType:  img.ContentType,
+		Width:        img.Width,
+		Height:       img.Height,
+		Size:         len(img.Data),
+		UploadedBy:   uploader,
+		UploadedAt:   time.Now(),
+	}
+	photo.URL = s.store.URL(photo.Key)
+	photo.ThumbnailURL = s.store.URL(photo.ThumbnailKey)
+
+	if err := s.store.Put(ctx, photo.Key, img.ContentType, img.Data); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, photo.ThumbnailKey, thumb.ContentType, thumb.Data); err != nil {
+		s.deleteObjects(ctx, photo)
+		return nil, err
+	}
+
+	_, err = s.save(ctx, venue, func(v *domain.Venue) error {
+		if len(v.Gallery) >= domain.MaxVenuePhotos {
+			return ErrTooManyPhotos
+		}
+		v.AddPhoto(photo)
+		return nil
+	})
+	if err != nil {
+		s.deleteObjects(ctx, photo)
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// Delete removes a photo from the venue's gallery and from storage. Venue
+// owners and admins can delete any photo, and anyone their own uploads.
+func (s *PhotoService) Delete(ctx context.Context, venueID, photoID, userID string, role domain.Role) error {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return err
+	}
+	photo, ok := venue.Photo(photoID)
+	if !ok {
+		return ErrPhotoNotFound
+	}
+	if photo.UploadedBy != userID && venue.RoleFor(userID, role) == domain.RoleContributor {
+		return ErrPhotoForbidden
+	}
+
+	var removed domain.VenuePhoto
+	_, err = s.save(ctx, venue, func(v *domain.Venue) error {
+		if removed, ok = v.RemovePhoto(photoID); !ok {
+			return ErrPhotoNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The venue no longer refers to the files, so a failure here only
+	// leaves orphaned objects behind
+	s.deleteObjects(ctx, removed)
+	return nil
+}
+
+// Reorder sets the order of the venue's gallery; the first photo becomes the
+// cover. Only venue owners and admins can reorder photos.
+func (s *PhotoService) Reorder(ctx context.Context, venueID string, photoIDs []string, userID string, role domain.Role) ([]domain.VenuePhoto, error) {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+	if venue.RoleFor(userID, role) == domain.RoleContributor {
+		return nil, ErrPhotoForbidden
+	}
+
+	venue, err = s.save(ctx, venue, func(v *domain.Venue) error {
+		return v.ReorderPhotos(photoIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return venue.Gallery, nil
+}
+
+// save applies change to the venue and saves it, re-reading the venue and
+// applying change again if another write got there first
+func (s *PhotoService) save(ctx context.Context, venue *domain.Venue, change func(*domain.Venue) error) (*domain.Venue, error) {
+	for attempt := 1; ; attempt++ {
+		if err := change(venue); err != nil {
+			return nil, err
+		}
+		err := s.venues.Update(ctx, venue)
+		if err == nil {
+			return venue, nil
+		}
+		var conflict *repository.VersionConflictError
+		if !errors.As(err, &conflict) || attempt >= maxSaveAttempts {
+			return nil, err
+		}
+		if venue, err = s.venues.GetByID(ctx, venue.ID); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// deleteObjects removes a photo's files, logging rather than returning
+// failures since nothing refers to them any more
+func (s *PhotoService) deleteObjects(ctx context.Context, photo domain.VenuePhoto) {
+	for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("deleting photo %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestPhotoService(t *testing.T) (*PhotoService, *VenueService, string) {
+	dir := t.TempDir()
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	return NewPhotoService(venues, storage.NewLocalStore(dir, "http://media.test")), venues, dir
+}
+
+func TestPhotoService_UploadAndDelete(t *testing.T) {
+	svc, venues, dir := newTestPhotoService(t)
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	_ = venues.Create(ctx, venue)
+
+	photo, err := svc.Upload(ctx, venue.ID, "user-1", testPNG(t, 1200, 800))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if photo.Width != 1200 || photo.ContentType != "image/png" {
+		t.Errorf("Upload() = %dpx %v, want 1200px image/png", photo.Width, photo.ContentType)
+	}
+	if photo.URL != "http://media.test/"+photo.Key {
+		t.Errorf("URL = %v", photo.URL)
+	}
+	for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+		if _, err := os.Stat(filepath.Join(dir, key)); err != nil {
+			t.Errorf("stored %s: %v", key, err)
+		}
+	}
+
+	stored, _ := venues.GetByID(ctx, venue.ID)
+	if len(stored.Gallery) != 1 || stored.Gallery[0].ID != photo.ID {
+		t.Fatalf("gallery = %+v, want the upload", stored.Gallery)
+	}
+
+	if err := svc.Delete(ctx, venue.ID, photo.ID, "user-2", domain.RoleContributor); !errors.Is(err, ErrPhotoForbidden) {
+		t.Fatalf("Delete() by another contributor error = %v, want ErrPhotoForbidden", err)
+	}
+	if err := svc.Delete(ctx, venue.ID, photo.ID, "user-1", domain.RoleContributor); err != nil {
+		t.Fatalf("Delete() by the uploader error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, photo.Key)); !os.IsNotExist(err) {
+		t.Errorf("photo file should be deleted, stat error = %v", err)
+	}
+	if err := svc.Delete(ctx, venue.ID, photo.ID, "user-1", domain.RoleContributor); !errors.Is(err, ErrPhotoNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrPhotoNotFound", err)
+	}
+}
+
+func TestPhotoService_UploadRejects(t *testing.T) {
+	svc, venues, dir := newTestPhotoService(t)
+	ctx := context.Background()
+
+	venue := newTestVenue("Hotel Utah", 37.7749, -122.4194)
+	_ = venues.Create(ctx, venue)
+
+	var invalid *InvalidPhotoError
+	if _, err := svc.Upload(ctx, venue.ID, "user-1", []byte("not an image")); !errors.As(err, &invalid) {
+		t.Errorf("Upload() error = %v, want InvalidPhotoError", err)
+	}
+	if _, err := svc.Upload(ctx, venue.ID, "user-1", make([]byte, MaxPhotoBytes+1)); !errors.Is(err, ErrPhotoTooLarge) {
+		t.Errorf("Upload() error = %v, want ErrPhotoTooLarge", err)
+	}
+
+	venue, _ = venues.GetByID(ctx, venue.ID)
+	for i := 0; i < domain.MaxVenuePhotos; i++ {
+		venue.AddPhoto(domain.VenuePhoto{ID: domain.NewPhotoID()})
+	}
+	_ = venues.Update(ctx, venue)
+	if _, err := svc.Upload(ctx, venue.ID, "user-1", testPNG(t, 10, 10)); !errors.Is(err, ErrTooManyPhotos) {
+		t.Errorf("Upload() error = %v, want ErrTooManyPhotos", err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("rejected uploads left %d files behind", len(entries))
+	}
+}
+
+func TestPhotoService_Reorder(t *testing.T) {
+	svc, venues, _ := newTestPhotoService(t)
+	ctx := context.Background()
+
+	venue := newTestVenue("The Chapel", 37.7749, -122.4194)
+	_ = venues.Create(ctx, venue)
+	first, _ := svc.Upload(ctx, venue.ID, "user-1", testPNG(t, 10, 10))
+	second, _ := svc.Upload(ctx, venue.ID, "user-1", testPNG(t, 10, 10))
+	order := []string{second.ID, first.ID}
+
+	if _, err := svc.Reorder(ctx, venue.ID, order, "user-1", domain.RoleContributor); !errors.Is(err, ErrPhotoForbidden) {
+		t.Fatalf("Reorder() by a contributor error = %v, want ErrPhotoForbidden", err)
+	}
+
+	gallery, err := svc.Reorder(ctx, venue.ID, order, "admin-1", domain.RoleAdmin)
+	if err != nil {
+		t.Fatalf("Reorder() error = %v", err)
+	}
+	if gallery[0].ID != second.ID {
+		t.Errorf("cover = %v, want %v", gallery[0].ID, second.ID)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+)
+
+// PurgeResult summarises a purge run
+type PurgeResult struct {
+	Due     int      `json:"due"`
+	Purged  int      `json:"purged"`
+	Blocked int      `json:"blocked"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// PurgeJob removes venues that were deleted more than the retention period
+// ago, along with their uploaded photos
+type PurgeJob struct {
+	venues    *VenueService
+	photos    storage.ObjectStore
+	retention time.Duration
+}
+
+// NewPurgeJob creates a purge job. photos may be nil, in which case uploaded
+// photo files are left in place.
+func NewPurgeJob(venues *VenueService, photos storage.ObjectStore, retention time.Duration) *PurgeJob {
+	return &PurgeJob{
+		venues:    venues,
+		photos:    photos,
+		retention: retention,
+	}
+}
+
+// Run purges every venue due as of now. Venues that bookings reference are
+// counted as blocked and kept; other failures are recorded and the run
+// continues.
+func (j *PurgeJob) Run(ctx context.Context, now time.Time) (*PurgeResult, error) {
+	// Collect first so the scan is not paging through a table being deleted from
+	var due []*domain.Venue
+	err := j.venues.ScanAll(ctx, func(venue *domain.Venue) error {
+		if venue.PurgeDue(now, j.retention) {
+			due = append(due, venue)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan venues: %w", err)
+	}
+
+	result := &PurgeResult{Due: len(due)}
+	for _, venue := range due {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		err := j.venues.Purge(ctx, venue.ID)
+		switch {
+		case errors.Is(err, ErrVenueHasBookings):
+			result.Blocked++
+		case err != nil:
+			result.Failed++
+			if len(result.Errors) < maxReportedErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", venue.ID, err))
+			}
+		default:
+			result.Purged++
+			j.deletePhotos(ctx, venue)
+		}
+	}
+
+	return result, nil
+}
+
+// deletePhotos removes a purged venue's uploaded photos from storage. The
+// venue is already gone, so failures are logged rather than returned.
+func (j *PurgeJob) deletePhotos(ctx context.Context, venue *domain.Venue) {
+	if j.photos == nil {
+		return
+	}
+	for _, photo := range venue.Gallery {
+		for _, key := range []string{photo.Key, photo.ThumbnailKey} {
+			if err := j.photos.Delete(ctx, key); err != nil {
+				log.Printf("deleting photo %s of purged venue %s: %v", key, venue.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/storage"
+)
+
+func TestPurgeJob_Run(t *testing.T) {
+	venueRepo := repository.NewMockVenueRepository()
+	venues := NewVenueService(venueRepo)
+	bookings := repository.NewMockBookingRepository()
+	venues.SetBookings(bookings)
+	dir := t.TempDir()
+	store := storage.NewLocalStore(dir, "http://media.test")
+	ctx := context.Background()
+
+	expired := newTestVenue("Expired", 37.7749, -122.4194)
+	expired.AddPhoto(domain.VenuePhoto{ID: "p1", Key: "venues/expired/p1.jpg", ThumbnailKey: "venues/expired/p1_thumb.jpg"})
+	booked := newTestVenue("Booked", 37.7749, -122.4194)
+	recent := newTestVenue("Recent", 37.7749, -122.4194)
+	live := newTestVenue("Live", 37.7749, -122.4194)
+	for _, v := range []*domain.Venue{expired, booked, recent, live} {
+		_ = venues.Create(ctx, v)
+	}
+	for _, key := range []string{"venues/expired/p1.jpg", "venues/expired/p1_thumb.jpg"} {
+		if err := store.Put(ctx, key, "image/jpeg", []byte("jpeg")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = bookings.Create(ctx, domain.NewBooking("artist-1", booked.ID, time.Now().Add(-time.Hour), 100))
+
+	if err := venues.Purge(ctx, live.ID); !errors.Is(err, ErrVenueNotDeleted) {
+		t.Errorf("Purge() of a live venue error = %v, want ErrVenueNotDeleted", err)
+	}
+	for _, v := range []*domain.Venue{expired, booked, recent} {
+		_ = venues.Delete(ctx, v.ID, "admin-1", domain.RoleAdmin)
+	}
+
+	// Only venues deleted a full retention period ago are due
+	now := time.Now().Add(domain.VenueRetention)
+	stored, _ := venueRepo.GetByID(ctx, recent.ID)
+	*stored.DeletedAt = now.Add(-time.Hour)
+	_ = venueRepo.Update(ctx, stored)
+
+	result, err := NewPurgeJob(venues, store, domain.VenueRetention).Run(ctx, now)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Due != 2 || result.Purged != 1 || result.Blocked != 1 || result.Failed != 0 {
+		t.Errorf("Run() = %+v, want 2 due, 1 purged and 1 blocked", result)
+	}
+
+	var notFound *repository.VenueNotFoundError
+	if _, err := venueRepo.GetByID(ctx, expired.ID); !errors.As(err, &notFound) {
+		t.Errorf("expired venue error = %v, want it purged", err)
+	}
+	for _, id := range []string{booked.ID, recent.ID, live.ID} {
+		if _, err := venueRepo.GetByID(ctx, id); err != nil {
+			t.Errorf("venue %s error = %v, want it kept", id, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "venues/expired/p1.jpg")); !os.IsNotExist(err) {
+		t.Errorf("photo of purged venue: %v, want it deleted", err)
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+const (
+	// maxSimilarArtists bounds how many peer artists contribute candidates
+	maxSimilarArtists = 20
+	// nearbyRadiusKm is how far from a played venue an unplayed venue counts as nearby
+	nearbyRadiusKm = 25.0
+	// nearbyCandidateLimit bounds the venues fetched around each played venue
+	nearbyCandidateLimit = 50
+)
+
+// Weights for each recommendation signal; they sum to 1
+const (
+	weightPeers     = 0.35
+	weightGenre     = 0.25
+	weightCapacity  = 0.2
+	weightFee       = 0.1
+	weightProximity = 0.1
+)
+
+// RecommendationService ranks unplayed venues for an artist from booking history
+type RecommendationService struct {
+	bookings repository.BookingRepository
+	venues   repository.VenueRepository
+}
+
+// NewRecommendationService creates a new recommendation service
+func NewRecommendationService(bookings repository.BookingRepository, venues repository.VenueRepository) *RecommendationService {
+	return &RecommendationService{
+		bookings: bookings,
+		venues:   venues,
+	}
+}
+
+// candidate accumulates evidence for one unplayed venue
+type candidate struct {
+	venue      *domain.Venue
+	peerWeight float64
+	peerCount  int
+	nearestKm  float64
+}
+
+// RecommendVenues returns up to limit unplayed venues ranked for the artist
+func (s *RecommendationService) RecommendVenues(ctx context.Context, artistID string, limit int) ([]*domain.VenueRecommendation, error) {
+	now := time.Now()
+	history, upcoming, err := s.playedBookings(ctx, artistID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return []*domain.VenueRecommendation{}, nil
+	}
+
+	profile, err := s.buildProfile(ctx, artistID, history)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[string]*candidate)
+
+	similar, err := s.similarArtists(ctx, profile, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addPeerCandidates(ctx, profile, similar, candidates, now); err != nil {
+		return nil, err
+	}
+	if err := s.addNearbyCandidates(ctx, profile, candidates); err != nil {
+		return nil, err
+	}
+
+	maxPeerWeight := 0.0
+	for _, c := range candidates {
+		maxPeerWeight = math.Max(maxPeerWeight, c.peerWeight)
+	}
+
+	recommendations := make([]*domain.VenueRecommendation, 0, len(candidates))
+	for _, c := range candidates {
+		if !c.venue.Active || upcoming[c.venue.ID] {
+			continue
+		}
+		recommendations = append(recommendations, s.score(profile, c, maxPeerWeight))
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Venue.Name < recommendations[j].Venue.Name
+	})
+
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations, nil
+}
+
+// playedBookings returns the artist's bookings for shows that went ahead,
+// and the venues of shows still to come, which are not worth recommending
+func (s *RecommendationService) playedBookings(ctx context.Context, artistID string, now time.Time) ([]*domain.Booking, map[string]bool, error) {
+	bookings, err := s.bookings.ListByArtist(ctx, artistID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list bookings for artist: %w", err)
+	}
+
+	played := make([]*domain.Booking, 0, len(bookings))
+	upcoming := make(map[string]bool)
+	for _, b := range bookings {
+		switch {
+		case b.Played(now):
+			played = append(played, b)
+		case b.Status != domain.StatusCancelled:
+			upcoming[b.VenueID] = true
+		}
+	}
+
+	return played, upcoming, nil
+}
+
+// buildProfile loads the venues behind an artist's bookings into a profile
+func (s *RecommendationService) buildProfile(ctx context.Context, artistID string, history []*domain.Booking) (*domain.ArtistProfile, error) {
+	venues := make([]*domain.Venue, 0)
+	seen := make(map[string]bool)
+	fees := make([]float64, 0, len(history))
+
+	for _, b := range history {
+		fees = append(fees, b.Fee)
+		if seen[b.VenueID] {
+			continue
+		}
+		seen[b.VenueID] = true
+
+		venue, err := s.venues.GetByID(ctx, b.VenueID)
+		if err != nil {
+			var notFound *repository.VenueNotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, err
+		}
+		venues = append(venues, venue)
+	}
+
+	return domain.NewArtistProfile(artistID, venues, fees), nil
+}
+
+// similarArtists weights other artists by the share of the artist's venues they have also played
+func (s *RecommendationService) similarArtists(ctx context.Context, profile *domain.ArtistProfile, now time.Time) (map[string]float64, error) {
+	shared := make(map[string]int)
+	for venueID := range profile.PlayedVenueIDs {
+		bookings, err := s.bookings.ListByVenue(ctx, venueID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bookings for venue: %w", err)
+		}
+
+		seen := make(map[string]bool)
+		for _, b := range bookings {
+			if b.ArtistID == profile.ArtistID || !b.Played(now) || seen[b.ArtistID] {
+				continue
+			}
+			seen[b.ArtistID] = true
+			shared[b.ArtistID]++
+		}
+	}
+
+	type peer struct {
+		id     string
+		shared int
+	}
+	peers := make([]peer, 0, len(shared))
+	for id, n := range shared {
+		peers = append(peers, peer{id: id, shared: n})
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].shared != peers[j].shared {
+			return peers[i].shared > peers[j].shared
+		}
+		return peers[i].id < peers[j].id
+	})
+	if len(peers) > maxSimilarArtists {
+		peers = peers[:maxSimilarArtists]
+	}
+
+	similarity := make(map[string]float64, len(peers))
+	for _, p := range peers {
+		similarity[p.id] = float64(p.shared) / float64(len(profile.PlayedVenueIDs))
+	}
+
+	return similarity, nil
+}
+
+// addPeerCandidates adds the unplayed venues that similar artists have played
+func (s *RecommendationService) addPeerCandidates(ctx context.Context, profile *domain.ArtistProfile, similar map[string]float64, candidates map[string]*candidate, now time.Time) error {
+	for artistID, similarity := range similar {
+		bookings, err := s.bookings.ListByArtist(ctx, artistID)
+		if err != nil {
+			return fmt.Errorf("failed to list bookings for artist: %w", err)
+		}
+
+		seen := make(map[string]bool)
+		for _, b := range bookings {
+			if !b.Played(now) || profile.PlayedVenueIDs[b.VenueID] || seen[b.VenueID] {
+				continue
+			}
+			seen[b.VenueID] = true
+
+			c, ok := candidates[b.VenueID]
+			if !ok {
+				venue, err := s.venues.GetByID(ctx, b.VenueID)
+				if err != nil {
+					var notFound *repository.VenueNotFoundError
+					if errors.As(err, &notFound) {
+						continue
+					}
+					return err
+				}
+				c = s.newCandidate(profile, venue)
+				candidates[b.VenueID] = c
+			}
+			c.peerWeight += similarity
+			c.peerCount++
+		}
+	}
+
+	return nil
+}
+
+// addNearbyCandidates adds unplayed venues close to venues the artist has played
+func (s *RecommendationService) addNearbyCandidates(ctx context.Context, profile *domain.ArtistProfile, candidates map[string]*candidate) error {
+	for _, played := range profile.PlayedVenues {
+		prefixes := domain.GetGeohashPrefixes(played.Location.Latitude, played.Location.Longitude, nearbyRadiusKm)
+		venues, err := s.venues.SearchByGeohash(ctx, prefixes, nearbyCandidateLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, venue := range venues {
+			if profile.PlayedVenueIDs[venue.ID] {
+				continue
+			}
+			if _, ok := candidates[venue.ID]; ok {
+				continue
+			}
+			c := s.newCandidate(profile, venue)
+			if c.nearestKm <= nearbyRadiusKm {
+				candidates[venue.ID] = c
+			}
+		}
+	}
+
+	return nil
+}
+
+// newCandidate wraps a venue with its distance to the nearest played venue
+func (s *RecommendationService) newCandidate(profile *domain.ArtistProfile, venue *domain.Venue) *candidate {
+	nearest := math.Inf(1)
+	for _, played := range profile.PlayedVenues {
+		d := domain.CalculateDistance(
+			played.Location.Latitude,
+			played.Location.Longitude,
+			venue.Location.Latitude,
+			venue.Location.Longitude,
+		)
+		nearest = math.Min(nearest, d)
+	}
+
+	return &candidate{venue: venue, nearestKm: nearest}
+}
+
+// score combines the recommendation signals for a candidate and explains the strongest ones
+func (s *RecommendationService) score(profile *domain.ArtistProfile, c *candidate, maxPeerWeight float64) *domain.VenueRecommendation {
+	reasons := make([]string, 0)
+	total := 0.0
+
+	if c.peerCount > 0 && maxPeerWeight > 0 {
+		total += weightPeers * (c.peerWeight / maxPeerWeight)
+		if c.peerCount == 1 {
+			reasons = append(reasons, "1 artist with a similar booking history has played here")
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%d artists with a similar booking history have played here", c.peerCount))
+		}
+	}
+
+	if genreScore, matched := genreOverlap(profile, c.venue); genreScore > 0 {
+		total += weightGenre * genreScore
+		reasons = append(reasons, fmt.Sprintf("Books genres you have played: %s", strings.Join(matched, ", ")))
+	}
+
+	if profile.AvgCapacity > 0 && c.venue.Capacity > 0 {
+		ratio := float64(c.venue.Capacity) / profile.AvgCapacity
+		capacityScore := math.Max(0, 1-math.Abs(math.Log2(ratio))/2)
+		total += weightCapacity * capacityScore
+		if capacityScore >= 0.5 {
+			reasons = append(reasons, fmt.Sprintf("Capacity %d is close to your typical room of %.0f", c.venue.Capacity, profile.AvgCapacity))
+		}
+	}
+
+	if profile.AvgFee > 0 && c.venue.PayRange != nil && c.venue.PayRange.Max > 0 {
+		feeScore := 0.0
+		switch {
+		case profile.AvgFee >= float64(c.venue.PayRange.Min) && profile.AvgFee <= float64(c.venue.PayRange.Max):
+			feeScore = 1
+		case profile.AvgFee < float64(c.venue.PayRange.Min):
+			feeScore = 1 // Pays more than the artist usually earns
+		default:
+			feeScore = float64(c.venue.PayRange.Max) / profile.AvgFee
+		}
+		total += weightFee * feeScore
+		if feeScore >= 0.75 {
+			reasons = append(reasons, fmt.Sprintf("Pays %d-%d %s, in line with your average fee of %.0f",
+				c.venue.PayRange.Min, c.venue.PayRange.Max, c.venue.PayRange.Currency, profile.AvgFee))
+		}
+	}
+
+	if c.nearestKm <= nearbyRadiusKm {
+		total += weightProximity * (1 - c.nearestKm/nearbyRadiusKm)
+		reasons = append(reasons, fmt.Sprintf("%.1f km from a venue you have played", c.nearestKm))
+	}
+
+	return &domain.VenueRecommendation{
+		Venue:   c.venue,
+		Score:   math.Round(total*1000) / 1000,
+		Reasons: reasons,
+	}
+}
+
+// genreOverlap scores how strongly a venue's genres match the artist's profile
+func genreOverlap(profile *domain.ArtistProfile, venue *domain.Venue) (float64, []string) {
+	if len(venue.Genres) == 0 || len(profile.GenreWeights) == 0 {
+		return 0, nil
+	}
+
+	best := 0.0
+	matched := make([]string, 0)
+	for _, g := range venue.Genres {
+		if w, ok := profile.GenreWeights[g]; ok {
+			best = math.Max(best, w)
+			matched = append(matched, g)
+		}
+	}
+
+	return best, matched
+}
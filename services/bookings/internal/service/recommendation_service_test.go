@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// playedShow books an artist for a confirmed show that has already happened
+func playedShow(t *testing.T, bookings *repository.MockBookingRepository, artistID, venueID string, fee float64) {
+	t.Helper()
+	booking := domain.NewBooking(artistID, venueID, time.Now().Add(-48*time.Hour), fee)
+	booking.Confirm()
+	if err := bookings.Create(context.Background(), booking); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func TestRecommendationService_RecommendVenues(t *testing.T) {
+	ctx := context.Background()
+	venues := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	svc := NewRecommendationService(bookings, venues)
+
+	played := newTestVenue("Played Club", 37.7749, -122.4194)
+	played.Capacity = 200
+	played.Genres = []string{"rock"}
+	peerVenue := newTestVenue("Peer Favourite", 37.8044, -122.2712)
+	peerVenue.Capacity = 250
+	peerVenue.Genres = []string{"rock"}
+	farAway := newTestVenue("Far Hall", 40.7128, -74.0060)
+	farAway.Capacity = 5000
+	farAway.Genres = []string{"classical"}
+	for _, v := range []*domain.Venue{played, peerVenue, farAway} {
+		_ = venues.Create(ctx, v)
+	}
+
+	playedShow(t, bookings, "artist-1", played.ID, 500)
+	playedShow(t, bookings, "artist-2", played.ID, 500)
+	playedShow(t, bookings, "artist-2", peerVenue.ID, 500)
+
+	recs, err := svc.RecommendVenues(ctx, "artist-1", 10)
+	if err != nil {
+		t.Fatalf("RecommendVenues() error = %v", err)
+	}
+	if len(recs) == 0 {
+		t.Fatal("RecommendVenues() returned no recommendations")
+	}
+	if recs[0].Venue.ID != peerVenue.ID {
+		t.Errorf("RecommendVenues() top venue = %v, want %v", recs[0].Venue.Name, peerVenue.Name)
+	}
+	if len(recs[0].Reasons) == 0 {
+		t.Error("RecommendVenues() should explain recommendations")
+	}
+	for _, rec := range recs {
+		if rec.Venue.ID == played.ID {
+			t.Error("RecommendVenues() should not recommend a venue the artist has played")
+		}
+	}
+}
+
+func TestRecommendationService_RecommendVenues_NearbyWithoutPeers(t *testing.T) {
+	ctx := context.Background()
+	venues := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	svc := NewRecommendationService(bookings, venues)
+
+	played := newTestVenue("Played Club", 37.7749, -122.4194)
+	played.Capacity = 200
+	played.Genres = []string{"indie"}
+	nearby := newTestVenue("Nearby Club", 37.7849, -122.4094)
+	nearby.Capacity = 180
+	nearby.Genres = []string{"indie"}
+	_ = venues.Create(ctx, played)
+	_ = venues.Create(ctx, nearby)
+	playedShow(t, bookings, "artist-1", played.ID, 300)
+
+	recs, err := svc.RecommendVenues(ctx, "artist-1", 10)
+	if err != nil {
+		t.Fatalf("RecommendVenues() error = %v", err)
+	}
+	if len(recs) != 1 || recs[0].Venue.ID != nearby.ID {
+		t.Fatalf("RecommendVenues() = %v, want only %v", recs, nearby.Name)
+	}
+}
+
+func TestRecommendationService_RecommendVenues_IgnoresCancelledAndInactive(t *testing.T) {
+	ctx := context.Background()
+	venues := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	svc := NewRecommendationService(bookings, venues)
+
+	played := newTestVenue("Played Club", 37.7749, -122.4194)
+	played.Capacity = 200
+	closed := newTestVenue("Closed Club", 37.7849, -122.4094)
+	closed.Capacity = 200
+	closed.Deactivate()
+	_ = venues.Create(ctx, played)
+	_ = venues.Create(ctx, closed)
+
+	cancelled := domain.NewBooking("artist-1", played.ID, time.Now().Add(-48*time.Hour), 300)
+	cancelled.Cancel()
+	_ = bookings.Create(ctx, cancelled)
+
+	recs, err := svc.RecommendVenues(ctx, "artist-1", 10)
+	if err != nil {
+		t.Fatalf("RecommendVenues() error = %v", err)
+	}
+	if len(recs) != 0 {
+		t.Errorf("RecommendVenues() returned %v recommendations, want 0", len(recs))
+	}
+
+	playedShow(t, bookings, "artist-1", played.ID, 300)
+	recs, _ = svc.RecommendVenues(ctx, "artist-1", 10)
+	if len(recs) != 0 {
+		t.Errorf("RecommendVenues() returned %v recommendations, want 0 (inactive venue)", len(recs))
+	}
+}
+
+func TestRecommendationService_RecommendVenues_IgnoresShowsNotYetPlayed(t *testing.T) {
+	ctx := context.Background()
+	venues := repository.NewMockVenueRepository()
+	bookings := repository.NewMockBookingRepository()
+	svc := NewRecommendationService(bookings, venues)
+
+	played := newTestVenue("Played Club", 37.7749, -122.4194)
+	played.Capacity = 200
+	booked := newTestVenue("Booked Club", 37.7849, -122.4094)
+	booked.Capacity = 200
+	// Too far away to be recommended as nearby, so only peers can suggest it
+	peerVenue := newTestVenue("Peer Pick", 34.0522, -118.2437)
+	peerVenue.Capacity = 200
+	for _, v := range []*domain.Venue{played, booked, peerVenue} {
+		_ = venues.Create(ctx, v)
+	}
+
+	// A future pending booking is not a played show
+	_ = bookings.Create(ctx, domain.NewBooking("artist-1", played.ID, time.Now().Add(48*time.Hour), 300))
+	recs, err := svc.RecommendVenues(ctx, "artist-1", 10)
+	if err != nil {
+		t.Fatalf("RecommendVenues() error = %v", err)
+	}
+	if len(recs) != 0 {
+		t.Errorf("RecommendVenues() from a future pending booking = %v recommendations, want 0", len(recs))
+	}
+
+	// Nor does a peer's upcoming show make a venue a peer pick, and the
+	// venue the artist is already booked at is not recommended
+	playedShow(t, bookings, "artist-1", played.ID, 300)
+	_ = bookings.Create(ctx, domain.NewBooking("artist-1", booked.ID, time.Now().Add(48*time.Hour), 300))
+	playedShow(t, bookings, "artist-2", played.ID, 300)
+	_ = bookings.Create(ctx, domain.NewBooking("artist-2", peerVenue.ID, time.Now().Add(48*time.Hour), 300))
+
+	recs, err = svc.RecommendVenues(ctx, "artist-1", 10)
+	if err != nil {
+		t.Fatalf("RecommendVenues() error = %v", err)
+	}
+	for _, rec := range recs {
+		if rec.Venue.ID == booked.ID {
+			t.Error("RecommendVenues() should not recommend a venue the artist is already booked at")
+		}
+		if rec.Venue.ID == peerVenue.ID {
+			t.Error("RecommendVenues() should not count a peer's upcoming show")
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// maxSaveAttempts bounds retries when another write to the same venue
+// lands between reading and saving it
+const maxSaveAttempts = 5
+
+// ErrNoPlayedBooking is returned when an artist reviews a venue they have
+// no played booking at
+var ErrNoPlayedBooking = errors.New("only artists who have played the venue can review it")
+
+// InvalidReviewError is returned when review scores are out of range
+type InvalidReviewError struct {
+	Err error
+}
+
+func (e *InvalidReviewError) Error() string {
+	return "invalid review: " + e.Err.Error()
+}
+
+func (e *InvalidReviewError) Unwrap() error {
+	return e.Err
+}
+
+// VenueReviews is one page of a venue's reviews with its aggregate rating
+type VenueReviews struct {
+	VenueID     string                 `json:"venue_id"`
+	Rating      float64                `json:"rating"`
+	ReviewCount int                    `json:"review_count"`
+	Breakdown   domain.RatingBreakdown `json:"breakdown"`
+	Reviews     []*domain.VenueReview  `json:"reviews"`
+	NextCursor  string                 `json:"next_cursor,omitempty"`
+}
+
+// ReviewService lets artists rate venues they have played and keeps each
+// venue's aggregate rating in step with its reviews
+type ReviewService struct {
+	reviews  repository.ReviewRepository
+	venues   *VenueService
+	bookings repository.BookingRepository
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(reviews repository.ReviewRepository, venues *VenueService, bookings repository.BookingRepository) *ReviewService {
+	return &ReviewService{
+		reviews:  reviews,
+		venues:   venues,
+		bookings: bookings,
+	}
+}
+
+// Submit saves an artist's review of a venue, replacing any earlier review
+// of theirs, and updates the venue's rating in the same write. The rating
+// change is recorded in the venue's history and passed to venue listeners.
+func (s *ReviewService) Submit(ctx context.Context, venueID, artistID string, scores domain.ReviewScores, comment string) (*domain.VenueReview, error) {
+	if err := scores.Validate(); err != nil {
+		return nil, &InvalidReviewError{Err: err}
+	}
+
+	for attempt := 0; ; attempt++ {
+		venue, err := s.venues.GetByID(ctx, venueID)
+		if err != nil {
+			return nil, err
+		}
+
+		booking, err := s.playedBooking(ctx, venue.ID, artistID)
+		if err != nil {
+			return nil, err
+		}
+
+		previous, err := s.reviews.Get(ctx, venue.ID, artistID)
+		if err != nil {
+			var notFound *repository.ReviewNotFoundError
+			if !errors.As(err, &notFound) {
+				return nil, err
+			}
+			previous = nil
+		}
+
+		review := domain.NewVenueReview(previous, venue.ID, artistID, booking.ID, scores, comment)
+		before := venue.Clone()
+		venue.ApplyReview(previous, review)
+
+		err = s.reviews.Save(ctx, review, venue)
+		if err == nil {
+			// The rating changed, so the venue's history and listeners such
+			// as saved searches hear about it like any other update
+			s.venues.recordSaved(ctx, before, venue)
+			return review, nil
+		}
+		var conflict *repository.VersionConflictError
+		if !errors.As(err, &conflict) || attempt+1 >= maxSaveAttempts {
+			return nil, err
+		}
+	}
+}
+
+// List returns a page of a venue's reviews, newest first, with its rating
+func (s *ReviewService) List(ctx context.Context, venueID string, limit int, cursor string) (*VenueReviews, error) {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, next, err := s.reviews.ListByVenue(ctx, venue.ID, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VenueReviews{
+		VenueID:     venue.ID,
+		Rating:      venue.Rating,
+		ReviewCount: venue.ReviewCount,
+		Breakdown:   venue.RatingBreakdown(),
+		Reviews:     reviews,
+		NextCursor:  next,
+	}, nil
+}
+
+// playedBooking finds the artist's most recent played booking at the venue
+func (s *ReviewService) playedBooking(ctx context.Context, venueID, artistID string) (*domain.Booking, error) {
+	bookings, err := s.bookings.ListByArtist(ctx, artistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings for artist: %w", err)
+	}
+
+	now := time.Now()
+	var latest *domain.Booking
+	for _, b := range bookings {
+		if b.VenueID == venueID && b.Played(now) && (latest == nil || b.EventDate.After(latest.EventDate)) {
+			latest = b
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoPlayedBooking
+	}
+	return latest, nil
+}
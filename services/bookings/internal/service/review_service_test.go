@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+func newTestReviewService() (*ReviewService, *VenueService, *repository.MockBookingRepository) {
+	venueRepo := repository.NewMockVenueRepository()
+	venues := NewVenueService(venueRepo)
+	bookings := repository.NewMockBookingRepository()
+	return NewReviewService(repository.NewMockReviewRepository(venueRepo), venues, bookings), venues, bookings
+}
+
+func playedBooking(t *testing.T, bookings *repository.MockBookingRepository, artistID, venueID string) {
+	t.Helper()
+	booking := domain.NewBooking(artistID, venueID, time.Now().Add(-48*time.Hour), 250)
+	booking.Confirm()
+	if err := bookings.Create(context.Background(), booking); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func TestReviewService_Submit(t *testing.T) {
+	svc, venues, bookings := newTestReviewService()
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	_ = venues.Create(ctx, venue)
+	scores := domain.ReviewScores{Sound: 5, Hospitality: 4, PaymentReliability: 5, Turnout: 3}
+
+	if _, err := svc.Submit(ctx, venue.ID, "artist-1", scores, ""); !errors.Is(err, ErrNoPlayedBooking) {
+		t.Fatalf("Submit() error = %v, want ErrNoPlayedBooking", err)
+	}
+
+	upcoming := domain.NewBooking("artist-1", venue.ID, time.Now().Add(48*time.Hour), 250)
+	upcoming.Confirm()
+	_ = bookings.Create(ctx, upcoming)
+	if _, err := svc.Submit(ctx, venue.ID, "artist-1", scores, ""); !errors.Is(err, ErrNoPlayedBooking) {
+		t.Fatalf("Submit() error = %v, want ErrNoPlayedBooking for an upcoming show", err)
+	}
+
+	playedBooking(t, bookings, "artist-1", venue.ID)
+	var invalid *InvalidReviewError
+	if _, err := svc.Submit(ctx, venue.ID, "artist-1", domain.ReviewScores{Sound: 9}, ""); !errors.As(err, &invalid) {
+		t.Fatalf("Submit() error = %v, want InvalidReviewError", err)
+	}
+
+	review, err := svc.Submit(ctx, venue.ID, "artist-1", scores, "Great room")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if review.Overall != 4.25 {
+		t.Errorf("Overall = %v, want 4.25", review.Overall)
+	}
+
+	playedBooking(t, bookings, "artist-2", venue.ID)
+	if _, err := svc.Submit(ctx, venue.ID, "artist-2", domain.ReviewScores{Sound: 3, Hospitality: 2, PaymentReliability: 1, Turnout: 1}, ""); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	// Revising a review replaces it rather than counting it twice
+	if _, err := svc.Submit(ctx, venue.ID, "artist-2", scores, ""); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	page, err := svc.List(ctx, venue.ID, 10, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if page.ReviewCount != 2 || page.Rating != 4.25 || len(page.Reviews) != 2 {
+		t.Errorf("List() = count %v rating %v with %d reviews, want 2, 4.25 and 2", page.ReviewCount, page.Rating, len(page.Reviews))
+	}
+
+	stored, _ := venues.GetByID(ctx, venue.ID)
+	if stored.ReviewCount != 2 || stored.Rating != 4.25 {
+		t.Errorf("stored venue count %v rating %v, want 2 and 4.25", stored.ReviewCount, stored.Rating)
+	}
+}
+
+// recordingListener remembers the venues it is told have changed
+type recordingListener struct {
+	venues []*domain.Venue
+}
+
+func (l *recordingListener) VenueChanged(ctx context.Context, venue *domain.Venue) {
+	l.venues = append(l.venues, venue.Clone())
+}
+
+func TestReviewService_Submit_RecordsRatingChange(t *testing.T) {
+	svc, venues, bookings := newTestReviewService()
+	history := repository.NewMockVenueHistoryRepository()
+	venues.SetHistory(history)
+	listener := &recordingListener{}
+	venues.AddListener(listener)
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	_ = venues.Create(ctx, venue)
+	playedBooking(t, bookings, "artist-1", venue.ID)
+	listener.venues = nil
+
+	scores := domain.ReviewScores{Sound: 5, Hospitality: 4, PaymentReliability: 5, Turnout: 3}
+	if _, err := svc.Submit(ctx, venue.ID, "artist-1", scores, ""); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	versions, _ := history.List(ctx, venue.ID, 10)
+	if len(versions) != 2 || versions[0].Action != domain.VenueUpdated {
+		t.Fatalf("History() = %d versions, want the review recorded as an update", len(versions))
+	}
+	if versions[0].Snapshot == nil || versions[0].Snapshot.Rating != 4.25 {
+		t.Errorf("History() latest snapshot = %+v, want rating 4.25", versions[0].Snapshot)
+	}
+	if len(listener.venues) != 1 || listener.venues[0].Rating != 4.25 {
+		t.Errorf("listeners heard %d changes, want one with rating 4.25", len(listener.venues))
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// baselineLimit caps how many existing venues are recorded when a search is saved
+const baselineLimit = 500
+
+// SavedSearchService manages saved venue searches and their new-match alerts
+type SavedSearchService struct {
+	repo   repository.SavedSearchRepository
+	venues *VenueService
+	sender notify.Sender
+}
+
+// NewSavedSearchService creates a new saved search service
+func NewSavedSearchService(repo repository.SavedSearchRepository, venues *VenueService, sender notify.Sender) *SavedSearchService {
+	return &SavedSearchService{
+		repo:   repo,
+		venues: venues,
+		sender: sender,
+	}
+}
+
+// Create saves a search and records the venues it already matches as a baseline,
+// so that only venues added or changed afterwards trigger alerts
+func (s *SavedSearchService) Create(ctx context.Context, search *domain.SavedSearch) error {
+	if search.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if err := s.venues.ValidateCriteria(&search.Criteria); err != nil {
+		return err
+	}
+	if search.Notify != nil {
+		switch notify.Channel(search.Notify.Channel) {
+		case notify.ChannelWebhook, notify.ChannelEmail:
+		default:
+			return fmt.Errorf("unsupported notification channel %q", search.Notify.Channel)
+		}
+		if search.Notify.Address == "" {
+			return fmt.Errorf("notification address is required")
+		}
+		if notify.Channel(search.Notify.Channel) == notify.ChannelWebhook {
+			if err := notify.CheckWebhookURL(ctx, search.Notify.Address); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The baseline is recorded before the search is saved, so a search is
+	// never evaluated without one and alerts on every venue it matches
+	baseline := search.Criteria
+	baseline.Limit = baselineLimit
+	baseline.Offset = 0
+	result, err := s.venues.Search(ctx, &baseline)
+	if err != nil {
+		return err
+	}
+	for _, v := range result.Venues {
+		if _, err := s.repo.RecordMatch(ctx, domain.NewSavedSearchMatch(search.ID, v.Venue, true)); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Create(ctx, search)
+}
+
+// GetByID retrieves a saved search by ID
+func (s *SavedSearchService) GetByID(ctx context.Context, id string) (*domain.SavedSearch, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListByUser lists a user's saved searches
+func (s *SavedSearchService) ListByUser(ctx context.Context, userID string) ([]*domain.SavedSearch, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Delete deletes a saved search
+func (s *SavedSearchService) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// ListMatches lists the venues recorded for a saved search
+func (s *SavedSearchService) ListMatches(ctx context.Context, id string) ([]*domain.SavedSearchMatch, error) {
+	return s.repo.ListMatches(ctx, id)
+}
+
+// Evaluate re-checks every saved search against a changed venue, recording new
+// matches and sending an alert for each one. A match whose alert failed to send
+// earlier is retried.
+func (s *SavedSearchService) Evaluate(ctx context.Context, venue *domain.Venue) error {
+	searches, err := s.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		if !s.venues.Matches(venue, &search.Criteria) {
+			continue
+		}
+
+		match := domain.NewSavedSearchMatch(search.ID, venue, false)
+		created, err := s.repo.RecordMatch(ctx, match)
+		if err != nil {
+			return err
+		}
+		if !created {
+			if match, err = s.repo.GetMatch(ctx, search.ID, venue.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.alert(ctx, search, venue, match); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RetryUnnotified resends alerts for matches whose earlier delivery failed
+func (s *SavedSearchService) RetryUnnotified(ctx context.Context) error {
+	searches, err := s.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		if search.Notify == nil {
+			continue
+		}
+
+		matches, err := s.repo.ListMatches(ctx, search.ID)
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			if match.Baseline || match.NotifiedAt != nil {
+				continue
+			}
+
+			venue, err := s.venues.GetByID(ctx, match.VenueID)
+			if err != nil {
+				log.Printf("saved search %s: cannot retry alert for venue %s: %v", search.ID, match.VenueID, err)
+				continue
+			}
+			if err := s.alert(ctx, search, venue, match); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// alert sends the alert for a match unless it is a baseline match or was
+// already delivered. The match is marked as notified only once the alert is
+// sent, so a failed delivery is retried later.
+func (s *SavedSearchService) alert(ctx context.Context, search *domain.SavedSearch, venue *domain.Venue, match *domain.SavedSearchMatch) error {
+	if search.Notify == nil || match.Baseline || match.NotifiedAt != nil {
+		return nil
+	}
+
+	if err := s.sender.Send(ctx, newMatchMessage(search, venue)); err != nil {
+		log.Printf("saved search %s: failed to notify %s: %v", search.ID, search.Notify.Address, err)
+		return nil
+	}
+
+	match.MarkNotified()
+	return s.repo.UpdateMatch(ctx, match)
+}
+
+// newMatchMessage builds the alert sent when a venue newly matches a saved search
+func newMatchMessage(search *domain.SavedSearch, venue *domain.Venue) notify.Message {
+	return notify.Message{
+		Channel: notify.Channel(search.Notify.Channel),
+		To:      search.Notify.Address,
+		Subject: fmt.Sprintf("New venue for %q: %s", search.Name, venue.Name),
+		Body: fmt.Sprintf("%s in %s, %s now matches your saved search %q.",
+			venue.Name, venue.Address.City, venue.Address.State, search.Name),
+		Data: map[string]any{
+			"saved_search_id": search.ID,
+			"venue_id":        venue.ID,
+			"venue_name":      venue.Name,
+		},
+	}
+}
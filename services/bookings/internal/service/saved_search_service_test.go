@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/notify"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+func newTestSavedSearchService() (*SavedSearchService, *VenueService, *repository.MockSavedSearchRepository, *notify.MemorySink) {
+	venueRepo := repository.NewMockVenueRepository()
+	venueService := NewVenueService(venueRepo)
+	searchRepo := repository.NewMockSavedSearchRepository()
+	sink := notify.NewMemorySink()
+	return NewSavedSearchService(searchRepo, venueService, sink), venueService, searchRepo, sink
+}
+
+func TestSavedSearchService_Create_RecordsBaseline(t *testing.T) {
+	svc, venues, repo, sink := newTestSavedSearchService()
+	ctx := context.Background()
+
+	existing := newTestVenue("Existing Club", 37.7749, -122.4194)
+	_ = venues.Create(ctx, existing)
+
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{
+		City:       "San Francisco",
+		State:      "CA",
+		VenueTypes: []domain.VenueType{domain.VenueTypeClub},
+	}, &domain.NotificationTarget{Channel: "email", Address: "agent@example.com"})
+
+	if err := svc.Create(ctx, search); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	matches, _ := repo.ListMatches(ctx, search.ID)
+	if len(matches) != 1 {
+		t.Fatalf("Create() recorded %v baseline matches, want 1", len(matches))
+	}
+	if !matches[0].Baseline {
+		t.Error("Create() baseline match should be flagged as baseline")
+	}
+	if len(sink.Messages()) != 0 {
+		t.Errorf("Create() sent %v notifications, want 0", len(sink.Messages()))
+	}
+}
+
+// failingMatchRepository fails to record matches
+type failingMatchRepository struct {
+	*repository.MockSavedSearchRepository
+}
+
+func (r *failingMatchRepository) RecordMatch(ctx context.Context, match *domain.SavedSearchMatch) (bool, error) {
+	return false, errors.New("matches table unavailable")
+}
+
+func TestSavedSearchService_Create_BaselineFailureSavesNothing(t *testing.T) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	repo := &failingMatchRepository{MockSavedSearchRepository: repository.NewMockSavedSearchRepository()}
+	svc := NewSavedSearchService(repo, venues, notify.NewMemorySink())
+	ctx := context.Background()
+
+	_ = venues.Create(ctx, newTestVenue("Existing Club", 37.7749, -122.4194))
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}, nil)
+	if err := svc.Create(ctx, search); err == nil {
+		t.Fatal("Create() should fail when the baseline cannot be recorded")
+	}
+	if _, err := repo.GetByID(ctx, search.ID); err == nil {
+		t.Error("Create() saved a search without its baseline")
+	}
+}
+
+func TestSavedSearchService_Create_Validation(t *testing.T) {
+	svc, _, _, _ := newTestSavedSearchService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		search *domain.SavedSearch
+	}{
+		{
+			name:   "missing name",
+			search: domain.NewSavedSearch("user-1", "", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"}, nil),
+		},
+		{
+			name:   "missing criteria",
+			search: domain.NewSavedSearch("user-1", "Anything", domain.VenueSearchCriteria{}, nil),
+		},
+		{
+			name: "unsupported channel",
+			search: domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+				&domain.NotificationTarget{Channel: "pigeon", Address: "roof"}),
+		},
+		{
+			name: "missing address",
+			search: domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+				&domain.NotificationTarget{Channel: "webhook"}),
+		},
+		{
+			name: "plain http webhook",
+			search: domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+				&domain.NotificationTarget{Channel: "webhook", Address: "http://93.184.216.34/hook"}),
+		},
+		{
+			name: "internal webhook",
+			search: domain.NewSavedSearch("user-1", "SF", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+				&domain.NotificationTarget{Channel: "webhook", Address: "https://169.254.169.254/latest/meta-data"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Create(ctx, tt.search); err == nil {
+				t.Error("Create() should return error")
+			}
+		})
+	}
+}
+
+func TestSavedSearchService_Evaluate_NotifiesNewMatch(t *testing.T) {
+	svc, _, repo, sink := newTestSavedSearchService()
+	ctx := context.Background()
+
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{
+		City:       "San Francisco",
+		State:      "CA",
+		VenueTypes: []domain.VenueType{domain.VenueTypeClub},
+	}, &domain.NotificationTarget{Channel: "webhook", Address: "https://93.184.216.34/hook"})
+	_ = svc.Create(ctx, search)
+
+	club := newTestVenue("New Club", 37.7749, -122.4194)
+	if err := svc.Evaluate(ctx, club); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	messages := sink.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Evaluate() sent %v notifications, want 1", len(messages))
+	}
+	if messages[0].Channel != notify.ChannelWebhook || messages[0].To != "https://93.184.216.34/hook" {
+		t.Errorf("Evaluate() sent to %v %v, want webhook https://93.184.216.34/hook", messages[0].Channel, messages[0].To)
+	}
+	if messages[0].Data["venue_id"] != club.ID {
+		t.Errorf("Evaluate() venue_id = %v, want %v", messages[0].Data["venue_id"], club.ID)
+	}
+
+	matches, _ := repo.ListMatches(ctx, search.ID)
+	if len(matches) != 1 || matches[0].NotifiedAt == nil {
+		t.Error("Evaluate() should record a notified match")
+	}
+
+	// Re-evaluating the same venue must not alert again
+	_ = svc.Evaluate(ctx, club)
+	if len(sink.Messages()) != 1 {
+		t.Errorf("Evaluate() sent %v notifications after re-evaluation, want 1", len(sink.Messages()))
+	}
+}
+
+// flakySender fails a set number of sends, then delivers to the sink
+type flakySender struct {
+	*notify.MemorySink
+	failures int
+}
+
+func (s *flakySender) Send(ctx context.Context, msg notify.Message) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("webhook unreachable")
+	}
+	return s.MemorySink.Send(ctx, msg)
+}
+
+func TestSavedSearchService_Evaluate_RetriesFailedAlert(t *testing.T) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	repo := repository.NewMockSavedSearchRepository()
+	sender := &flakySender{MemorySink: notify.NewMemorySink(), failures: 1}
+	svc := NewSavedSearchService(repo, venues, sender)
+	ctx := context.Background()
+
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+		&domain.NotificationTarget{Channel: "email", Address: "agent@example.com"})
+	_ = svc.Create(ctx, search)
+
+	club := newTestVenue("New Club", 37.7749, -122.4194)
+	if err := svc.Evaluate(ctx, club); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(sender.Messages()) != 0 {
+		t.Fatalf("Evaluate() delivered %v notifications while the sender was failing, want 0", len(sender.Messages()))
+	}
+	matches, _ := repo.ListMatches(ctx, search.ID)
+	if len(matches) != 1 || matches[0].NotifiedAt != nil {
+		t.Fatal("Evaluate() should record the match as not yet notified")
+	}
+
+	// The next run delivers the alert, and only once
+	if err := svc.Evaluate(ctx, club); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	_ = svc.Evaluate(ctx, club)
+	if len(sender.Messages()) != 1 {
+		t.Errorf("Evaluate() delivered %v notifications on later runs, want 1", len(sender.Messages()))
+	}
+	matches, _ = repo.ListMatches(ctx, search.ID)
+	if len(matches) != 1 || matches[0].NotifiedAt == nil {
+		t.Error("Evaluate() should mark the match notified once the alert is delivered")
+	}
+}
+
+func TestSavedSearchService_RetryUnnotified(t *testing.T) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	repo := repository.NewMockSavedSearchRepository()
+	sender := &flakySender{MemorySink: notify.NewMemorySink(), failures: 1}
+	svc := NewSavedSearchService(repo, venues, sender)
+	ctx := context.Background()
+
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{City: "San Francisco", State: "CA"},
+		&domain.NotificationTarget{Channel: "email", Address: "agent@example.com"})
+	_ = venues.Create(ctx, newTestVenue("Existing Club", 37.7749, -122.4194))
+	_ = svc.Create(ctx, search)
+
+	club := newTestVenue("New Club", 37.7749, -122.4194)
+	_ = venues.Create(ctx, club)
+	_ = svc.Evaluate(ctx, club)
+
+	if err := svc.RetryUnnotified(ctx); err != nil {
+		t.Fatalf("RetryUnnotified() error = %v", err)
+	}
+	messages := sender.Messages()
+	if len(messages) != 1 || messages[0].Data["venue_id"] != club.ID {
+		t.Fatalf("RetryUnnotified() delivered %v, want one alert for %v", messages, club.ID)
+	}
+
+	// Baseline and delivered matches are not alerted again
+	_ = svc.RetryUnnotified(ctx)
+	if len(sender.Messages()) != 1 {
+		t.Errorf("RetryUnnotified() delivered %v notifications on a second run, want 1", len(sender.Messages()))
+	}
+}
+
+func TestSavedSearchService_Evaluate_IgnoresNonMatching(t *testing.T) {
+	svc, _, repo, sink := newTestSavedSearchService()
+	ctx := context.Background()
+
+	search := domain.NewSavedSearch("user-1", "SF clubs", domain.VenueSearchCriteria{
+		City:       "San Francisco",
+		State:      "CA",
+		VenueTypes: []domain.VenueType{domain.VenueTypeClub},
+	}, &domain.NotificationTarget{Channel: "email", Address: "agent@example.com"})
+	_ = svc.Create(ctx, search)
+
+	winery := newTestVenue("A Winery", 37.7749, -122.4194)
+	winery.VenueTypes = []domain.VenueType{domain.VenueTypeWinery}
+	_ = svc.Evaluate(ctx, winery)
+
+	if len(sink.Messages()) != 0 {
+		t.Errorf("Evaluate() sent %v notifications, want 0", len(sink.Messages()))
+	}
+	matches, _ := repo.ListMatches(ctx, search.ID)
+	if len(matches) != 0 {
+		t.Errorf("Evaluate() recorded %v matches, want 0", len(matches))
+	}
+}
+
+func TestSavedSearchWorker_EvaluatesChangedVenues(t *testing.T) {
+	svc, venues, _, sink := newTestSavedSearchService()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	search := domain.NewSavedSearch("user-1", "Nearby", domain.VenueSearchCriteria{
+		Location: &domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194},
+		RadiusKm: 5,
+	}, &domain.NotificationTarget{Channel: "email", Address: "agent@example.com"})
+	_ = svc.Create(ctx, search)
+
+	worker := NewSavedSearchWorker(svc, 10)
+	venues.AddListener(worker)
+	go worker.Run(ctx)
+
+	_ = venues.Create(ctx, newTestVenue("Fresh Venue", 37.7749, -122.4194))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sink.Messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(sink.Messages()) != 1 {
+		t.Errorf("worker sent %v notifications, want 1", len(sink.Messages()))
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// savedSearchRetryInterval is how often alerts that failed to send are retried
+const savedSearchRetryInterval = 5 * time.Minute
+
+// SavedSearchWorker re-evaluates saved searches in the background whenever a
+// venue is created or updated, and periodically retries failed alerts
+type SavedSearchWorker struct {
+	searches      *SavedSearchService
+	queue         chan domain.Venue
+	retryInterval time.Duration
+}
+
+// NewSavedSearchWorker creates a worker with a bounded queue of pending venues
+func NewSavedSearchWorker(searches *SavedSearchService, queueSize int) *SavedSearchWorker {
+	return &SavedSearchWorker{
+		searches:      searches,
+		queue:         make(chan domain.Venue, queueSize),
+		retryInterval: savedSearchRetryInterval,
+	}
+}
+
+// VenueChanged queues the venue for evaluation without blocking the caller.
+// A snapshot is queued so later mutations by the caller are not observed.
+func (w *SavedSearchWorker) VenueChanged(ctx context.Context, venue *domain.Venue) {
+	select {
+	case w.queue <- *venue:
+	default:
+		log.Printf("saved search queue full, dropping evaluation for venue %s", venue.ID)
+	}
+}
+
+// Run processes queued venues and retries failed alerts until the context is
+// cancelled
+func (w *SavedSearchWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.searches.RetryUnnotified(ctx); err != nil {
+				log.Printf("failed to retry saved search alerts: %v", err)
+			}
+		case venue := <-w.queue:
+			if err := w.searches.Evaluate(ctx, &venue); err != nil {
+				log.Printf("failed to evaluate saved searches for venue %s: %v", venue.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/crowdunlocked/services/bookings/internal/bulk"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// BulkRowStatus is what an import did with one row
+type BulkRowStatus string
+
+const (
+	BulkRowCreated   BulkRowStatus = "created"
+	BulkRowValid     BulkRowStatus = "valid" // Would be created; dry runs only
+	BulkRowDuplicate BulkRowStatus = "duplicate"
+	BulkRowInvalid   BulkRowStatus = "invalid"
+	BulkRowFailed    BulkRowStatus = "failed"
+)
+
+// BulkRowResult reports the outcome for one row of an import
+type BulkRowResult struct {
+	Line        int           `json:"line"`
+	Name        string        `json:"name,omitempty"`
+	Status      BulkRowStatus `json:"status"`
+	VenueID     string        `json:"venue_id,omitempty"`
+	DuplicateOf string        `json:"duplicate_of,omitempty"` // Existing venue, or "line N" for an earlier row
+	Error       string        `json:"error,omitempty"`
+}
+
+// BulkImportReport summarises an import and lists every row's outcome
+type BulkImportReport struct {
+	DryRun     bool            `json:"dry_run"`
+	Total      int             `json:"total"`
+	Created    int             `json:"created"`
+	Valid      int             `json:"valid"`
+	Duplicates int             `json:"duplicates"`
+	Invalid    int             `json:"invalid"`
+	Failed     int             `json:"failed"`
+	Rows       []BulkRowResult `json:"rows"`
+}
+
+func (r *BulkImportReport) add(result BulkRowResult) {
+	r.Rows = append(r.Rows, result)
+	switch result.Status {
+	case BulkRowCreated:
+		r.Created++
+	case BulkRowValid:
+		r.Valid++
+	case BulkRowDuplicate:
+		r.Duplicates++
+	case BulkRowInvalid:
+		r.Invalid++
+	case BulkRowFailed:
+		r.Failed++
+	}
+}
+
+// Import validates, geocodes and dedupes rows read from a bulk file and
+// saves the new venues in batches, attributed to actor. A row is skipped as
+// a duplicate when it shares an external ID with a stored venue or an
+// earlier row, or scores above the auto-merge threshold against one. A dry
+// run does everything but save.
+func (s *VenueService) Import(ctx context.Context, rows []bulk.Row, actor string, dryRun bool) (*BulkImportReport, error) {
+	report := &BulkImportReport{DryRun: dryRun, Total: len(rows), Rows: make([]BulkRowResult, 0, len(rows))}
+	accepted := make([]*domain.Venue, 0, len(rows))
+	results := make(map[string]BulkRowResult)
+	lines := make(map[string]int)
+
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := BulkRowResult{Line: row.Line}
+		if row.Venue != nil {
+			result.Name = row.Venue.Name
+		}
+		venue, duplicateOf, err := s.prepareImport(ctx, row, accepted, lines)
+		switch {
+		case err != nil:
+			// Rows that can never import are invalid; anything else may work on a retry
+			var unresolved *LocationUnresolvedError
+			result.Status = BulkRowFailed
+			if errors.Is(err, errInvalidImportRow) || errors.As(err, &unresolved) {
+				result.Status = BulkRowInvalid
+			}
+			result.Error = err.Error()
+			report.add(result)
+		case duplicateOf != "":
+			result.Status = BulkRowDuplicate
+			result.DuplicateOf = duplicateOf
+			report.add(result)
+		default:
+			venue.RecordChanges(nil, domain.SourceManual, actor)
+			accepted = append(accepted, venue)
+			lines[venue.ID] = row.Line
+			result.VenueID = venue.ID
+			result.Status = BulkRowValid
+			results[venue.ID] = result
+		}
+	}
+
+	if dryRun || len(accepted) == 0 {
+		for _, venue := range accepted {
+			report.add(results[venue.ID])
+		}
+		sortRowResults(report.Rows)
+		return report, nil
+	}
+
+	failed := make(map[string]error)
+	if err := s.repo.BatchCreate(ctx, accepted); err != nil {
+		var batchErr *repository.BatchWriteError
+		if !errors.As(err, &batchErr) {
+			return nil, err
+		}
+		failed = batchErr.Failed
+	}
+
+	for _, venue := range accepted {
+		result := results[venue.ID]
+		if err, ok := failed[venue.ID]; ok {
+			result.Status = BulkRowFailed
+			result.Error = err.Error()
+			result.VenueID = ""
+		} else {
+			result.Status = BulkRowCreated
+			s.recordHistory(ctx, nil, venue, domain.VenueCreated, 0)
+			s.notifyListeners(ctx, venue)
+		}
+		report.add(result)
+	}
+	sortRowResults(report.Rows)
+	return report, nil
+}
+
+// errInvalidImportRow marks validation failures of an import row
+var errInvalidImportRow = errors.New("invalid row")
+
+// prepareImport validates and geocodes one row and checks it against stored
+// venues and the rows accepted before it. It returns the venue to create, or
+// what it duplicates.
+func (s *VenueService) prepareImport(ctx context.Context, row bulk.Row, accepted []*domain.Venue, lines map[string]int) (*domain.Venue, string, error) {
+	if row.Err != nil {
+		return nil, "", fmt.Errorf("%w: %v", errInvalidImportRow, row.Err)
+	}
+	venue := row.Venue
+	if !venue.HasLocation() && venue.Address.City == "" {
+		return nil, "", fmt.Errorf("%w: location or address is required", errInvalidImportRow)
+	}
+	if err := venue.Validate(); err != nil {
+		return nil, "", fmt.Errorf("%w: %v", errInvalidImportRow, err)
+	}
+	if err := s.geocode(ctx, venue, nil); err != nil {
+		return nil, "", err
+	}
+	venue.Location.Geohash = domain.EncodeGeohash(venue.Location.Latitude, venue.Location.Longitude, 6)
+	venue.AssignRoomIDs()
+
+	for source, id := range venue.ExternalIDs() {
+		existing, err := s.repo.GetByExternalID(ctx, source, id)
+		if err == nil {
+			return nil, existing.ID, nil
+		}
+		var notFound *repository.VenueNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, "", err
+		}
+	}
+
+	for _, other := range accepted {
+		if domain.ScoreDuplicate(venue, other).Total >= domain.DuplicateAutoMergeThreshold {
+			return nil, fmt.Sprintf("line %d", lines[other.ID]), nil
+		}
+	}
+
+	if s.dupes != nil {
+		candidates, err := s.dupes.FindDuplicates(ctx, venue)
+		if err != nil {
+			return nil, "", err
+		}
+		// Candidates come best first
+		if len(candidates) > 0 && candidates[0].Score.Total >= domain.DuplicateAutoMergeThreshold {
+			other := candidates[0].VenueID
+			if other == venue.ID {
+				other = candidates[0].OtherID
+			}
+			return nil, other, nil
+		}
+	}
+
+	return venue, "", nil
+}
+
+// sortRowResults puts results back in file order
+func sortRowResults(rows []BulkRowResult) {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Line < rows[j].Line })
+}
+
+// Export calls fn with every stored venue, including those not listed in
+// search, in no particular order
+func (s *VenueService) Export(ctx context.Context, fn func(*domain.Venue) error) error {
+	return s.repo.ScanAll(ctx, fn)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/bulk"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+const bulkImportCSV = `name,latitude,longitude,city,state,venue_types,capacity,songkick_id
+The Chapel,37.7603,-122.4213,San Francisco,CA,club,400,sk-chapel
+Bottom of the Hill,37.7651,-122.3963,San Francisco,CA,club,250,sk-existing
+The Chapel,37.7603,-122.4213,San Francisco,CA,club,400,
+No Types,37.77,-122.41,San Francisco,CA,,100,
+Nowhere,,,,,bar,50,
+`
+
+func TestVenueService_Import(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	svc := NewVenueService(repo)
+	ctx := context.Background()
+
+	existing := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	existing.SongkickID = "sk-existing"
+	_ = svc.Create(ctx, existing)
+
+	rows, err := bulk.Read(strings.NewReader(bulkImportCSV), bulk.FormatCSV)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	dryRun, err := svc.Import(ctx, rows, "admin-1", true)
+	if err != nil {
+		t.Fatalf("Import() dry run error = %v", err)
+	}
+	if dryRun.Valid != 1 || dryRun.Created != 0 {
+		t.Errorf("dry run = %d valid, %d created, want 1 and 0", dryRun.Valid, dryRun.Created)
+	}
+	if _, err := svc.GetByID(ctx, dryRun.Rows[0].VenueID); err == nil {
+		t.Error("a dry run should not save venues")
+	}
+
+	rows, _ = bulk.Read(strings.NewReader(bulkImportCSV), bulk.FormatCSV)
+	report, err := svc.Import(ctx, rows, "admin-1", false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if report.Total != 5 || report.Created != 1 || report.Duplicates != 2 || report.Invalid != 2 {
+		t.Fatalf("Import() = %+v", report)
+	}
+
+	want := []struct {
+		status      BulkRowStatus
+		duplicateOf string
+	}{
+		{BulkRowCreated, ""},
+		{BulkRowDuplicate, existing.ID},
+		{BulkRowDuplicate, "line 2"},
+		{BulkRowInvalid, ""},
+		{BulkRowInvalid, ""},
+	}
+	for i, w := range want {
+		row := report.Rows[i]
+		if row.Line != i+2 || row.Status != w.status || row.DuplicateOf != w.duplicateOf {
+			t.Errorf("row %d = %+v, want %v %q", i, row, w.status, w.duplicateOf)
+		}
+	}
+
+	created, err := svc.GetByID(ctx, report.Rows[0].VenueID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if created.Location.Geohash == "" || created.FieldSource(domain.FieldName).Actor != "admin-1" {
+		t.Errorf("created venue geohash %q, name actor %q", created.Location.Geohash, created.FieldSource(domain.FieldName).Actor)
+	}
+
+	count := 0
+	_ = svc.Export(ctx, func(*domain.Venue) error { count++; return nil })
+	if count != 2 {
+		t.Errorf("Export() = %d venues, want 2", count)
+	}
+}
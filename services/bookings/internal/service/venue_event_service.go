@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+)
+
+// VenueEvents is one page of a venue's past events with stats over the
+// last EventStatsMonths
+type VenueEvents struct {
+	VenueID    string               `json:"venue_id"`
+	Stats      domain.EventStats    `json:"stats"`
+	Events     []*domain.VenueEvent `json:"events"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// EventSyncResult summarises an event sync run
+type EventSyncResult struct {
+	Scanned       int      `json:"scanned"`
+	Events        int      `json:"events"`
+	GenresUpdated int      `json:"genres_updated"`
+	Failed        int      `json:"failed"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// EventService keeps the calendar of shows each venue has hosted, from our
+// bookings and providers' calendars, and derives venue genres from it
+type EventService struct {
+	events   repository.VenueEventRepository
+	venues   *VenueService
+	bookings repository.BookingRepository
+	sources  []source.EventSource
+}
+
+// NewEventService creates a new event service
+func NewEventService(events repository.VenueEventRepository, venues *VenueService, bookings repository.BookingRepository) *EventService {
+	return &EventService{
+		events:   events,
+		venues:   venues,
+		bookings: bookings,
+	}
+}
+
+// AddSource makes Sync read past events from a provider's calendar
+func (s *EventService) AddSource(src source.EventSource) {
+	s.sources = append(s.sources, src)
+}
+
+// List returns a page of a venue's events, latest first, with its stats
+func (s *EventService) List(ctx context.Context, venueID string, limit int, cursor string) (*VenueEvents, error) {
+	venue, err := s.venues.GetByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, next, err := s.events.ListByVenue(ctx, venue.ID, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.stats(ctx, venue.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &VenueEvents{
+		VenueID:    venue.ID,
+		Stats:      stats,
+		Events:     events,
+		NextCursor: next,
+	}, nil
+}
+
+// SyncAll refreshes the events of every active venue. Failures on
+// individual venues are recorded and the run continues; it stops early only
+// if ctx is done or the venue scan itself fails.
+func (s *EventService) SyncAll(ctx context.Context) (*EventSyncResult, error) {
+	result := &EventSyncResult{}
+
+	err := s.venues.ScanAll(ctx, func(venue *domain.Venue) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !venue.Active {
+			return nil
+		}
+
+		result.Scanned++
+		saved, updated, err := s.Sync(ctx, venue)
+		result.Events += saved
+		if updated {
+			result.GenresUpdated++
+		}
+		if err != nil {
+			result.Failed++
+			if len(result.Errors) < maxReportedErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", venue.ID, err))
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// Sync records a venue's played bookings and its shows in every provider
+// calendar it is linked to over the last EventStatsMonths, then updates its
+// genres from the stats. It returns how many events were saved and whether
+// the genres changed.
+func (s *EventService) Sync(ctx context.Context, venue *domain.Venue) (int, bool, error) {
+	now := time.Now()
+	since := now.AddDate(0, -domain.EventStatsMonths, 0)
+
+	events, err := s.bookedEvents(ctx, venue.ID, since, now)
+	if err != nil {
+		return 0, false, err
+	}
+
+	externalIDs := venue.ExternalIDs()
+	for _, src := range s.sources {
+		externalID := externalIDs[src.Source()]
+		if externalID == "" {
+			continue
+		}
+		fetched, err := src.PastEvents(ctx, venue.ID, externalID, since)
+		if err != nil {
+			var notFound *source.NotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return 0, false, err
+		}
+		events = append(events, fetched...)
+	}
+
+	if err := s.events.Save(ctx, events); err != nil {
+		return 0, false, err
+	}
+
+	stats, err := s.stats(ctx, venue.ID, now)
+	if err != nil {
+		return len(events), false, err
+	}
+	if !venue.ApplyEventGenres(stats.TypicalGenres) {
+		return len(events), false, nil
+	}
+	if err := s.venues.Update(ctx, venue); err != nil {
+		return len(events), false, fmt.Errorf("failed to update genres: %w", err)
+	}
+	return len(events), true, nil
+}
+
+// bookedEvents turns the venue's bookings played between since and now
+// into events
+func (s *EventService) bookedEvents(ctx context.Context, venueID string, since, now time.Time) ([]*domain.VenueEvent, error) {
+	bookings, err := s.bookings.ListByVenue(ctx, venueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings for venue: %w", err)
+	}
+
+	events := make([]*domain.VenueEvent, 0, len(bookings))
+	for _, b := range bookings {
+		if b.Played(now) && !b.EventDate.Before(since) {
+			events = append(events, domain.EventFromBooking(b))
+		}
+	}
+	return events, nil
+}
+
+// stats summarises a venue's stored events over the last EventStatsMonths
+func (s *EventService) stats(ctx context.Context, venueID string, now time.Time) (domain.EventStats, error) {
+	recent, err := s.events.ListSince(ctx, venueID, now.AddDate(0, -domain.EventStatsMonths, 0))
+	if err != nil {
+		return domain.EventStats{}, err
+	}
+	return domain.SummarizeEvents(recent, now, domain.EventStatsMonths), nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/source"
+)
+
+// fakeEventSource serves a Songkick calendar from memory
+type fakeEventSource struct {
+	events map[string][]*domain.VenueEvent
+}
+
+func (f *fakeEventSource) Source() domain.DataSource { return domain.SourceSongkick }
+
+func (f *fakeEventSource) PastEvents(ctx context.Context, venueID, externalID string, since time.Time) ([]*domain.VenueEvent, error) {
+	events, ok := f.events[externalID]
+	if !ok {
+		return nil, &source.NotFoundError{Source: domain.SourceSongkick, ExternalID: externalID}
+	}
+	return events, nil
+}
+
+func TestEventService_Sync(t *testing.T) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	bookings := repository.NewMockBookingRepository()
+	events := NewEventService(repository.NewMockVenueEventRepository(), venues, bookings)
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	venue.SongkickID = "sk-1"
+	_ = venues.Create(ctx, venue)
+	unlinked := newTestVenue("Hemlock Tavern", 37.7749, -122.4194)
+	unlinked.SongkickID = "sk-gone"
+	_ = venues.Create(ctx, unlinked)
+
+	calendar := make([]*domain.VenueEvent, 0)
+	for i, genre := range []string{"punk", "punk", "Punk", "garage rock", "garage rock", "jazz"} {
+		e := domain.NewVenueEvent(venue.ID, domain.SourceSongkick, string(rune('a'+i)), time.Now().AddDate(0, -i-1, 0))
+		e.Genres = []string{genre}
+		calendar = append(calendar, e)
+	}
+	events.AddSource(&fakeEventSource{events: map[string][]*domain.VenueEvent{"sk-1": calendar}})
+
+	played := domain.NewBooking("artist-1", venue.ID, time.Now().Add(-48*time.Hour), 300)
+	played.Confirm()
+	upcoming := domain.NewBooking("artist-2", venue.ID, time.Now().Add(48*time.Hour), 300)
+	upcoming.Confirm()
+	_ = bookings.Create(ctx, played)
+	_ = bookings.Create(ctx, upcoming)
+
+	result, err := events.SyncAll(ctx)
+	if err != nil {
+		t.Fatalf("SyncAll() error = %v", err)
+	}
+	if result.Scanned != 2 || result.Events != 7 || result.GenresUpdated != 1 || result.Failed != 0 {
+		t.Errorf("SyncAll() = %+v, want 7 events saved and one venue's genres updated", result)
+	}
+
+	stored, _ := venues.GetByID(ctx, venue.ID)
+	if len(stored.Genres) != 2 || stored.Genres[0] != "punk" || stored.Genres[1] != "garage rock" {
+		t.Errorf("genres = %v, want punk and garage rock", stored.Genres)
+	}
+
+	page, err := events.List(ctx, venue.ID, 3, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Events) != 3 || page.NextCursor == "" {
+		t.Errorf("List() returned %d events and cursor %q, want a full first page", len(page.Events), page.NextCursor)
+	}
+	if page.Events[0].Source != domain.SourceBooking || page.Events[0].ArtistID != "artist-1" {
+		t.Errorf("List()[0] = %+v, want the played booking first", page.Events[0])
+	}
+	if page.Stats.Shows != 7 || page.Stats.Genres[0].Genre != "punk" || page.Stats.Genres[0].Shows != 3 {
+		t.Errorf("List() stats = %+v", page.Stats)
+	}
+
+	// Syncing again replaces rather than duplicates events
+	if _, _, err := events.Sync(ctx, stored); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	page, _ = events.List(ctx, venue.ID, 3, "")
+	if page.Stats.Shows != 7 {
+		t.Errorf("shows after a second sync = %d, want 7", page.Stats.Shows)
+	}
+}
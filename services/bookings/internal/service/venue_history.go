@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+// ErrHistoryDisabled is returned by history operations when no history
+// repository is configured
+var ErrHistoryDisabled = errors.New("venue history is not enabled")
+
+// History lists a venue's versions, newest first
+func (s *VenueService) History(ctx context.Context, id string, limit int) ([]*domain.VenueVersion, error) {
+	if s.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+	return s.history.List(ctx, id, limit)
+}
+
+// GetVersion retrieves one version of a venue
+func (s *VenueService) GetVersion(ctx context.Context, id string, version int) (*domain.VenueVersion, error) {
+	if s.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+	return s.history.Get(ctx, id, version)
+}
+
+// Revert restores a venue to the state recorded in one of its versions. A
+// deleted venue is recreated as it was. The revert is itself appended to
+// the history, and fields it changes on a live venue are attributed to actor.
+func (s *VenueService) Revert(ctx context.Context, id string, version int, actor string) (*domain.Venue, error) {
+	target, err := s.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		var notFound *repository.VenueNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+
+		restored := target.Snapshot.Clone()
+		restored.Update()
+		if err := s.create(ctx, restored, version); err != nil {
+			return nil, err
+		}
+		return restored, nil
+	}
+
+	restored := current.RevertTo(target.Snapshot)
+	restored.RecordChanges(current, domain.SourceUserSubmitted, actor)
+	if err := s.update(ctx, restored, version); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// recordHistory appends a change to the venue's history. The venue itself
+// is already saved, so a failure is logged rather than returned.
+func (s *VenueService) recordHistory(ctx context.Context, before, after *domain.Venue, action domain.VenueChangeAction, revertedTo int) {
+	if s.history == nil {
+		return
+	}
+
+	version := domain.NewVenueVersion(before, after, action)
+	if revertedTo > 0 {
+		version.Action = domain.VenueReverted
+		version.RevertedTo = revertedTo
+	}
+
+	if err := s.history.Append(ctx, version); err != nil {
+		log.Printf("recording history for venue %s: %v", version.VenueID, err)
+	}
+}
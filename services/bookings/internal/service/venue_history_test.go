@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+func TestVenueService_History_RecordsEveryChange(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	service := NewVenueService(repo)
+	service.SetHistory(repository.NewMockVenueHistoryRepository())
+	ctx := context.Background()
+
+	venue := newTestVenue("The Chapel", 37.7603, -122.4212)
+	if err := service.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	venue.Capacity = 450
+	if err := service.Update(ctx, venue); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := service.Delete(ctx, venue.ID, "user-1", domain.RoleAdmin); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	versions, err := service.History(ctx, venue.ID, 10)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("History() returned %d versions, want 3", len(versions))
+	}
+	if versions[0].Action != domain.VenueDeleted || versions[2].Action != domain.VenueCreated {
+		t.Errorf("History() actions = %v, %v; want delete first and create last", versions[0].Action, versions[2].Action)
+	}
+
+	update := versions[1]
+	if len(update.Changes) != 1 || update.Changes[0].Field != domain.FieldCapacity || update.Changes[0].New != 450 {
+		t.Errorf("update changes = %+v, want capacity 0 -> 450", update.Changes)
+	}
+}
+
+func TestVenueService_Revert(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	service := NewVenueService(repo)
+	service.SetHistory(repository.NewMockVenueHistoryRepository())
+	ctx := context.Background()
+
+	venue := newTestVenue("The Chapel", 37.7603, -122.4212)
+	_ = service.Create(ctx, venue)
+	venue.Name = "Vandalised"
+	_ = service.Update(ctx, venue)
+
+	reverted, err := service.Revert(ctx, venue.ID, 1, "user-1")
+	if err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	if reverted.Name != "The Chapel" {
+		t.Errorf("Revert() name = %v, want The Chapel", reverted.Name)
+	}
+	if p := reverted.Provenance[domain.FieldName]; p.Actor != "user-1" {
+		t.Errorf("Revert() name provenance = %+v, want user-1", p)
+	}
+
+	latest, _ := service.History(ctx, venue.ID, 1)
+	if latest[0].Action != domain.VenueReverted || latest[0].RevertedTo != 1 {
+		t.Errorf("Revert() recorded %v to %d, want revert to 1", latest[0].Action, latest[0].RevertedTo)
+	}
+}
+
+func TestVenueService_Revert_RecreatesDeletedVenue(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	service := NewVenueService(repo)
+	service.SetHistory(repository.NewMockVenueHistoryRepository())
+	ctx := context.Background()
+
+	venue := newTestVenue("The Chapel", 37.7603, -122.4212)
+	_ = service.Create(ctx, venue)
+	_ = service.Delete(ctx, venue.ID, "user-1", domain.RoleAdmin)
+
+	if _, err := service.Revert(ctx, venue.ID, 2, "user-1"); err != nil {
+		t.Fatalf("Revert() error = %v", err)
+	}
+	restored, err := service.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() after revert error = %v", err)
+	}
+	if restored.Name != "The Chapel" {
+		t.Errorf("restored name = %v, want The Chapel", restored.Name)
+	}
+}
+
+func TestVenueService_History_Disabled(t *testing.T) {
+	service := NewVenueService(repository.NewMockVenueRepository())
+
+	if _, err := service.History(context.Background(), "venue-1", 10); !errors.Is(err, ErrHistoryDisabled) {
+		t.Errorf("History() error = %v, want ErrHistoryDisabled", err)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+var (
+	// ErrVenueStatusForbidden is returned when someone other than an owner
+	// or admin deactivates, reactivates or deletes a venue
+	ErrVenueStatusForbidden = errors.New("only venue owners and admins can deactivate, reactivate or delete a venue")
+	// ErrVenueNotListed is returned when reactivating a submission that has
+	// not passed moderation
+	ErrVenueNotListed = errors.New("venue has not been approved by a moderator")
+	// ErrVenueNotDeleted is returned when purging a venue that was never deleted
+	ErrVenueNotDeleted = errors.New("only deleted venues can be purged")
+	// ErrVenueHasBookings is returned when purging a venue that bookings
+	// still reference
+	ErrVenueHasBookings = errors.New("venue has bookings and cannot be purged")
+)
+
+// Deactivate takes a venue out of search without deleting it, for example
+// while it is closed for renovation
+func (s *VenueService) Deactivate(ctx context.Context, id, userID string, role domain.Role) (*domain.Venue, error) {
+	venue, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if venue.RoleFor(userID, role) == domain.RoleContributor {
+		return nil, ErrVenueStatusForbidden
+	}
+
+	venue.Deactivate()
+	if err := s.Update(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// Reactivate lists a deactivated venue again. A deleted venue that has not
+// been purged yet is restored.
+func (s *VenueService) Reactivate(ctx context.Context, id, userID string, role domain.Role) (*domain.Venue, error) {
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if venue.RoleFor(userID, role) == domain.RoleContributor {
+		return nil, ErrVenueStatusForbidden
+	}
+	if !venue.Listed() {
+		return nil, ErrVenueNotListed
+	}
+
+	venue.Restore()
+	if err := s.Update(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// Delete soft-deletes a venue on behalf of an owner or admin. It disappears
+// from search and lookups, and can be restored with Reactivate until it is
+// purged.
+func (s *VenueService) Delete(ctx context.Context, id, userID string, role domain.Role) error {
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if venue.Deleted() {
+		return &repository.VenueNotFoundError{}
+	}
+	if venue.RoleFor(userID, role) == domain.RoleContributor {
+		return ErrVenueStatusForbidden
+	}
+
+	previous := venue.Clone()
+	venue.SoftDelete(userID)
+	if err := s.repo.Update(ctx, venue); err != nil {
+		return err
+	}
+
+	s.recordHistory(ctx, previous, nil, domain.VenueDeleted, 0)
+	s.notifyListeners(ctx, venue)
+	return nil
+}
+
+// Purge removes a deleted venue for good. Venues that bookings still
+// reference are kept so the bookings never point at a missing venue.
+func (s *VenueService) Purge(ctx context.Context, id string) error {
+	if s.bookings == nil {
+		return errors.New("cannot purge venues without a booking repository")
+	}
+
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !venue.Deleted() {
+		return ErrVenueNotDeleted
+	}
+
+	bookings, err := s.bookings.ListByVenue(ctx, venue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list bookings for venue: %w", err)
+	}
+	if len(bookings) > 0 {
+		return ErrVenueHasBookings
+	}
+
+	return s.remove(ctx, venue, domain.VenuePurged)
+}
+
+// remove deletes a stored venue outright and records the removal
+func (s *VenueService) remove(ctx context.Context, venue *domain.Venue, action domain.VenueChangeAction) error {
+	if err := s.repo.Delete(ctx, venue.ID); err != nil {
+		return err
+	}
+
+	s.recordHistory(ctx, venue, nil, action, 0)
+	return nil
+}
+
+// merge saves the survivor of a merge and deletes the merged venue in one
+// write that also leaves the redirect between them, then records both
+// changes
+func (s *VenueService) merge(ctx context.Context, survivor, merged *domain.Venue, redirect *domain.VenueRedirect) error {
+	survivor.Update()
+
+	var previous *domain.Venue
+	if s.geocoder != nil || s.history != nil {
+		var err error
+		previous, err = s.repo.GetByID(ctx, survivor.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if s.geocoder != nil {
+		if err := s.geocode(ctx, survivor, previous); err != nil {
+			return err
+		}
+	}
+	survivor.AssignRoomIDs()
+
+	if err := s.repo.Merge(ctx, survivor, merged, redirect); err != nil {
+		return err
+	}
+
+	s.recordHistory(ctx, previous, survivor, domain.VenueUpdated, 0)
+	s.recordHistory(ctx, merged, nil, domain.VenueDeleted, 0)
+	s.notifyListeners(ctx, survivor)
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+)
+
+func TestVenueService_DeleteAndReactivate(t *testing.T) {
+	venues := NewVenueService(repository.NewMockVenueRepository())
+	ctx := context.Background()
+
+	venue := newTestVenue("Bottom of the Hill", 37.7749, -122.4194)
+	venue.OwnerIDs = []string{"owner-1"}
+	_ = venues.Create(ctx, venue)
+
+	if _, err := venues.Deactivate(ctx, venue.ID, "user-2", domain.RoleContributor); !errors.Is(err, ErrVenueStatusForbidden) {
+		t.Fatalf("Deactivate() by a contributor error = %v, want ErrVenueStatusForbidden", err)
+	}
+	deactivated, err := venues.Deactivate(ctx, venue.ID, "owner-1", domain.RoleOwner)
+	if err != nil || deactivated.Active {
+		t.Fatalf("Deactivate() = active %v, %v; want inactive", deactivated != nil && deactivated.Active, err)
+	}
+
+	if err := venues.Delete(ctx, venue.ID, "admin-1", domain.RoleAdmin); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	var notFound *repository.VenueNotFoundError
+	if _, err := venues.GetByID(ctx, venue.ID); !errors.As(err, &notFound) {
+		t.Errorf("GetByID() of a deleted venue error = %v, want not found", err)
+	}
+	result, _ := venues.Search(ctx, &domain.VenueSearchCriteria{City: "San Francisco", State: "CA", Limit: 10})
+	if len(result.Venues) != 0 {
+		t.Errorf("Search() returned %d venues, want deleted venues hidden", len(result.Venues))
+	}
+	if _, err := venues.Deactivate(ctx, venue.ID, "admin-1", domain.RoleAdmin); !errors.As(err, &notFound) {
+		t.Errorf("Deactivate() of a deleted venue error = %v, want not found", err)
+	}
+
+	restored, err := venues.Reactivate(ctx, venue.ID, "admin-1", domain.RoleAdmin)
+	if err != nil {
+		t.Fatalf("Reactivate() error = %v", err)
+	}
+	if !restored.Active || restored.Deleted() {
+		t.Errorf("Reactivate() = active %v, deleted %v; want restored", restored.Active, restored.Deleted())
+	}
+
+	pending := newTestVenue("The Lost Church", 37.7749, -122.4194)
+	_ = venues.Submit(ctx, pending, "user-2")
+	if _, err := venues.Reactivate(ctx, pending.ID, "admin-1", domain.RoleAdmin); !errors.Is(err, ErrVenueNotListed) {
+		t.Errorf("Reactivate() of a pending submission error = %v, want ErrVenueNotListed", err)
+	}
+}
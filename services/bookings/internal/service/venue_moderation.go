@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+var (
+	// ErrNotPendingModeration is returned when approving or rejecting a
+	// venue that is not waiting for a decision
+	ErrNotPendingModeration = errors.New("venue is not awaiting moderation")
+	// ErrRejectionReasonRequired is returned when a rejection has no reason
+	ErrRejectionReasonRequired = errors.New("a reason is required to reject a venue")
+)
+
+// Submit saves a community submission in the pending state and runs the
+// automatic checks, recording what they find for the moderator. The venue
+// is not listed until it is approved.
+func (s *VenueService) Submit(ctx context.Context, venue *domain.Venue, submitter string) error {
+	venue.Submit(submitter)
+	if err := s.Create(ctx, venue); err != nil {
+		return err
+	}
+
+	issues := s.checkSubmission(ctx, venue)
+	if len(issues) == 0 {
+		return nil
+	}
+	venue.Moderation.Issues = issues
+	return s.Update(ctx, venue)
+}
+
+// checkSubmission runs the automatic moderation checks on a saved venue
+func (s *VenueService) checkSubmission(ctx context.Context, venue *domain.Venue) []domain.ModerationIssue {
+	issues := make([]domain.ModerationIssue, 0)
+
+	if s.dupes != nil {
+		candidates, err := s.dupes.FindDuplicates(ctx, venue)
+		if err != nil {
+			log.Printf("checking venue %s for duplicates: %v", venue.ID, err)
+		}
+		for _, c := range candidates {
+			issues = append(issues, domain.ModerationIssue{
+				Check:  domain.CheckDuplicate,
+				Detail: fmt.Sprintf("may duplicate venue %s (score %.2f)", c.OtherID, c.Score.Total),
+			})
+		}
+	}
+
+	if venue.HasFlag(domain.FlagLocationMismatch) {
+		issues = append(issues, domain.ModerationIssue{
+			Check:  domain.CheckLocationMismatch,
+			Detail: "coordinates are far from where the address geocodes to",
+		})
+	}
+
+	if words := venue.ProfaneWords(); len(words) > 0 {
+		issues = append(issues, domain.ModerationIssue{
+			Check:  domain.CheckProfanity,
+			Detail: "contains " + strings.Join(words, ", "),
+		})
+	}
+
+	if missing := venue.MissingFields(); len(missing) > 0 {
+		issues = append(issues, domain.ModerationIssue{
+			Check:  domain.CheckMissingFields,
+			Detail: "missing " + strings.Join(missing, ", "),
+		})
+	}
+
+	return issues
+}
+
+// ModerationQueue lists submissions in a moderation state, oldest first
+func (s *VenueService) ModerationQueue(ctx context.Context, status domain.ModerationStatus, limit int) ([]*domain.Venue, error) {
+	return s.repo.ListByModerationStatus(ctx, status, limit)
+}
+
+// Approve lists a pending or previously rejected submission
+func (s *VenueService) Approve(ctx context.Context, id, moderator, note string) (*domain.Venue, error) {
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if venue.ModerationStatus != domain.ModerationPending && venue.ModerationStatus != domain.ModerationRejected {
+		return nil, ErrNotPendingModeration
+	}
+
+	venue.Approve(moderator, note)
+	if err := s.Update(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+// Reject keeps a pending submission out of search, with a reason the
+// submitter can see
+func (s *VenueService) Reject(ctx context.Context, id, moderator, reason string) (*domain.Venue, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if venue.ModerationStatus != domain.ModerationPending {
+		return nil, ErrNotPendingModeration
+	}
+
+	venue.Reject(moderator, reason)
+	if err := s.Update(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+func hasIssue(venue *domain.Venue, check domain.ModerationCheck) bool {
+	for _, issue := range venue.Moderation.Issues {
+		if issue.Check == check {
+			return true
+		}
+	}
+	return false
+}
+
+func TestVenueService_Submit_RunsChecks(t *testing.T) {
+	f := newDedupFixture()
+	f.venues.SetDuplicateFinder(f.svc)
+	ctx := context.Background()
+
+	existing := newTestVenue("The Fillmore", 37.7840, -122.4330)
+	existing.Address.Street = "1805 Geary Blvd"
+	existing.Source = domain.SourceGooglePlaces
+	_ = f.venues.Create(ctx, existing)
+
+	submitted := newTestVenue("The Fillmore", 37.7841, -122.4331)
+	submitted.Address.Street = "1805 Geary Blvd"
+	submitted.Description = "Best shit venue in town"
+	if err := f.venues.Submit(ctx, submitted, "user-1"); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	stored, _ := f.venues.GetByID(ctx, submitted.ID)
+	if stored.ModerationStatus != domain.ModerationPending || stored.Active {
+		t.Errorf("Submit() status = %v, active = %v, want pending and inactive", stored.ModerationStatus, stored.Active)
+	}
+	if stored.Moderation.SubmittedBy != "user-1" {
+		t.Errorf("Submit() submitted by = %v, want user-1", stored.Moderation.SubmittedBy)
+	}
+	for _, check := range []domain.ModerationCheck{domain.CheckDuplicate, domain.CheckProfanity, domain.CheckMissingFields} {
+		if !hasIssue(stored, check) {
+			t.Errorf("Submit() issues = %+v, want %s", stored.Moderation.Issues, check)
+		}
+	}
+}
+
+func TestVenueService_Moderation_HidesUntilApproved(t *testing.T) {
+	venues := newDedupFixture().venues
+	ctx := context.Background()
+	criteria := &domain.VenueSearchCriteria{City: "San Francisco", State: "CA", Limit: 10}
+
+	submitted := newTestVenue("Neck of the Woods", 37.7749, -122.4194)
+	if err := venues.Submit(ctx, submitted, "user-1"); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	result, _ := venues.Search(ctx, criteria)
+	if result.Total != 0 {
+		t.Errorf("Search() found %d venues, want pending submission hidden", result.Total)
+	}
+
+	queue, _ := venues.ModerationQueue(ctx, domain.ModerationPending, 10)
+	if len(queue) != 1 || queue[0].ID != submitted.ID {
+		t.Fatalf("ModerationQueue() = %d venues, want the submission", len(queue))
+	}
+
+	if _, err := venues.Reject(ctx, submitted.ID, "mod-1", ""); !errors.Is(err, ErrRejectionReasonRequired) {
+		t.Errorf("Reject() without reason error = %v", err)
+	}
+	rejected, err := venues.Reject(ctx, submitted.ID, "mod-1", "closed permanently")
+	if err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if rejected.Moderation.Reason != "closed permanently" || rejected.Moderation.ReviewedBy != "mod-1" {
+		t.Errorf("Reject() review = %+v", rejected.Moderation)
+	}
+	if _, err := venues.Reject(ctx, submitted.ID, "mod-1", "again"); !errors.Is(err, ErrNotPendingModeration) {
+		t.Errorf("Reject() twice error = %v, want ErrNotPendingModeration", err)
+	}
+
+	approved, err := venues.Approve(ctx, submitted.ID, "mod-2", "reopened")
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if !approved.Active || approved.ModerationStatus != domain.ModerationApproved {
+		t.Errorf("Approve() active = %v, status = %v", approved.Active, approved.ModerationStatus)
+	}
+
+	result, _ = venues.Search(ctx, criteria)
+	if result.Total != 1 {
+		t.Errorf("Search() found %d venues after approval, want 1", result.Total)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/mergepatch"
+)
+
+// PatchForbiddenError is returned when a patch changes fields the caller's
+// role may not write
+type PatchForbiddenError struct {
+	Role   domain.Role
+	Fields []string
+}
+
+func (e *PatchForbiddenError) Error() string {
+	return fmt.Sprintf("role %s may not change %s", e.Role, strings.Join(e.Fields, ", "))
+}
+
+// InvalidPatchError is returned when a patch is malformed, names unknown
+// fields or leaves the venue invalid
+type InvalidPatchError struct {
+	Err error
+}
+
+func (e *InvalidPatchError) Error() string {
+	return "invalid patch: " + e.Err.Error()
+}
+
+func (e *InvalidPatchError) Unwrap() error {
+	return e.Err
+}
+
+// Patch applies a JSON merge patch (RFC 7396) to a venue and saves it.
+// Members set to null are cleared. Only fields the role may write can
+// appear in the patch, and changed fields are attributed to actor.
+func (s *VenueService) Patch(ctx context.Context, venue *domain.Venue, patch []byte, role domain.Role, actor string) (*domain.Venue, error) {
+	members, err := mergepatch.Parse(patch)
+	if err != nil {
+		return nil, &InvalidPatchError{Err: err}
+	}
+
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	unknown, forbidden := role.CheckPatchFields(keys)
+	if len(unknown) > 0 {
+		return nil, &InvalidPatchError{Err: fmt.Errorf("unknown fields %s", strings.Join(unknown, ", "))}
+	}
+	if len(forbidden) > 0 {
+		return nil, &PatchForbiddenError{Role: role, Fields: forbidden}
+	}
+
+	doc, err := json.Marshal(venue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal venue: %w", err)
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return nil, &InvalidPatchError{Err: err}
+	}
+
+	var patched domain.Venue
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		return nil, &InvalidPatchError{Err: err}
+	}
+
+	// Provenance, review totals and photo storage keys are not part of the
+	// JSON model, and the gallery is read-only anyway
+	original := venue.Clone()
+	patched.Provenance = original.Provenance
+	patched.ReviewTotals = venue.ReviewTotals
+	patched.Gallery = original.Gallery
+
+	// The geohash is derived, so recompute it only when the point moved
+	if patched.Location.Latitude != venue.Location.Latitude ||
+		patched.Location.Longitude != venue.Location.Longitude {
+		patched.Location.Geohash = ""
+	} else {
+		patched.Location.Geohash = venue.Location.Geohash
+	}
+
+	// Cleared lists are stored empty, as NewVenue creates them
+	if patched.Genres == nil {
+		patched.Genres = []string{}
+	}
+	if patched.Amenities == nil {
+		patched.Amenities = []domain.Amenity{}
+	}
+	if patched.Photos == nil {
+		patched.Photos = []string{}
+	}
+
+	if err := patched.Validate(); err != nil {
+		return nil, &InvalidPatchError{Err: err}
+	}
+
+	source := domain.SourceUserSubmitted
+	if role == domain.RoleAdmin {
+		source = domain.SourceManual
+	}
+	patched.RecordChanges(venue, source, actor)
+
+	if err := s.Update(ctx, &patched); err != nil {
+		return nil, err
+	}
+	return &patched, nil
+}
@@ -2,16 +2,69 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"sort"
+	"time"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/geocode"
 	"github.com/crowdunlocked/services/bookings/internal/repository"
 )
 
+// VenueListener is notified after a venue has been created or updated
+type VenueListener interface {
+	VenueChanged(ctx context.Context, venue *domain.Venue)
+}
+
+// DuplicateFinder finds existing venues that a venue probably duplicates
+type DuplicateFinder interface {
+	FindDuplicates(ctx context.Context, venue *domain.Venue) ([]*domain.DuplicateCandidate, error)
+}
+
+// RedirectResolver looks up where a merged venue ID now points
+type RedirectResolver interface {
+	GetRedirect(ctx context.Context, fromID string) (*domain.VenueRedirect, error)
+}
+
+// maxRedirectHops bounds how many merge redirects GetByID follows
+const maxRedirectHops = 5
+
+// locationMismatchKm is how far coordinates may sit from the geocoded
+// address before the venue is flagged, by geocoding accuracy
+var locationMismatchKm = map[geocode.Accuracy]float64{
+	geocode.AccuracyAddress: 2,
+	geocode.AccuracyCity:    50,
+}
+
+// LocationUnresolvedError is returned when a venue has no coordinates and
+// its address cannot be geocoded
+type LocationUnresolvedError struct {
+	Address domain.Address
+	Err     error
+}
+
+func (e *LocationUnresolvedError) Error() string {
+	if e.Err == nil {
+		return "venue has no location"
+	}
+	return fmt.Sprintf("could not locate address %q: %v", geocode.FormatAddress(e.Address), e.Err)
+}
+
+func (e *LocationUnresolvedError) Unwrap() error {
+	return e.Err
+}
+
 // VenueService provides business logic for venue operations
 type VenueService struct {
-	repo repository.VenueRepository
+	repo      repository.VenueRepository
+	listeners []VenueListener
+	redirects RedirectResolver
+	geocoder  geocode.Geocoder
+	history   repository.VenueHistoryRepository
+	dupes     DuplicateFinder
+	bookings  repository.BookingRepository
 }
 
 // NewVenueService creates a new venue service
@@ -21,8 +74,93 @@ func NewVenueService(repo repository.VenueRepository) *VenueService {
 	}
 }
 
+// AddListener registers a listener for venue changes
+func (s *VenueService) AddListener(listener VenueListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// SetRedirectResolver makes GetByID follow redirects left behind by venue merges
+func (s *VenueService) SetRedirectResolver(redirects RedirectResolver) {
+	s.redirects = redirects
+}
+
+// SetGeocoder makes Create and Update geocode venue addresses and coordinates
+func (s *VenueService) SetGeocoder(geocoder geocode.Geocoder) {
+	s.geocoder = geocoder
+}
+
+// SetHistory makes every create, update and delete append to the venue's
+// change history
+func (s *VenueService) SetHistory(history repository.VenueHistoryRepository) {
+	s.history = history
+}
+
+// SetDuplicateFinder makes Submit check community submissions for duplicates
+func (s *VenueService) SetDuplicateFinder(dupes DuplicateFinder) {
+	s.dupes = dupes
+}
+
+// SetBookings lets Purge check that no booking still references a venue, and
+// Search leave out rooms booked on the requested date
+func (s *VenueService) SetBookings(bookings repository.BookingRepository) {
+	s.bookings = bookings
+}
+
+// notifyListeners tells every registered listener that a venue changed
+func (s *VenueService) notifyListeners(ctx context.Context, venue *domain.Venue) {
+	for _, listener := range s.listeners {
+		listener.VenueChanged(ctx, venue)
+	}
+}
+
+// recordSaved records a change saved outside Update, such as a rating
+// written together with a review, and tells listeners as Update does
+func (s *VenueService) recordSaved(ctx context.Context, previous, venue *domain.Venue) {
+	s.recordHistory(ctx, previous, venue, domain.VenueUpdated, 0)
+	s.notifyListeners(ctx, venue)
+}
+
+// ValidateCriteria checks that criteria include a primary search dimension
+func (s *VenueService) ValidateCriteria(criteria *domain.VenueSearchCriteria) error {
+	hasLocation := criteria.Location != nil && criteria.RadiusKm > 0
+	hasCity := criteria.City != "" && criteria.State != ""
+	if !hasLocation && !hasCity && len(criteria.VenueTypes) == 0 {
+		return fmt.Errorf("search criteria must include location, city, or venue type")
+	}
+	return nil
+}
+
+// Matches reports whether a single venue satisfies the search criteria
+func (s *VenueService) Matches(venue *domain.Venue, criteria *domain.VenueSearchCriteria) bool {
+	if criteria.Location != nil && criteria.RadiusKm > 0 {
+		distance := domain.CalculateDistance(
+			criteria.Location.Latitude,
+			criteria.Location.Longitude,
+			venue.Location.Latitude,
+			venue.Location.Longitude,
+		)
+		if distance > criteria.RadiusKm {
+			return false
+		}
+	} else if criteria.City != "" && criteria.State != "" {
+		if venue.Address.City != criteria.City || venue.Address.State != criteria.State {
+			return false
+		}
+	}
+
+	if len(criteria.VenueTypes) > 0 && !s.hasAnyVenueType(venue.VenueTypes, criteria.VenueTypes) {
+		return false
+	}
+
+	return s.matchesFilters(venue, criteria)
+}
+
 // Search searches for venues based on criteria
 func (s *VenueService) Search(ctx context.Context, criteria *domain.VenueSearchCriteria) (*domain.VenueSearchResult, error) {
+	if err := s.ValidateCriteria(criteria); err != nil {
+		return nil, err
+	}
+
 	var venues []*domain.Venue
 	var err error
 
@@ -33,11 +171,9 @@ func (s *VenueService) Search(ctx context.Context, criteria *domain.VenueSearchC
 	} else if criteria.City != "" && criteria.State != "" {
 		// City search
 		venues, err = s.repo.SearchByCity(ctx, criteria.City, criteria.State, criteria.Limit*2) // Get more for filtering
-	} else if len(criteria.VenueTypes) > 0 {
+	} else {
 		// Type search
 		venues, err = s.searchByTypes(ctx, criteria)
-	} else {
-		return nil, fmt.Errorf("search criteria must include location, city, or venue type")
 	}
 
 	if err != nil {
@@ -49,6 +185,17 @@ func (s *VenueService) Search(ctx context.Context, criteria *domain.VenueSearchC
 
 	// Calculate distances if location provided
 	venuesWithDistance := s.calculateDistances(venues, criteria.Location)
+	for _, v := range venuesWithDistance {
+		v.MatchingRooms = s.matchingRoomIDs(v.Venue, criteria)
+	}
+
+	// Rooms booked on the requested date are not on offer
+	if criteria.Date != nil {
+		venuesWithDistance, err = s.filterBooked(ctx, venuesWithDistance, *criteria.Date)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Filter by radius if location provided
 	if criteria.Location != nil && criteria.RadiusKm > 0 {
@@ -81,8 +228,13 @@ func (s *VenueService) Search(ctx context.Context, criteria *domain.VenueSearchC
 	}, nil
 }
 
-// searchByLocation performs geospatial search using geohash
+// searchByLocation performs geospatial search by distance when the
+// repository supports it, and using geohash otherwise
 func (s *VenueService) searchByLocation(ctx context.Context, criteria *domain.VenueSearchCriteria) ([]*domain.Venue, error) {
+	if nearby, ok := s.repo.(repository.NearbySearcher); ok {
+		return nearby.SearchNearby(ctx, criteria.Location.Latitude, criteria.Location.Longitude, criteria.RadiusKm, criteria.Limit*3)
+	}
+
 	// Get geohash prefixes for the search area
 	geohashPrefixes := domain.GetGeohashPrefixes(
 		criteria.Location.Latitude,
@@ -132,11 +284,14 @@ func (s *VenueService) applyFilters(venues []*domain.Venue, criteria *domain.Ven
 
 // matchesFilters checks if a venue matches all filter criteria
 func (s *VenueService) matchesFilters(venue *domain.Venue, criteria *domain.VenueSearchCriteria) bool {
-	// Capacity filter
-	if criteria.MinCapacity > 0 && venue.Capacity < criteria.MinCapacity {
+	// Submissions awaiting or failing moderation and deleted venues are
+	// never listed
+	if !venue.Listed() || venue.Deleted() {
 		return false
 	}
-	if criteria.MaxCapacity > 0 && venue.Capacity > criteria.MaxCapacity {
+
+	// Capacity, amenity and payment filters must all hold for one room
+	if len(s.matchingSpaces(venue, criteria)) == 0 {
 		return false
 	}
 
@@ -145,37 +300,111 @@ func (s *VenueService) matchesFilters(venue *domain.Venue, criteria *domain.Venu
 		return false
 	}
 
-	// Amenities filter
-	if len(criteria.Amenities) > 0 && !s.hasAllAmenities(venue.Amenities, criteria.Amenities) {
+	// Rating filter
+	if criteria.MinRating > 0 && venue.Rating < criteria.MinRating {
 		return false
 	}
 
-	// Payment filter
-	if criteria.MinPay > 0 && venue.PayRange != nil && venue.PayRange.Max < criteria.MinPay {
+	// Verified filter
+	if criteria.VerifiedOnly && !venue.Verified {
 		return false
 	}
-	if criteria.MaxPay > 0 && venue.PayRange != nil && venue.PayRange.Min > criteria.MaxPay {
+
+	// Active filter
+	if criteria.ActiveOnly && !venue.Active {
 		return false
 	}
 
-	// Rating filter
-	if criteria.MinRating > 0 && venue.Rating < criteria.MinRating {
+	return true
+}
+
+// matchingSpaces returns the spaces at a venue that meet the capacity,
+// amenity and payment filters; see Venue.Spaces
+func (s *VenueService) matchingSpaces(venue *domain.Venue, criteria *domain.VenueSearchCriteria) []domain.Room {
+	matching := make([]domain.Room, 0)
+	for _, space := range venue.Spaces() {
+		if s.spaceMatches(space, criteria) {
+			matching = append(matching, space)
+		}
+	}
+	return matching
+}
+
+// spaceMatches checks a single room, or a venue without rooms, against the
+// capacity, amenity and payment filters
+func (s *VenueService) spaceMatches(space domain.Room, criteria *domain.VenueSearchCriteria) bool {
+	// Capacity filter
+	if criteria.MinCapacity > 0 && space.Capacity < criteria.MinCapacity {
+		return false
+	}
+	if criteria.MaxCapacity > 0 && space.Capacity > criteria.MaxCapacity {
 		return false
 	}
 
-	// Verified filter
-	if criteria.VerifiedOnly && !venue.Verified {
+	// Amenities filter
+	if len(criteria.Amenities) > 0 && !s.hasAllAmenities(space.Amenities, criteria.Amenities) {
 		return false
 	}
 
-	// Active filter
-	if criteria.ActiveOnly && !venue.Active {
+	// Payment filter
+	if criteria.MinPay > 0 && space.PayRange != nil && space.PayRange.Max < criteria.MinPay {
+		return false
+	}
+	if criteria.MaxPay > 0 && space.PayRange != nil && space.PayRange.Min > criteria.MaxPay {
 		return false
 	}
 
 	return true
 }
 
+// matchingRoomIDs lists the rooms that meet the filters at a venue with rooms
+func (s *VenueService) matchingRoomIDs(venue *domain.Venue, criteria *domain.VenueSearchCriteria) []string {
+	if len(venue.Rooms) == 0 {
+		return nil
+	}
+	spaces := s.matchingSpaces(venue, criteria)
+	ids := make([]string, len(spaces))
+	for i, space := range spaces {
+		ids[i] = space.ID
+	}
+	return ids
+}
+
+// filterBooked drops the matching rooms booked on the date, and the venues
+// left with nothing free; see domain.RoomBooked
+func (s *VenueService) filterBooked(ctx context.Context, venues []*domain.VenueWithDistance, date time.Time) ([]*domain.VenueWithDistance, error) {
+	if s.bookings == nil {
+		return nil, errors.New("cannot search by date without a booking repository")
+	}
+
+	free := make([]*domain.VenueWithDistance, 0, len(venues))
+	for _, v := range venues {
+		bookings, err := s.bookings.ListByVenue(ctx, v.Venue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bookings for venue: %w", err)
+		}
+
+		if len(v.Venue.Rooms) == 0 {
+			if !domain.RoomBooked(bookings, "", date) {
+				free = append(free, v)
+			}
+			continue
+		}
+
+		rooms := make([]string, 0, len(v.MatchingRooms))
+		for _, id := range v.MatchingRooms {
+			if !domain.RoomBooked(bookings, id, date) {
+				rooms = append(rooms, id)
+			}
+		}
+		if len(rooms) > 0 {
+			v.MatchingRooms = rooms
+			free = append(free, v)
+		}
+	}
+	return free, nil
+}
+
 // hasAnyGenre checks if venue has any of the requested genres
 func (s *VenueService) hasAnyGenre(venueGenres, requestedGenres []string) bool {
 	genreMap := make(map[string]bool)
@@ -192,6 +421,19 @@ func (s *VenueService) hasAnyGenre(venueGenres, requestedGenres []string) bool {
 	return false
 }
 
+// hasAnyVenueType checks if venue has any of the requested venue types
+func (s *VenueService) hasAnyVenueType(venueTypes, requestedTypes []domain.VenueType) bool {
+	for _, vt := range venueTypes {
+		for _, rt := range requestedTypes {
+			if vt == rt {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // hasAllAmenities checks if venue has all requested amenities
 func (s *VenueService) hasAllAmenities(venueAmenities, requestedAmenities []domain.Amenity) bool {
 	amenityMap := make(map[domain.Amenity]bool)
@@ -289,13 +531,44 @@ func (s *VenueService) sortResults(venues []*domain.VenueWithDistance, criteria
 	})
 }
 
-// GetByID retrieves a venue by ID
+// GetByID retrieves a venue by ID, following redirects for venues merged
+// into another. Deleted venues are not found.
 func (s *VenueService) GetByID(ctx context.Context, id string) (*domain.Venue, error) {
-	return s.repo.GetByID(ctx, id)
+	venue, err := s.repo.GetByID(ctx, id)
+	if err != nil && s.redirects != nil {
+		var notFound *repository.VenueNotFoundError
+		for hops := 0; hops < maxRedirectHops && errors.As(err, &notFound); hops++ {
+			redirect, redirectErr := s.redirects.GetRedirect(ctx, id)
+			if redirectErr != nil {
+				return nil, redirectErr
+			}
+			if redirect == nil {
+				break
+			}
+
+			id = redirect.ToID
+			venue, err = s.repo.GetByID(ctx, id)
+		}
+	}
+
+	if err == nil && venue.Deleted() {
+		return nil, &repository.VenueNotFoundError{}
+	}
+	return venue, err
 }
 
 // Create creates a new venue
 func (s *VenueService) Create(ctx context.Context, venue *domain.Venue) error {
+	return s.create(ctx, venue, 0)
+}
+
+// create saves a new venue; revertedTo is the version it restores, if any
+func (s *VenueService) create(ctx context.Context, venue *domain.Venue, revertedTo int) error {
+	if err := s.geocode(ctx, venue, nil); err != nil {
+		return err
+	}
+	venue.AssignRoomIDs()
+
 	// Generate geohash if not provided
 	if venue.Location.Geohash == "" {
 		venue.Location.Geohash = domain.EncodeGeohash(
@@ -305,13 +578,40 @@ func (s *VenueService) Create(ctx context.Context, venue *domain.Venue) error {
 		)
 	}
 
-	return s.repo.Create(ctx, venue)
+	if err := s.repo.Create(ctx, venue); err != nil {
+		return err
+	}
+
+	s.recordHistory(ctx, nil, venue, domain.VenueCreated, revertedTo)
+	s.notifyListeners(ctx, venue)
+	return nil
 }
 
 // Update updates an existing venue
 func (s *VenueService) Update(ctx context.Context, venue *domain.Venue) error {
+	return s.update(ctx, venue, 0)
+}
+
+// update saves a venue; revertedTo is the version it restores, if any
+func (s *VenueService) update(ctx context.Context, venue *domain.Venue, revertedTo int) error {
 	venue.Update()
 
+	var previous *domain.Venue
+	if s.geocoder != nil || s.history != nil {
+		var err error
+		previous, err = s.repo.GetByID(ctx, venue.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if s.geocoder != nil {
+		if err := s.geocode(ctx, venue, previous); err != nil {
+			return err
+		}
+	}
+
+	venue.AssignRoomIDs()
+
 	// Update geohash if location changed
 	if venue.Location.Geohash == "" {
 		venue.Location.Geohash = domain.EncodeGeohash(
@@ -321,15 +621,80 @@ func (s *VenueService) Update(ctx context.Context, venue *domain.Venue) error {
 		)
 	}
 
-	return s.repo.Update(ctx, venue)
-}
+	if err := s.repo.Update(ctx, venue); err != nil {
+		return err
+	}
 
-// Delete deletes a venue
-func (s *VenueService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	s.recordHistory(ctx, previous, venue, domain.VenueUpdated, revertedTo)
+	s.notifyListeners(ctx, venue)
+	return nil
 }
 
 // GetByExternalID retrieves a venue by external source ID
 func (s *VenueService) GetByExternalID(ctx context.Context, source domain.DataSource, externalID string) (*domain.Venue, error) {
 	return s.repo.GetByExternalID(ctx, source, externalID)
 }
+
+// ScanAll calls fn for every stored venue
+func (s *VenueService) ScanAll(ctx context.Context, fn func(*domain.Venue) error) error {
+	return s.repo.ScanAll(ctx, fn)
+}
+
+// geocode fills in missing coordinates from the address, fills missing
+// address parts from the coordinates, and flags venues whose coordinates are
+// far from their stated address. previous is the stored venue on update;
+// when neither location nor address changed nothing is looked up. Lookup
+// failures other than an unlocatable venue are logged, not returned.
+func (s *VenueService) geocode(ctx context.Context, venue, previous *domain.Venue) error {
+	if !venue.HasLocation() {
+		if s.geocoder == nil {
+			return &LocationUnresolvedError{Address: venue.Address}
+		}
+		result, err := s.geocoder.Forward(ctx, venue.Address)
+		if err != nil {
+			return &LocationUnresolvedError{Address: venue.Address, Err: err}
+		}
+		venue.Location = result.Location
+		venue.Address.FillFrom(result.Address)
+		venue.SetFlag(domain.FlagLocationMismatch, false)
+		return nil
+	}
+
+	if s.geocoder == nil {
+		return nil
+	}
+	if previous != nil &&
+		previous.Location.Latitude == venue.Location.Latitude &&
+		previous.Location.Longitude == venue.Location.Longitude &&
+		previous.Address == venue.Address {
+		return nil
+	}
+
+	stated := venue.Address.Street != "" || venue.Address.City != ""
+
+	if venue.Address.City == "" || venue.Address.State == "" || venue.Address.Country == "" {
+		result, err := s.geocoder.Reverse(ctx, venue.Location)
+		if err != nil {
+			log.Printf("reverse geocoding venue %s: %v", venue.ID, err)
+		} else {
+			venue.Address.FillFrom(result.Address)
+		}
+	}
+
+	if !stated {
+		return nil
+	}
+
+	result, err := s.geocoder.Forward(ctx, venue.Address)
+	if err != nil {
+		log.Printf("checking location of venue %s: %v", venue.ID, err)
+		return nil
+	}
+	distance := domain.CalculateDistance(
+		venue.Location.Latitude, venue.Location.Longitude,
+		result.Location.Latitude, result.Location.Longitude,
+	)
+	venue.SetFlag(domain.FlagLocationMismatch, distance > locationMismatchKm[result.Accuracy])
+
+	return nil
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/geocode"
 	"github.com/crowdunlocked/services/bookings/internal/repository"
 )
 
@@ -465,7 +467,7 @@ func TestVenueService_Delete(t *testing.T) {
 	)
 	_ = service.Create(ctx, venue)
 
-	err := service.Delete(ctx, venue.ID)
+	err := service.Delete(ctx, venue.ID, "user-1", domain.RoleAdmin)
 	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
@@ -475,3 +477,285 @@ func TestVenueService_Delete(t *testing.T) {
 		t.Error("GetByID() should return error after deletion")
 	}
 }
+
+// stubGeocoder answers every forward lookup with one result and every
+// reverse lookup with another
+type stubGeocoder struct {
+	forward  *geocode.Result
+	reverse  *geocode.Result
+	forwards int
+}
+
+func (g *stubGeocoder) Forward(ctx context.Context, address domain.Address) (*geocode.Result, error) {
+	g.forwards++
+	if g.forward == nil {
+		return nil, &geocode.NotFoundError{Query: geocode.FormatAddress(address)}
+	}
+	return g.forward, nil
+}
+
+func (g *stubGeocoder) Reverse(ctx context.Context, point domain.GeoPoint) (*geocode.Result, error) {
+	if g.reverse == nil {
+		return nil, &geocode.NotFoundError{}
+	}
+	return g.reverse, nil
+}
+
+func TestVenueService_Create_GeocodesMissingLocation(t *testing.T) {
+	service := NewVenueService(repository.NewMockVenueRepository())
+	service.SetGeocoder(&stubGeocoder{forward: &geocode.Result{
+		Location: domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376},
+		Address:  domain.Address{PostalCode: "94117", Country: "US"},
+		Accuracy: geocode.AccuracyAddress,
+	}})
+
+	venue := domain.NewVenue(
+		"The Independent",
+		domain.GeoPoint{},
+		domain.Address{Street: "628 Divisadero St", City: "San Francisco", State: "CA"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	if err := service.Create(context.Background(), venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if venue.Location.Latitude != 37.7755 || venue.Location.Geohash == "" {
+		t.Errorf("Create() location = %+v, want geocoded point with geohash", venue.Location)
+	}
+	if venue.Address.PostalCode != "94117" || venue.Address.Street != "628 Divisadero St" {
+		t.Errorf("Create() address = %+v, want gaps filled and street kept", venue.Address)
+	}
+}
+
+func TestVenueService_Create_UnresolvedLocation(t *testing.T) {
+	service := NewVenueService(repository.NewMockVenueRepository())
+	service.SetGeocoder(&stubGeocoder{})
+
+	venue := domain.NewVenue("Nowhere", domain.GeoPoint{}, domain.Address{City: "Atlantis"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceUserSubmitted)
+	err := service.Create(context.Background(), venue)
+	if _, ok := err.(*LocationUnresolvedError); !ok {
+		t.Errorf("Create() error = %v, want LocationUnresolvedError", err)
+	}
+}
+
+func TestVenueService_Create_ReverseGeocodesAddress(t *testing.T) {
+	service := NewVenueService(repository.NewMockVenueRepository())
+	service.SetGeocoder(&stubGeocoder{reverse: &geocode.Result{
+		Address:  domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		Accuracy: geocode.AccuracyCity,
+	}})
+
+	venue := domain.NewVenue("Pop-Up", domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376}, domain.Address{}, []domain.VenueType{domain.VenueTypeOther}, domain.SourceUserSubmitted)
+	if err := service.Create(context.Background(), venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if venue.Address.City != "San Francisco" || venue.Address.Country != "US" {
+		t.Errorf("Create() address = %+v, want reverse geocoded", venue.Address)
+	}
+	if venue.HasFlag(domain.FlagLocationMismatch) {
+		t.Error("Create() should not flag a venue whose address came from its coordinates")
+	}
+}
+
+func TestVenueService_Update_FlagsLocationMismatch(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	service := NewVenueService(repo)
+	geocoder := &stubGeocoder{forward: &geocode.Result{
+		Location: domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376},
+		Accuracy: geocode.AccuracyAddress,
+	}}
+	service.SetGeocoder(geocoder)
+	ctx := context.Background()
+
+	venue := domain.NewVenue(
+		"The Independent",
+		domain.GeoPoint{Latitude: 37.7755, Longitude: -122.4376},
+		domain.Address{Street: "628 Divisadero St", City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	if err := service.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if venue.HasFlag(domain.FlagLocationMismatch) {
+		t.Fatal("Create() flagged a venue at its address")
+	}
+
+	// Coordinates moved across the bay; the address did not
+	moved := *venue
+	moved.Location = domain.GeoPoint{Latitude: 37.8044, Longitude: -122.2712}
+	if err := service.Update(ctx, &moved); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if !moved.HasFlag(domain.FlagLocationMismatch) {
+		t.Error("Update() should flag coordinates far from the address")
+	}
+
+	// Saving again without changes does not geocode
+	before := geocoder.forwards
+	again := moved
+	if err := service.Update(ctx, &again); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if geocoder.forwards != before {
+		t.Error("Update() should skip geocoding when location and address are unchanged")
+	}
+}
+
+func TestVenueService_Search_MatchesRooms(t *testing.T) {
+	repo := repository.NewMockVenueRepository()
+	service := NewVenueService(repo)
+	ctx := context.Background()
+
+	// The hall's rooms are big or have a green room, never both
+	hall := newTestVenue("Hall", 37.7749, -122.4194)
+	hall.Capacity = 1000
+	hall.Amenities = []domain.Amenity{domain.AmenityParking}
+	mainRoom := domain.NewRoom("Main Room", 800)
+	sideStage := domain.NewRoom("Side Stage", 150)
+	sideStage.Amenities = []domain.Amenity{domain.AmenityGreenRoom}
+	hall.Rooms = []domain.Room{*mainRoom, *sideStage}
+	club := newTestVenue("Club", 37.7749, -122.4194)
+	club.Capacity = 600
+	club.Amenities = []domain.Amenity{domain.AmenityGreenRoom, domain.AmenityParking}
+	_ = service.Create(ctx, hall)
+	_ = service.Create(ctx, club)
+
+	search := func(criteria domain.VenueSearchCriteria) map[string][]string {
+		criteria.City, criteria.State, criteria.Limit = "San Francisco", "CA", 10
+		result, err := service.Search(ctx, &criteria)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		found := make(map[string][]string)
+		for _, v := range result.Venues {
+			found[v.Name] = v.MatchingRooms
+		}
+		return found
+	}
+
+	found := search(domain.VenueSearchCriteria{MinCapacity: 500, Amenities: []domain.Amenity{domain.AmenityGreenRoom}})
+	if _, ok := found["Hall"]; ok || len(found) != 1 {
+		t.Errorf("Search() for a big room with a green room = %v, want only Club", found)
+	}
+
+	found = search(domain.VenueSearchCriteria{MaxCapacity: 200, Amenities: []domain.Amenity{domain.AmenityParking, domain.AmenityGreenRoom}})
+	if rooms := found["Hall"]; len(rooms) != 1 || rooms[0] != sideStage.ID {
+		t.Errorf("Search() matching rooms = %v, want the side stage", rooms)
+	}
+	if _, ok := found["Club"]; ok {
+		t.Errorf("Search() returned Club, whose only room is too big")
+	}
+}
+
+func TestVenueService_Search_SkipsBookedRooms(t *testing.T) {
+	bookings := repository.NewMockBookingRepository()
+	service := NewVenueService(repository.NewMockVenueRepository())
+	service.SetBookings(bookings)
+	ctx := context.Background()
+
+	hall := newTestVenue("Hall", 37.7749, -122.4194)
+	mainRoom := domain.NewRoom("Main Room", 800)
+	sideStage := domain.NewRoom("Side Stage", 150)
+	hall.Rooms = []domain.Room{*mainRoom, *sideStage}
+	club := newTestVenue("Club", 37.7749, -122.4194)
+	_ = service.Create(ctx, hall)
+	_ = service.Create(ctx, club)
+
+	date := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+	book := func(venueID, roomID string) *domain.Booking {
+		booking := domain.NewBooking("artist-1", venueID, date.Add(20*time.Hour), 500)
+		booking.RoomID = roomID
+		_ = bookings.Create(ctx, booking)
+		return booking
+	}
+	book(hall.ID, mainRoom.ID)
+	book(club.ID, "")
+
+	search := func(date *time.Time) map[string][]string {
+		criteria := &domain.VenueSearchCriteria{City: "San Francisco", State: "CA", Limit: 10, Date: date}
+		result, err := service.Search(ctx, criteria)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		found := make(map[string][]string)
+		for _, v := range result.Venues {
+			found[v.Name] = v.MatchingRooms
+		}
+		return found
+	}
+
+	found := search(&date)
+	if rooms, ok := found["Hall"]; !ok || len(rooms) != 1 || rooms[0] != sideStage.ID {
+		t.Errorf("Search() Hall rooms = %v, want only the side stage", rooms)
+	}
+	if _, ok := found["Club"]; ok {
+		t.Error("Search() returned Club, which is booked that day")
+	}
+
+	nextDay := date.Add(24 * time.Hour)
+	if found := search(&nextDay); len(found) != 2 || len(found["Hall"]) != 2 {
+		t.Errorf("Search() the next day = %v, want both venues and both rooms", found)
+	}
+	if found := search(nil); len(found) != 2 {
+		t.Errorf("Search() without a date = %v, want both venues", found)
+	}
+}
+
+// nearbyVenueRepository answers nearby searches itself, like the PostgreSQL
+// store, and records the radius asked for
+type nearbyVenueRepository struct {
+	*repository.MockVenueRepository
+	radiusKm float64
+}
+
+func (r *nearbyVenueRepository) SearchNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*domain.Venue, error) {
+	r.radiusKm = radiusKm
+	return r.MockVenueRepository.SearchByCity(ctx, "San Francisco", "CA", limit)
+}
+
+func TestVenueService_Search_UsesNearbySearcher(t *testing.T) {
+	repo := &nearbyVenueRepository{MockVenueRepository: repository.NewMockVenueRepository()}
+	service := NewVenueService(repo)
+	ctx := context.Background()
+
+	// No geohash, so only a nearby search can find the venue
+	venue := domain.NewVenue(
+		"The Chapel",
+		domain.GeoPoint{Latitude: 37.7604, Longitude: -122.4212},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+	_ = repo.Create(ctx, venue)
+
+	criteria := &domain.VenueSearchCriteria{
+		Location: &domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194},
+		RadiusKm: 5.0,
+		Limit:    10,
+	}
+	result, err := service.Search(ctx, criteria)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if repo.radiusKm != 5.0 {
+		t.Errorf("SearchNearby() radius = %v, want 5", repo.radiusKm)
+	}
+	if len(result.Venues) != 1 || result.Venues[0].DistanceKm <= 0 {
+		t.Errorf("Search() returned %+v, want The Chapel with its distance", result.Venues)
+	}
+}
+
+// newTestVenue builds a user-submitted San Francisco club at the given
+// coordinates, the fixture shared by the service tests
+func newTestVenue(name string, lat, lng float64) *domain.Venue {
+	return domain.NewVenue(
+		name,
+		domain.GeoPoint{Latitude: lat, Longitude: lng, Geohash: domain.EncodeGeohash(lat, lng, 6)},
+		domain.Address{City: "San Francisco", State: "CA", Country: "US"},
+		[]domain.VenueType{domain.VenueTypeClub},
+		domain.SourceUserSubmitted,
+	)
+}
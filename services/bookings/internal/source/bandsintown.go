@@ -0,0 +1,222 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const (
+	// DefaultBandsintownURL is the Bandsintown API endpoint
+	DefaultBandsintownURL = "https://rest.bandsintown.com"
+	// BandsintownRequestsPerSecond is a conservative limit; Bandsintown does
+	// not publish one
+	BandsintownRequestsPerSecond = 2
+)
+
+// BandsintownSource looks venues up in the Bandsintown API
+type BandsintownSource struct {
+	baseURL string
+	appID   string
+	client  *client
+}
+
+// NewBandsintownSource creates a Bandsintown connector. An empty baseURL
+// uses the public endpoint and a nil httpClient a default with a timeout.
+func NewBandsintownSource(baseURL, appID string, httpClient *http.Client) *BandsintownSource {
+	if baseURL == "" {
+		baseURL = DefaultBandsintownURL
+	}
+	return &BandsintownSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		appID:   appID,
+		client:  newClient(httpClient, NewLimiter(BandsintownRequestsPerSecond)),
+	}
+}
+
+// Source returns domain.SourceBandsintown
+func (s *BandsintownSource) Source() domain.DataSource {
+	return domain.SourceBandsintown
+}
+
+// BandsintownVenue is a Bandsintown venue resource. Coordinates arrive as
+// strings.
+type BandsintownVenue struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Latitude      string `json:"latitude"`
+	Longitude     string `json:"longitude"`
+	StreetAddress string `json:"street_address"`
+	City          string `json:"city"`
+	Region        string `json:"region"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+}
+
+// SearchByName runs a Bandsintown venue search near a point
+func (s *BandsintownSource) SearchByName(ctx context.Context, name string, near domain.GeoPoint) ([]*domain.Venue, error) {
+	query := url.Values{
+		"query":     {name},
+		"latitude":  {strconv.FormatFloat(near.Latitude, 'f', 6, 64)},
+		"longitude": {strconv.FormatFloat(near.Longitude, 'f', 6, 64)},
+	}
+
+	var result []BandsintownVenue
+	found, err := s.get(ctx, "/venues/search", query, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search bandsintown venues: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	venues := make([]*domain.Venue, 0, len(result))
+	for i := range result {
+		venue, ok := MapBandsintownVenue(&result[i])
+		if !ok {
+			continue // Unlocated venues can never be matched by distance
+		}
+		venues = append(venues, venue)
+	}
+	sortByDistance(venues, near)
+
+	return venues, nil
+}
+
+// FetchDetails loads a Bandsintown venue by ID
+func (s *BandsintownSource) FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error) {
+	var result BandsintownVenue
+	found, err := s.get(ctx, "/venues/"+url.PathEscape(externalID), url.Values{}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bandsintown venue: %w", err)
+	}
+	if !found || result.ID == "" {
+		return nil, &NotFoundError{Source: domain.SourceBandsintown, ExternalID: externalID}
+	}
+
+	venue, _ := MapBandsintownVenue(&result)
+	return venue, nil
+}
+
+// BandsintownEvent is a Bandsintown event resource. Times are local to the
+// venue and carry no zone, so they are read as UTC.
+type BandsintownEvent struct {
+	ID       string   `json:"id"`
+	DateTime string   `json:"datetime"`
+	Lineup   []string `json:"lineup"`
+	Artist   struct {
+		Name   string   `json:"name"`
+		Genres []string `json:"genres"`
+	} `json:"artist"`
+}
+
+// PastEvents lists a venue's Bandsintown events from since until now
+func (s *BandsintownSource) PastEvents(ctx context.Context, venueID, externalID string, since time.Time) ([]*domain.VenueEvent, error) {
+	query := url.Values{
+		"date": {since.Format("2006-01-02") + "," + time.Now().Format("2006-01-02")},
+	}
+
+	var result []BandsintownEvent
+	found, err := s.get(ctx, "/venues/"+url.PathEscape(externalID)+"/events", query, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bandsintown events: %w", err)
+	}
+	if !found {
+		return nil, &NotFoundError{Source: domain.SourceBandsintown, ExternalID: externalID}
+	}
+
+	events := make([]*domain.VenueEvent, 0, len(result))
+	for i := range result {
+		if event, ok := MapBandsintownEvent(venueID, &result[i]); ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// MapBandsintownEvent converts a Bandsintown event into an event at our
+// venue. It reports false when the date is malformed.
+func MapBandsintownEvent(venueID string, raw *BandsintownEvent) (*domain.VenueEvent, bool) {
+	date, err := time.Parse("2006-01-02T15:04:05", raw.DateTime)
+	if err != nil {
+		return nil, false
+	}
+
+	event := domain.NewVenueEvent(venueID, domain.SourceBandsintown, raw.ID, date)
+	event.ArtistName = raw.Artist.Name
+	if event.ArtistName == "" && len(raw.Lineup) > 0 {
+		event.ArtistName = raw.Lineup[0]
+	}
+	if len(raw.Artist.Genres) > 0 {
+		event.Genres = append(event.Genres, raw.Artist.Genres...)
+	}
+	return event, true
+}
+
+// get sends an authenticated GET and decodes the body into out. It reports
+// false when Bandsintown answers 404.
+func (s *BandsintownSource) get(ctx context.Context, path string, query url.Values, out any) (bool, error) {
+	query.Set("app_id", s.appID)
+	endpoint := s.baseURL + path + "?" + query.Encode()
+
+	resp, err := s.client.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("bandsintown returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode bandsintown response: %w", err)
+	}
+	return true, nil
+}
+
+// MapBandsintownVenue converts a Bandsintown venue into a new venue. It
+// reports false when the coordinates are missing or malformed.
+func MapBandsintownVenue(raw *BandsintownVenue) (*domain.Venue, bool) {
+	lat, latErr := strconv.ParseFloat(raw.Latitude, 64)
+	lng, lngErr := strconv.ParseFloat(raw.Longitude, 64)
+	located := latErr == nil && lngErr == nil
+
+	var location domain.GeoPoint
+	if located {
+		location = domain.GeoPoint{Latitude: lat, Longitude: lng}
+	}
+
+	venue := domain.NewVenue(
+		raw.Name,
+		location,
+		domain.Address{
+			Street:     raw.StreetAddress,
+			City:       raw.City,
+			State:      raw.Region,
+			PostalCode: raw.PostalCode,
+			Country:    raw.Country,
+		},
+		[]domain.VenueType{domain.VenueTypeOther},
+		domain.SourceBandsintown,
+	)
+	venue.BandsintownID = raw.ID
+	return venue, located
+}
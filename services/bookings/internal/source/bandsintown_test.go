@@ -0,0 +1,86 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/source/sourcetest"
+)
+
+func newBandsintownFixtures() (*sourcetest.FixtureServer, *BandsintownSource) {
+	server := sourcetest.NewFixtureServer("testdata", map[string]string{
+		"/venues/search":        "bandsintown_search_venues.json",
+		"/venues/bit-77":        "bandsintown_venue.json",
+		"/venues/bit-77/events": "bandsintown_venue_events.json",
+	})
+	src := NewBandsintownSource(server.URL, "bit-app", server.Client())
+	src.client.limiter = nil
+	src.client.backoff = time.Millisecond
+	return server, src
+}
+
+func TestBandsintownSource_SearchByName(t *testing.T) {
+	server, src := newBandsintownFixtures()
+	defer server.Close()
+
+	near := domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194}
+	venues, err := src.SearchByName(context.Background(), "Independent", near)
+	if err != nil {
+		t.Fatalf("SearchByName() error = %v", err)
+	}
+
+	query := server.LastQuery()
+	if query.Get("app_id") != "bit-app" || query.Get("latitude") != "37.774900" {
+		t.Errorf("request query = %v", query)
+	}
+	if len(venues) != 1 || venues[0].BandsintownID != "bit-77" {
+		t.Fatalf("SearchByName() = %v, want only the located venue", venues)
+	}
+	if venues[0].Location.Latitude != 37.7756 {
+		t.Errorf("latitude = %v, want 37.7756", venues[0].Location.Latitude)
+	}
+}
+
+func TestBandsintownSource_FetchDetails(t *testing.T) {
+	server, src := newBandsintownFixtures()
+	defer server.Close()
+
+	venue, err := src.FetchDetails(context.Background(), "bit-77")
+	if err != nil {
+		t.Fatalf("FetchDetails() error = %v", err)
+	}
+	if venue.Source != domain.SourceBandsintown || venue.Address.State != "CA" {
+		t.Errorf("FetchDetails() = %+v", venue)
+	}
+
+	if _, err := src.FetchDetails(context.Background(), "missing"); err == nil {
+		t.Error("FetchDetails() should fail for an unknown venue")
+	}
+}
+
+func TestBandsintownSource_PastEvents(t *testing.T) {
+	server, src := newBandsintownFixtures()
+	defer server.Close()
+
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	events, err := src.PastEvents(context.Background(), "venue-1", "bit-77", since)
+	if err != nil {
+		t.Fatalf("PastEvents() error = %v", err)
+	}
+
+	if query := server.LastQuery(); query.Get("app_id") != "bit-app" || query.Get("date")[:11] != "2024-06-01," {
+		t.Errorf("request query = %v", query)
+	}
+	// The undated event is dropped
+	if len(events) != 2 {
+		t.Fatalf("PastEvents() returned %d events, want 2", len(events))
+	}
+	if events[0].ArtistName != "Ty Segall" || len(events[0].Genres) != 2 || events[0].Genres[0] != "Garage Rock" {
+		t.Errorf("PastEvents()[0] = %+v", events[0])
+	}
+	if events[1].ArtistName != "Bleached" {
+		t.Errorf("PastEvents()[1] artist = %v, want the first act in the lineup", events[1].ArtistName)
+	}
+}
@@ -0,0 +1,166 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 500 * time.Millisecond
+	maxBackoff        = 30 * time.Second
+)
+
+// Limiter spaces requests evenly to stay under a provider's rate limit
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter allows up to perSecond requests per second; zero or less
+// disables limiting
+func NewLimiter(perSecond float64) *Limiter {
+	l := &Limiter{}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// Wait blocks until the next request may be sent or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.interval == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// client sends rate-limited requests and retries throttling, server errors
+// and transport failures with exponential backoff
+type client struct {
+	http       *http.Client
+	limiter    *Limiter
+	maxRetries int
+	backoff    time.Duration
+}
+
+func newClient(httpClient *http.Client, limiter *Limiter) *client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &client{
+		http:       httpClient,
+		limiter:    limiter,
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+}
+
+// do sends the request built by newReq, rebuilding it for each attempt so
+// request bodies can be replayed. Non-retryable responses are returned as is.
+func (c *client) do(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.retryDelay(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := newReq()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = &retryableStatusError{
+				status:     resp.StatusCode,
+				retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+}
+
+// retryDelay honours Retry-After when the provider sent one, otherwise
+// doubles the base backoff on each attempt
+func (c *client) retryDelay(attempt int, lastErr error) time.Duration {
+	if statusErr, ok := lastErr.(*retryableStatusError); ok && statusErr.retryAfter > 0 {
+		return min(statusErr.retryAfter, maxBackoff)
+	}
+	return min(c.backoff<<(attempt-1), maxBackoff)
+}
+
+// retryableStatusError records a throttled or failed response
+type retryableStatusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *retryableStatusError) Error() string {
+	return fmt.Sprintf("provider returned status %d", e.status)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter_SpacesRequests(t *testing.T) {
+	limiter := NewLimiter(100)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+
+	// The first call is immediate, the next four wait 10ms each
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("5 requests at 100/s took %v, want at least 40ms", elapsed)
+	}
+}
+
+func TestLimiter_RespectsContext(t *testing.T) {
+	limiter := NewLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_ = limiter.Wait(ctx)
+	cancel()
+
+	if err := limiter.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("parseRetryAfter(2) = %v, want 2s", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter('') = %v, want 0", got)
+	}
+}
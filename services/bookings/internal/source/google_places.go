@@ -0,0 +1,275 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const (
+	// DefaultGooglePlacesURL is the Places API (New) endpoint
+	DefaultGooglePlacesURL = "https://places.googleapis.com"
+	// GooglePlacesRequestsPerSecond keeps nearby and details calls well
+	// under the per-minute project quota
+	GooglePlacesRequestsPerSecond = 10
+
+	googleMaxResults    = 20
+	googleSearchMask    = "places.id,places.displayName"
+	googleDetailsFields = "id,displayName,location,formattedAddress,addressComponents,types,rating,userRatingCount,photos,websiteUri,nationalPhoneNumber"
+)
+
+// googleTypes maps our venue types to Google place types for nearby search
+var googleTypes = map[domain.VenueType][]string{
+	domain.VenueTypeBar:         {"bar"},
+	domain.VenueTypeClub:        {"night_club"},
+	domain.VenueTypeRestaurant:  {"restaurant"},
+	domain.VenueTypeCoffeehouse: {"cafe", "coffee_shop"},
+	domain.VenueTypeBrewery:     {"brewery"},
+	domain.VenueTypeWinery:      {"winery"},
+	domain.VenueTypeTheater:     {"performing_arts_theater"},
+	domain.VenueTypeArena:       {"arena", "stadium"},
+}
+
+// GooglePlacesSource imports venues from the Google Places API (New)
+type GooglePlacesSource struct {
+	baseURL string
+	apiKey  string
+	client  *client
+}
+
+// NewGooglePlacesSource creates a Google Places connector. An empty baseURL
+// uses the public endpoint and a nil httpClient a default with a timeout.
+func NewGooglePlacesSource(baseURL, apiKey string, httpClient *http.Client) *GooglePlacesSource {
+	if baseURL == "" {
+		baseURL = DefaultGooglePlacesURL
+	}
+	return &GooglePlacesSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  newClient(httpClient, NewLimiter(GooglePlacesRequestsPerSecond)),
+	}
+}
+
+// Source returns domain.SourceGooglePlaces
+func (s *GooglePlacesSource) Source() domain.DataSource {
+	return domain.SourceGooglePlaces
+}
+
+// googleSearchRequest is the searchNearby request body
+type googleSearchRequest struct {
+	IncludedTypes       []string `json:"includedTypes,omitempty"`
+	MaxResultCount      int      `json:"maxResultCount"`
+	LocationRestriction struct {
+		Circle struct {
+			Center googleLatLng `json:"center"`
+			Radius float64      `json:"radius"`
+		} `json:"circle"`
+	} `json:"locationRestriction"`
+}
+
+type googleLatLng struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type googleText struct {
+	Text string `json:"text"`
+}
+
+type googleAddressComponent struct {
+	LongText  string   `json:"longText"`
+	ShortText string   `json:"shortText"`
+	Types     []string `json:"types"`
+}
+
+type googlePhoto struct {
+	Name string `json:"name"`
+}
+
+// GooglePlace is the subset of a Places API place resource we import
+type GooglePlace struct {
+	ID                  string                   `json:"id"`
+	DisplayName         googleText               `json:"displayName"`
+	Location            googleLatLng             `json:"location"`
+	FormattedAddress    string                   `json:"formattedAddress,omitempty"`
+	AddressComponents   []googleAddressComponent `json:"addressComponents,omitempty"`
+	Types               []string                 `json:"types,omitempty"`
+	Rating              float64                  `json:"rating,omitempty"`
+	UserRatingCount     int                      `json:"userRatingCount,omitempty"`
+	Photos              []googlePhoto            `json:"photos,omitempty"`
+	WebsiteURI          string                   `json:"websiteUri,omitempty"`
+	NationalPhoneNumber string                   `json:"nationalPhoneNumber,omitempty"`
+}
+
+type googleSearchResponse struct {
+	Places []GooglePlace `json:"places"`
+}
+
+// SearchArea runs a nearby search around the area centre
+func (s *GooglePlacesSource) SearchArea(ctx context.Context, area Area) ([]Listing, error) {
+	var body googleSearchRequest
+	body.MaxResultCount = googleMaxResults
+	if area.Limit > 0 && area.Limit < googleMaxResults {
+		body.MaxResultCount = area.Limit
+	}
+	body.LocationRestriction.Circle.Center = googleLatLng{
+		Latitude:  area.Center.Latitude,
+		Longitude: area.Center.Longitude,
+	}
+	body.LocationRestriction.Circle.Radius = area.RadiusKm * 1000
+	for _, vt := range area.VenueTypes {
+		body.IncludedTypes = append(body.IncludedTypes, googleTypes[vt]...)
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal nearby search: %w", err)
+	}
+
+	resp, err := s.client.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/v1/places:searchNearby", bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		s.authorize(req, googleSearchMask)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search google places: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google places search returned status %d", resp.StatusCode)
+	}
+
+	var result googleSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode google places search: %w", err)
+	}
+
+	listings := make([]Listing, 0, len(result.Places))
+	for _, place := range result.Places {
+		listings = append(listings, Listing{ExternalID: place.ID, Name: place.DisplayName.Text})
+	}
+	return listings, nil
+}
+
+// FetchDetails loads a place and maps it to a venue
+func (s *GooglePlacesSource) FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error) {
+	resp, err := s.client.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/v1/places/"+url.PathEscape(externalID), nil)
+		if err != nil {
+			return nil, err
+		}
+		s.authorize(req, googleDetailsFields)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch google place: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &NotFoundError{Source: domain.SourceGooglePlaces, ExternalID: externalID}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google place details returned status %d", resp.StatusCode)
+	}
+
+	var place GooglePlace
+	if err := json.NewDecoder(resp.Body).Decode(&place); err != nil {
+		return nil, fmt.Errorf("failed to decode google place: %w", err)
+	}
+
+	return MapGooglePlace(&place), nil
+}
+
+func (s *GooglePlacesSource) authorize(req *http.Request, fieldMask string) {
+	req.Header.Set("X-Goog-Api-Key", s.apiKey)
+	req.Header.Set("X-Goog-FieldMask", fieldMask)
+}
+
+// MapGooglePlace converts a place resource into a new venue
+func MapGooglePlace(place *GooglePlace) *domain.Venue {
+	venue := domain.NewVenue(
+		place.DisplayName.Text,
+		domain.GeoPoint{
+			Latitude:  place.Location.Latitude,
+			Longitude: place.Location.Longitude,
+		},
+		googleAddress(place),
+		googleVenueTypes(place.Types),
+		domain.SourceGooglePlaces,
+	)
+	venue.GooglePlaceID = place.ID
+	venue.Rating = place.Rating
+	venue.ReviewCount = place.UserRatingCount
+	venue.ContactInfo.Website = place.WebsiteURI
+	venue.ContactInfo.Phone = place.NationalPhoneNumber
+	for _, photo := range place.Photos {
+		venue.Photos = append(venue.Photos, photo.Name)
+	}
+	return venue
+}
+
+// googleAddress builds an address from structured components
+func googleAddress(place *GooglePlace) domain.Address {
+	var address domain.Address
+	var number, route string
+
+	for _, component := range place.AddressComponents {
+		for _, t := range component.Types {
+			switch t {
+			case "street_number":
+				number = component.LongText
+			case "route":
+				route = component.LongText
+			case "locality":
+				address.City = component.LongText
+			case "postal_town":
+				if address.City == "" {
+					address.City = component.LongText
+				}
+			case "administrative_area_level_1":
+				address.State = component.ShortText
+			case "postal_code":
+				address.PostalCode = component.LongText
+			case "country":
+				address.Country = component.ShortText
+			}
+		}
+	}
+	address.Street = strings.TrimSpace(number + " " + route)
+
+	return address
+}
+
+// googleVenueTypes maps Google place types back to our venue types
+func googleVenueTypes(types []string) []domain.VenueType {
+	venueTypes := make([]domain.VenueType, 0)
+	seen := make(map[domain.VenueType]bool)
+
+	for _, t := range types {
+		for vt, googleNames := range googleTypes {
+			for _, name := range googleNames {
+				if name == t && !seen[vt] {
+					seen[vt] = true
+					venueTypes = append(venueTypes, vt)
+				}
+			}
+		}
+	}
+
+	if len(venueTypes) == 0 {
+		venueTypes = append(venueTypes, domain.VenueTypeOther)
+	}
+	return venueTypes
+}
@@ -0,0 +1,136 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/source/sourcetest"
+)
+
+var fillmore = sourcetest.Place{
+	ID:          "place-fillmore",
+	DisplayName: sourcetest.Text{Text: "The Fillmore"},
+	Location:    sourcetest.LatLng{Latitude: 37.7840, Longitude: -122.4330},
+	AddressComponents: []sourcetest.AddressComponent{
+		{LongText: "1805", ShortText: "1805", Types: []string{"street_number"}},
+		{LongText: "Geary Boulevard", ShortText: "Geary Blvd", Types: []string{"route"}},
+		{LongText: "San Francisco", ShortText: "SF", Types: []string{"locality", "political"}},
+		{LongText: "California", ShortText: "CA", Types: []string{"administrative_area_level_1"}},
+		{LongText: "94115", ShortText: "94115", Types: []string{"postal_code"}},
+		{LongText: "United States", ShortText: "US", Types: []string{"country"}},
+	},
+	Types:           []string{"night_club", "bar", "point_of_interest"},
+	Rating:          4.7,
+	UserRatingCount: 3200,
+	WebsiteURI:      "https://thefillmore.example",
+}
+
+func newTestGooglePlaces(server *sourcetest.GooglePlacesServer, apiKey string) *GooglePlacesSource {
+	src := NewGooglePlacesSource(server.URL, apiKey, server.Client())
+	src.client.limiter = nil
+	src.client.backoff = time.Millisecond
+	return src
+}
+
+func TestGooglePlacesSource_SearchArea(t *testing.T) {
+	cafe := sourcetest.Place{ID: "place-cafe", DisplayName: sourcetest.Text{Text: "Cafe"}, Types: []string{"cafe"}}
+	server := sourcetest.NewGooglePlacesServer("key", fillmore, cafe)
+	defer server.Close()
+
+	src := newTestGooglePlaces(server, "key")
+	listings, err := src.SearchArea(context.Background(), Area{
+		Center:     domain.GeoPoint{Latitude: 37.78, Longitude: -122.43},
+		RadiusKm:   5,
+		VenueTypes: []domain.VenueType{domain.VenueTypeClub},
+	})
+	if err != nil {
+		t.Fatalf("SearchArea() error = %v", err)
+	}
+
+	if len(listings) != 1 || listings[0].ExternalID != "place-fillmore" {
+		t.Errorf("SearchArea() = %v, want only the club", listings)
+	}
+}
+
+func TestGooglePlacesSource_FetchDetails(t *testing.T) {
+	server := sourcetest.NewGooglePlacesServer("key", fillmore)
+	defer server.Close()
+
+	venue, err := newTestGooglePlaces(server, "key").FetchDetails(context.Background(), "place-fillmore")
+	if err != nil {
+		t.Fatalf("FetchDetails() error = %v", err)
+	}
+
+	if venue.GooglePlaceID != "place-fillmore" || venue.Source != domain.SourceGooglePlaces {
+		t.Errorf("FetchDetails() id/source = %v/%v", venue.GooglePlaceID, venue.Source)
+	}
+	if venue.Name != "The Fillmore" {
+		t.Errorf("FetchDetails() name = %v, want The Fillmore", venue.Name)
+	}
+	want := domain.Address{Street: "1805 Geary Boulevard", City: "San Francisco", State: "CA", PostalCode: "94115", Country: "US"}
+	if venue.Address != want {
+		t.Errorf("FetchDetails() address = %+v, want %+v", venue.Address, want)
+	}
+	if len(venue.VenueTypes) != 2 || venue.VenueTypes[0] != domain.VenueTypeClub || venue.VenueTypes[1] != domain.VenueTypeBar {
+		t.Errorf("FetchDetails() venue types = %v, want [club bar]", venue.VenueTypes)
+	}
+	if venue.Rating != 4.7 || venue.ReviewCount != 3200 {
+		t.Errorf("FetchDetails() rating = %v (%v), want 4.7 (3200)", venue.Rating, venue.ReviewCount)
+	}
+	if venue.ContactInfo.Website != "https://thefillmore.example" {
+		t.Errorf("FetchDetails() website = %v", venue.ContactInfo.Website)
+	}
+}
+
+func TestGooglePlacesSource_FetchDetails_NotFound(t *testing.T) {
+	server := sourcetest.NewGooglePlacesServer("key")
+	defer server.Close()
+
+	_, err := newTestGooglePlaces(server, "key").FetchDetails(context.Background(), "missing")
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("FetchDetails() error = %v, want NotFoundError", err)
+	}
+}
+
+func TestGooglePlacesSource_RetriesServerErrors(t *testing.T) {
+	server := sourcetest.NewGooglePlacesServer("key", fillmore)
+	defer server.Close()
+	server.FailNext(2)
+
+	_, err := newTestGooglePlaces(server, "key").FetchDetails(context.Background(), "place-fillmore")
+	if err != nil {
+		t.Fatalf("FetchDetails() error = %v", err)
+	}
+	if server.Requests() != 3 {
+		t.Errorf("requests = %v, want 3", server.Requests())
+	}
+}
+
+func TestGooglePlacesSource_GivesUpAfterMaxRetries(t *testing.T) {
+	server := sourcetest.NewGooglePlacesServer("key", fillmore)
+	defer server.Close()
+	server.FailNext(10)
+
+	_, err := newTestGooglePlaces(server, "key").FetchDetails(context.Background(), "place-fillmore")
+	if err == nil {
+		t.Fatal("FetchDetails() should fail once retries are exhausted")
+	}
+	if server.Requests() != defaultMaxRetries+1 {
+		t.Errorf("requests = %v, want %v", server.Requests(), defaultMaxRetries+1)
+	}
+}
+
+func TestGooglePlacesSource_RejectedKey(t *testing.T) {
+	server := sourcetest.NewGooglePlacesServer("key", fillmore)
+	defer server.Close()
+
+	_, err := newTestGooglePlaces(server, "wrong").FetchDetails(context.Background(), "place-fillmore")
+	if err == nil {
+		t.Fatal("FetchDetails() should fail with a rejected key")
+	}
+	if server.Requests() != 1 {
+		t.Errorf("requests = %v, want 1; client errors are not retried", server.Requests())
+	}
+}
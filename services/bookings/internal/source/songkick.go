@@ -0,0 +1,302 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+const (
+	// DefaultSongkickURL is the Songkick API endpoint
+	DefaultSongkickURL = "https://api.songkick.com"
+	// SongkickRequestsPerSecond is Songkick's documented rate limit
+	SongkickRequestsPerSecond = 5
+)
+
+// SongkickSource looks venues up in the Songkick API
+type SongkickSource struct {
+	baseURL string
+	apiKey  string
+	client  *client
+}
+
+// NewSongkickSource creates a Songkick connector. An empty baseURL uses the
+// public endpoint and a nil httpClient a default with a timeout.
+func NewSongkickSource(baseURL, apiKey string, httpClient *http.Client) *SongkickSource {
+	if baseURL == "" {
+		baseURL = DefaultSongkickURL
+	}
+	return &SongkickSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  newClient(httpClient, NewLimiter(SongkickRequestsPerSecond)),
+	}
+}
+
+// Source returns domain.SourceSongkick
+func (s *SongkickSource) Source() domain.DataSource {
+	return domain.SourceSongkick
+}
+
+type songkickName struct {
+	DisplayName string `json:"displayName"`
+}
+
+// SongkickVenue is the subset of a Songkick venue resource we import
+type SongkickVenue struct {
+	ID          int      `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Lat         *float64 `json:"lat"`
+	Lng         *float64 `json:"lng"`
+	Capacity    *int     `json:"capacity"`
+	Street      string   `json:"street"`
+	Zip         string   `json:"zip"`
+	Phone       string   `json:"phone"`
+	Website     string   `json:"website"`
+	Description string   `json:"description"`
+	City        struct {
+		DisplayName string       `json:"displayName"`
+		State       songkickName `json:"state"`
+		Country     songkickName `json:"country"`
+	} `json:"city"`
+}
+
+type songkickSearchResponse struct {
+	ResultsPage struct {
+		Status  string `json:"status"`
+		Results struct {
+			Venue []SongkickVenue `json:"venue"`
+		} `json:"results"`
+	} `json:"resultsPage"`
+}
+
+type songkickVenueResponse struct {
+	ResultsPage struct {
+		Status  string `json:"status"`
+		Results struct {
+			Venue *SongkickVenue `json:"venue"`
+		} `json:"results"`
+	} `json:"resultsPage"`
+}
+
+// SearchByName runs a Songkick venue search and orders hits by distance
+// from near
+func (s *SongkickSource) SearchByName(ctx context.Context, name string, near domain.GeoPoint) ([]*domain.Venue, error) {
+	query := url.Values{"query": {name}}
+
+	var result songkickSearchResponse
+	found, err := s.get(ctx, "/api/3.0/search/venues.json", query, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search songkick venues: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	venues := make([]*domain.Venue, 0, len(result.ResultsPage.Results.Venue))
+	for i := range result.ResultsPage.Results.Venue {
+		raw := &result.ResultsPage.Results.Venue[i]
+		if raw.Lat == nil || raw.Lng == nil {
+			continue // Unlocated venues can never be matched by distance
+		}
+		venues = append(venues, MapSongkickVenue(raw))
+	}
+	sortByDistance(venues, near)
+
+	return venues, nil
+}
+
+// FetchDetails loads a Songkick venue by ID
+func (s *SongkickSource) FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error) {
+	var result songkickVenueResponse
+	found, err := s.get(ctx, "/api/3.0/venues/"+url.PathEscape(externalID)+".json", url.Values{}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch songkick venue: %w", err)
+	}
+	if !found || result.ResultsPage.Results.Venue == nil {
+		return nil, &NotFoundError{Source: domain.SourceSongkick, ExternalID: externalID}
+	}
+
+	return MapSongkickVenue(result.ResultsPage.Results.Venue), nil
+}
+
+// songkickEventsPerPage and maxSongkickEventPages bound how much of a
+// venue's gigography one sync reads
+const (
+	songkickEventsPerPage = 50
+	maxSongkickEventPages = 10
+)
+
+// SongkickEvent is the subset of a Songkick event resource we record
+type SongkickEvent struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Start  struct {
+		Date     string  `json:"date"`
+		DateTime *string `json:"datetime"`
+	} `json:"start"`
+	Performance []struct {
+		Billing      string `json:"billing"`
+		BillingIndex int    `json:"billingIndex"`
+		Artist       struct {
+			DisplayName string `json:"displayName"`
+		} `json:"artist"`
+	} `json:"performance"`
+}
+
+type songkickGigographyResponse struct {
+	ResultsPage struct {
+		Status  string `json:"status"`
+		Results struct {
+			Event []SongkickEvent `json:"event"`
+		} `json:"results"`
+		TotalEntries int `json:"totalEntries"`
+	} `json:"resultsPage"`
+}
+
+// PastEvents reads a venue's Songkick gigography from since onwards,
+// latest first. Cancelled events are skipped.
+func (s *SongkickSource) PastEvents(ctx context.Context, venueID, externalID string, since time.Time) ([]*domain.VenueEvent, error) {
+	path := "/api/3.0/venues/" + url.PathEscape(externalID) + "/gigography.json"
+	events := make([]*domain.VenueEvent, 0)
+
+	read := 0
+	for page := 1; page <= maxSongkickEventPages; page++ {
+		query := url.Values{
+			"min_date": {since.Format("2006-01-02")},
+			"max_date": {time.Now().Format("2006-01-02")},
+			"order":    {"desc"},
+			"page":     {strconv.Itoa(page)},
+			"per_page": {strconv.Itoa(songkickEventsPerPage)},
+		}
+
+		var result songkickGigographyResponse
+		found, err := s.get(ctx, path, query, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch songkick gigography: %w", err)
+		}
+		if !found {
+			return nil, &NotFoundError{Source: domain.SourceSongkick, ExternalID: externalID}
+		}
+
+		for i := range result.ResultsPage.Results.Event {
+			if event, ok := MapSongkickEvent(venueID, &result.ResultsPage.Results.Event[i]); ok {
+				events = append(events, event)
+			}
+		}
+		read += len(result.ResultsPage.Results.Event)
+		if len(result.ResultsPage.Results.Event) == 0 || read >= result.ResultsPage.TotalEntries {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+// MapSongkickEvent converts a Songkick event into an event at our venue,
+// naming its headliner. It reports false for cancelled or undated events.
+func MapSongkickEvent(venueID string, raw *SongkickEvent) (*domain.VenueEvent, bool) {
+	if raw.Status == "cancelled" {
+		return nil, false
+	}
+
+	// Songkick gives a datetime with a numeric zone when the start time is
+	// known and only a date otherwise
+	var date time.Time
+	var err error
+	if raw.Start.DateTime != nil {
+		date, err = time.Parse("2006-01-02T15:04:05-0700", *raw.Start.DateTime)
+	} else {
+		date, err = time.Parse("2006-01-02", raw.Start.Date)
+	}
+	if err != nil {
+		return nil, false
+	}
+
+	event := domain.NewVenueEvent(venueID, domain.SourceSongkick, strconv.Itoa(raw.ID), date)
+	headliner := -1
+	for i, p := range raw.Performance {
+		if headliner < 0 || p.BillingIndex < raw.Performance[headliner].BillingIndex {
+			headliner = i
+		}
+	}
+	if headliner >= 0 {
+		event.ArtistName = raw.Performance[headliner].Artist.DisplayName
+	}
+	return event, true
+}
+
+// get sends an authenticated GET and decodes the body into out. It reports
+// false when Songkick answers 404.
+func (s *SongkickSource) get(ctx context.Context, path string, query url.Values, out any) (bool, error) {
+	query.Set("apikey", s.apiKey)
+	endpoint := s.baseURL + path + "?" + query.Encode()
+
+	resp, err := s.client.do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("songkick returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode songkick response: %w", err)
+	}
+	return true, nil
+}
+
+// MapSongkickVenue converts a Songkick venue into a new venue
+func MapSongkickVenue(raw *SongkickVenue) *domain.Venue {
+	var location domain.GeoPoint
+	if raw.Lat != nil && raw.Lng != nil {
+		location = domain.GeoPoint{Latitude: *raw.Lat, Longitude: *raw.Lng}
+	}
+
+	venue := domain.NewVenue(
+		raw.DisplayName,
+		location,
+		domain.Address{
+			Street:     raw.Street,
+			City:       raw.City.DisplayName,
+			State:      raw.City.State.DisplayName,
+			PostalCode: raw.Zip,
+			Country:    raw.City.Country.DisplayName,
+		},
+		[]domain.VenueType{domain.VenueTypeOther},
+		domain.SourceSongkick,
+	)
+	venue.SongkickID = strconv.Itoa(raw.ID)
+	if raw.Capacity != nil {
+		venue.Capacity = *raw.Capacity
+	}
+	venue.Description = raw.Description
+	venue.ContactInfo.Phone = raw.Phone
+	venue.ContactInfo.Website = raw.Website
+	return venue
+}
+
+// sortByDistance orders venues nearest first
+func sortByDistance(venues []*domain.Venue, near domain.GeoPoint) {
+	distance := func(v *domain.Venue) float64 {
+		return domain.CalculateDistance(near.Latitude, near.Longitude, v.Location.Latitude, v.Location.Longitude)
+	}
+	sort.SliceStable(venues, func(i, j int) bool {
+		return distance(venues[i]) < distance(venues[j])
+	})
+}
@@ -0,0 +1,110 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/source/sourcetest"
+)
+
+func newSongkickFixtures() (*sourcetest.FixtureServer, *SongkickSource) {
+	server := sourcetest.NewFixtureServer("testdata", map[string]string{
+		"/api/3.0/search/venues.json":          "songkick_search_venues.json",
+		"/api/3.0/venues/9001.json":            "songkick_venue.json",
+		"/api/3.0/venues/9001/gigography.json": "songkick_gigography.json",
+	})
+	src := NewSongkickSource(server.URL, "sk-key", server.Client())
+	src.client.limiter = nil
+	src.client.backoff = time.Millisecond
+	return server, src
+}
+
+func TestSongkickSource_SearchByName(t *testing.T) {
+	server, src := newSongkickFixtures()
+	defer server.Close()
+
+	sf := domain.GeoPoint{Latitude: 37.7749, Longitude: -122.4194}
+	venues, err := src.SearchByName(context.Background(), "The Independent", sf)
+	if err != nil {
+		t.Fatalf("SearchByName() error = %v", err)
+	}
+
+	if query := server.LastQuery(); query.Get("apikey") != "sk-key" || query.Get("query") != "The Independent" {
+		t.Errorf("request query = %v", query)
+	}
+	// The unlocated venue is dropped and the nearest comes first
+	if len(venues) != 2 {
+		t.Fatalf("SearchByName() returned %v venues, want 2", len(venues))
+	}
+	if venues[0].SongkickID != "9001" {
+		t.Errorf("SearchByName()[0] = %v, want the San Francisco venue", venues[0].SongkickID)
+	}
+	if venues[0].Capacity != 500 || venues[1].Capacity != 0 {
+		t.Errorf("capacities = %v/%v, want 500/0", venues[0].Capacity, venues[1].Capacity)
+	}
+}
+
+func TestSongkickSource_FetchDetails(t *testing.T) {
+	server, src := newSongkickFixtures()
+	defer server.Close()
+
+	venue, err := src.FetchDetails(context.Background(), "9001")
+	if err != nil {
+		t.Fatalf("FetchDetails() error = %v", err)
+	}
+
+	if venue.Source != domain.SourceSongkick || venue.SongkickID != "9001" {
+		t.Errorf("FetchDetails() source/id = %v/%v", venue.Source, venue.SongkickID)
+	}
+	want := domain.Address{Street: "628 Divisadero St", City: "San Francisco", State: "CA", PostalCode: "94117", Country: "US"}
+	if venue.Address != want {
+		t.Errorf("FetchDetails() address = %+v, want %+v", venue.Address, want)
+	}
+	if venue.ContactInfo.Website != "http://www.theindependentsf.com" {
+		t.Errorf("FetchDetails() website = %v", venue.ContactInfo.Website)
+	}
+
+	if _, err := src.FetchDetails(context.Background(), "404"); err == nil {
+		t.Error("FetchDetails() should fail for an unknown venue")
+	} else if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("FetchDetails() error = %v, want NotFoundError", err)
+	}
+}
+
+func TestSongkickSource_PastEvents(t *testing.T) {
+	server, src := newSongkickFixtures()
+	defer server.Close()
+
+	since := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	events, err := src.PastEvents(context.Background(), "venue-1", "9001", since)
+	if err != nil {
+		t.Fatalf("PastEvents() error = %v", err)
+	}
+
+	if query := server.LastQuery(); query.Get("min_date") != "2024-06-01" || query.Get("order") != "desc" {
+		t.Errorf("request query = %v", query)
+	}
+	// The cancelled show is skipped
+	if len(events) != 2 {
+		t.Fatalf("PastEvents() returned %d events, want 2", len(events))
+	}
+	first := events[0]
+	if first.VenueID != "venue-1" || first.Source != domain.SourceSongkick || first.SourceID != "41234567" {
+		t.Errorf("PastEvents()[0] = %+v", first)
+	}
+	if first.ArtistName != "Thee Oh Sees" {
+		t.Errorf("PastEvents()[0] artist = %v, want the headliner", first.ArtistName)
+	}
+	if want := time.Date(2025, 5, 4, 3, 0, 0, 0, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("PastEvents()[0] date = %v, want %v", first.Date, want)
+	}
+	if want := time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC); !events[1].Date.Equal(want) {
+		t.Errorf("PastEvents()[1] date = %v, want the start date %v", events[1].Date, want)
+	}
+
+	if _, err := src.PastEvents(context.Background(), "venue-1", "404", since); err == nil {
+		t.Error("PastEvents() should fail for an unknown venue")
+	}
+}
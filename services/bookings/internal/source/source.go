@@ -0,0 +1,64 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+)
+
+// Area describes a circular region to search for venues
+type Area struct {
+	Center     domain.GeoPoint
+	RadiusKm   float64
+	VenueTypes []domain.VenueType
+	Limit      int
+}
+
+// Listing is a search hit from a data source; details are fetched separately
+type Listing struct {
+	ExternalID string
+	Name       string
+}
+
+// VenueSource is a third-party venue data provider
+type VenueSource interface {
+	// Source identifies the provider; it is stored on imported venues
+	Source() domain.DataSource
+	// SearchArea lists venues the provider knows of inside the area
+	SearchArea(ctx context.Context, area Area) ([]Listing, error)
+	// FetchDetails loads a venue by its provider ID and maps it to a
+	// domain.Venue carrying the provider's external ID
+	FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error)
+}
+
+// VenueSearcher is a source that looks venues up by name, used to enrich
+// venues we already hold rather than to discover new ones
+type VenueSearcher interface {
+	// Source identifies the provider
+	Source() domain.DataSource
+	// SearchByName returns the provider's venues matching name, mapped to
+	// domain.Venue, preferring those near the given point
+	SearchByName(ctx context.Context, name string, near domain.GeoPoint) ([]*domain.Venue, error)
+	// FetchDetails loads a venue by its provider ID
+	FetchDetails(ctx context.Context, externalID string) (*domain.Venue, error)
+}
+
+// EventSource is a source that lists the shows a venue has hosted
+type EventSource interface {
+	// Source identifies the provider
+	Source() domain.DataSource
+	// PastEvents lists the shows at the venue with the given provider ID
+	// from since until now, mapped to events at our venue venueID
+	PastEvents(ctx context.Context, venueID, externalID string, since time.Time) ([]*domain.VenueEvent, error)
+}
+
+// NotFoundError is returned when the provider has no venue with the given ID
+type NotFoundError struct {
+	Source     domain.DataSource
+	ExternalID string
+}
+
+func (e *NotFoundError) Error() string {
+	return string(e.Source) + " venue not found: " + e.ExternalID
+}
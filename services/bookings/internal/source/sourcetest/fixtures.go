@@ -0,0 +1,65 @@
+package sourcetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FixtureServer replays recorded provider responses from JSON files. Routes
+// map a request path to a file name in dir; unknown paths get a 404.
+type FixtureServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	dir       string
+	routes    map[string]string
+	requests  int
+	lastQuery url.Values
+}
+
+// NewFixtureServer starts a server replaying the fixtures in dir
+func NewFixtureServer(dir string, routes map[string]string) *FixtureServer {
+	s := &FixtureServer{dir: dir, routes: routes}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Requests returns the number of requests received
+func (s *FixtureServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// LastQuery returns the query string of the most recent request
+func (s *FixtureServer) LastQuery() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastQuery
+}
+
+func (s *FixtureServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.lastQuery = r.URL.Query()
+	file, ok := s.routes[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := os.ReadFile(filepath.Join(s.dir, file))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,146 @@
+// Package sourcetest provides HTTP stub servers that mimic the venue data
+// providers, for exercising connectors without network access
+package sourcetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Place is a Google Places resource as served by the stub
+type Place struct {
+	ID                string             `json:"id"`
+	DisplayName       Text               `json:"displayName"`
+	Location          LatLng             `json:"location"`
+	FormattedAddress  string             `json:"formattedAddress,omitempty"`
+	AddressComponents []AddressComponent `json:"addressComponents,omitempty"`
+	Types             []string           `json:"types,omitempty"`
+	Rating            float64            `json:"rating,omitempty"`
+	UserRatingCount   int                `json:"userRatingCount,omitempty"`
+	WebsiteURI        string             `json:"websiteUri,omitempty"`
+}
+
+// Text is a localized string
+type Text struct {
+	Text string `json:"text"`
+}
+
+// LatLng is a coordinate
+type LatLng struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// AddressComponent is one structured part of an address
+type AddressComponent struct {
+	LongText  string   `json:"longText"`
+	ShortText string   `json:"shortText"`
+	Types     []string `json:"types"`
+}
+
+// GooglePlacesServer serves searchNearby and place details from memory
+type GooglePlacesServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	apiKey   string
+	places   []Place
+	failures int
+	requests int
+}
+
+// NewGooglePlacesServer starts a stub that requires apiKey on every request
+func NewGooglePlacesServer(apiKey string, places ...Place) *GooglePlacesServer {
+	s := &GooglePlacesServer{apiKey: apiKey, places: places}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FailNext makes the next n requests return 503
+func (s *GooglePlacesServer) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Requests returns the number of requests received
+func (s *GooglePlacesServer) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *GooglePlacesServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	if s.failures > 0 {
+		s.failures--
+		s.mu.Unlock()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	places := append([]Place(nil), s.places...)
+	s.mu.Unlock()
+
+	if r.Header.Get("X-Goog-Api-Key") != s.apiKey {
+		http.Error(w, "invalid api key", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/places:searchNearby":
+		var body struct {
+			IncludedTypes  []string `json:"includedTypes"`
+			MaxResultCount int      `json:"maxResultCount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		matched := make([]Place, 0)
+		for _, place := range places {
+			if len(body.IncludedTypes) == 0 || hasAny(place.Types, body.IncludedTypes) {
+				matched = append(matched, place)
+			}
+			if body.MaxResultCount > 0 && len(matched) >= body.MaxResultCount {
+				break
+			}
+		}
+		writeJSON(w, map[string][]Place{"places": matched})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/places/"):
+		id := strings.TrimPrefix(r.URL.Path, "/v1/places/")
+		for _, place := range places {
+			if place.ID == id {
+				writeJSON(w, place)
+				return
+			}
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func hasAny(values, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir, "http://localhost:8080/media/")
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "venues/v1/p1.jpg", "image/jpeg", []byte("jpeg")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "venues", "v1", "p1.jpg"))
+	if err != nil || string(data) != "jpeg" {
+		t.Fatalf("stored file = %q, %v", data, err)
+	}
+	if got := store.URL("venues/v1/p1.jpg"); got != "http://localhost:8080/media/venues/v1/p1.jpg" {
+		t.Errorf("URL() = %v", got)
+	}
+
+	if err := store.Delete(ctx, "venues/v1/p1.jpg"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(ctx, "venues/v1/p1.jpg"); err != nil {
+		t.Errorf("Delete() of a missing object error = %v", err)
+	}
+
+	if err := store.Put(ctx, "../escape.jpg", "image/jpeg", nil); err == nil {
+		t.Error("Put() should refuse keys outside the directory")
+	}
+}
@@ -0,0 +1,74 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/crowdunlocked/services/bookings/internal/migrate"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+)
+
+func TestMigrations(t *testing.T) {
+	requireDynamoDB(t)
+	ctx := context.Background()
+
+	prefix := fmt.Sprintf("it-%d-%d-", runID, tableSets.Add(1))
+	tables := schema.DefaultTables().Prefixed(prefix)
+	migrations := schema.Migrations(tables)
+	for _, migration := range migrations {
+		for _, table := range migration.Tables {
+			deleteTable(t, table.Name)
+		}
+	}
+	deleteTable(t, prefix+"schema-migrations")
+
+	migrator := migrate.NewMigrator(client, prefix+"schema-migrations", "bookings")
+	migrator.PollInterval = 50 * time.Millisecond
+
+	done, err := migrator.Up(ctx, migrations)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Errorf("Up() applied %d migrations, want %d", len(done), len(migrations))
+	}
+
+	// Every declared index exists
+	for _, migration := range migrations {
+		for _, table := range migration.Tables {
+			out, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table.Name)})
+			if err != nil {
+				t.Fatalf("DescribeTable(%s) error = %v", table.Name, err)
+			}
+			indexes := make(map[string]bool)
+			for _, index := range out.Table.GlobalSecondaryIndexes {
+				indexes[aws.ToString(index.IndexName)] = true
+			}
+			for _, index := range out.Table.LocalSecondaryIndexes {
+				indexes[aws.ToString(index.IndexName)] = true
+			}
+			for _, declared := range [][]migrate.Index{table.GlobalIndexes, table.LocalIndexes} {
+				for _, index := range declared {
+					if !indexes[index.Name] {
+						t.Errorf("table %s has no index %s", table.Name, index.Name)
+					}
+				}
+			}
+		}
+	}
+
+	// Running again changes nothing
+	done, err = migrator.Up(ctx, migrations)
+	if err != nil {
+		t.Fatalf("second Up() error = %v", err)
+	}
+	if len(done) != 0 {
+		t.Errorf("second Up() applied %d migrations, want 0", len(done))
+	}
+}
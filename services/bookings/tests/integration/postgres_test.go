@@ -0,0 +1,135 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgisImage is the image started when POSTGRES_URL is unset
+const postgisImage = "postgis/postgis:16-3.4"
+
+var (
+	// pool talks to PostgreSQL; it is nil when none is available
+	pool *pgxpool.Pool
+	// postgresSkipReason says why no PostgreSQL is available
+	postgresSkipReason string
+)
+
+// setupPostgres connects pool to PostgreSQL, starting it if needed. It sets
+// postgresSkipReason instead when none can be started.
+func setupPostgres(ctx context.Context) (func(), error) {
+	stop := func() {}
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		started, stopContainer, err := startPostgres()
+		if err != nil {
+			postgresSkipReason = fmt.Sprintf("POSTGRES_URL is unset and PostgreSQL could not be started: %v", err)
+			return stop, nil
+		}
+		url, stop = started, stopContainer
+	}
+
+	var err error
+	pool, err = pgxpool.New(ctx, url)
+	if err != nil {
+		return stop, fmt.Errorf("unable to configure PostgreSQL: %w", err)
+	}
+	stopContainer := stop
+	stop = func() {
+		pool.Close()
+		stopContainer()
+	}
+	if err := waitPostgresReady(ctx); err != nil {
+		return stop, fmt.Errorf("PostgreSQL is not reachable: %w", err)
+	}
+	return stop, nil
+}
+
+// startPostgres runs PostgreSQL with PostGIS in Docker on a free port and
+// returns its URL and a function that removes the container
+func startPostgres() (string, func(), error) {
+	out, err := exec.Command("docker", "run", "-d", "--rm", "-p", "127.0.0.1::5432",
+		"-e", "POSTGRES_PASSWORD=postgres", postgisImage).Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("docker run: %w", err)
+	}
+	container := strings.TrimSpace(string(out))
+	stop := func() {
+		if err := exec.Command("docker", "stop", container).Run(); err != nil {
+			log.Printf("failed to stop PostgreSQL container %s: %v", container, err)
+		}
+	}
+
+	out, err = exec.Command("docker", "port", container, "5432/tcp").Output()
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("docker port: %w", err)
+	}
+	address := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+	return "postgres://postgres:postgres@" + address + "/postgres?sslmode=disable", stop, nil
+}
+
+// waitPostgresReady polls until PostgreSQL accepts connections. The image
+// only listens on TCP once its first-run setup is done.
+func waitPostgresReady(ctx context.Context) error {
+	for {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// requirePostgres skips the test when no PostgreSQL is available
+func requirePostgres(t *testing.T) {
+	t.Helper()
+	if pool == nil {
+		t.Skip(postgresSkipReason)
+	}
+}
+
+// newPostgresTables creates a fresh, uniquely named set of the PostgreSQL
+// venue store's tables and drops them when the test ends
+func newPostgresTables(t *testing.T) schema.PostgresTables {
+	t.Helper()
+	requirePostgres(t)
+
+	prefix := fmt.Sprintf("it_%d_%d_", runID, tableSets.Add(1))
+	tables := schema.PostgresTables{
+		Venues:       prefix + "venues",
+		VenueReviews: prefix + "venue_reviews",
+	}
+	ctx := context.Background()
+	t.Cleanup(func() {
+		drop := fmt.Sprintf("DROP TABLE IF EXISTS %s, %s, %s",
+			pgx.Identifier{tables.Venues + "_external_ids"}.Sanitize(),
+			pgx.Identifier{tables.Venues}.Sanitize(),
+			pgx.Identifier{tables.VenueReviews}.Sanitize())
+		if _, err := pool.Exec(ctx, drop); err != nil {
+			t.Errorf("failed to drop tables %s: %v", prefix+"*", err)
+		}
+	})
+	for _, m := range schema.PostgresMigrations(pool, tables) {
+		if err := m.Apply(ctx); err != nil {
+			t.Fatalf("failed to create tables: %v", err)
+		}
+	}
+	return tables
+}
@@ -0,0 +1,173 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crowdunlocked/services/bookings/internal/cache"
+	"github.com/crowdunlocked/services/bookings/internal/domain"
+	"github.com/crowdunlocked/services/bookings/internal/repository"
+	"github.com/crowdunlocked/services/bookings/internal/repository/repositorytest"
+	"github.com/crowdunlocked/services/bookings/internal/schema"
+)
+
+func TestDynamoDBVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		tables := newTables(t, func(tables schema.Tables) []string { return []string{tables.Venues} })
+		return repository.NewDynamoDBVenueRepository(client, tables.Venues)
+	})
+}
+
+func TestCachedDynamoDBVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		tables := newTables(t, func(tables schema.Tables) []string { return []string{tables.Venues} })
+		venues := repository.NewDynamoDBVenueRepository(client, tables.Venues)
+		return repository.NewCachedVenueRepository(venues, cache.NewLRU(1000), time.Minute)
+	})
+}
+
+func TestPostgresVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		tables := newPostgresTables(t)
+		return repository.NewPostgresVenueRepository(pool, tables.Venues)
+	})
+}
+
+func TestCachedPostgresVenueRepository_Conformance(t *testing.T) {
+	repositorytest.TestVenueRepository(t, func(t *testing.T) repository.VenueRepository {
+		tables := newPostgresTables(t)
+		venues := repository.NewPostgresVenueRepository(pool, tables.Venues)
+		return repository.NewCachedVenueRepository(venues, cache.NewLRU(1000), time.Minute)
+	})
+}
+
+func TestDynamoDBBookingRepository_Conformance(t *testing.T) {
+	repositorytest.TestBookingRepository(t, func(t *testing.T) repository.BookingRepository {
+		tables := newTables(t, func(tables schema.Tables) []string { return []string{tables.Bookings} })
+		return repository.NewDynamoDBBookingRepository(client, tables.Bookings)
+	})
+}
+
+func TestPostgresReviewRepository_SaveAndList(t *testing.T) {
+	tables := newPostgresTables(t)
+	venues := repository.NewPostgresVenueRepository(pool, tables.Venues)
+	reviews := repository.NewPostgresReviewRepository(pool, tables.VenueReviews, venues)
+	ctx := context.Background()
+
+	venue := domain.NewVenue("Bottom of the Hill", domain.GeoPoint{Latitude: 37.765, Longitude: -122.396, Geohash: "9q8yy"},
+		domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceUserSubmitted)
+	if err := venues.Create(ctx, venue); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stale := venue.Clone()
+
+	start := time.Now()
+	for i, artistID := range []string{"artist-1", "artist-2", "artist-3"} {
+		review := &domain.VenueReview{VenueID: venue.ID, ArtistID: artistID, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := reviews.Save(ctx, review, venue); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	got, err := venues.GetByID(ctx, venue.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Version != venue.Version {
+		t.Errorf("GetByID() after saving reviews Version = %d, want %d", got.Version, venue.Version)
+	}
+
+	page, next, err := reviews.ListByVenue(ctx, venue.ID, 2, "")
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ArtistID != "artist-3" || page[1].ArtistID != "artist-2" || next == "" {
+		t.Fatalf("first page = %+v next %q, want artist-3 then artist-2 and a cursor", page, next)
+	}
+	page, next, err = reviews.ListByVenue(ctx, venue.ID, 2, next)
+	if err != nil {
+		t.Fatalf("ListByVenue() error = %v", err)
+	}
+	if len(page) != 1 || page[0].ArtistID != "artist-1" || next != "" {
+		t.Errorf("last page = %+v next %q, want artist-1 and no cursor", page, next)
+	}
+
+	var conflict *repository.VersionConflictError
+	review := &domain.VenueReview{VenueID: venue.ID, ArtistID: "artist-4", CreatedAt: start}
+	if err := reviews.Save(ctx, review, stale); !errors.As(err, &conflict) {
+		t.Fatalf("Save() with a stale venue error = %v, want VersionConflictError", err)
+	}
+	var notFound *repository.ReviewNotFoundError
+	if _, err := reviews.Get(ctx, venue.ID, "artist-4"); !errors.As(err, &notFound) {
+		t.Errorf("Get() of a review whose venue write failed error = %v, want ReviewNotFoundError", err)
+	}
+}
+
+func TestDynamoDBVenueRepository_Merge(t *testing.T) {
+	tables := newTables(t, func(tables schema.Tables) []string {
+		return []string{tables.Venues, tables.VenueDuplicates, tables.VenueRedirects}
+	})
+	venues := repository.NewDynamoDBVenueRepository(client, tables.Venues)
+	venues.SetRedirectsTable(tables.VenueRedirects)
+	duplicates := repository.NewDynamoDBDuplicateRepository(client, tables.VenueDuplicates, tables.VenueRedirects)
+	ctx := context.Background()
+
+	newVenue := func(name string) *domain.Venue {
+		return domain.NewVenue(name, domain.GeoPoint{Latitude: 37.784, Longitude: -122.433, Geohash: "9q8yy"},
+			domain.Address{City: "San Francisco", State: "CA"}, []domain.VenueType{domain.VenueTypeClub}, domain.SourceManual)
+	}
+	survivor := newVenue("The Fillmore")
+	survivor.GooglePlaceID = "gp-1"
+	merged := newVenue("Fillmore")
+	merged.SongkickID = "sk-1"
+	merged.BandsintownID = "bit-1"
+	if err := venues.Create(ctx, survivor); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := venues.Create(ctx, merged); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	redirect := &domain.VenueRedirect{FromID: merged.ID, ToID: survivor.ID, CreatedAt: time.Now()}
+
+	// A stale merged venue fails the whole transaction
+	stale := merged.Clone()
+	stale.Version = 0
+	taken := survivor.Clone()
+	taken.SongkickID = "sk-1"
+	var conflict *repository.VersionConflictError
+	if err := venues.Merge(ctx, taken, stale, redirect); !errors.As(err, &conflict) {
+		t.Fatalf("Merge() with a stale venue error = %v, want VersionConflictError", err)
+	}
+	if got, err := venues.GetByExternalID(ctx, domain.SourceSongkick, "sk-1"); err != nil || got.ID != merged.ID {
+		t.Errorf("GetByExternalID() after a failed merge = %v, %v, want merged venue", got, err)
+	}
+	if got, _ := duplicates.GetRedirect(ctx, merged.ID); got != nil {
+		t.Errorf("GetRedirect() after a failed merge = %+v, want none", got)
+	}
+
+	// The survivor takes one of the merged venue's IDs; the other is released
+	survivor.SongkickID = "sk-1"
+	if err := venues.Merge(ctx, survivor, merged, redirect); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if survivor.Version != 2 {
+		t.Errorf("Merge() survivor Version = %d, want 2", survivor.Version)
+	}
+	if got, err := venues.GetByExternalID(ctx, domain.SourceSongkick, "sk-1"); err != nil || got.ID != survivor.ID {
+		t.Errorf("GetByExternalID(sk-1) = %v, %v, want survivor", got, err)
+	}
+	var notFound *repository.VenueNotFoundError
+	if _, err := venues.GetByExternalID(ctx, domain.SourceBandsintown, "bit-1"); !errors.As(err, &notFound) {
+		t.Errorf("GetByExternalID(bit-1) error = %v, want VenueNotFoundError", err)
+	}
+	if _, err := venues.GetByID(ctx, merged.ID); !errors.As(err, &notFound) {
+		t.Errorf("GetByID(merged) error = %v, want VenueNotFoundError", err)
+	}
+	if got, err := duplicates.GetRedirect(ctx, merged.ID); err != nil || got == nil || got.ToID != survivor.ID {
+		t.Errorf("GetRedirect() = %+v, %v, want redirect to survivor", got, err)
+	}
+}